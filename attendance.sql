@@ -0,0 +1,53 @@
+-- name: CreateClockIn :exec
+INSERT INTO attendance_records (id, employee_id, clock_in, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetOpenShiftForUpdate :one
+SELECT id, employee_id, clock_in, clock_out, created_at, updated_at
+FROM attendance_records
+WHERE employee_id = $1 AND clock_out IS NULL
+FOR UPDATE;
+
+-- name: CountShiftsEndingAfter :one
+-- shifts still open or ending after a time, which a shift starting then would overlap
+SELECT COUNT(*)
+FROM attendance_records
+WHERE employee_id = sqlc.arg(employee_id)
+  AND (clock_out IS NULL OR clock_out > sqlc.arg(at)::timestamp);
+
+-- name: SetClockOut :execrows
+UPDATE attendance_records
+SET clock_out = $2, updated_at = $3
+WHERE id = $1;
+
+-- name: ListAttendance :many
+-- shifts starting on or after from_time and before to_time, earliest first
+SELECT id, employee_id, clock_in, clock_out, created_at, updated_at
+FROM attendance_records
+WHERE employee_id = sqlc.arg(employee_id)
+  AND clock_in >= sqlc.arg(from_time)::timestamp
+  AND clock_in < sqlc.arg(to_time)::timestamp
+ORDER BY clock_in;
+
+-- name: AggregateTimesheet :many
+-- completed shifts are summed per day they start on, then per day, week
+-- (from Monday) or month; overtime is each day's hours beyond standard_hours
+WITH days AS (
+    SELECT clock_in::date AS day,
+           COUNT(*) AS shifts,
+           (SUM(EXTRACT(EPOCH FROM clock_out - clock_in)) / 3600)::float8 AS hours
+    FROM attendance_records
+    WHERE employee_id = sqlc.arg(employee_id)
+      AND clock_out IS NOT NULL
+      AND clock_in >= sqlc.arg(from_time)::timestamp
+      AND clock_in < sqlc.arg(to_time)::timestamp
+    GROUP BY clock_in::date
+)
+SELECT date_trunc(sqlc.arg(period)::text, day)::date AS period_start,
+       COUNT(*) AS days_worked,
+       SUM(shifts)::bigint AS shifts,
+       ROUND(SUM(hours)::numeric, 2)::float8 AS hours,
+       ROUND(SUM(GREATEST(hours - sqlc.arg(standard_hours)::float8, 0))::numeric, 2)::float8 AS overtime_hours
+FROM days
+GROUP BY 1
+ORDER BY 1;
@@ -0,0 +1,26 @@
+-- name: CreateAuditEntry :exec
+INSERT INTO audit_log (id, actor_id, actor_email, action, entity_type, entity_id, changes, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListAuditEntries :many
+SELECT id, actor_id, actor_email, action, entity_type, entity_id, changes, created_at
+FROM audit_log
+WHERE (sqlc.narg(actor_id)::uuid IS NULL OR actor_id = sqlc.narg(actor_id)::uuid)
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action)::text)
+  AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type)::text)
+  AND (sqlc.narg(entity_id)::uuid IS NULL OR entity_id = sqlc.narg(entity_id)::uuid)
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at <= sqlc.narg(created_to)::timestamp)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountAuditEntries :one
+SELECT COUNT(*)
+FROM audit_log
+WHERE (sqlc.narg(actor_id)::uuid IS NULL OR actor_id = sqlc.narg(actor_id)::uuid)
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action)::text)
+  AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type)::text)
+  AND (sqlc.narg(entity_id)::uuid IS NULL OR entity_id = sqlc.narg(entity_id)::uuid)
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at <= sqlc.narg(created_to)::timestamp);
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+// Claims is the JWT payload identifying the caller. Subject holds the user ID
+// and Id a unique token ID (jti) used to revoke the token on logout.
+type Claims struct {
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	EmployeeID *uuid.UUID `json:"employee_id,omitempty"`
+	jwt.StandardClaims
+}
+
+// UserID returns the authenticated user's ID from the subject claim
+func (c *Claims) UserID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// NewToken signs an HS256 token for user that expires after ttl
+func NewToken(secret string, user *database.User, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Email:      user.Email,
+		Role:       user.Role,
+		EmployeeID: user.EmployeeID,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			Subject:   user.ID.String(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ttl).Unix(),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
+// ParseToken verifies an HS256 token and returns its claims
+func ParseToken(secret, tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return []byte(secret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	claims := token.Claims.(*Claims)
+	if claims.Id == "" {
+		return nil, errors.New("token has no ID")
+	}
+	//tokens issued before roles existed carry no role and are no longer accepted
+	if !ValidRole(claims.Role) {
+		return nil, errors.New("token has no valid role")
+	}
+	return claims, nil
+}
@@ -0,0 +1,87 @@
+package auth
+
+// Role names stored on users and carried in the JWT
+const (
+	RoleAdmin   = "admin"
+	RoleHR      = "hr"
+	RoleManager = "manager"
+	RoleViewer  = "viewer"
+)
+
+// Permission names an action that routes can require
+type Permission string
+
+const (
+	PermEmployeesWrite   Permission = "employees:write"
+	PermHierarchyWrite   Permission = "hierarchy:write"
+	PermDepartmentsWrite Permission = "departments:write"
+	PermUsersManage      Permission = "users:manage"
+	PermAuditRead        Permission = "audit:read"
+	//PermDeletedManage covers listing, restoring and purging soft-deleted employees
+	PermDeletedManage Permission = "employees:deleted"
+
+	//salary visibility on reads: everyone's, or only the caller's own reports
+	PermSalaryReadAll     Permission = "salary:read:all"
+	PermSalaryReadReports Permission = "salary:read:reports"
+
+	//leave decisions: anyone's leave and leave types, or only the caller's direct reports
+	PermLeaveManage         Permission = "leave:manage"
+	PermLeaveApproveReports Permission = "leave:approve:reports"
+
+	//attendance: recording and reading anyone's, or reading the caller's direct reports'
+	PermAttendanceManage      Permission = "attendance:manage"
+	PermAttendanceReadReports Permission = "attendance:read:reports"
+
+	//payroll: rules, computing runs and reading payslips, or approving and finalizing runs
+	PermPayrollManage  Permission = "payroll:manage"
+	PermPayrollApprove Permission = "payroll:approve"
+
+	//PermExchangeRatesManage covers storing, importing and deleting exchange rates
+	PermExchangeRatesManage Permission = "exchange_rates:manage"
+)
+
+// rolePermissions grants each role its permissions; reads stay open to everyone
+var rolePermissions = map[string]map[Permission]bool{
+	RoleAdmin: {
+		PermEmployeesWrite:   true,
+		PermHierarchyWrite:   true,
+		PermDepartmentsWrite: true,
+		PermUsersManage:      true,
+		PermAuditRead:        true,
+		PermDeletedManage:    true,
+		PermSalaryReadAll:    true,
+		PermLeaveManage:      true,
+		PermAttendanceManage: true,
+		PermPayrollManage:    true,
+		PermPayrollApprove:   true,
+
+		PermExchangeRatesManage: true,
+	},
+	RoleHR: {
+		PermEmployeesWrite:   true,
+		PermHierarchyWrite:   true,
+		PermDepartmentsWrite: true,
+		PermAuditRead:        true,
+		PermSalaryReadAll:    true,
+		PermLeaveManage:      true,
+		PermAttendanceManage: true,
+		PermPayrollManage:    true,
+	},
+	RoleManager: {
+		PermSalaryReadReports:     true,
+		PermLeaveApproveReports:   true,
+		PermAttendanceReadReports: true,
+	},
+	RoleViewer: {},
+}
+
+// ValidRole reports whether role is one of the known roles
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Can reports whether role has been granted perm
+func Can(role string, perm Permission) bool {
+	return rolePermissions[role][perm]
+}
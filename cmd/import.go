@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/importer"
+	"github.com/lijuuu/EmployeeManagement/repo"
+	"github.com/lijuuu/EmployeeManagement/service"
+	"github.com/lijuuu/EmployeeManagement/validation"
+)
+
+const importUsage = "usage: import [-dry-run] [-mode best_effort|all_or_nothing] FILE.csv|FILE.xlsx"
+
+// runImport handles the `import` subcommand, which loads employees from a
+// sheet as POST /employees/import does. It fails when any row was rejected.
+func runImport(ctx context.Context, cfg *config.Config, db *pgxpool.Pool, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without keeping it")
+	mode := flags.String("mode", string(database.BulkBestEffort), "all_or_nothing or best_effort")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errors.New(importUsage)
+	}
+
+	path := flags.Arg(0)
+	format, err := importer.FormatOf(path)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	//created employees are invalidated in the cache like API writes
+	redisClient, err := database.InitRedis(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	defer redisClient.Close()
+
+	svc := service.NewEmployeeService(db, repo.NewEmployeeRepo(db), repo.NewAuditRepo(db), repo.NewExchangeRateRepo(db), redisClient)
+	report, err := importer.New(svc, validation.New(cfg.EmployeePositions)).Import(ctx, file, format, importer.Options{
+		Mode:   database.BulkMode(*mode),
+		DryRun: *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, col := range report.IgnoredColumns {
+		fmt.Printf("Ignored column %q\n", col)
+	}
+	for _, e := range report.Errors {
+		if e.Field != "" {
+			fmt.Printf("Row %d: %s: %s\n", e.Row, e.Field, e.Message)
+		} else {
+			fmt.Printf("Row %d: %s\n", e.Row, e.Message)
+		}
+	}
+	if report.ErrorsTruncated {
+		fmt.Println("More errors were found than are listed")
+	}
+
+	verb := "Imported"
+	if report.DryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d of %d rows\n", verb, report.Imported, report.Rows)
+	if report.Failed > 0 {
+		return fmt.Errorf("%d rows failed", report.Failed)
+	}
+	return nil
+}
@@ -3,16 +3,21 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/lijuuu/EmployeeManagement/config"
 	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/customerr"
 	"github.com/lijuuu/EmployeeManagement/database"
 	_ "github.com/lijuuu/EmployeeManagement/docs"
 	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/migrations"
 	"github.com/lijuuu/EmployeeManagement/repo"
 	"github.com/lijuuu/EmployeeManagement/routes"
 	"github.com/lijuuu/EmployeeManagement/service"
+	"github.com/lijuuu/EmployeeManagement/validation"
 )
 
 // @title Employee Management API
@@ -35,34 +40,95 @@ import (
 
 
 func main() {
+	//run returns instead of exiting so its deferred closes happen first
+	if err := run(); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		fmt.Printf("Failed to load config: %v\n", err)
-		return
+		return fmt.Errorf("Failed to load config: %w", err)
 	}
 
-	db, err := database.NewPostgresConn(context.Background(), cfg.PostgresDSN)
+	db, err := database.NewPostgresPool(context.Background(), cfg)
 	if err != nil {
-		fmt.Printf("Failed to connect to database: %v\n", err)
-		return
+		return fmt.Errorf("Failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	//subcommands run against the database and exit without serving
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			err = runMigrate(context.Background(), db, os.Args[2:])
+		case "import":
+			err = runImport(context.Background(), cfg, db, os.Args[2:])
+		default:
+			err = fmt.Errorf("unknown command %q", os.Args[1])
+		}
+		return err
+	}
+
+	if cfg.AutoMigrate {
+		applied, err := migrations.Up(context.Background(), db)
+		if err != nil {
+			return fmt.Errorf("Failed to run migrations: %w", err)
+		}
+		for _, m := range applied {
+			fmt.Printf("Applied migration %04d_%s\n", m.Version, m.Name)
+		}
 	}
-	defer db.Close(context.Background())
 
 	redisClient, err := database.InitRedis(cfg)
 	if err != nil {
-		fmt.Printf("Failed to connect to Redis: %v\n", err)
-		return
+		return fmt.Errorf("Failed to connect to Redis: %w", err)
 	}
 	defer redisClient.Close()
 
 	e := echo.New()
+	e.HTTPErrorHandler = customerr.HTTPErrorHandler
+	e.Validator = validation.New(cfg.EmployeePositions)
+	e.Use(echomiddleware.RequestID())
 	e.Use(middleware.RequestLoggerMiddleware())
 
 	employeeRepo := repo.NewEmployeeRepo(db)
-	employeeService := service.NewEmployeeService(employeeRepo, redisClient)
+	auditRepo := repo.NewAuditRepo(db)
+	ratesRepo := repo.NewExchangeRateRepo(db)
+	employeeService := service.NewEmployeeService(db, employeeRepo, auditRepo, ratesRepo, redisClient)
 	employeeController := controller.NewEmployeeController(employeeService, cfg)
 
-	routes.SetupRoutes(e, employeeController, cfg)
+	departmentRepo := repo.NewDepartmentRepo(db)
+	departmentService := service.NewDepartmentService(db, departmentRepo, auditRepo, employeeService, ratesRepo, redisClient)
+	departmentController := controller.NewDepartmentController(departmentService)
+
+	auditController := controller.NewAuditController(service.NewAuditService(auditRepo))
+
+	leaveService := service.NewLeaveService(db, repo.NewLeaveRepo(db), employeeRepo, auditRepo)
+	leaveController := controller.NewLeaveController(leaveService)
+
+	attendanceService := service.NewAttendanceService(db, repo.NewAttendanceRepo(db), employeeRepo)
+	attendanceController := controller.NewAttendanceController(attendanceService)
+
+	payrollService := service.NewPayrollService(db, repo.NewPayrollRepo(db), auditRepo, ratesRepo)
+	payrollController := controller.NewPayrollController(payrollService)
+
+	exchangeRateService := service.NewExchangeRateService(db, ratesRepo, auditRepo)
+	exchangeRateController := controller.NewExchangeRateController(exchangeRateService)
+
+	userService := service.NewUserService(repo.NewUserRepo(db), redisClient, cfg)
+	userController := controller.NewUserController(userService)
+
+	//seed the first admin account from ADMIN_EMAIL/ADMIN_PASSWORD
+	if cfg.AdminEmail != "" && cfg.AdminPassword != "" {
+		if err := userService.EnsureAdmin(context.Background(), cfg.AdminEmail, cfg.AdminPassword); err != nil {
+			return fmt.Errorf("Failed to seed admin user: %w", err)
+		}
+	}
+
+	routes.SetupRoutes(e, employeeController, departmentController, userController, auditController, leaveController, attendanceController, payrollController, exchangeRateController, userService, cfg)
 
-	e.Start(":8080")
+	return e.Start(":8080")
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lijuuu/EmployeeManagement/migrations"
+)
+
+const migrateUsage = "usage: migrate up | migrate down N | migrate status"
+
+// runMigrate handles the `migrate up`, `migrate down N` and `migrate status` subcommands
+func runMigrate(ctx context.Context, db *pgxpool.Pool, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(ctx, db)
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+		return nil
+
+	case "down":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		steps, err := strconv.Atoi(args[1])
+		if err != nil || steps < 1 {
+			return errors.New("migrate down expects a positive number of steps")
+		}
+		reverted, err := migrations.Down(ctx, db, steps)
+		for _, m := range reverted {
+			fmt.Printf("Reverted %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := migrations.List(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	}
+
+	return errors.New(migrateUsage)
+}
@@ -2,7 +2,11 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +19,26 @@ type Config struct {
 	AdminEmail    string
 	AdminPassword string
 	JWTSecret     string
+
+	//connection pool settings
+	DBMaxConns          int32
+	DBMinConns          int32
+	DBMaxConnLifetime   time.Duration
+	DBMaxConnIdleTime   time.Duration
+	DBHealthCheckPeriod time.Duration
+
+	//token lifetimes
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	//apply pending migrations before serving
+	AutoMigrate bool
+
+	//how long soft-deleted employees are kept before a purge removes them
+	SoftDeleteRetention time.Duration
+
+	//positions an employee may hold; empty allows any
+	EmployeePositions []string
 }
 
 func LoadConfig() (*Config, error) {
@@ -35,5 +59,88 @@ func LoadConfig() (*Config, error) {
 		return nil, errors.New("required environment variables are missing")
 	}
 
+	var err error
+	if cfg.DBMaxConns, err = getEnvInt32("DB_MAX_CONNS", 10); err != nil {
+		return nil, err
+	}
+	if cfg.DBMinConns, err = getEnvInt32("DB_MIN_CONNS", 0); err != nil {
+		return nil, err
+	}
+	if cfg.DBMaxConnLifetime, err = getEnvDuration("DB_MAX_CONN_LIFETIME", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.DBMaxConnIdleTime, err = getEnvDuration("DB_MAX_CONN_IDLE_TIME", 30*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.DBHealthCheckPeriod, err = getEnvDuration("DB_HEALTH_CHECK_PERIOD", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.AccessTokenTTL, err = getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.RefreshTokenTTL, err = getEnvDuration("REFRESH_TOKEN_TTL", 7*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.AutoMigrate, err = getEnvBool("AUTO_MIGRATE", false); err != nil {
+		return nil, err
+	}
+	if cfg.SoftDeleteRetention, err = getEnvDuration("SOFT_DELETE_RETENTION", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+	cfg.EmployeePositions = getEnvList("EMPLOYEE_POSITIONS")
+	if cfg.DBMaxConns < 1 || cfg.DBMinConns > cfg.DBMaxConns {
+		return nil, errors.New("DB_MAX_CONNS must be at least 1 and not below DB_MIN_CONNS")
+	}
+
 	return cfg, nil
 }
+
+// getEnvInt32 reads a positive integer variable, falling back to def when unset
+func getEnvInt32(key string, def int32) (int32, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return int32(n), nil
+}
+
+// getEnvDuration reads a Go duration string such as "30s", falling back to def when unset
+func getEnvDuration(key string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration", key)
+	}
+	return d, nil
+}
+
+// getEnvBool reads a boolean variable such as "true" or "0", falling back to def when unset
+func getEnvBool(key string, def bool) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", key)
+	}
+	return b, nil
+}
+
+// getEnvList reads a comma-separated variable, dropping blank entries
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/exporter"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/service"
+)
+
+// maxTimesheetDays caps the date range of an attendance listing or timesheet
+const maxTimesheetDays = 366
+
+// AttendanceController handles HTTP requests for clock-ins, clock-outs and timesheets
+type AttendanceController struct {
+	service service.AttendanceService
+}
+
+func NewAttendanceController(service service.AttendanceService) *AttendanceController {
+	return &AttendanceController{service: service}
+}
+
+// ClockIn godoc
+// @Summary Clock in
+// @Description Start a shift for the employee, now unless HR gives `at` to correct a forgotten punch. Employees clock in themselves; HR may clock in anyone. Fails with 409 when the employee is already clocked in or the shift would overlap a recorded one. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param punch body database.Punch false "Punch time"
+// @Success 201 {object} Response{payload=database.AttendanceRecord}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/attendance/clock-in [post]
+func (c *AttendanceController) ClockIn(ctx echo.Context) error {
+	return c.punch(ctx, http.StatusCreated, c.service.ClockIn)
+}
+
+// ClockOut godoc
+// @Summary Clock out
+// @Description End the employee's open shift, now unless HR gives `at`. Fails with 409 when the employee is not clocked in. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags attendance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param punch body database.Punch false "Punch time"
+// @Success 200 {object} Response{payload=database.AttendanceRecord}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/attendance/clock-out [post]
+func (c *AttendanceController) ClockOut(ctx echo.Context) error {
+	return c.punch(ctx, http.StatusOK, c.service.ClockOut)
+}
+
+// ListAttendance godoc
+// @Summary List an employee's shifts
+// @Description List the shifts starting from `from` to `to` inclusive, earliest first, defaulting to the current month. Only the employee, their manager and HR may read them. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param from query string false "First day" format(date)
+// @Param to query string false "Last day, at most 366 days after from" format(date)
+// @Success 200 {object} Response{payload=[]database.AttendanceRecord}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/attendance [get]
+func (c *AttendanceController) ListAttendance(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+	from, to, err := parseTimesheetRange(ctx)
+	if err != nil {
+		return err
+	}
+
+	records, err := c.service.ListAttendance(ctx.Request().Context(), id, from, to, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    records,
+	})
+}
+
+// GetTimesheet godoc
+// @Summary Get an employee's timesheet
+// @Description Sum the completed shifts starting from `from` to `to` inclusive per day, week (from Monday) or month, defaulting to the current month. A shift counts towards the day it starts on, and hours beyond 8 in a day are overtime. Only the employee, their manager and HR may read it. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags attendance
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param period query string false "Period to sum over" Enums(daily, weekly, monthly) default(daily)
+// @Param from query string false "First day" format(date)
+// @Param to query string false "Last day, at most 366 days after from" format(date)
+// @Success 200 {object} Response{payload=database.Timesheet}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/timesheet [get]
+func (c *AttendanceController) GetTimesheet(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+	query, err := parseTimesheetQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheet, err := c.service.GetTimesheet(ctx.Request().Context(), id, query, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    sheet,
+	})
+}
+
+// ExportTimesheet godoc
+// @Summary Export an employee's timesheet
+// @Description Download the entries of `GET /employees/{id}/timesheet` as CSV (the default), JSON Lines or an XLSX workbook. Only the employee, their manager and HR may export it. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags attendance
+// @Produce text/csv
+// @Produce application/jsonl
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param format query string false "File format" Enums(csv, jsonl, xlsx) default(csv)
+// @Param period query string false "Period to sum over" Enums(daily, weekly, monthly) default(daily)
+// @Param from query string false "First day" format(date)
+// @Param to query string false "Last day, at most 366 days after from" format(date)
+// @Success 200 {file} file
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/timesheet/export [get]
+func (c *AttendanceController) ExportTimesheet(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+	format, err := exporter.ParseFormat(ctx.QueryParam("format"))
+	if err != nil {
+		return err
+	}
+	query, err := parseTimesheetQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	//a timesheet has at most one entry per day of a year, so it is
+	//read whole and access is settled before anything is written
+	sheet, err := c.service.GetTimesheet(ctx.Request().Context(), id, query, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	res := ctx.Response()
+	writer, err := exporter.NewTimesheetWriter(res, format)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+	res.Header().Set(echo.HeaderContentType, format.ContentType())
+	filename := fmt.Sprintf("timesheet-%s-%s.%s", id, time.Now().UTC().Format(time.DateOnly), format)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	for _, entry := range sheet.Entries {
+		if err = writer.Write(entry); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		return nil
+	}
+
+	if !res.Committed {
+		res.Header().Del(echo.HeaderContentDisposition)
+		return err
+	}
+	log.Printf("Error: timesheet export aborted after %d bytes [%s]: %v", res.Size, customerr.RequestID(ctx), err)
+	panic(http.ErrAbortHandler)
+}
+
+// punch records a clock-in or clock-out for the employee in the path
+func (c *AttendanceController) punch(ctx echo.Context, status int, record func(ctx context.Context, employeeID uuid.UUID, punch *database.Punch, caller *auth.Claims) (*database.AttendanceRecord, error)) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+	var punch database.Punch
+	if err := ctx.Bind(&punch); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&punch); err != nil {
+		return err
+	}
+
+	rec, err := record(ctx.Request().Context(), id, &punch, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(status, Response{
+		Status:     "success",
+		StatusCode: status,
+		Payload:    rec,
+	})
+}
+
+// parseTimesheetQuery reads the period and date range of a timesheet
+func parseTimesheetQuery(ctx echo.Context) (database.TimesheetQuery, error) {
+	query := database.TimesheetQuery{Period: database.TimesheetDaily}
+	switch v := database.TimesheetPeriod(ctx.QueryParam("period")); v {
+	case "":
+	case database.TimesheetDaily, database.TimesheetWeekly, database.TimesheetMonthly:
+		query.Period = v
+	default:
+		return query, customerr.InvalidField("period", customerr.FieldInvalidValue, "period must be daily, weekly or monthly")
+	}
+	var err error
+	query.From, query.To, err = parseTimesheetRange(ctx)
+	return query, err
+}
+
+// parseTimesheetRange reads the from and to dates, defaulting to the first
+// of the current month and today
+func parseTimesheetRange(ctx echo.Context) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from, to := today.AddDate(0, 0, 1-today.Day()), today
+
+	f, err := parseDateParam(ctx, "from")
+	if err != nil {
+		return from, to, err
+	}
+	if f != nil {
+		from = *f
+	}
+	t, err := parseDateParam(ctx, "to")
+	if err != nil {
+		return from, to, err
+	}
+	if t != nil {
+		to = *t
+	}
+	if to.Before(from) || to.After(from.AddDate(0, 0, maxTimesheetDays)) {
+		return from, to, customerr.InvalidField("to", customerr.FieldOutOfRange, "to must be between from and 366 days after it")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/service"
+)
+
+// AuditController handles HTTP requests for the audit log
+type AuditController struct {
+	service service.AuditService
+}
+
+func NewAuditController(service service.AuditService) *AuditController {
+	return &AuditController{service: service}
+}
+
+// ListAudit godoc
+// @Summary List audit log entries
+// @Description Retrieve a page of recorded mutations, newest first. Each entry holds the acting user, the action and the changed fields with their before and after values. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param offset query int false "Entries to skip" default(0)
+// @Param actor_id query string false "User ID of the actor" format(uuid)
+// @Param action query string false "Action" Enums(create, update, delete, set_manager, restore, purge, apply, approve, reject, cancel, recompute, finalize)
+// @Param entity_type query string false "Entity type" Enums(employee, leave_type, leave_request, payroll_rule, payroll_run)
+// @Param entity_id query string false "Entity ID" format(uuid)
+// @Param from query string false "Earliest time, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "Latest time, RFC 3339 or YYYY-MM-DD (inclusive)"
+// @Success 200 {object} Response{payload=database.AuditPage}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /audit [get]
+func (c *AuditController) ListAudit(ctx echo.Context) error {
+	filter, err := parseAuditFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	if v := ctx.QueryParam("entity_type"); v != "" {
+		filter.EntityType = &v
+	}
+	if v := ctx.QueryParam("entity_id"); v != "" {
+		entityID, err := uuid.Parse(v)
+		if err != nil {
+			return customerr.InvalidField("entity_id", customerr.FieldInvalidUUID, "entity_id must be a UUID")
+		}
+		filter.EntityID = &entityID
+	}
+
+	page, err := c.service.ListEntries(ctx.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    page,
+	})
+}
+
+// GetEmployeeHistory godoc
+// @Summary Get an employee's change history
+// @Description Retrieve the audit entries for one employee, newest first. History stays available after the employee is deleted. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param offset query int false "Entries to skip" default(0)
+// @Param actor_id query string false "User ID of the actor" format(uuid)
+// @Param action query string false "Action" Enums(create, update, delete, set_manager, restore, purge)
+// @Param from query string false "Earliest time, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "Latest time, RFC 3339 or YYYY-MM-DD (inclusive)"
+// @Success 200 {object} Response{payload=database.AuditPage}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/history [get]
+func (c *AuditController) GetEmployeeHistory(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseAuditFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	page, err := c.service.EmployeeHistory(ctx.Request().Context(), id, filter)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    page,
+	})
+}
+
+// parseAuditFilter reads the paging, actor, action and time range parameters shared by the audit endpoints
+func parseAuditFilter(ctx echo.Context) (database.AuditFilter, error) {
+	var filter database.AuditFilter
+	var err error
+	if filter.Limit, filter.Offset, err = parsePaging(ctx); err != nil {
+		return filter, err
+	}
+
+	if v := ctx.QueryParam("actor_id"); v != "" {
+		actorID, err := uuid.Parse(v)
+		if err != nil {
+			return filter, customerr.InvalidField("actor_id", customerr.FieldInvalidUUID, "actor_id must be a UUID")
+		}
+		filter.ActorID = &actorID
+	}
+
+	if v := ctx.QueryParam("action"); v != "" {
+		filter.Action = &v
+	}
+
+	if filter.From, err = parseTimeParam(ctx, "from", false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeParam(ctx, "to", true); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// parseTimeParam accepts an RFC 3339 timestamp or a plain date; with endOfDay
+// a plain date covers the whole day
+func parseTimeParam(ctx echo.Context, name string, endOfDay bool) (*time.Time, error) {
+	v := ctx.QueryParam(name)
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return nil, customerr.InvalidField(name, customerr.FieldInvalid, name+" must be an RFC 3339 timestamp or a date in YYYY-MM-DD format")
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
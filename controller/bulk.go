@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+)
+
+// MaxBulkItems caps the items of one bulk request, which all run in a single transaction
+const MaxBulkItems = 500
+
+// BulkCreateEmployees godoc
+// @Summary Create employees in bulk
+// @Description Create up to 500 employees in one transaction. Each item is validated as for `POST /employees`. In `all_or_nothing` mode (the default) nothing is kept when any item fails; in `best_effort` mode the valid items are created and the others reported. Every item's outcome is listed in request order; failed items carry a problem in `error`. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags employees
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body database.BulkCreateRequest true "Employees to create"
+// @Success 200 {object} Response{payload=database.BulkResult}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/bulk [post]
+func (c *EmployeeController) BulkCreateEmployees(ctx echo.Context) error {
+	var req database.BulkCreateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	batch, err := newBulkBatch(req.Mode, len(req.Employees))
+	if err != nil {
+		return err
+	}
+
+	var emps []database.Employee
+	for i := range req.Employees {
+		if batch.check(i, ctx.Validate(&req.Employees[i])) {
+			emps = append(emps, req.Employees[i].Employee())
+		}
+	}
+
+	result, err := batch.run(func(mode database.BulkMode) (*database.BulkResult, error) {
+		return c.service.BulkCreateEmployees(ctx.Request().Context(), emps, mode, middleware.ClaimsFrom(ctx))
+	})
+	if err != nil {
+		return err
+	}
+	return bulkResponse(ctx, result)
+}
+
+// BulkPatchEmployees godoc
+// @Summary Patch employees in bulk
+// @Description Apply a merge patch to each of up to 500 employees in one transaction. Each `patch` follows the rules of `PATCH /employees/{id}`, and a `version` makes its item conditional as `If-Match` does. The modes and results work as for `POST /employees/bulk`. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags employees
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body database.BulkPatchRequest true "Employees and the patches to apply"
+// @Success 200 {object} Response{payload=database.BulkResult}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/bulk [patch]
+func (c *EmployeeController) BulkPatchEmployees(ctx echo.Context) error {
+	var req database.BulkPatchRequest
+	if err := ctx.Bind(&req); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	batch, err := newBulkBatch(req.Mode, len(req.Employees))
+	if err != nil {
+		return err
+	}
+
+	var patches []database.BulkPatch
+	for i, item := range req.Employees {
+		var patch *database.EmployeePatch
+		var err error
+		switch {
+		case item.ID == uuid.Nil:
+			err = customerr.InvalidField("id", customerr.FieldRequired, "id is required")
+		case len(item.Patch) == 0:
+			err = customerr.InvalidField("patch", customerr.FieldRequired, "patch is required")
+		default:
+			if patch, err = parseEmployeePatch(item.Patch); err == nil {
+				err = ctx.Validate(patch)
+			}
+		}
+		if batch.check(i, err) {
+			patches = append(patches, database.BulkPatch{ID: item.ID, Patch: patch, Pre: versionPrecondition(item.Version)})
+		}
+	}
+
+	result, err := batch.run(func(mode database.BulkMode) (*database.BulkResult, error) {
+		return c.service.BulkPatchEmployees(ctx.Request().Context(), patches, mode, middleware.ClaimsFrom(ctx))
+	})
+	if err != nil {
+		return err
+	}
+	return bulkResponse(ctx, result)
+}
+
+// BulkDeleteEmployees godoc
+// @Summary Delete employees in bulk
+// @Description Soft-delete up to 500 employees in one transaction, as `DELETE /employees/{id}` does for one. A `version` makes its item conditional as `If-Match` does. The modes and results work as for `POST /employees/bulk`. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags employees
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body database.BulkDeleteRequest true "Employees to delete"
+// @Success 200 {object} Response{payload=database.BulkResult}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/bulk [delete]
+func (c *EmployeeController) BulkDeleteEmployees(ctx echo.Context) error {
+	var req database.BulkDeleteRequest
+	if err := ctx.Bind(&req); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	batch, err := newBulkBatch(req.Mode, len(req.Employees))
+	if err != nil {
+		return err
+	}
+
+	var deletes []database.BulkDelete
+	for i, item := range req.Employees {
+		var err error
+		if item.ID == uuid.Nil {
+			err = customerr.InvalidField("id", customerr.FieldRequired, "id is required")
+		}
+		if batch.check(i, err) {
+			deletes = append(deletes, database.BulkDelete{ID: item.ID, Pre: versionPrecondition(item.Version)})
+		}
+	}
+
+	result, err := batch.run(func(mode database.BulkMode) (*database.BulkResult, error) {
+		return c.service.BulkDeleteEmployees(ctx.Request().Context(), deletes, mode, middleware.ClaimsFrom(ctx))
+	})
+	if err != nil {
+		return err
+	}
+	return bulkResponse(ctx, result)
+}
+
+// bulkBatch tracks which items of a bulk request passed validation
+type bulkBatch struct {
+	mode    database.BulkMode
+	results []database.BulkItemResult
+	//accepted holds the request index of each item handed to the service
+	accepted []int
+}
+
+// newBulkBatch checks the mode and size of a bulk request of n items
+func newBulkBatch(mode database.BulkMode, n int) (*bulkBatch, error) {
+	if mode == "" {
+		mode = database.BulkAllOrNothing
+	}
+	if mode != database.BulkAllOrNothing && mode != database.BulkBestEffort {
+		return nil, customerr.InvalidField("mode", customerr.FieldInvalidValue, "mode must be all_or_nothing or best_effort")
+	}
+	if n == 0 || n > MaxBulkItems {
+		return nil, customerr.InvalidField("employees", customerr.FieldOutOfRange, fmt.Sprintf("employees must hold between 1 and %d items", MaxBulkItems))
+	}
+	return &bulkBatch{mode: mode, results: make([]database.BulkItemResult, n)}, nil
+}
+
+// check records item i as failed when err is set and reports whether it was accepted
+func (b *bulkBatch) check(i int, err error) bool {
+	if err != nil {
+		b.results[i] = database.BulkItemResult{Status: database.BulkStatusFailed, Err: err}
+		return false
+	}
+	b.accepted = append(b.accepted, i)
+	return true
+}
+
+// run hands the accepted items to apply and merges its results with the
+// rejected items, in request order. An all-or-nothing batch with a rejected
+// item is not applied at all.
+func (b *bulkBatch) run(apply func(mode database.BulkMode) (*database.BulkResult, error)) (*database.BulkResult, error) {
+	rejected := len(b.accepted) < len(b.results)
+	if len(b.accepted) > 0 && (!rejected || b.mode == database.BulkBestEffort) {
+		applied, err := apply(b.mode)
+		if err != nil {
+			return nil, err
+		}
+		for j, item := range applied.Results {
+			b.results[b.accepted[j]] = item
+		}
+	}
+
+	for i := range b.results {
+		b.results[i].Index = i
+		if b.results[i].Status == "" {
+			b.results[i].Status = database.BulkStatusSkipped
+		}
+	}
+	result := &database.BulkResult{Mode: b.mode, Results: b.results}
+	result.Tally()
+	return result, nil
+}
+
+// bulkResponse renders the failed items' errors as problems and writes result
+func bulkResponse(ctx echo.Context, result *database.BulkResult) error {
+	for i := range result.Results {
+		if err := result.Results[i].Err; err != nil {
+			problem := customerr.ProblemOf(err)
+			result.Results[i].Error = &problem
+		}
+	}
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    result,
+	})
+}
+
+// versionPrecondition is the precondition for a bulk item's optional version
+func versionPrecondition(version *int64) *database.Precondition {
+	if version == nil {
+		return nil
+	}
+	return &database.Precondition{Versions: []int64{*version}}
+}
@@ -2,14 +2,12 @@ package controller
 
 import (
 	"net/http"
-	"time"
 
-	"github.com/dgrijalva/jwt-go"
-	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/lijuuu/EmployeeManagement/config"
 	"github.com/lijuuu/EmployeeManagement/customerr"
 	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
 	"github.com/lijuuu/EmployeeManagement/service"
 )
 
@@ -30,70 +28,37 @@ func NewEmployeeController(service service.EmployeeService, cfg *config.Config)
 	return &EmployeeController{service: service, cfg: cfg}
 }
 
-// Login godoc
-// @Summary Admin login
-// @Description Authenticate admin and return a JWT token for use in the Authorization header as `Bearer <token>`.
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param credentials body database.Credentials true "Admin credentials"
-// @Success 200 {object} Response
-// @Failure 400 {object} customerr.ErrorResponse
-// @Failure 401 {object} customerr.ErrorResponse
-// @Failure 500 {object} customerr.ErrorResponse
-// @Router /login [post]
-func (c *EmployeeController) Login(ctx echo.Context) error {
-	var credentials database.Credentials
-	if err := ctx.Bind(&credentials); err != nil {
-		return customerr.NewError(ctx, http.StatusBadRequest, "Invalid request body")
-	}
-
-	if credentials.Email != c.cfg.AdminEmail || c.cfg.AdminPassword != credentials.Password {
-		return customerr.NewError(ctx, http.StatusUnauthorized, "Invalid credentials")
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"email": credentials.Email,
-		"exp":   time.Now().Add(time.Hour * 24).Unix(),
-	})
-
-	tokenString, err := token.SignedString([]byte(c.cfg.JWTSecret))
-	if err != nil {
-		return customerr.NewError(ctx, http.StatusInternalServerError, "Failed to generate token")
-	}
-
-	return ctx.JSON(http.StatusOK, Response{
-		Status:     "success",
-		StatusCode: http.StatusOK,
-		Payload:    tokenString,
-	})
-}
-
 // CreateEmployee godoc
 // @Summary Create a new employee
-// @Description Create a new employee record. Requires an `Authorization` header with a valid Bearer token (`Bearer <token>`).
+// @Description Create a new employee record. Every invalid field is listed in the problem's `errors`; `position` must be one of `EMPLOYEE_POSITIONS` when that is set. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
 // @Tags employees
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param employee body database.Employee true "Employee data"
-// @Success 201 {object} Response
-// @Failure 400 {object} customerr.ErrorResponse
-// @Failure 401 {object} customerr.ErrorResponse
-// @Failure 500 {object} customerr.ErrorResponse
+// @Param employee body database.EmployeeCreateRequest true "Employee data"
+// @Success 201 {object} Response{payload=database.Employee}
+// @Header 201 {string} ETag "Version of the new employee"
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
 // @Router /employees [post]
 func (c *EmployeeController) CreateEmployee(ctx echo.Context) error {
-	var emp database.Employee
-	if err := ctx.Bind(&emp); err != nil {
-		return customerr.NewError(ctx, http.StatusBadRequest, "Invalid request body")
+	var req database.EmployeeCreateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&req); err != nil {
+		return err
 	}
 
-	id, err := c.service.CreateEmployee(ctx.Request().Context(), &emp)
+	emp := req.Employee()
+	id, err := c.service.CreateEmployee(ctx.Request().Context(), &emp, middleware.ClaimsFrom(ctx))
 	if err != nil {
-		return customerr.NewError(ctx, http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	emp.ID = id
+	setEmployeeETag(ctx, &emp)
 	return ctx.JSON(http.StatusCreated, Response{
 		Status:     "success",
 		StatusCode: http.StatusCreated,
@@ -103,26 +68,35 @@ func (c *EmployeeController) CreateEmployee(ctx echo.Context) error {
 
 // GetEmployee godoc
 // @Summary Get employee by ID
-// @Description Retrieve details of a specific employee. No authentication required.
+// @Description Retrieve details of a specific employee. Authentication is optional: `salary` is only included for the `hr` and `admin` roles, and for a `manager` looking at one of their direct or indirect reports. The `ETag` names the employee's version; send it back in `If-None-Match` to get `304 Not Modified` while the employee is unchanged, or in `If-Match` to update or delete it safely.
 // @Tags employees
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Employee ID" format(uuid)
-// @Success 200 {object} Response
-// @Failure 400 {object} customerr.ErrorResponse
-// @Failure 404 {object} customerr.ErrorResponse
+// @Param If-None-Match header string false "ETag from an earlier response"
+// @Success 200 {object} Response{payload=database.EmployeeView}
+// @Header 200 {string} ETag "Version of the employee"
+// @Success 304 "The employee has not changed"
+// @Failure 400 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
 // @Router /employees/{id} [get]
 func (c *EmployeeController) GetEmployee(ctx echo.Context) error {
-	id, err := uuid.Parse(ctx.Param("id"))
+	id, err := parseUUIDParam(ctx, "id")
 	if err != nil {
-		return customerr.NewError(ctx, http.StatusBadRequest, "Invalid employee ID")
+		return err
 	}
 
-	emp, err := c.service.GetEmployeeByID(ctx.Request().Context(), id)
+	emp, err := c.service.GetEmployeeByID(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx))
 	if err != nil {
-		return customerr.NewError(ctx, http.StatusNotFound, "Employee not found")
+		return err
 	}
 
+	setEmployeeETag(ctx, &emp.Employee)
+	if notModified(ctx, emp.Version) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
 	return ctx.JSON(http.StatusOK, Response{
 		Status:     "success",
 		StatusCode: http.StatusOK,
@@ -132,85 +106,164 @@ func (c *EmployeeController) GetEmployee(ctx echo.Context) error {
 
 // UpdateEmployee godoc
 // @Summary Update an employee
-// @Description Update details of a specific employee. Requires an `Authorization` header with a valid Bearer token (`Bearer <token>`).
+// @Description Replace an employee's name, position, salary, currency and hired date; an omitted `hired_date` or `currency` is left unchanged. The same validation rules as for creation apply. With `If-Match`, the update only happens while the employee is still at that `ETag`; otherwise it fails with `412`. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
 // @Tags employees
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Employee ID" format(uuid)
-// @Param employee body database.Employee true "Employee data"
-// @Success 200 {object} Response
-// @Failure 400 {object} customerr.ErrorResponse
-// @Failure 401 {object} customerr.ErrorResponse
-// @Failure 404 {object} customerr.ErrorResponse
+// @Param If-Match header string false "ETag the update is conditional on"
+// @Param employee body database.EmployeeUpdateRequest true "Employee data"
+// @Success 200 {object} Response{payload=database.Employee}
+// @Header 200 {string} ETag "New version of the employee"
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 412 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
 // @Router /employees/{id} [put]
 func (c *EmployeeController) UpdateEmployee(ctx echo.Context) error {
-	id, err := uuid.Parse(ctx.Param("id"))
+	id, err := parseUUIDParam(ctx, "id")
 	if err != nil {
-		return customerr.NewError(ctx, http.StatusBadRequest, "Invalid employee ID")
+		return err
 	}
 
-	var emp database.Employee
-	if err := ctx.Bind(&emp); err != nil {
-		return customerr.NewError(ctx, http.StatusBadRequest, "Invalid request body")
+	var req database.EmployeeUpdateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&req); err != nil {
+		return err
 	}
 
-	if err := c.service.UpdateEmployee(ctx.Request().Context(), id, &emp); err != nil {
-		return customerr.NewError(ctx, http.StatusNotFound, "Employee not found")
+	emp := req.Employee()
+	updated, err := c.service.UpdateEmployee(ctx.Request().Context(), id, &emp, parseIfMatch(ctx), middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
 	}
 
-	emp.ID = id
+	setEmployeeETag(ctx, updated)
 	return ctx.JSON(http.StatusOK, Response{
 		Status:     "success",
 		StatusCode: http.StatusOK,
-		Payload:    emp,
+		Payload:    updated,
+	})
+}
+
+// PatchEmployee godoc
+// @Summary Partially update an employee
+// @Description Apply an RFC 7396 JSON merge patch: only the members present are changed. `name`, `position`, `salary`, `currency` and `hired_date` may be set, with the same validation as creation; they cannot be removed with `null`. Returns the full record as stored. `If-Match` makes the patch conditional as for `PUT`. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags employees
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param If-Match header string false "ETag the patch is conditional on"
+// @Param patch body database.EmployeePatch true "Fields to change"
+// @Success 200 {object} Response{payload=database.Employee}
+// @Header 200 {string} ETag "New version of the employee"
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 412 {object} customerr.Problem
+// @Failure 415 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id} [patch]
+func (c *EmployeeController) PatchEmployee(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	patch, err := decodeEmployeePatch(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Validate(patch); err != nil {
+		return err
+	}
+
+	updated, err := c.service.PatchEmployee(ctx.Request().Context(), id, patch, parseIfMatch(ctx), middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	setEmployeeETag(ctx, updated)
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    updated,
 	})
 }
 
 // DeleteEmployee godoc
 // @Summary Delete an employee
-// @Description Delete a specific employee. Requires an `Authorization` header with a valid Bearer token (`Bearer <token>`).
+// @Description Soft-delete a specific employee: it disappears from every read and its direct reports lose their manager, but an admin can restore it until it is purged. `If-Match` makes the delete conditional as for `PUT`. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
 // @Tags employees
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Employee ID" format(uuid)
+// @Param If-Match header string false "ETag the delete is conditional on"
 // @Success 204
-// @Failure 400 {object} customerr.ErrorResponse
-// @Failure 401 {object} customerr.ErrorResponse
-// @Failure 404 {object} customerr.ErrorResponse
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 412 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
 // @Router /employees/{id} [delete]
 func (c *EmployeeController) DeleteEmployee(ctx echo.Context) error {
-	id, err := uuid.Parse(ctx.Param("id"))
+	id, err := parseUUIDParam(ctx, "id")
 	if err != nil {
-		return customerr.NewError(ctx, http.StatusBadRequest, "Invalid employee ID")
+		return err
 	}
 
-	if err := c.service.DeleteEmployee(ctx.Request().Context(), id); err != nil {
-		return customerr.NewError(ctx, http.StatusNotFound, "Employee not found")
+	if err := c.service.DeleteEmployee(ctx.Request().Context(), id, parseIfMatch(ctx), middleware.ClaimsFrom(ctx)); err != nil {
+		return err
 	}
 
 	return ctx.NoContent(http.StatusNoContent)
 }
 
 // ListEmployees godoc
-// @Summary List all employees
-// @Description Retrieve a list of all employees. No authentication required.
+// @Summary List employees
+// @Description Retrieve a page of employees with optional filters and sorting. Use either `offset` or the `next_cursor` of a previous page as `cursor`. Authentication is optional: salaries are projected as for `GET /employees/{id}`, and salary filters or sorting by salary need the `hr` or `admin` role.
 // @Tags employees
 // @Accept json
 // @Produce json
-// @Success 200 {object} Response
-// @Failure 500 {object} customerr.ErrorResponse
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param offset query int false "Rows to skip; ignored when cursor is set" default(0)
+// @Param cursor query string false "Keyset cursor from a previous page's next_cursor"
+// @Param position query string false "Exact position match"
+// @Param department_id query string false "Department ID" format(uuid)
+// @Param min_salary query number false "Minimum salary"
+// @Param max_salary query number false "Maximum salary"
+// @Param salary_currency query string false "Only employees paid in this ISO 4217 currency"
+// @Param hired_from query string false "Earliest hired date" format(date)
+// @Param hired_to query string false "Latest hired date" format(date)
+// @Param sort_by query string false "Sort column" Enums(id, name, position, salary, hired_date, created_at, updated_at) default(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(asc)
+// @Security BearerAuth
+// @Success 200 {object} Response{payload=database.EmployeeViewPage}
+// @Failure 400 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
 // @Router /employees [get]
 func (c *EmployeeController) ListEmployees(ctx echo.Context) error {
-	employees, err := c.service.ListEmployees(ctx.Request().Context())
+	filter, err := parseEmployeeFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	page, err := c.service.ListEmployees(ctx.Request().Context(), filter, middleware.ClaimsFrom(ctx))
 	if err != nil {
-		return customerr.NewError(ctx, http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, Response{
 		Status:     "success",
 		StatusCode: http.StatusOK,
-		Payload:    employees,
+		Payload:    page,
 	})
-}
\ No newline at end of file
+}
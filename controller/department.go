@@ -0,0 +1,321 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/service"
+)
+
+// DepartmentController handles HTTP requests for department operations
+type DepartmentController struct {
+	service service.DepartmentService
+}
+
+func NewDepartmentController(service service.DepartmentService) *DepartmentController {
+	return &DepartmentController{service: service}
+}
+
+// CreateDepartment godoc
+// @Summary Create a new department
+// @Description Create a new department. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param department body database.Department true "Department data"
+// @Success 201 {object} Response{payload=database.Department}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /departments [post]
+func (c *DepartmentController) CreateDepartment(ctx echo.Context) error {
+	var dept database.Department
+	if err := ctx.Bind(&dept); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if dept.Name == "" {
+		return customerr.InvalidField("name", customerr.FieldRequired, "name is required")
+	}
+
+	id, err := c.service.CreateDepartment(ctx.Request().Context(), &dept)
+	if err != nil {
+		return err
+	}
+
+	dept.ID = id
+	return ctx.JSON(http.StatusCreated, Response{
+		Status:     "success",
+		StatusCode: http.StatusCreated,
+		Payload:    dept,
+	})
+}
+
+// GetDepartment godoc
+// @Summary Get department by ID
+// @Description Retrieve details of a specific department. No authentication required.
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Param id path string true "Department ID" format(uuid)
+// @Success 200 {object} Response{payload=database.Department}
+// @Failure 400 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /departments/{id} [get]
+func (c *DepartmentController) GetDepartment(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	dept, err := c.service.GetDepartmentByID(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    dept,
+	})
+}
+
+// UpdateDepartment godoc
+// @Summary Update a department
+// @Description Update the name and description of a department. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Department ID" format(uuid)
+// @Param department body database.Department true "Department data"
+// @Success 200 {object} Response{payload=database.Department}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /departments/{id} [put]
+func (c *DepartmentController) UpdateDepartment(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	var dept database.Department
+	if err := ctx.Bind(&dept); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if dept.Name == "" {
+		return customerr.InvalidField("name", customerr.FieldRequired, "name is required")
+	}
+
+	if err := c.service.UpdateDepartment(ctx.Request().Context(), id, &dept); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    dept,
+	})
+}
+
+// DeleteDepartment godoc
+// @Summary Delete a department
+// @Description Delete a department. Its members stay on record without a department, and each release is recorded in their audit history. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Department ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /departments/{id} [delete]
+func (c *DepartmentController) DeleteDepartment(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := c.service.DeleteDepartment(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx)); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// ListDepartments godoc
+// @Summary List all departments
+// @Description Retrieve all departments ordered by name. No authentication required.
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Success 200 {object} Response{payload=[]database.Department}
+// @Failure 500 {object} customerr.Problem
+// @Router /departments [get]
+func (c *DepartmentController) ListDepartments(ctx echo.Context) error {
+	depts, err := c.service.ListDepartments(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    depts,
+	})
+}
+
+// GetDepartmentCosts godoc
+// @Summary Report salary costs per department
+// @Description Sum the annual salaries of each department's current employees per currency. With `currency`, each department's salaries and their grand total are also given in that currency, converted at the exchange rates in effect on `as_of` (default today); every currency the employees are paid in then needs a rate. Employees without a department are left out. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags departments
+// @Produce json
+// @Param currency query string false "ISO 4217 currency to convert the costs to"
+// @Param as_of query string false "Day whose exchange rates are used" format(date)
+// @Security BearerAuth
+// @Success 200 {object} Response{payload=database.DepartmentCostReport}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /departments/costs [get]
+func (c *DepartmentController) GetDepartmentCosts(ctx echo.Context) error {
+	conv, err := parseConversion(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, err := c.service.CostReport(ctx.Request().Context(), conv)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    report,
+	})
+}
+
+// ListDepartmentEmployees godoc
+// @Summary List a department's members
+// @Description Retrieve a page of the department's employees. Accepts the same paging, filter and sort parameters as `GET /employees`, and projects salaries the same way. Authentication is optional.
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Param id path string true "Department ID" format(uuid)
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param offset query int false "Rows to skip; ignored when cursor is set" default(0)
+// @Param cursor query string false "Keyset cursor from a previous page's next_cursor"
+// @Param sort_by query string false "Sort column" Enums(id, name, position, salary, hired_date, created_at, updated_at) default(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(asc)
+// @Security BearerAuth
+// @Success 200 {object} Response{payload=database.EmployeeViewPage}
+// @Failure 400 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /departments/{id}/employees [get]
+func (c *DepartmentController) ListDepartmentEmployees(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseEmployeeFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	page, err := c.service.ListMembers(ctx.Request().Context(), id, filter, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    page,
+	})
+}
+
+// MoveEmployees godoc
+// @Summary Move employees into a department
+// @Description Assign the listed employees to this department, moving them out of any department they were in. The response lists the employees that were moved; unknown IDs are skipped. Each move is recorded in the employee's audit history as `set_department`. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Department ID" format(uuid)
+// @Param assignment body database.DepartmentAssignment true "Employees to move"
+// @Success 200 {object} Response{payload=database.DepartmentAssignment}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /departments/{id}/employees [post]
+func (c *DepartmentController) MoveEmployees(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	var assignment database.DepartmentAssignment
+	if err := ctx.Bind(&assignment); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if len(assignment.EmployeeIDs) == 0 {
+		return customerr.InvalidField("employee_ids", customerr.FieldRequired, "employee_ids must not be empty")
+	}
+
+	moved, err := c.service.MoveEmployees(ctx.Request().Context(), id, assignment.EmployeeIDs, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    database.DepartmentAssignment{EmployeeIDs: moved},
+	})
+}
+
+// RemoveEmployee godoc
+// @Summary Remove an employee from a department
+// @Description Leave the employee without a department, recorded in their audit history. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags departments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Department ID" format(uuid)
+// @Param employee_id path string true "Employee ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /departments/{id}/employees/{employee_id} [delete]
+func (c *DepartmentController) RemoveEmployee(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+	employeeID, err := parseUUIDParam(ctx, "employee_id")
+	if err != nil {
+		return err
+	}
+
+	if err := c.service.RemoveEmployee(ctx.Request().Context(), id, employeeID, middleware.ClaimsFrom(ctx)); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
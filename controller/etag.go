@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+// Conditional request headers, which echo has no constants for
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// employeeETag is the strong entity tag for an employee at version. Every
+// projection of the record shares it, so responses also vary on Authorization.
+func employeeETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// setEmployeeETag sets the ETag of emp on the response
+func setEmployeeETag(ctx echo.Context, emp *database.Employee) {
+	ctx.Response().Header().Set(headerETag, employeeETag(emp.Version))
+	ctx.Response().Header().Add(echo.HeaderVary, echo.HeaderAuthorization)
+}
+
+// entityTag is one member of an If-Match or If-None-Match list
+type entityTag struct {
+	weak    bool
+	version int64
+	//ok is false for tags that were not issued by this API and never match
+	ok bool
+}
+
+// parseEntityTags splits a comma-separated entity tag list; wildcard reports a bare *
+func parseEntityTags(header string) (tags []entityTag, wildcard bool) {
+	for _, raw := range strings.Split(header, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "*" {
+			return nil, true
+		}
+
+		var tag entityTag
+		if rest, found := strings.CutPrefix(raw, "W/"); found {
+			tag.weak = true
+			raw = rest
+		}
+		if len(raw) >= 2 && raw[0] == '"' && raw[len(raw)-1] == '"' {
+			version, err := strconv.ParseInt(raw[1:len(raw)-1], 10, 64)
+			tag.version, tag.ok = version, err == nil
+		}
+		tags = append(tags, tag)
+	}
+	return tags, false
+}
+
+// parseIfMatch turns the If-Match header into a write precondition; nil when
+// the header is absent. If-Match uses the strong comparison, so weak tags and
+// tags this API did not issue never match.
+func parseIfMatch(ctx echo.Context) *database.Precondition {
+	header := ctx.Request().Header.Get(headerIfMatch)
+	if header == "" {
+		return nil
+	}
+
+	tags, wildcard := parseEntityTags(header)
+	pre := &database.Precondition{Any: wildcard}
+	for _, tag := range tags {
+		if tag.ok && !tag.weak {
+			pre.Versions = append(pre.Versions, tag.version)
+		}
+	}
+	return pre
+}
+
+// notModified reports whether If-None-Match already names version, using
+// the weak comparison as RFC 9110 requires for GET
+func notModified(ctx echo.Context, version int64) bool {
+	header := ctx.Request().Header.Get(headerIfNoneMatch)
+	if header == "" {
+		return false
+	}
+
+	tags, wildcard := parseEntityTags(header)
+	if wildcard {
+		return true
+	}
+	for _, tag := range tags {
+		if tag.ok && tag.version == version {
+			return true
+		}
+	}
+	return false
+}
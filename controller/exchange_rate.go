@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/importer"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/service"
+)
+
+// ExchangeRateController handles HTTP requests for the stored exchange rates
+type ExchangeRateController struct {
+	service service.ExchangeRateService
+}
+
+func NewExchangeRateController(service service.ExchangeRateService) *ExchangeRateController {
+	return &ExchangeRateController{service: service}
+}
+
+// ListExchangeRates godoc
+// @Summary List exchange rates
+// @Description List the stored rates, each the number of units of `currency` one USD buys from `effective_date` on. With `as_of`, only the rate of each currency in effect on that day is listed. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags exchange-rates
+// @Produce json
+// @Security BearerAuth
+// @Param currency query string false "Only rates of this ISO 4217 currency"
+// @Param as_of query string false "Only the rates in effect on this date" format(date)
+// @Success 200 {object} Response{payload=[]database.ExchangeRate}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /exchange-rates [get]
+func (c *ExchangeRateController) ListExchangeRates(ctx echo.Context) error {
+	currency, err := parseCurrencyQuery(ctx, "currency")
+	if err != nil {
+		return err
+	}
+	asOf, err := parseDateParam(ctx, "as_of")
+	if err != nil {
+		return err
+	}
+
+	rates, err := c.service.ListRates(ctx.Request().Context(), database.ExchangeRateFilter{Currency: currency, AsOf: asOf})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    rates,
+	})
+}
+
+// SetExchangeRate godoc
+// @Summary Store an exchange rate
+// @Description Store how many units of `currency` one USD buys from `effective_date` until the currency's next rate. A rate for a currency and day that is already stored is replaced. USD itself always has a rate of 1 and cannot be stored. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `admin` role.
+// @Tags exchange-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rate body database.ExchangeRate true "Exchange rate"
+// @Success 200 {object} Response{payload=database.ExchangeRate}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /exchange-rates [post]
+func (c *ExchangeRateController) SetExchangeRate(ctx echo.Context) error {
+	var rate database.ExchangeRate
+	if err := ctx.Bind(&rate); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&rate); err != nil {
+		return err
+	}
+
+	if err := c.service.SetRate(ctx.Request().Context(), &rate, middleware.ClaimsFrom(ctx)); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    rate,
+	})
+}
+
+// ImportExchangeRates godoc
+// @Summary Import exchange rates from a CSV or XLSX sheet
+// @Description Upload a `.csv` or `.xlsx` file as the multipart field `file` with the columns `currency`, `rate` and `effective_date` (YYYY-MM-DD); other columns are ignored. Each row is stored as by `POST /exchange-rates`, all in one transaction: when any row is invalid nothing is stored and the invalid rows are listed in `errors` by row number. With `dry_run=true` the rows are only checked. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `admin` role.
+// @Tags exchange-rates
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Sheet to import"
+// @Param dry_run query bool false "Report what would be imported without keeping it" default(false)
+// @Success 200 {object} Response{payload=database.ImportReport}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 415 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /exchange-rates/import [post]
+func (c *ExchangeRateController) ImportExchangeRates(ctx echo.Context) error {
+	dryRun, err := parseBoolQuery(ctx, "dry_run")
+	if err != nil {
+		return err
+	}
+
+	ctx.Request().Body = http.MaxBytesReader(ctx.Response(), ctx.Request().Body, MaxImportBytes)
+	parts, err := ctx.Request().MultipartReader()
+	if err != nil {
+		return customerr.New(customerr.CodeUnsupportedMedia, "the sheet must be uploaded as multipart/form-data")
+	}
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			return customerr.InvalidField("file", customerr.FieldRequired, "file is required")
+		}
+		if err != nil {
+			return customerr.InvalidBody(err)
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		format, err := importer.FormatOf(part.FileName())
+		if err != nil {
+			return err
+		}
+		report, err := importer.NewRateImporter(c.service, ctx.Echo().Validator).Import(ctx.Request().Context(), part, format, dryRun, middleware.ClaimsFrom(ctx))
+		if err != nil {
+			return err
+		}
+		return ctx.JSON(http.StatusOK, Response{
+			Status:     "success",
+			StatusCode: http.StatusOK,
+			Payload:    report,
+		})
+	}
+}
+
+// DeleteExchangeRate godoc
+// @Summary Delete an exchange rate
+// @Description Delete a stored rate; the currency's previous rate stays in effect in its place. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `admin` role.
+// @Tags exchange-rates
+// @Security BearerAuth
+// @Param id path string true "Exchange rate ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /exchange-rates/{id} [delete]
+func (c *ExchangeRateController) DeleteExchangeRate(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := c.service.DeleteRate(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx)); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
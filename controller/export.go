@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/exporter"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+)
+
+// ExportEmployees godoc
+// @Summary Export employees
+// @Description Download every employee matching the `GET /employees` filters as CSV (the default), JSON Lines or an XLSX workbook, in the requested order. Rows are streamed from the database as they are written. Authentication is optional: salaries are shown or left empty exactly as in the listing, and salary filters or sorting by salary need the `hr` or `admin` role. CSV and XLSX columns use the names `POST /employees/import` reads. With `currency`, the salaries shown are converted to it at the exchange rates in effect on `as_of` (default today), and every currency employees are paid in needs a rate.
+// @Tags employees
+// @Produce text/csv
+// @Produce application/jsonl
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format" Enums(csv, jsonl, xlsx) default(csv)
+// @Param position query string false "Exact position match"
+// @Param department_id query string false "Department ID" format(uuid)
+// @Param min_salary query number false "Minimum salary"
+// @Param max_salary query number false "Maximum salary"
+// @Param salary_currency query string false "Only employees paid in this ISO 4217 currency"
+// @Param hired_from query string false "Earliest hired date" format(date)
+// @Param hired_to query string false "Latest hired date" format(date)
+// @Param sort_by query string false "Sort column" Enums(id, name, position, salary, hired_date, created_at, updated_at) default(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(asc)
+// @Param currency query string false "ISO 4217 currency to convert salaries to"
+// @Param as_of query string false "Day whose exchange rates are used" format(date)
+// @Security BearerAuth
+// @Success 200 {file} file
+// @Failure 400 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/export [get]
+func (c *EmployeeController) ExportEmployees(ctx echo.Context) error {
+	format, err := exporter.ParseFormat(ctx.QueryParam("format"))
+	if err != nil {
+		return err
+	}
+	filter, err := parseEmployeeFilter(ctx)
+	if err != nil {
+		return err
+	}
+	//an export covers every matching row, so paging parameters are ignored
+	filter.Limit, filter.Offset, filter.Cursor = 0, 0, nil
+	conv, err := parseConversion(ctx)
+	if err != nil {
+		return err
+	}
+
+	res := ctx.Response()
+	writer, err := exporter.NewWriter(res, format)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+	res.Header().Set(echo.HeaderContentType, format.ContentType())
+	filename := fmt.Sprintf("employees-%s.%s", time.Now().UTC().Format(time.DateOnly), format)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	err = c.service.ExportEmployees(ctx.Request().Context(), filter, conv, middleware.ClaimsFrom(ctx), writer.Write)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		return nil
+	}
+
+	//the writer buffers, so errors before the first rows, such as a
+	//forbidden filter, are still answered with a problem
+	if !res.Committed {
+		res.Header().Del(echo.HeaderContentDisposition)
+		return err
+	}
+	//rows already sent cannot be taken back: drop the connection so the
+	//client sees the export was cut short rather than a complete file
+	log.Printf("Error: export aborted after %d bytes [%s]: %v", res.Size, customerr.RequestID(ctx), err)
+	panic(http.ErrAbortHandler)
+}
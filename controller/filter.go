@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// sortableColumns are the employee columns accepted by sort_by
+var sortableColumns = map[string]bool{
+	"id":         true,
+	"name":       true,
+	"position":   true,
+	"salary":     true,
+	"hired_date": true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// parseEmployeeFilter reads the listing query parameters into an EmployeeFilter
+func parseEmployeeFilter(ctx echo.Context) (database.EmployeeFilter, error) {
+	filter := database.EmployeeFilter{
+		Limit:    defaultPageLimit,
+		Position: ctx.QueryParam("position"),
+		SortBy:   "created_at",
+	}
+
+	var err error
+	if filter.DepartmentID, err = parseUUIDQuery(ctx, "department_id"); err != nil {
+		return filter, err
+	}
+
+	if v := ctx.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return filter, customerr.InvalidField("limit", customerr.FieldOutOfRange, "limit must be between 1 and 100")
+		}
+		filter.Limit = limit
+	}
+
+	if v := ctx.QueryParam("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return filter, customerr.InvalidField("offset", customerr.FieldOutOfRange, "offset must be a non-negative integer")
+		}
+		filter.Offset = offset
+	}
+
+	if v := ctx.QueryParam("sort_by"); v != "" {
+		if !sortableColumns[v] {
+			return filter, customerr.InvalidField("sort_by", customerr.FieldInvalidValue, "invalid sort_by column")
+		}
+		filter.SortBy = v
+	}
+
+	switch ctx.QueryParam("order") {
+	case "", "asc":
+	case "desc":
+		filter.SortDesc = true
+	default:
+		return filter, customerr.InvalidField("order", customerr.FieldInvalidValue, "order must be asc or desc")
+	}
+
+	if filter.MinSalary, err = parseAmountParam(ctx, "min_salary"); err != nil {
+		return filter, err
+	}
+	if filter.MaxSalary, err = parseAmountParam(ctx, "max_salary"); err != nil {
+		return filter, err
+	}
+	if filter.Currency, err = parseCurrencyQuery(ctx, "salary_currency"); err != nil {
+		return filter, err
+	}
+	if filter.HiredFrom, err = parseDateParam(ctx, "hired_from"); err != nil {
+		return filter, err
+	}
+	if filter.HiredTo, err = parseDateParam(ctx, "hired_to"); err != nil {
+		return filter, err
+	}
+
+	if v := ctx.QueryParam("cursor"); v != "" {
+		cursor, err := database.DecodeEmployeeCursor(v)
+		if err != nil {
+			return filter, customerr.InvalidField("cursor", customerr.FieldInvalid, "invalid cursor")
+		}
+		//a cursor only makes sense for the ordering it was issued under
+		if cursor.SortBy != filter.SortBy {
+			return filter, customerr.InvalidField("cursor", customerr.FieldInvalidValue, "cursor does not match sort_by")
+		}
+		filter.Cursor = cursor
+		filter.Offset = 0
+	}
+
+	return filter, nil
+}
+
+// parsePaging reads limit and offset, defaulting to the first page of defaultPageLimit rows
+func parsePaging(ctx echo.Context) (limit, offset int, err error) {
+	limit = defaultPageLimit
+	if v := ctx.QueryParam("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return 0, 0, customerr.InvalidField("limit", customerr.FieldOutOfRange, "limit must be between 1 and 100")
+		}
+	}
+
+	if v := ctx.QueryParam("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, customerr.InvalidField("offset", customerr.FieldOutOfRange, "offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+func parseAmountParam(ctx echo.Context, name string) (*money.Amount, error) {
+	v := ctx.QueryParam(name)
+	if v == "" {
+		return nil, nil
+	}
+	amount, err := money.Parse(v)
+	if err != nil {
+		return nil, customerr.InvalidField(name, customerr.FieldInvalid, name+" must be a number")
+	}
+	return &amount, nil
+}
+
+// parseCurrencyQuery reads an optional ISO 4217 code, empty when it is absent
+func parseCurrencyQuery(ctx echo.Context, name string) (string, error) {
+	v := ctx.QueryParam(name)
+	if v != "" && !money.ValidCurrency(v) {
+		return "", customerr.InvalidField(name, customerr.FieldInvalidValue, name+" must be an ISO 4217 currency code")
+	}
+	return v, nil
+}
+
+// parseConversion reads currency= and as_of=, asking for amounts converted to
+// currency at the exchange rates in effect on as_of; nil when currency is absent
+func parseConversion(ctx echo.Context) (*database.Conversion, error) {
+	currency, err := parseCurrencyQuery(ctx, "currency")
+	if err != nil {
+		return nil, err
+	}
+	asOf, err := parseDateParam(ctx, "as_of")
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		if asOf != nil {
+			return nil, customerr.InvalidField("currency", customerr.FieldRequired, "currency is required with as_of")
+		}
+		return nil, nil
+	}
+	conv := &database.Conversion{Currency: currency}
+	if asOf != nil {
+		conv.AsOf = *asOf
+	}
+	return conv, nil
+}
+
+func parseDateParam(ctx echo.Context, name string) (*time.Time, error) {
+	v := ctx.QueryParam(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return nil, customerr.InvalidField(name, customerr.FieldInvalid, name+" must be a date in YYYY-MM-DD format")
+	}
+	return &t, nil
+}
+
+// parseBoolQuery reads the query parameter name as a boolean, false when absent
+func parseBoolQuery(ctx echo.Context, name string) (bool, error) {
+	v := ctx.QueryParam(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, customerr.InvalidField(name, customerr.FieldInvalid, name+" must be true or false")
+	}
+	return b, nil
+}
+
+// parseUUIDQuery reads the query parameter name as a UUID, nil when absent
+func parseUUIDQuery(ctx echo.Context, name string) (*uuid.UUID, error) {
+	v := ctx.QueryParam(name)
+	if v == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return nil, customerr.InvalidField(name, customerr.FieldInvalidUUID, name+" must be a UUID")
+	}
+	return &id, nil
+}
+
+// parseUUIDParam reads the path parameter name as a UUID
+func parseUUIDParam(ctx echo.Context, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(ctx.Param(name))
+	if err != nil {
+		return uuid.Nil, customerr.InvalidField(name, customerr.FieldInvalidUUID, name+" must be a UUID")
+	}
+	return id, nil
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+)
+
+// SetManager godoc
+// @Summary Set an employee's manager
+// @Description Set the employee's manager, or clear it with `{"manager_id": null}`. Assignments that would create a reporting cycle are rejected. Send the employee's `ETag` as `If-Match` to make the change conditional on it being unchanged. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags hierarchy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param If-Match header string false "ETag the change is conditional on"
+// @Param assignment body database.ManagerAssignment true "New manager"
+// @Success 204
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 412 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/manager [put]
+func (c *EmployeeController) SetManager(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	var assignment database.ManagerAssignment
+	if err := ctx.Bind(&assignment); err != nil {
+		return customerr.InvalidBody(err)
+	}
+
+	err = c.service.SetManager(ctx.Request().Context(), id, assignment.ManagerID, parseIfMatch(ctx), middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetManagerChain godoc
+// @Summary Get an employee's chain of managers
+// @Description Retrieve the employee's manager, that manager's manager and so on up to the top of the hierarchy, nearest first. No authentication required.
+// @Tags hierarchy
+// @Accept json
+// @Produce json
+// @Param id path string true "Employee ID" format(uuid)
+// @Success 200 {object} Response{payload=[]database.OrgNode}
+// @Failure 400 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/managers [get]
+func (c *EmployeeController) GetManagerChain(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	chain, err := c.service.GetManagerChain(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    chain,
+	})
+}
+
+// GetReports godoc
+// @Summary Get an employee's reports as a tree
+// @Description Retrieve the employee with their direct and indirect reports nested under `reports`. No authentication required.
+// @Tags hierarchy
+// @Accept json
+// @Produce json
+// @Param id path string true "Employee ID" format(uuid)
+// @Success 200 {object} Response{payload=database.OrgNode}
+// @Failure 400 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/reports [get]
+func (c *EmployeeController) GetReports(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	tree, err := c.service.GetReportTree(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    tree,
+	})
+}
+
+// GetOrgChart godoc
+// @Summary Get the organisation chart
+// @Description Retrieve every employee arranged by reporting line. Each top-level entry has no manager; reports are nested under `reports`. No authentication required.
+// @Tags hierarchy
+// @Accept json
+// @Produce json
+// @Success 200 {object} Response{payload=[]database.OrgNode}
+// @Failure 500 {object} customerr.Problem
+// @Router /org-chart [get]
+func (c *EmployeeController) GetOrgChart(ctx echo.Context) error {
+	roots, err := c.service.GetOrgChart(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    roots,
+	})
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/importer"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+)
+
+// MaxImportBytes caps the size of an uploaded import request
+const MaxImportBytes = 32 << 20
+
+// ImportEmployees godoc
+// @Summary Import employees from a CSV or XLSX sheet
+// @Description Upload a `.csv` or `.xlsx` file as the multipart field `file`. The first row names the columns: `name`, `position` and `salary` are required, `currency`, `hired_date` (YYYY-MM-DD), `department_id` and `manager_id` are optional and other columns are ignored. Each row is validated as for `POST /employees`. In `best_effort` mode (the default) the valid rows are created in one transaction and the others listed in `errors` by row number; in `all_or_nothing` mode nothing is created unless every row is valid. With `dry_run=true` the rows are checked against the database but nothing is kept. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags employees
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Sheet to import"
+// @Param mode query string false "all_or_nothing or best_effort" default(best_effort)
+// @Param dry_run query bool false "Report what would be imported without keeping it" default(false)
+// @Success 200 {object} Response{payload=database.ImportReport}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 415 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/import [post]
+func (c *EmployeeController) ImportEmployees(ctx echo.Context) error {
+	dryRun, err := parseBoolQuery(ctx, "dry_run")
+	if err != nil {
+		return err
+	}
+	opts := importer.Options{
+		Mode:   database.BulkMode(ctx.QueryParam("mode")),
+		DryRun: dryRun,
+		Actor:  middleware.ClaimsFrom(ctx),
+	}
+
+	//read the upload part by part so a CSV is imported as it arrives
+	ctx.Request().Body = http.MaxBytesReader(ctx.Response(), ctx.Request().Body, MaxImportBytes)
+	parts, err := ctx.Request().MultipartReader()
+	if err != nil {
+		return customerr.New(customerr.CodeUnsupportedMedia, "the sheet must be uploaded as multipart/form-data")
+	}
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			return customerr.InvalidField("file", customerr.FieldRequired, "file is required")
+		}
+		if err != nil {
+			return customerr.InvalidBody(err)
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		format, err := importer.FormatOf(part.FileName())
+		if err != nil {
+			return err
+		}
+		report, err := importer.New(c.service, ctx.Echo().Validator).Import(ctx.Request().Context(), part, format, opts)
+		if err != nil {
+			return err
+		}
+		return ctx.JSON(http.StatusOK, Response{
+			Status:     "success",
+			StatusCode: http.StatusOK,
+			Payload:    report,
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/service"
+)
+
+// maxCalendarDays caps the date range of a team calendar
+const maxCalendarDays = 366
+
+// LeaveController handles HTTP requests for leave types, balances and requests
+type LeaveController struct {
+	service service.LeaveService
+}
+
+func NewLeaveController(service service.LeaveService) *LeaveController {
+	return &LeaveController{service: service}
+}
+
+// ListLeaveTypes godoc
+// @Summary List leave types
+// @Description List the kinds of leave employees can request and how many days of each accrue per year worked. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response{payload=[]database.LeaveType}
+// @Failure 401 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /leave-types [get]
+func (c *LeaveController) ListLeaveTypes(ctx echo.Context) error {
+	leaveTypes, err := c.service.ListLeaveTypes(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    leaveTypes,
+	})
+}
+
+// CreateLeaveType godoc
+// @Summary Create a leave type
+// @Description Add a kind of leave accruing `days_per_year` days for every year an employee has worked since their hired date. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param leave_type body database.LeaveType true "Leave type"
+// @Success 201 {object} Response{payload=database.LeaveType}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /leave-types [post]
+func (c *LeaveController) CreateLeaveType(ctx echo.Context) error {
+	var leaveType database.LeaveType
+	if err := ctx.Bind(&leaveType); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&leaveType); err != nil {
+		return err
+	}
+
+	if err := c.service.CreateLeaveType(ctx.Request().Context(), &leaveType, middleware.ClaimsFrom(ctx)); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, Response{
+		Status:     "success",
+		StatusCode: http.StatusCreated,
+		Payload:    leaveType,
+	})
+}
+
+// GetLeaveBalances godoc
+// @Summary Get an employee's leave balances
+// @Description Return the days of each leave type the employee has accrued since their hired date, used on approved leave, and holds on pending requests. `available` is what may still be requested. Only the employee, their manager and HR may read them. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Success 200 {object} Response{payload=[]database.LeaveBalance}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/leave-balances [get]
+func (c *LeaveController) GetLeaveBalances(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	balances, err := c.service.GetBalances(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    balances,
+	})
+}
+
+// ListEmployeeLeaveRequests godoc
+// @Summary List an employee's leave requests
+// @Description List the employee's leave requests, latest first. Only the employee, their manager and HR may read them. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param status query string false "Only requests with this status" Enums(pending, approved, rejected, cancelled)
+// @Success 200 {object} Response{payload=[]database.LeaveRequest}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/leave-requests [get]
+func (c *LeaveController) ListEmployeeLeaveRequests(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+	var status *string
+	switch v := ctx.QueryParam("status"); v {
+	case "":
+	case database.LeaveStatusPending, database.LeaveStatusApproved, database.LeaveStatusRejected, database.LeaveStatusCancelled:
+		status = &v
+	default:
+		return customerr.InvalidField("status", customerr.FieldInvalidValue, "status must be pending, approved, rejected or cancelled")
+	}
+
+	requests, err := c.service.ListEmployeeRequests(ctx.Request().Context(), id, status, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    requests,
+	})
+}
+
+// ApplyLeave godoc
+// @Summary Apply for leave
+// @Description Request leave from `start_date` to `end_date` inclusive. The request counts the working days, Monday to Friday, in the range and is refused with `409` when it overlaps another pending or approved request or needs more days than are available; unpaid leave types need no balance. It stays pending until the employee's manager or HR approves or rejects it. `employee_id` defaults to the caller's own employee record; only HR may apply for someone else. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param application body database.LeaveApplication true "Leave application"
+// @Success 201 {object} Response{payload=database.LeaveRequest}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /leave-requests [post]
+func (c *LeaveController) ApplyLeave(ctx echo.Context) error {
+	var app database.LeaveApplication
+	if err := ctx.Bind(&app); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&app); err != nil {
+		return err
+	}
+	if app.EndDate.Before(app.StartDate) {
+		return customerr.InvalidField("end_date", customerr.FieldOutOfRange, "end_date must not be before start_date")
+	}
+
+	req, err := c.service.Apply(ctx.Request().Context(), &app, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, Response{
+		Status:     "success",
+		StatusCode: http.StatusCreated,
+		Payload:    req,
+	})
+}
+
+// GetLeaveRequest godoc
+// @Summary Get a leave request
+// @Description Retrieve a leave request. Only the employee, their manager and HR may read it. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Leave request ID" format(uuid)
+// @Success 200 {object} Response{payload=database.LeaveRequest}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /leave-requests/{id} [get]
+func (c *LeaveController) GetLeaveRequest(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	req, err := c.service.GetRequest(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    req,
+	})
+}
+
+// ApproveLeave godoc
+// @Summary Approve a leave request
+// @Description Approve a pending request, drawing its days from the employee's balance unless the leave type is unpaid. Only the employee's manager, for their direct reports, or HR may approve, and never their own leave. Fails with `409` when the request is no longer pending or the days are no longer available. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Leave request ID" format(uuid)
+// @Param decision body database.LeaveDecision false "Note for the employee"
+// @Success 200 {object} Response{payload=database.LeaveRequest}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /leave-requests/{id}/approve [post]
+func (c *LeaveController) ApproveLeave(ctx echo.Context) error {
+	return c.decideLeave(ctx, c.service.Approve)
+}
+
+// RejectLeave godoc
+// @Summary Reject a leave request
+// @Description Reject a pending request, releasing the days it held. Only the employee's manager, for their direct reports, or HR may reject, and never their own leave. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Leave request ID" format(uuid)
+// @Param decision body database.LeaveDecision false "Reason for the rejection"
+// @Success 200 {object} Response{payload=database.LeaveRequest}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /leave-requests/{id}/reject [post]
+func (c *LeaveController) RejectLeave(ctx echo.Context) error {
+	return c.decideLeave(ctx, c.service.Reject)
+}
+
+// CancelLeave godoc
+// @Summary Cancel a leave request
+// @Description Withdraw a pending or approved request; cancelling approved leave returns its days to the balance. The employee may cancel until the leave starts, their manager and HR at any time. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags leave
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Leave request ID" format(uuid)
+// @Param decision body database.LeaveDecision false "Reason for the cancellation"
+// @Success 200 {object} Response{payload=database.LeaveRequest}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /leave-requests/{id}/cancel [post]
+func (c *LeaveController) CancelLeave(ctx echo.Context) error {
+	return c.decideLeave(ctx, c.service.Cancel)
+}
+
+// GetLeaveCalendar godoc
+// @Summary Get a team leave calendar
+// @Description List approved leave overlapping `from` to `to` for the members of a department or the direct reports of a manager, earliest first; `include_pending` adds requests awaiting a decision. Without `department_id` or `manager_id` the caller's own direct reports are shown. Reasons are left out. HR may view any team; a `manager` only sees their own direct reports, so a `department_id` is narrowed to them and another manager's `manager_id` is refused. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr`, `admin` or `manager` role.
+// @Tags leave
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "First day" format(date)
+// @Param to query string true "Last day (at most 366 days after from)" format(date)
+// @Param department_id query string false "Department whose members are shown" format(uuid)
+// @Param manager_id query string false "Manager whose direct reports are shown" format(uuid)
+// @Param include_pending query bool false "Include pending requests" default(false)
+// @Success 200 {object} Response{payload=[]database.LeaveCalendarEntry}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /leave-calendar [get]
+func (c *LeaveController) GetLeaveCalendar(ctx echo.Context) error {
+	filter, err := parseLeaveCalendarFilter(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := c.service.TeamCalendar(ctx.Request().Context(), filter, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    entries,
+	})
+}
+
+// decideLeave runs one of the approve, reject and cancel transitions on the request in the path
+func (c *LeaveController) decideLeave(ctx echo.Context, decide func(ctx context.Context, id uuid.UUID, decision *database.LeaveDecision, caller *auth.Claims) (*database.LeaveRequest, error)) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+	var decision database.LeaveDecision
+	if err := ctx.Bind(&decision); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&decision); err != nil {
+		return err
+	}
+
+	req, err := decide(ctx.Request().Context(), id, &decision, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    req,
+	})
+}
+
+// parseLeaveCalendarFilter reads the date range and team of a calendar query
+func parseLeaveCalendarFilter(ctx echo.Context) (database.LeaveCalendarFilter, error) {
+	var filter database.LeaveCalendarFilter
+	from, err := parseDateParam(ctx, "from")
+	if err != nil {
+		return filter, err
+	}
+	if from == nil {
+		return filter, customerr.InvalidField("from", customerr.FieldRequired, "from is required")
+	}
+	to, err := parseDateParam(ctx, "to")
+	if err != nil {
+		return filter, err
+	}
+	if to == nil {
+		return filter, customerr.InvalidField("to", customerr.FieldRequired, "to is required")
+	}
+	if to.Before(*from) || to.After(from.AddDate(0, 0, maxCalendarDays)) {
+		return filter, customerr.InvalidField("to", customerr.FieldOutOfRange, "to must be between from and 366 days after it")
+	}
+	filter.From, filter.To = *from, *to
+
+	if filter.DepartmentID, err = parseUUIDQuery(ctx, "department_id"); err != nil {
+		return filter, err
+	}
+	if filter.ManagerID, err = parseUUIDQuery(ctx, "manager_id"); err != nil {
+		return filter, err
+	}
+	if filter.DepartmentID != nil && filter.ManagerID != nil {
+		return filter, customerr.InvalidField("manager_id", customerr.FieldInvalid, "department_id and manager_id cannot be combined")
+	}
+	if filter.IncludePending, err = parseBoolQuery(ctx, "include_pending"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+)
+
+// MIMEMergePatchJSON is the media type of an RFC 7396 JSON merge patch
+const MIMEMergePatchJSON = "application/merge-patch+json"
+
+// patchableFields are the employee fields a merge patch may set
+var patchableFields = map[string]bool{
+	"name":       true,
+	"position":   true,
+	"salary":     true,
+	"currency":   true,
+	"hired_date": true,
+}
+
+// readOnlyFields are employee fields a merge patch may not touch; department,
+// manager and termination changes go through their own endpoints
+var readOnlyFields = map[string]bool{
+	"id":               true,
+	"department_id":    true,
+	"manager_id":       true,
+	"termination_date": true,
+	"created_at":       true,
+	"updated_at":       true,
+	"deleted_at":       true,
+	"version":          true,
+}
+
+// fieldEndpoints names the endpoint that changes a read-only field instead
+var fieldEndpoints = map[string]string{
+	"department_id":    "POST /departments/{id}/employees",
+	"manager_id":       "PUT /employees/{id}/manager",
+	"termination_date": "PUT /employees/{id}/termination",
+}
+
+// decodeEmployeePatch reads an RFC 7396 merge patch for an employee. Every
+// patchable field is required on the record, so removing one with null is
+// rejected along with unknown and read-only members.
+func decodeEmployeePatch(ctx echo.Context) (*database.EmployeePatch, error) {
+	mediaType, _, err := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || (mediaType != MIMEMergePatchJSON && mediaType != echo.MIMEApplicationJSON) {
+		return nil, customerr.New(customerr.CodeUnsupportedMedia, "Content-Type must be "+MIMEMergePatchJSON)
+	}
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, customerr.InvalidBody(err)
+	}
+	return parseEmployeePatch(body)
+}
+
+// parseEmployeePatch decodes the merge patch in body, which is also how each
+// item of a bulk patch is read
+func parseEmployeePatch(body []byte) (*database.EmployeePatch, error) {
+	//a merge patch that is not an object would replace the whole record
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil || members == nil {
+		return nil, customerr.InvalidBody(err)
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fields []customerr.FieldError
+	for _, name := range names {
+		switch {
+		case readOnlyFields[name]:
+			message := name + " cannot be changed with PATCH"
+			if endpoint := fieldEndpoints[name]; endpoint != "" {
+				message += "; use " + endpoint
+			}
+			fields = append(fields, customerr.FieldError{Field: name, Code: customerr.FieldReadOnly, Message: message})
+		case !patchableFields[name]:
+			fields = append(fields, customerr.FieldError{Field: name, Code: customerr.FieldUnknown, Message: name + " is not an employee field"})
+		case bytes.Equal(bytes.TrimSpace(members[name]), []byte("null")):
+			fields = append(fields, customerr.FieldError{Field: name, Code: customerr.FieldRequired, Message: name + " cannot be removed"})
+		}
+	}
+	if len(fields) > 0 {
+		return nil, customerr.InvalidFields(fields...)
+	}
+
+	var patch database.EmployeePatch
+	if err := json.Unmarshal(body, &patch); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		var timeErr *time.ParseError
+		switch {
+		case errors.As(err, &typeErr):
+			return nil, customerr.InvalidField(typeErr.Field, customerr.FieldInvalid, typeErr.Field+" has the wrong type")
+		case errors.As(err, &timeErr):
+			//hired_date is the only time field
+			return nil, customerr.InvalidField("hired_date", customerr.FieldInvalid, "hired_date must be an RFC 3339 timestamp")
+		case errors.Is(err, money.ErrSyntax), errors.Is(err, money.ErrPrecision), errors.Is(err, money.ErrOutOfRange):
+			//and salary the only amount
+			return nil, customerr.InvalidField("salary", customerr.FieldInvalid, "salary must be a number with at most 4 decimal places")
+		}
+		return nil, customerr.InvalidBody(err)
+	}
+	return &patch, nil
+}
@@ -0,0 +1,404 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/service"
+)
+
+// PayrollController handles HTTP requests for payroll rules, runs and payslips
+type PayrollController struct {
+	service service.PayrollService
+}
+
+func NewPayrollController(service service.PayrollService) *PayrollController {
+	return &PayrollController{service: service}
+}
+
+// ListPayrollRules godoc
+// @Summary List payroll rules
+// @Description List the allowances and deductions applied to every payslip. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags payroll
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response{payload=[]database.PayrollRule}
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-rules [get]
+func (c *PayrollController) ListPayrollRules(ctx echo.Context) error {
+	rules, err := c.service.ListRules(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    rules,
+	})
+}
+
+// CreatePayrollRule godoc
+// @Summary Create a payroll rule
+// @Description Add an allowance or deduction to every payslip computed from now on: a `fixed` amount per month in a `currency`, pro rata for the days paid and only paid to employees paid in that currency, or a `percent` of gross pay. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule body database.PayrollRule true "Payroll rule"
+// @Success 201 {object} Response{payload=database.PayrollRule}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-rules [post]
+func (c *PayrollController) CreatePayrollRule(ctx echo.Context) error {
+	var rule database.PayrollRule
+	if err := ctx.Bind(&rule); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&rule); err != nil {
+		return err
+	}
+
+	if err := c.service.CreateRule(ctx.Request().Context(), &rule, middleware.ClaimsFrom(ctx)); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, Response{
+		Status:     "success",
+		StatusCode: http.StatusCreated,
+		Payload:    rule,
+	})
+}
+
+// UpdatePayrollRule godoc
+// @Summary Update a payroll rule
+// @Description Replace a rule. Runs already computed keep the amounts they were computed with until a draft is recomputed. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payroll rule ID" format(uuid)
+// @Param rule body database.PayrollRule true "Payroll rule"
+// @Success 200 {object} Response{payload=database.PayrollRule}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-rules/{id} [put]
+func (c *PayrollController) UpdatePayrollRule(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+	var rule database.PayrollRule
+	if err := ctx.Bind(&rule); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&rule); err != nil {
+		return err
+	}
+
+	if err := c.service.UpdateRule(ctx.Request().Context(), id, &rule, middleware.ClaimsFrom(ctx)); err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    rule,
+	})
+}
+
+// DeletePayrollRule godoc
+// @Summary Delete a payroll rule
+// @Description Stop applying a rule. Payslips already computed keep its lines. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags payroll
+// @Security BearerAuth
+// @Param id path string true "Payroll rule ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-rules/{id} [delete]
+func (c *PayrollController) DeletePayrollRule(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := c.service.DeleteRule(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx)); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// CreatePayrollRun godoc
+// @Summary Run payroll for a month
+// @Description Compute a draft payslip for everyone employed during the month: gross pay pro rata for the hired date, deletion and approved unpaid leave, plus the current allowances and deductions. There is one run per month, and the month may not be later than the current one. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param run body database.PayrollRunRequest true "Month to pay, as YYYY-MM"
+// @Success 201 {object} Response{payload=database.PayrollRun}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-runs [post]
+func (c *PayrollController) CreatePayrollRun(ctx echo.Context) error {
+	var body database.PayrollRunRequest
+	if err := ctx.Bind(&body); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&body); err != nil {
+		return err
+	}
+	period, err := time.Parse("2006-01", body.Period)
+	if err != nil || period.Year() < 1900 {
+		return customerr.InvalidField("period", customerr.FieldInvalid, "period must be a month in YYYY-MM format")
+	}
+
+	run, err := c.service.CreateRun(ctx.Request().Context(), period, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, Response{
+		Status:     "success",
+		StatusCode: http.StatusCreated,
+		Payload:    run,
+	})
+}
+
+// ListPayrollRuns godoc
+// @Summary List payroll runs
+// @Description List every payroll run, latest month first. With `currency`, each run's totals are also summed in that currency as `converted`, at the exchange rates in effect on `as_of` or by default on the last day of the run's month. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags payroll
+// @Produce json
+// @Param currency query string false "ISO 4217 currency to convert the totals to"
+// @Param as_of query string false "Day whose exchange rates are used" format(date)
+// @Security BearerAuth
+// @Success 200 {object} Response{payload=[]database.PayrollRun}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-runs [get]
+func (c *PayrollController) ListPayrollRuns(ctx echo.Context) error {
+	conv, err := parseConversion(ctx)
+	if err != nil {
+		return err
+	}
+	runs, err := c.service.ListRuns(ctx.Request().Context(), conv)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    runs,
+	})
+}
+
+// GetPayrollRun godoc
+// @Summary Get a payroll run
+// @Description Return a payroll run with its totals per currency. With `currency`, the totals are also summed in that currency as `converted`, at the exchange rates in effect on `as_of` or by default on the last day of the run's month. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags payroll
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payroll run ID" format(uuid)
+// @Param currency query string false "ISO 4217 currency to convert the totals to"
+// @Param as_of query string false "Day whose exchange rates are used" format(date)
+// @Success 200 {object} Response{payload=database.PayrollRun}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-runs/{id} [get]
+func (c *PayrollController) GetPayrollRun(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	conv, err := parseConversion(ctx)
+	if err != nil {
+		return err
+	}
+	run, err := c.service.GetRun(ctx.Request().Context(), id, conv)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    run,
+	})
+}
+
+// ListPayrollRunPayslips godoc
+// @Summary List a run's payslips
+// @Description List the payslips of a payroll run by employee name. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags payroll
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payroll run ID" format(uuid)
+// @Success 200 {object} Response{payload=[]database.Payslip}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-runs/{id}/payslips [get]
+func (c *PayrollController) ListPayrollRunPayslips(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	slips, err := c.service.ListRunPayslips(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    slips,
+	})
+}
+
+// RecomputePayrollRun godoc
+// @Summary Recompute a draft payroll run
+// @Description Replace the payslips of a draft run with ones computed from the current employees, salaries, leave and rules. Approved and finalized runs cannot be recomputed. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags payroll
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payroll run ID" format(uuid)
+// @Success 200 {object} Response{payload=database.PayrollRun}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-runs/{id}/recompute [post]
+func (c *PayrollController) RecomputePayrollRun(ctx echo.Context) error {
+	return c.transitionRun(ctx, func(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error) {
+		return c.service.Recompute(ctx, id, actor)
+	})
+}
+
+// ApprovePayrollRun godoc
+// @Summary Approve a payroll run
+// @Description Approve a draft run, freezing its payslips. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `admin` role.
+// @Tags payroll
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payroll run ID" format(uuid)
+// @Success 200 {object} Response{payload=database.PayrollRun}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-runs/{id}/approve [post]
+func (c *PayrollController) ApprovePayrollRun(ctx echo.Context) error {
+	return c.transitionRun(ctx, func(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error) {
+		return c.service.Approve(ctx, id, actor)
+	})
+}
+
+// FinalizePayrollRun godoc
+// @Summary Finalize a payroll run
+// @Description Finalize an approved run once it has been paid. A finalized run and its payslips never change, and employees can then see their payslips. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `admin` role.
+// @Tags payroll
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payroll run ID" format(uuid)
+// @Success 200 {object} Response{payload=database.PayrollRun}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 409 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /payroll-runs/{id}/finalize [post]
+func (c *PayrollController) FinalizePayrollRun(ctx echo.Context) error {
+	return c.transitionRun(ctx, func(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error) {
+		return c.service.Finalize(ctx, id, actor)
+	})
+}
+
+// ListEmployeePayslips godoc
+// @Summary List an employee's payslips
+// @Description List the employee's payslips of finalized runs, latest month first. Only the employee and HR may read them. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags payroll
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Success 200 {object} Response{payload=[]database.Payslip}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/payslips [get]
+func (c *PayrollController) ListEmployeePayslips(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	slips, err := c.service.EmployeePayslips(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    slips,
+	})
+}
+
+// transitionRun runs one of the recompute, approve and finalize steps on the run in the path
+func (c *PayrollController) transitionRun(ctx echo.Context, step func(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error)) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	run, err := step(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    run,
+	})
+}
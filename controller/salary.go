@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+)
+
+// ChangeSalary godoc
+// @Summary Record a salary change
+// @Description Append a salary change with its effective date and reason to the employee's history, approved by the caller. `effective_date` may be in the past, for back pay, but not in the future or before the employee was hired. `currency` defaults to the employee's. The change becomes the employee's current salary and currency unless a record with a later effective date exists. Changing `salary` with `PUT` or `PATCH` also records a change, effective the day it is made. `If-Match` makes the change conditional as for `PUT`. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags salary
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param If-Match header string false "ETag the change is conditional on"
+// @Param change body database.SalaryChangeRequest true "Salary change"
+// @Success 201 {object} Response{payload=database.SalaryRecord}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 412 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/salary-history [post]
+func (c *EmployeeController) ChangeSalary(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	var req database.SalaryChangeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&req); err != nil {
+		return err
+	}
+
+	rec, err := c.service.ChangeSalary(ctx.Request().Context(), id, &req, parseIfMatch(ctx), middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, Response{
+		Status:     "success",
+		StatusCode: http.StatusCreated,
+		Payload:    rec,
+	})
+}
+
+// ListSalaryHistory godoc
+// @Summary Get an employee's compensation timeline
+// @Description List every salary the employee has had, latest effective date first. Only callers who may see the employee's salary, as for `GET /employees/{id}`, may read it. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags salary
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Success 200 {object} Response{payload=[]database.SalaryRecord}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/salary-history [get]
+func (c *EmployeeController) ListSalaryHistory(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	records, err := c.service.ListSalaryHistory(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    records,
+	})
+}
+
+// GetSalaryAsOf godoc
+// @Summary Get an employee's salary on a date
+// @Description Return the salary record in effect on `as_of` (today by default), for back-pay calculations. Fails with `404` for dates before the employee's history starts. Only callers who may see the employee's salary may read it. Requires an `Authorization` header with a Bearer token (`Bearer <token>`).
+// @Tags salary
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param as_of query string false "Date to look up" format(date)
+// @Success 200 {object} Response{payload=database.SalaryRecord}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/salary [get]
+func (c *EmployeeController) GetSalaryAsOf(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+	asOf, err := parseDateParam(ctx, "as_of")
+	if err != nil {
+		return err
+	}
+	if asOf == nil {
+		today := time.Now()
+		asOf = &today
+	}
+
+	rec, err := c.service.GetSalaryAsOf(ctx.Request().Context(), id, *asOf, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    rec,
+	})
+}
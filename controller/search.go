@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+)
+
+// maxSearchQueryLength caps q in characters
+const maxSearchQueryLength = 200
+
+// SearchEmployees godoc
+// @Summary Search employees
+// @Description Find employees whose name or position matches `q`, best match first. Whole words are matched with full-text search, which understands `"quoted phrases"`, `or` and `-excluded` words; misspelt or partial words are matched by trigram similarity. Name matches rank above position matches. `highlights` repeats the name and position as HTML-escaped text with the matched words wrapped in `<mark>` tags. Authentication is optional: salaries are projected as for `GET /employees/{id}`.
+// @Tags employees
+// @Produce json
+// @Param q query string true "Search terms (at most 200 characters)"
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param offset query int false "Results to skip" default(0)
+// @Security BearerAuth
+// @Success 200 {object} Response{payload=database.EmployeeSearchViewPage}
+// @Failure 400 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/search [get]
+func (c *EmployeeController) SearchEmployees(ctx echo.Context) error {
+	query := strings.TrimSpace(ctx.QueryParam("q"))
+	if query == "" {
+		return customerr.InvalidField("q", customerr.FieldRequired, "q is required")
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return customerr.InvalidField("q", customerr.FieldOutOfRange, "q must be at most 200 characters")
+	}
+	limit, offset, err := parsePaging(ctx)
+	if err != nil {
+		return err
+	}
+
+	page, err := c.service.SearchEmployees(ctx.Request().Context(), database.EmployeeSearch{
+		Query:  query,
+		Limit:  limit,
+		Offset: offset,
+	}, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    page,
+	})
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+)
+
+// ListDeletedEmployees godoc
+// @Summary List soft-deleted employees
+// @Description Retrieve a page of deleted employees that can still be restored, most recently deleted first. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `admin` role.
+// @Tags employees
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param offset query int false "Rows to skip" default(0)
+// @Success 200 {object} Response{payload=database.EmployeePage}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/deleted [get]
+func (c *EmployeeController) ListDeletedEmployees(ctx echo.Context) error {
+	limit, offset, err := parsePaging(ctx)
+	if err != nil {
+		return err
+	}
+
+	page, err := c.service.ListDeletedEmployees(ctx.Request().Context(), limit, offset)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    page,
+	})
+}
+
+// RestoreEmployee godoc
+// @Summary Restore a soft-deleted employee
+// @Description Undo the deletion of an employee that has not been purged yet. Former direct reports are not reattached, and the manager is dropped if it has been deleted since. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `admin` role.
+// @Tags employees
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Success 200 {object} Response{payload=database.Employee}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/restore [post]
+func (c *EmployeeController) RestoreEmployee(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	emp, err := c.service.RestoreEmployee(ctx.Request().Context(), id, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    emp,
+	})
+}
+
+// PurgeEmployees godoc
+// @Summary Purge old soft-deleted employees
+// @Description Permanently remove employees deleted longer ago than the retention period (`SOFT_DELETE_RETENTION`, 30 days by default). Their audit history is kept. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `admin` role.
+// @Tags employees
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response{payload=database.PurgeResult}
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/purge [post]
+func (c *EmployeeController) PurgeEmployees(ctx echo.Context) error {
+	result, err := c.service.PurgeDeletedEmployees(ctx.Request().Context(), c.cfg.SoftDeleteRetention, middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    result,
+	})
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+)
+
+// SetTermination godoc
+// @Summary Set an employee's termination date
+// @Description Set the last day the employee is employed, or clear it with `{"termination_date": null}`. Payroll pays up to this date; deleting an employee does not end their pay, and a deleted employee without a termination date is left out of payroll runs. The date may lie in the future but not before the hired date. With `If-Match`, the change only happens while the employee is still at that `ETag`; otherwise it fails with `412`. Requires an `Authorization` header with a Bearer token (`Bearer <token>`) for the `hr` or `admin` role.
+// @Tags employees
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Employee ID" format(uuid)
+// @Param If-Match header string false "ETag the change is conditional on"
+// @Param termination body database.TerminationAssignment true "Termination date"
+// @Success 200 {object} Response{payload=database.Employee}
+// @Header 200 {string} ETag "New version of the employee"
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 412 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /employees/{id}/termination [put]
+func (c *EmployeeController) SetTermination(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	var assignment database.TerminationAssignment
+	if err := ctx.Bind(&assignment); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if err := ctx.Validate(&assignment); err != nil {
+		return err
+	}
+
+	updated, err := c.service.SetTermination(ctx.Request().Context(), id, assignment.TerminationDate, parseIfMatch(ctx), middleware.ClaimsFrom(ctx))
+	if err != nil {
+		return err
+	}
+
+	setEmployeeETag(ctx, updated)
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    updated,
+	})
+}
@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"net/http"
+	"net/mail"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/service"
+)
+
+// minPasswordLength is the shortest password accepted for new accounts
+const minPasswordLength = 8
+
+// UserController handles login and account management
+type UserController struct {
+	service service.UserService
+}
+
+func NewUserController(service service.UserService) *UserController {
+	return &UserController{service: service}
+}
+
+// Login godoc
+// @Summary Log in
+// @Description Authenticate a user and return a short-lived access token, for use in the Authorization header as `Bearer <token>`, and a single-use refresh token for `POST /refresh`. The access token carries the user's ID and role.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body database.Credentials true "User credentials"
+// @Success 200 {object} Response{payload=database.TokenResponse}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /login [post]
+func (c *UserController) Login(ctx echo.Context) error {
+	var credentials database.Credentials
+	if err := ctx.Bind(&credentials); err != nil {
+		return customerr.InvalidBody(err)
+	}
+
+	tokens, err := c.service.Login(ctx.Request().Context(), credentials.Email, credentials.Password)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    tokens,
+	})
+}
+
+// Refresh godoc
+// @Summary Refresh the access token
+// @Description Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once; presenting a used one ends the whole session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body database.RefreshRequest true "Refresh token"
+// @Success 200 {object} Response{payload=database.TokenResponse}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /refresh [post]
+func (c *UserController) Refresh(ctx echo.Context) error {
+	var req database.RefreshRequest
+	if err := ctx.Bind(&req); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if req.RefreshToken == "" {
+		return customerr.InvalidField("refresh_token", customerr.FieldRequired, "refresh_token is required")
+	}
+
+	tokens, err := c.service.Refresh(ctx.Request().Context(), req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    tokens,
+	})
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revoke the access token used for this request and, when given, the session of the refresh token.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token body database.RefreshRequest false "Refresh token to revoke"
+// @Success 204
+// @Failure 401 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /logout [post]
+func (c *UserController) Logout(ctx echo.Context) error {
+	var req database.RefreshRequest
+	//the body is optional; an empty or missing one only revokes the access token
+	ctx.Bind(&req)
+
+	if err := c.service.Logout(ctx.Request().Context(), middleware.ClaimsFrom(ctx), req.RefreshToken); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// CreateUser godoc
+// @Summary Create a user account
+// @Description Create an account with a role of admin, hr, manager or viewer. Managers should be linked to their own employee record through `employee_id`. Requires the admin role.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user body database.NewUser true "Account data"
+// @Success 201 {object} Response{payload=database.User}
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /users [post]
+func (c *UserController) CreateUser(ctx echo.Context) error {
+	var newUser database.NewUser
+	if err := ctx.Bind(&newUser); err != nil {
+		return customerr.InvalidBody(err)
+	}
+	if _, err := mail.ParseAddress(newUser.Email); err != nil {
+		return customerr.InvalidField("email", customerr.FieldInvalid, "email must be a valid email address")
+	}
+	if len(newUser.Password) < minPasswordLength {
+		return customerr.InvalidField("password", customerr.FieldOutOfRange, "password must be at least 8 characters")
+	}
+
+	user, err := c.service.CreateUser(ctx.Request().Context(), &newUser)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, Response{
+		Status:     "success",
+		StatusCode: http.StatusCreated,
+		Payload:    user,
+	})
+}
+
+// ListUsers godoc
+// @Summary List user accounts
+// @Description Retrieve all accounts ordered by email. Requires the admin role.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response{payload=[]database.User}
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /users [get]
+func (c *UserController) ListUsers(ctx echo.Context) error {
+	users, err := c.service.ListUsers(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, Response{
+		Status:     "success",
+		StatusCode: http.StatusOK,
+		Payload:    users,
+	})
+}
+
+// UpdateUserRole godoc
+// @Summary Change a user's role
+// @Description Set the account's role and linked employee record. Takes effect on the user's next login. Requires the admin role.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID" format(uuid)
+// @Param role body database.RoleUpdate true "New role"
+// @Success 204
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /users/{id}/role [put]
+func (c *UserController) UpdateUserRole(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	var update database.RoleUpdate
+	if err := ctx.Bind(&update); err != nil {
+		return customerr.InvalidBody(err)
+	}
+
+	err = c.service.UpdateRole(ctx.Request().Context(), id, &update)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// DeleteUser godoc
+// @Summary Delete a user account
+// @Description Delete an account. Requires the admin role.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID" format(uuid)
+// @Success 204
+// @Failure 400 {object} customerr.Problem
+// @Failure 401 {object} customerr.Problem
+// @Failure 403 {object} customerr.Problem
+// @Failure 404 {object} customerr.Problem
+// @Failure 500 {object} customerr.Problem
+// @Router /users/{id} [delete]
+func (c *UserController) DeleteUser(ctx echo.Context) error {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := c.service.DeleteUser(ctx.Request().Context(), id); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
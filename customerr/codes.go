@@ -0,0 +1,106 @@
+package customerr
+
+import "net/http"
+
+// Code is a stable, machine-readable error identifier; clients should branch
+// on it rather than on the human-readable title or detail
+type Code string
+
+// The error code catalog. Codes are part of the API: add new ones freely but
+// never rename or reuse an existing one.
+const (
+	CodeInvalidRequest     Code = "invalid_request"
+	CodeInvalidBody        Code = "invalid_body"
+	CodeValidationFailed   Code = "validation_failed"
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodeInvalidToken       Code = "invalid_token"
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodeForbidden          Code = "forbidden"
+	CodeNotFound           Code = "not_found"
+	CodeMethodNotAllowed   Code = "method_not_allowed"
+	CodeConflict           Code = "conflict"
+	CodeUnsupportedMedia   Code = "unsupported_media_type"
+	CodePreconditionFailed Code = "precondition_failed"
+	CodeInternal           Code = "internal_error"
+	CodeUnavailable        Code = "service_unavailable"
+)
+
+// Field error codes used in Problem.Errors
+const (
+	FieldRequired     = "required"
+	FieldInvalid      = "invalid"
+	FieldInvalidUUID  = "invalid_uuid"
+	FieldOutOfRange   = "out_of_range"
+	FieldInvalidValue = "invalid_value"
+	FieldReadOnly     = "read_only"
+	FieldUnknown      = "unknown"
+)
+
+// typeBase prefixes every code to form the problem type URI
+const typeBase = "urn:employee-management:problem:"
+
+type codeInfo struct {
+	status int
+	title  string
+	kind   error
+}
+
+var catalog = map[Code]codeInfo{
+	CodeInvalidRequest:     {http.StatusBadRequest, "Invalid request", ErrValidation},
+	CodeInvalidBody:        {http.StatusBadRequest, "Malformed request body", ErrValidation},
+	CodeValidationFailed:   {http.StatusBadRequest, "Validation failed", ErrValidation},
+	CodeUnauthenticated:    {http.StatusUnauthorized, "Authentication required", ErrUnauthorized},
+	CodeInvalidToken:       {http.StatusUnauthorized, "Invalid token", ErrUnauthorized},
+	CodeInvalidCredentials: {http.StatusUnauthorized, "Invalid credentials", ErrUnauthorized},
+	CodeForbidden:          {http.StatusForbidden, "Forbidden", ErrForbidden},
+	CodeNotFound:           {http.StatusNotFound, "Resource not found", ErrNotFound},
+	CodeMethodNotAllowed:   {http.StatusMethodNotAllowed, "Method not allowed", nil},
+	CodeConflict:           {http.StatusConflict, "Conflict", ErrConflict},
+	CodeUnsupportedMedia:   {http.StatusUnsupportedMediaType, "Unsupported media type", nil},
+	CodePreconditionFailed: {http.StatusPreconditionFailed, "Precondition failed", ErrPreconditionFailed},
+	CodeInternal:           {http.StatusInternalServerError, "Internal server error", nil},
+	CodeUnavailable:        {http.StatusServiceUnavailable, "Service unavailable", ErrUnavailable},
+}
+
+// kindCodes is the default code for each error kind
+var kindCodes = []struct {
+	kind error
+	code Code
+}{
+	{ErrNotFound, CodeNotFound},
+	{ErrConflict, CodeConflict},
+	{ErrValidation, CodeValidationFailed},
+	{ErrUnauthorized, CodeUnauthenticated},
+	{ErrForbidden, CodeForbidden},
+	{ErrUnavailable, CodeUnavailable},
+	{ErrPreconditionFailed, CodePreconditionFailed},
+}
+
+// codeForStatus picks the catalog code for a bare HTTP status, such as one from
+// an echo.HTTPError raised by the router
+func codeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMedia
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+	if status < http.StatusInternalServerError {
+		return CodeInvalidRequest
+	}
+	return CodeInternal
+}
@@ -1,13 +1,70 @@
 package customerr
 
 import (
+	"net/http"
+
 	"github.com/labstack/echo/v4"
 )
 
-type ErrorResponse struct {
-	Error string `json:"error" example:"Invalid request body"`
+// MIMEProblemJSON is the media type of error responses (RFC 7807)
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem is the body of every error response, following RFC 7807
+type Problem struct {
+	Type      string       `json:"type" example:"urn:employee-management:problem:validation_failed"`
+	Title     string       `json:"title" example:"Validation failed"`
+	Status    int          `json:"status" example:"400"`
+	Detail    string       `json:"detail,omitempty" example:"limit must be between 1 and 100"`
+	Instance  string       `json:"instance,omitempty" example:"/employees"`
+	Code      Code         `json:"code" example:"validation_failed"`
+	RequestID string       `json:"request_id,omitempty" example:"b0c5fb2a-6a8e-4c0c-9a43-5f1d2f0e7d11"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes one invalid field, query or path parameter
+type FieldError struct {
+	Field   string `json:"field" example:"limit"`
+	Code    string `json:"code" example:"out_of_range"`
+	Message string `json:"message" example:"limit must be between 1 and 100"`
+}
+
+// NewProblem builds the Problem describing err for the current request
+func NewProblem(ctx echo.Context, err error) Problem {
+	problem := ProblemOf(err)
+	problem.Instance = ctx.Request().URL.Path
+	problem.RequestID = RequestID(ctx)
+	return problem
+}
+
+// ProblemOf builds the Problem describing err outside of any one response,
+// such as for a failed item of a bulk request
+func ProblemOf(err error) Problem {
+	status, code, detail, fields := classify(err)
+	return Problem{
+		Type:   typeBase + string(code),
+		Title:  catalog[code].title,
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Errors: fields,
+	}
+}
+
+// Respond writes err as a problem+json response
+func Respond(ctx echo.Context, err error) error {
+	problem := NewProblem(ctx, err)
+	if ctx.Request().Method == http.MethodHead {
+		return ctx.NoContent(problem.Status)
+	}
+	ctx.Response().Header().Set(echo.HeaderContentType, MIMEProblemJSON)
+	return ctx.JSON(problem.Status, problem)
 }
 
-func NewError(ctx echo.Context, status int, message string) error {
-	return ctx.JSON(status, ErrorResponse{Error: message})
+// RequestID returns the ID assigned to the request by the RequestID middleware,
+// or the one the client sent
+func RequestID(ctx echo.Context) string {
+	if id := ctx.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	return ctx.Request().Header.Get(echo.HeaderXRequestID)
 }
@@ -0,0 +1,157 @@
+package customerr
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Error kinds shared by the repo, service and controller layers; match them with errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnavailable  = errors.New("service unavailable")
+	//ErrPreconditionFailed means the record changed since the caller last read it
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// Error is a domain error of one Kind. Code, Message and Fields are safe to
+// show to clients; Cause, when set, is the underlying failure and is only logged.
+type Error struct {
+	Kind    error
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Cause   error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Cause != nil {
+		errs = append(errs, e.Cause)
+	}
+	return errs
+}
+
+// New returns an error with a catalog code; its kind follows from the code
+func New(code Code, message string) error {
+	return &Error{Kind: catalog[code].kind, Code: code, Message: message}
+}
+
+func NotFound(message string) error {
+	return New(CodeNotFound, message)
+}
+
+func Conflict(message string) error {
+	return New(CodeConflict, message)
+}
+
+func Validation(message string) error {
+	return New(CodeValidationFailed, message)
+}
+
+// Wrap attaches a kind and a client-safe message to cause
+func Wrap(kind error, message string, cause error) error {
+	return &Error{Kind: kind, Message: message, Cause: cause}
+}
+
+// InvalidField reports a single invalid field, query or path parameter
+func InvalidField(field, code, message string) error {
+	return &Error{
+		Kind:    ErrValidation,
+		Code:    CodeValidationFailed,
+		Message: message,
+		Fields:  []FieldError{{Field: field, Code: code, Message: message}},
+	}
+}
+
+// InvalidFields reports every invalid field of a request at once
+func InvalidFields(fields ...FieldError) error {
+	return &Error{
+		Kind:    ErrValidation,
+		Code:    CodeValidationFailed,
+		Message: "one or more fields are invalid",
+		Fields:  fields,
+	}
+}
+
+// InvalidBody reports a request body that could not be decoded
+func InvalidBody(cause error) error {
+	return &Error{Kind: ErrValidation, Code: CodeInvalidBody, Message: "request body could not be decoded", Cause: cause}
+}
+
+// classify works out the status, code, client-safe detail and field errors for err
+func classify(err error) (int, Code, string, []FieldError) {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		code := codeForStatus(httpErr.Code)
+		detail := catalog[code].title
+		if m, ok := httpErr.Message.(string); ok && httpErr.Code < http.StatusInternalServerError {
+			detail = m
+		}
+		return httpErr.Code, code, detail, nil
+	}
+
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		code := domainErr.Code
+		if code == "" {
+			code = kindCode(domainErr.Kind)
+		}
+		if code != CodeInternal {
+			return catalog[code].status, code, domainErr.Message, domainErr.Fields
+		}
+	}
+
+	code := kindCode(err)
+	return catalog[code].status, code, catalog[code].title, nil
+}
+
+func kindCode(err error) Code {
+	if err != nil {
+		for _, k := range kindCodes {
+			if errors.Is(err, k.kind) {
+				return k.code
+			}
+		}
+	}
+	return CodeInternal
+}
+
+// StatusOf returns the HTTP status for err, 500 when it has no known kind
+func StatusOf(err error) int {
+	status, _, _, _ := classify(err)
+	return status
+}
+
+// HTTPErrorHandler is the Echo error handler: it answers errors returned by
+// handlers with a Problem and logs server-side failures in full
+func HTTPErrorHandler(err error, ctx echo.Context) {
+	if ctx.Response().Committed {
+		return
+	}
+
+	if StatusOf(err) >= http.StatusInternalServerError {
+		log.Printf("Error: %s %s [%s]: %v", ctx.Request().Method, ctx.Request().URL.Path, RequestID(ctx), err)
+	}
+
+	if err := Respond(ctx, err); err != nil {
+		log.Printf("Failed to write error response: %v", err)
+	}
+}
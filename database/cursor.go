@@ -0,0 +1,36 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// EmployeeCursor marks the last row of a page for keyset pagination.
+// Value holds the sort column of that row in its text form.
+type EmployeeCursor struct {
+	SortBy string    `json:"s"`
+	Value  string    `json:"v"`
+	ID     uuid.UUID `json:"id"`
+}
+
+// Encode returns the opaque form handed to clients as next_cursor
+func (c EmployeeCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeEmployeeCursor parses a cursor previously produced by Encode
+func DecodeEmployeeCursor(s string) (*EmployeeCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var c EmployeeCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -1,19 +1,328 @@
 package database
 
 import (
+	"cmp"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/money"
 )
 
 type Employee struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Position  string    `json:"position"`
-	Salary    float64   `json:"salary"`
-	HiredDate time.Time `json:"hired_date"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Position string    `json:"position"`
+	//Salary is the annual salary in Currency, an ISO 4217 code
+	Salary    money.Amount `json:"salary" swaggertype:"number" example:"60000"`
+	Currency  string       `json:"currency" example:"USD"`
+	HiredDate time.Time    `json:"hired_date"`
+	//DepartmentID is nil while the employee is not assigned to a department
+	DepartmentID *uuid.UUID `json:"department_id"`
+	//ManagerID is nil for employees at the top of the hierarchy
+	ManagerID *uuid.UUID `json:"manager_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	//TerminationDate is the last day employed, nil while the employee has not
+	//left; unlike deletion it is what ends pay
+	TerminationDate *time.Time `json:"termination_date" example:"2025-03-31T00:00:00Z"`
+	//DeletedAt is only set on soft-deleted employees, which are hidden from every other read
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	//Version is bumped by every change to the record and is served as its ETag
+	Version int64 `json:"version" example:"3"`
+}
+
+// EmployeeCreateRequest is the body of POST /employees; IDs and timestamps are
+// assigned by the server. HiredDate defaults to today when omitted, and
+// Currency to USD.
+type EmployeeCreateRequest struct {
+	Name         string       `json:"name" validate:"notblank,max=200" example:"Jane Doe"`
+	Position     string       `json:"position" validate:"notblank,max=100,position" example:"Software Engineer"`
+	Salary       money.Amount `json:"salary" validate:"gt=0,lte=100000000" swaggertype:"number" example:"60000"`
+	Currency     string       `json:"currency" validate:"omitempty,currency" example:"USD"`
+	HiredDate    time.Time    `json:"hired_date" validate:"omitempty,notfuture,mindate=1900-01-01" example:"2024-06-01T00:00:00Z"`
+	DepartmentID *uuid.UUID   `json:"department_id"`
+	ManagerID    *uuid.UUID   `json:"manager_id"`
+}
+
+func (r *EmployeeCreateRequest) Employee() Employee {
+	return Employee{
+		Name:         r.Name,
+		Position:     r.Position,
+		Salary:       r.Salary,
+		Currency:     cmp.Or(r.Currency, money.DefaultCurrency),
+		HiredDate:    r.HiredDate,
+		DepartmentID: r.DepartmentID,
+		ManagerID:    r.ManagerID,
+	}
+}
+
+// EmployeeUpdateRequest is the body of PUT /employees/{id}. HiredDate and
+// Currency keep their current values when omitted; department and manager
+// have their own endpoints.
+type EmployeeUpdateRequest struct {
+	Name      string       `json:"name" validate:"notblank,max=200" example:"Jane Doe"`
+	Position  string       `json:"position" validate:"notblank,max=100,position" example:"Senior Software Engineer"`
+	Salary    money.Amount `json:"salary" validate:"gt=0,lte=100000000" swaggertype:"number" example:"75000"`
+	Currency  string       `json:"currency" validate:"omitempty,currency" example:"USD"`
+	HiredDate time.Time    `json:"hired_date" validate:"omitempty,notfuture,mindate=1900-01-01" example:"2024-06-01T00:00:00Z"`
+}
+
+func (r *EmployeeUpdateRequest) Employee() Employee {
+	return Employee{
+		Name:      r.Name,
+		Position:  r.Position,
+		Salary:    r.Salary,
+		Currency:  r.Currency,
+		HiredDate: r.HiredDate,
+	}
+}
+
+// EmployeePatch holds the fields of a PATCH /employees/{id} merge patch; nil
+// fields are left unchanged
+type EmployeePatch struct {
+	Name      *string       `json:"name" validate:"omitnil,notblank,max=200" example:"Jane Doe"`
+	Position  *string       `json:"position" validate:"omitnil,notblank,max=100,position" example:"Staff Engineer"`
+	Salary    *money.Amount `json:"salary" validate:"omitnil,gt=0,lte=100000000" swaggertype:"number" example:"90000"`
+	Currency  *string       `json:"currency" validate:"omitnil,currency" example:"EUR"`
+	HiredDate *time.Time    `json:"hired_date" validate:"omitnil,notfuture,mindate=1900-01-01" example:"2024-06-01T00:00:00Z"`
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p *EmployeePatch) IsEmpty() bool {
+	return p.Name == nil && p.Position == nil && p.Salary == nil && p.Currency == nil && p.HiredDate == nil
+}
+
+// SalaryRecord is one entry of an employee's compensation timeline: the
+// salary paid from EffectiveDate until the next record takes effect
+type SalaryRecord struct {
+	ID            uuid.UUID    `json:"id"`
+	EmployeeID    uuid.UUID    `json:"employee_id"`
+	Salary        money.Amount `json:"salary" swaggertype:"number" example:"75000"`
+	Currency      string       `json:"currency" example:"USD"`
+	EffectiveDate time.Time    `json:"effective_date" example:"2024-06-01T00:00:00Z"`
+	Reason        string       `json:"reason" example:"Annual review"`
+	//ApprovedBy is the user who recorded the change, nil when it was not made through the API
+	ApprovedBy      *uuid.UUID `json:"approved_by"`
+	ApprovedByEmail string     `json:"approved_by_email" example:"hr@example.com"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// SalaryChangeRequest is the body of POST /employees/{id}/salary-history. The
+// change may be backdated, but not to before the employee was hired.
+// Currency defaults to the employee's current one.
+type SalaryChangeRequest struct {
+	Salary        money.Amount `json:"salary" validate:"gt=0,lte=100000000" swaggertype:"number" example:"82000"`
+	Currency      string       `json:"currency" validate:"omitempty,currency" example:"USD"`
+	EffectiveDate time.Time    `json:"effective_date" validate:"required,notfuture,mindate=1900-01-01" example:"2024-06-01T00:00:00Z"`
+	Reason        string       `json:"reason" validate:"max=500" example:"Promotion to senior engineer"`
+}
+
+// Precondition is a parsed If-Match header: a write goes ahead only while the
+// record is at one of Versions. Any is set by If-Match: *, and a nil
+// Precondition (no header) matches every version.
+type Precondition struct {
+	Any      bool
+	Versions []int64
+}
+
+// Matches reports whether a record at version satisfies the precondition
+func (p *Precondition) Matches(version int64) bool {
+	if p == nil || p.Any {
+		return true
+	}
+	for _, v := range p.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// BulkMode decides what a bulk request does when one of its items fails
+type BulkMode string
+
+const (
+	//BulkAllOrNothing rolls back every item when any one fails; it is the default
+	BulkAllOrNothing BulkMode = "all_or_nothing"
+	//BulkBestEffort keeps the items that succeed and reports the rest
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+// Statuses of one item of a bulk request
+const (
+	BulkStatusCreated = "created"
+	BulkStatusUpdated = "updated"
+	BulkStatusDeleted = "deleted"
+	BulkStatusFailed  = "failed"
+	//BulkStatusRolledBack items succeeded but were undone because another item failed
+	BulkStatusRolledBack = "rolled_back"
+	//BulkStatusSkipped items were not attempted because another item failed
+	BulkStatusSkipped = "skipped"
+)
+
+// BulkCreateRequest is the body of POST /employees/bulk
+type BulkCreateRequest struct {
+	Mode      BulkMode                `json:"mode" enums:"all_or_nothing,best_effort" example:"all_or_nothing"`
+	Employees []EmployeeCreateRequest `json:"employees"`
+}
+
+// BulkPatchItem is one employee of PATCH /employees/bulk. Patch is an RFC 7396
+// merge patch as for PATCH /employees/{id}; Version, when set, makes the item
+// conditional as If-Match does.
+type BulkPatchItem struct {
+	ID      uuid.UUID       `json:"id"`
+	Version *int64          `json:"version" example:"3"`
+	Patch   json.RawMessage `json:"patch" swaggertype:"object"`
+}
+
+// BulkPatchRequest is the body of PATCH /employees/bulk
+type BulkPatchRequest struct {
+	Mode      BulkMode        `json:"mode" enums:"all_or_nothing,best_effort" example:"best_effort"`
+	Employees []BulkPatchItem `json:"employees"`
+}
+
+// BulkDeleteItem is one employee of DELETE /employees/bulk; Version works as for BulkPatchItem
+type BulkDeleteItem struct {
+	ID      uuid.UUID `json:"id"`
+	Version *int64    `json:"version" example:"3"`
+}
+
+// BulkDeleteRequest is the body of DELETE /employees/bulk
+type BulkDeleteRequest struct {
+	Mode      BulkMode         `json:"mode" enums:"all_or_nothing,best_effort" example:"all_or_nothing"`
+	Employees []BulkDeleteItem `json:"employees"`
+}
+
+// BulkPatch is a decoded BulkPatchItem
+type BulkPatch struct {
+	ID    uuid.UUID
+	Patch *EmployeePatch
+	Pre   *Precondition
+}
+
+// BulkDelete is a decoded BulkDeleteItem
+type BulkDelete struct {
+	ID  uuid.UUID
+	Pre *Precondition
+}
+
+// BulkItemResult is the outcome of one item, at Index in the request
+type BulkItemResult struct {
+	Index  int        `json:"index" example:"0"`
+	Status string     `json:"status" enums:"created,updated,deleted,failed,rolled_back,skipped" example:"created"`
+	ID     *uuid.UUID `json:"id,omitempty"`
+	//Employee is the record as stored after a create or update that was kept
+	Employee *Employee `json:"employee,omitempty"`
+	//Error explains a failed item; it is built from Err
+	Error *customerr.Problem `json:"error,omitempty"`
+	Err   error              `json:"-"`
+}
+
+// BulkResult reports every item of a bulk request in request order
+type BulkResult struct {
+	Mode      BulkMode         `json:"mode" example:"all_or_nothing"`
+	Succeeded int              `json:"succeeded" example:"12"`
+	Failed    int              `json:"failed" example:"0"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// Tally counts the succeeded and failed items of r
+func (r *BulkResult) Tally() {
+	r.Succeeded, r.Failed = 0, 0
+	for _, item := range r.Results {
+		switch item.Status {
+		case BulkStatusCreated, BulkStatusUpdated, BulkStatusDeleted:
+			r.Succeeded++
+		case BulkStatusFailed:
+			r.Failed++
+		}
+	}
+}
+
+// ImportReport summarises an import of employees from a sheet
+type ImportReport struct {
+	DryRun bool     `json:"dry_run"`
+	Mode   BulkMode `json:"mode" example:"best_effort"`
+	//Rows counts the data rows read, leaving out the header and blank rows
+	Rows int `json:"rows" example:"120"`
+	//Imported counts the rows committed, or that would have been on a dry run
+	Imported int `json:"imported" example:"118"`
+	Failed   int `json:"failed" example:"2"`
+	//IgnoredColumns are header columns that match no employee field
+	IgnoredColumns []string         `json:"ignored_columns,omitempty"`
+	Errors         []ImportRowError `json:"errors"`
+	//ErrorsTruncated is set when there were more errors than are listed
+	ErrorsTruncated bool `json:"errors_truncated,omitempty"`
+}
+
+// ImportRowError is one problem with one row; Row numbers start at 1, the header
+type ImportRowError struct {
+	Row     int    `json:"row" example:"7"`
+	Field   string `json:"field,omitempty" example:"salary"`
+	Code    string `json:"code" example:"out_of_range"`
+	Message string `json:"message" example:"salary must be greater than 0"`
+}
+
+type Department struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name" example:"Engineering"`
+	Description string    `json:"description" example:"Builds and runs the product"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DepartmentAssignment lists the employees to move into a department
+type DepartmentAssignment struct {
+	EmployeeIDs []uuid.UUID `json:"employee_ids"`
+}
+
+// OrgNode is one employee in the reporting hierarchy
+type OrgNode struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	Position     string     `json:"position"`
+	DepartmentID *uuid.UUID `json:"department_id"`
+	ManagerID    *uuid.UUID `json:"manager_id"`
+	Reports      []*OrgNode `json:"reports,omitempty"`
+}
+
+// ManagerAssignment sets an employee's manager, or clears it when ManagerID is null
+type ManagerAssignment struct {
+	ManagerID *uuid.UUID `json:"manager_id"`
+}
+
+// TerminationAssignment sets the last day an employee is employed, or clears
+// it when TerminationDate is null
+type TerminationAssignment struct {
+	TerminationDate *time.Time `json:"termination_date" validate:"omitnil,mindate=1900-01-01" example:"2025-03-31T00:00:00Z"`
+}
+
+// User is an account that can log in; Role is one of admin, hr, manager or viewer
+type User struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email" example:"hr@example.com"`
+	Role  string    `json:"role" example:"hr"`
+	//EmployeeID links the account to the caller's own employee record
+	EmployeeID   *uuid.UUID `json:"employee_id"`
+	PasswordHash string     `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// NewUser is the request body for creating an account
+type NewUser struct {
+	Email      string     `json:"email" example:"hr@example.com"`
+	Password   string     `json:"password" example:"s3cret-pass"`
+	Role       string     `json:"role" example:"hr"`
+	EmployeeID *uuid.UUID `json:"employee_id"`
+}
+
+// RoleUpdate changes an account's role and linked employee
+type RoleUpdate struct {
+	Role       string     `json:"role" example:"manager"`
+	EmployeeID *uuid.UUID `json:"employee_id"`
 }
 
 type Credentials struct {
@@ -21,6 +330,473 @@ type Credentials struct {
 	Password string `json:"password" example:"password"`
 }
 
+// TokenResponse is returned by login and refresh. The refresh token is single use:
+// each refresh returns a new one and retires the old.
 type TokenResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refresh_token" example:"q3v0bW9yZS1yYW5kb20tYnl0ZXMtaGVyZQ"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in" example:"900"`
+}
+
+// RefreshRequest carries a refresh token for POST /refresh and POST /logout
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" example:"q3v0bW9yZS1yYW5kb20tYnl0ZXMtaGVyZQ"`
+}
+
+// EmployeeFilter holds the paging, filtering and sorting options for listing employees
+type EmployeeFilter struct {
+	Limit    int             `json:"limit"`
+	Offset   int             `json:"offset"`
+	Cursor   *EmployeeCursor `json:"cursor,omitempty"`
+	Position string          `json:"position,omitempty"`
+	//DepartmentID restricts the listing to one department's members
+	DepartmentID *uuid.UUID    `json:"department_id,omitempty"`
+	MinSalary    *money.Amount `json:"min_salary,omitempty"`
+	MaxSalary    *money.Amount `json:"max_salary,omitempty"`
+	//Currency restricts the listing to employees paid in it
+	Currency  string     `json:"currency,omitempty"`
+	HiredFrom *time.Time `json:"hired_from,omitempty"`
+	HiredTo   *time.Time `json:"hired_to,omitempty"`
+	SortBy    string     `json:"sort_by"`
+	SortDesc  bool       `json:"sort_desc"`
+}
+
+// EmployeePage is one page of a filtered employee listing
+type EmployeePage struct {
+	Employees  []Employee `json:"employees"`
+	Total      int64      `json:"total" example:"42"`
+	NextCursor string     `json:"next_cursor,omitempty" example:"eyJzIjoibmFtZSIsInYiOiJKb2huIERvZSJ9"`
+}
+
+// EmployeeView is an employee as returned to one caller. Salary shadows the
+// embedded field and is left nil when the caller may not see it.
+type EmployeeView struct {
+	Employee
+	Salary *money.Amount `json:"salary,omitempty" swaggertype:"number" example:"60000"`
+}
+
+// EmployeeViewPage is one page of a listing projected for the caller
+type EmployeeViewPage struct {
+	Employees  []EmployeeView `json:"employees"`
+	Total      int64          `json:"total" example:"42"`
+	NextCursor string         `json:"next_cursor,omitempty" example:"eyJzIjoibmFtZSIsInYiOiJKb2huIERvZSJ9"`
+}
+
+// EmployeeSearch is one search request: a query and the page of results wanted
+type EmployeeSearch struct {
+	Query  string `json:"query"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// Highlights hold an employee's name and position as HTML with the words
+// that matched the query wrapped in <mark> tags; everything else is escaped
+type Highlights struct {
+	Name     string `json:"name" example:"<mark>Jane</mark> Doe"`
+	Position string `json:"position" example:"Software Engineer"`
+}
+
+// EmployeeSearchResult is one ranked match before salaries are projected
+type EmployeeSearchResult struct {
+	Employee   Employee   `json:"employee"`
+	Rank       float64    `json:"rank"`
+	Highlights Highlights `json:"highlights"`
+}
+
+// EmployeeSearchPage is one page of search results, best match first
+type EmployeeSearchPage struct {
+	Results []EmployeeSearchResult `json:"results"`
+	Total   int64                  `json:"total"`
+}
+
+// EmployeeSearchHit is a search result as returned to one caller
+type EmployeeSearchHit struct {
+	EmployeeView
+	//Rank orders the results; higher is a better match
+	Rank       float64    `json:"rank" example:"0.87"`
+	Highlights Highlights `json:"highlights"`
+}
+
+// EmployeeSearchViewPage is one page of search results projected for the caller
+type EmployeeSearchViewPage struct {
+	Results []EmployeeSearchHit `json:"results"`
+	Total   int64               `json:"total" example:"3"`
+}
+
+// FieldChange is one field's value before and after a change; Before is null
+// on creation and After is null on deletion
+type FieldChange struct {
+	Before json.RawMessage `json:"before" swaggertype:"object"`
+	After  json.RawMessage `json:"after" swaggertype:"object"`
+}
+
+// AuditEntry records one mutation, who made it and which fields it changed
+type AuditEntry struct {
+	ID uuid.UUID `json:"id"`
+	//ActorID is nil when the change was not made by an authenticated user
+	ActorID    *uuid.UUID             `json:"actor_id"`
+	ActorEmail string                 `json:"actor_email" example:"hr@example.com"`
+	Action     string                 `json:"action" example:"update"`
+	EntityType string                 `json:"entity_type" example:"employee"`
+	EntityID   uuid.UUID              `json:"entity_id"`
+	Changes    map[string]FieldChange `json:"changes"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// AuditFilter narrows an audit log listing; nil fields are not filtered on
+type AuditFilter struct {
+	Limit      int
+	Offset     int
+	ActorID    *uuid.UUID
+	Action     *string
+	EntityType *string
+	EntityID   *uuid.UUID
+	From       *time.Time
+	To         *time.Time
+}
+
+// AuditPage is one page of audit entries, newest first
+type AuditPage struct {
+	Entries []AuditEntry `json:"entries"`
+	Total   int64        `json:"total" example:"42"`
+}
+
+// PurgeResult reports the employees permanently removed by a purge
+type PurgeResult struct {
+	//Retention is how long ago an employee must have been deleted to be purged
+	Retention   string      `json:"retention" example:"720h0m0s"`
+	Purged      int         `json:"purged" example:"3"`
+	EmployeeIDs []uuid.UUID `json:"employee_ids"`
+}
+
+// LeaveType is a kind of leave, accrued at DaysPerYear for every year worked
+type LeaveType struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name" validate:"notblank,max=100" example:"Annual leave"`
+	DaysPerYear float64   `json:"days_per_year" validate:"gte=0,lte=366" example:"20"`
+	//Unpaid leave is deducted from pay by payroll runs instead of drawing on
+	//an accrued balance
+	Unpaid    bool      `json:"unpaid" example:"false"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LeaveBalance is an employee's standing for one leave type. Days are accrued
+// from the hired date; Pending counts requests awaiting a decision and
+// Available is what may still be requested.
+type LeaveBalance struct {
+	LeaveTypeID uuid.UUID `json:"leave_type_id"`
+	LeaveType   string    `json:"leave_type" example:"Annual leave"`
+	Accrued     float64   `json:"accrued" example:"13.7"`
+	Used        float64   `json:"used" example:"5"`
+	Pending     float64   `json:"pending" example:"2"`
+	Available   float64   `json:"available" example:"6.7"`
+}
+
+// Statuses of a leave request; only pending requests can be decided, and
+// only pending or approved ones cancelled
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusApproved  = "approved"
+	LeaveStatusRejected  = "rejected"
+	LeaveStatusCancelled = "cancelled"
+)
+
+// LeaveRequest is a request for leave from StartDate to EndDate inclusive
+type LeaveRequest struct {
+	ID          uuid.UUID `json:"id"`
+	EmployeeID  uuid.UUID `json:"employee_id"`
+	LeaveTypeID uuid.UUID `json:"leave_type_id"`
+	StartDate   time.Time `json:"start_date" example:"2024-08-05T00:00:00Z"`
+	EndDate     time.Time `json:"end_date" example:"2024-08-09T00:00:00Z"`
+	//Days counts the working days, Monday to Friday, the leave takes
+	Days   float64 `json:"days" example:"5"`
+	Reason string  `json:"reason" example:"Family holiday"`
+	Status string  `json:"status" enums:"pending,approved,rejected,cancelled" example:"pending"`
+	//DecidedBy is the user who approved, rejected or cancelled the request
+	DecidedBy      *uuid.UUID `json:"decided_by"`
+	DecidedByEmail string     `json:"decided_by_email" example:"manager@example.com"`
+	DecidedAt      *time.Time `json:"decided_at"`
+	DecisionNote   string     `json:"decision_note" example:"Enjoy the break"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// LeaveApplication is the body of POST /leave-requests. EmployeeID defaults to
+// the caller's own employee record; only HR may apply for someone else.
+type LeaveApplication struct {
+	EmployeeID  *uuid.UUID `json:"employee_id"`
+	LeaveTypeID uuid.UUID  `json:"leave_type_id" validate:"required"`
+	StartDate   time.Time  `json:"start_date" validate:"required,mindate=1900-01-01" example:"2024-08-05T00:00:00Z"`
+	EndDate     time.Time  `json:"end_date" validate:"required,mindate=1900-01-01" example:"2024-08-09T00:00:00Z"`
+	Reason      string     `json:"reason" validate:"max=500" example:"Family holiday"`
+}
+
+// LeaveDecision is the body of the approve, reject and cancel endpoints
+type LeaveDecision struct {
+	Note string `json:"note" validate:"max=500" example:"Enjoy the break"`
+}
+
+// LeaveCalendarFilter selects the team whose leave is shown and the dates
+// shown; exactly one of DepartmentID and ManagerID is set
+type LeaveCalendarFilter struct {
+	From           time.Time
+	To             time.Time
+	DepartmentID   *uuid.UUID
+	ManagerID      *uuid.UUID
+	IncludePending bool
+}
+
+// LeaveCalendarEntry is one employee's leave on a team calendar; the reason is
+// left out as it is only shown to the employee, their manager and HR
+type LeaveCalendarEntry struct {
+	RequestID    uuid.UUID `json:"request_id"`
+	EmployeeID   uuid.UUID `json:"employee_id"`
+	EmployeeName string    `json:"employee_name" example:"Jane Doe"`
+	LeaveTypeID  uuid.UUID `json:"leave_type_id"`
+	LeaveType    string    `json:"leave_type" example:"Annual leave"`
+	StartDate    time.Time `json:"start_date" example:"2024-08-05T00:00:00Z"`
+	EndDate      time.Time `json:"end_date" example:"2024-08-09T00:00:00Z"`
+	Days         float64   `json:"days" example:"5"`
+	Status       string    `json:"status" enums:"pending,approved" example:"approved"`
+}
+
+// AttendanceRecord is one shift, from a clock-in to a clock-out; ClockOut is
+// nil while the employee is still clocked in. Times are UTC.
+type AttendanceRecord struct {
+	ID         uuid.UUID  `json:"id"`
+	EmployeeID uuid.UUID  `json:"employee_id"`
+	ClockIn    time.Time  `json:"clock_in" example:"2024-08-05T09:00:00Z"`
+	ClockOut   *time.Time `json:"clock_out" example:"2024-08-05T17:30:00Z"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Punch is the body of the clock-in and clock-out endpoints. At defaults to
+// now; only HR may set it, to correct a forgotten punch.
+type Punch struct {
+	At *time.Time `json:"at" validate:"omitnil,notfuture,mindate=1900-01-01" example:"2024-08-05T17:30:00Z"`
+}
+
+// TimesheetPeriod is the length of the periods a timesheet sums hours over
+type TimesheetPeriod string
+
+const (
+	TimesheetDaily   TimesheetPeriod = "daily"
+	TimesheetWeekly  TimesheetPeriod = "weekly"
+	TimesheetMonthly TimesheetPeriod = "monthly"
+)
+
+// TimesheetQuery selects the completed shifts starting from From to To
+// inclusive and how they are grouped
+type TimesheetQuery struct {
+	Period TimesheetPeriod
+	From   time.Time
+	To     time.Time
+}
+
+// TimesheetEntry sums the shifts of one day, week (from Monday) or month.
+// Overtime is the hours beyond the standard working day, counted per day.
+type TimesheetEntry struct {
+	PeriodStart   time.Time `json:"period_start" example:"2024-08-05T00:00:00Z"`
+	PeriodEnd     time.Time `json:"period_end" example:"2024-08-11T00:00:00Z"`
+	DaysWorked    int64     `json:"days_worked" example:"5"`
+	Shifts        int64     `json:"shifts" example:"5"`
+	Hours         float64   `json:"hours" example:"42.5"`
+	OvertimeHours float64   `json:"overtime_hours" example:"2.5"`
+}
+
+// Timesheet is an employee's hours from From to To, one entry per period
+// with any completed shift
+type Timesheet struct {
+	EmployeeID         uuid.UUID        `json:"employee_id"`
+	Period             TimesheetPeriod  `json:"period" enums:"daily,weekly,monthly" example:"weekly"`
+	From               time.Time        `json:"from" example:"2024-08-01T00:00:00Z"`
+	To                 time.Time        `json:"to" example:"2024-08-31T00:00:00Z"`
+	StandardHours      float64          `json:"standard_hours" example:"8"`
+	Entries            []TimesheetEntry `json:"entries"`
+	TotalHours         float64          `json:"total_hours" example:"170"`
+	TotalOvertimeHours float64          `json:"total_overtime_hours" example:"6.5"`
+}
+
+// Payroll rule kinds and calculations
+const (
+	PayrollAllowance = "allowance"
+	PayrollDeduction = "deduction"
+
+	PayrollFixed   = "fixed"
+	PayrollPercent = "percent"
+)
+
+// PayrollRule adds an allowance to or takes a deduction from payslips. A
+// fixed Amount is per month in Currency, pro rata for the days paid, and only
+// applies to employees paid in Currency; a percent Amount is a percentage of
+// everyone's gross pay and has no currency.
+type PayrollRule struct {
+	ID          uuid.UUID    `json:"id"`
+	Name        string       `json:"name" validate:"notblank,max=100" example:"Income tax"`
+	Kind        string       `json:"kind" validate:"required,oneof=allowance deduction" enums:"allowance,deduction" example:"deduction"`
+	Calculation string       `json:"calculation" validate:"required,oneof=fixed percent" enums:"fixed,percent" example:"percent"`
+	Amount      money.Amount `json:"amount" validate:"gte=0" swaggertype:"number" example:"20"`
+	Currency    string       `json:"currency,omitempty" validate:"omitempty,currency" example:"USD"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Payroll run statuses. A draft may be recomputed; approved and finalized
+// runs may not, and a finalized run never changes again.
+const (
+	PayrollStatusDraft     = "draft"
+	PayrollStatusApproved  = "approved"
+	PayrollStatusFinalized = "finalized"
+)
+
+// PayrollRunRequest is the body of POST /payroll-runs
+type PayrollRunRequest struct {
+	//Period is the calendar month to pay
+	Period string `json:"period" validate:"required" example:"2024-08"`
+}
+
+// PayrollRun pays every employee for one calendar month. Totals sum its
+// payslips per currency; the actors are copied from the JWT like the audit
+// log's.
+type PayrollRun struct {
+	ID            uuid.UUID      `json:"id"`
+	PeriodStart   time.Time      `json:"period_start" example:"2024-08-01T00:00:00Z"`
+	PeriodEnd     time.Time      `json:"period_end" example:"2024-08-31T00:00:00Z"`
+	Status        string         `json:"status" enums:"draft,approved,finalized" example:"draft"`
+	EmployeeCount int            `json:"employee_count" example:"42"`
+	Totals        []PayrollTotal `json:"totals"`
+	//Converted sums Totals in the currency asked for with currency=, at the
+	//exchange rates in effect on RateDate
+	Converted        *PayrollTotal `json:"converted,omitempty"`
+	RateDate         *time.Time    `json:"rate_date,omitempty" example:"2024-08-31T00:00:00Z"`
+	CreatedBy        *uuid.UUID    `json:"created_by"`
+	CreatedByEmail   string        `json:"created_by_email" example:"hr@example.com"`
+	ApprovedBy       *uuid.UUID    `json:"approved_by"`
+	ApprovedByEmail  string        `json:"approved_by_email" example:"admin@example.com"`
+	ApprovedAt       *time.Time    `json:"approved_at"`
+	FinalizedBy      *uuid.UUID    `json:"finalized_by"`
+	FinalizedByEmail string        `json:"finalized_by_email" example:"admin@example.com"`
+	FinalizedAt      *time.Time    `json:"finalized_at"`
+	//ComputedAt is when the payslips were last computed
+	ComputedAt time.Time `json:"computed_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PayrollTotal sums the payslips of a run paid in one currency
+type PayrollTotal struct {
+	Currency      string       `json:"currency" example:"USD"`
+	EmployeeCount int          `json:"employee_count" example:"40"`
+	Gross         money.Amount `json:"gross" swaggertype:"number" example:"262500"`
+	Allowances    money.Amount `json:"allowances" swaggertype:"number" example:"4200"`
+	Deductions    money.Amount `json:"deductions" swaggertype:"number" example:"52500"`
+	Net           money.Amount `json:"net" swaggertype:"number" example:"214200"`
+}
+
+// PayslipLine is one allowance or deduction on a payslip, copied from its
+// rule when the payslip was computed
+type PayslipLine struct {
+	Name   string       `json:"name" example:"Income tax"`
+	Kind   string       `json:"kind" enums:"allowance,deduction" example:"deduction"`
+	Amount money.Amount `json:"amount" swaggertype:"number" example:"1250"`
+}
+
+// Payslip is one employee's pay in a run. Working days are Monday to Friday;
+// gross pay is the monthly salary in effect on each day paid, divided by the
+// working days of the month. Amounts are in Currency, the employee's.
+type Payslip struct {
+	ID          uuid.UUID `json:"id"`
+	RunID       uuid.UUID `json:"run_id"`
+	PeriodStart time.Time `json:"period_start" example:"2024-08-01T00:00:00Z"`
+	PeriodEnd   time.Time `json:"period_end" example:"2024-08-31T00:00:00Z"`
+	Status      string    `json:"status" enums:"draft,approved,finalized" example:"finalized"`
+	EmployeeID  uuid.UUID `json:"employee_id"`
+	//EmployeeName and Position are copied, so payslips outlive purged employees
+	EmployeeName string `json:"employee_name" example:"John Doe"`
+	Position     string `json:"position" example:"Software Engineer"`
+	Currency     string `json:"currency" example:"USD"`
+	//AnnualSalary is the salary in effect at the end of the period, or when the employee left
+	AnnualSalary    money.Amount  `json:"annual_salary" swaggertype:"number" example:"75000"`
+	WorkingDays     int           `json:"working_days" example:"22"`
+	PaidDays        int           `json:"paid_days" example:"20"`
+	UnpaidLeaveDays int           `json:"unpaid_leave_days" example:"2"`
+	Gross           money.Amount  `json:"gross" swaggertype:"number" example:"5681.82"`
+	Allowances      money.Amount  `json:"allowances" swaggertype:"number" example:"100"`
+	Deductions      money.Amount  `json:"deductions" swaggertype:"number" example:"1136.36"`
+	Net             money.Amount  `json:"net" swaggertype:"number" example:"4645.46"`
+	Lines           []PayslipLine `json:"lines"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+// PayrollEmployee is what a payroll run needs to pay one employee
+type PayrollEmployee struct {
+	ID        uuid.UUID
+	Name      string
+	Position  string
+	Salary    money.Amount
+	Currency  string
+	HiredDate time.Time
+	//TerminationDate is the last day employed, nil while the employee has not left
+	TerminationDate *time.Time
+	//Salaries are the employee's salary records, oldest first
+	Salaries []SalaryRecord
+	//UnpaidLeave are approved unpaid leave requests overlapping the period
+	UnpaidLeave []LeaveRequest
+}
+
+// ExchangeRate is how many units of Currency one USD buys from EffectiveDate
+// until the currency's next rate. USD itself is always 1.
+type ExchangeRate struct {
+	ID            uuid.UUID  `json:"id"`
+	Currency      string     `json:"currency" validate:"required,currency" example:"EUR"`
+	Rate          money.Rate `json:"rate" validate:"gt=0" swaggertype:"number" example:"0.9215"`
+	EffectiveDate time.Time  `json:"effective_date" validate:"required" example:"2024-08-01T00:00:00Z"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ExchangeRateFilter narrows GET /exchange-rates
+type ExchangeRateFilter struct {
+	Currency string
+	//AsOf lists only the rate of each currency in effect on the day
+	AsOf *time.Time
+}
+
+// Conversion asks for amounts in Currency at the exchange rates in effect on
+// AsOf; when AsOf is zero each report picks its own day
+type Conversion struct {
+	Currency string
+	AsOf     time.Time
+}
+
+// DepartmentCostReport is what each department's current employees are paid
+// a year, optionally converted to one currency
+type DepartmentCostReport struct {
+	//Currency and RateDate are set when the costs were converted
+	Currency    string           `json:"currency,omitempty" example:"EUR"`
+	RateDate    *time.Time       `json:"rate_date,omitempty" example:"2024-08-31T00:00:00Z"`
+	Departments []DepartmentCost `json:"departments"`
+	//Total is every department's Converted cost summed
+	Total *money.Amount `json:"total,omitempty" swaggertype:"number" example:"2150000"`
+}
+
+// DepartmentCost sums the annual salaries of a department's employees
+type DepartmentCost struct {
+	DepartmentID uuid.UUID `json:"department_id"`
+	Name         string    `json:"name" example:"Engineering"`
+	Headcount    int       `json:"headcount" example:"12"`
+	//Salaries has one sum per currency the employees are paid in
+	Salaries []CurrencyAmount `json:"salaries"`
+	//Converted is the sum of Salaries in the report's currency
+	Converted *money.Amount `json:"converted,omitempty" swaggertype:"number" example:"1075000"`
+}
+
+// CurrencyAmount is the sum of the salaries of Headcount employees paid in Currency
+type CurrencyAmount struct {
+	Currency  string       `json:"currency" example:"USD"`
+	Headcount int          `json:"headcount" example:"10"`
+	Amount    money.Amount `json:"amount" swaggertype:"number" example:"950000"`
 }
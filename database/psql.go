@@ -3,13 +3,31 @@ package database
 import (
 	"context"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lijuuu/EmployeeManagement/config"
 )
 
-func NewPostgresConn(ctx context.Context, connString string) (*pgx.Conn, error) {
-	conn, err := pgx.Connect(ctx, connString)
+// NewPostgresPool opens a connection pool sized and tuned from cfg and
+// verifies it can reach the database
+func NewPostgresPool(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.PostgresDSN)
 	if err != nil {
 		return nil, err
 	}
-	return conn, nil
+	poolCfg.MaxConns = cfg.DBMaxConns
+	poolCfg.MinConns = cfg.DBMinConns
+	poolCfg.MaxConnLifetime = cfg.DBMaxConnLifetime
+	poolCfg.MaxConnIdleTime = cfg.DBMaxConnIdleTime
+	poolCfg.HealthCheckPeriod = cfg.DBHealthCheckPeriod
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
 }
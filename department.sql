@@ -0,0 +1,74 @@
+-- name: CreateDepartment :one
+INSERT INTO departments (id, name, description, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id;
+
+-- name: GetDepartmentByID :one
+SELECT id, name, description, created_at, updated_at
+FROM departments
+WHERE id = $1;
+
+-- name: UpdateDepartment :execrows
+UPDATE departments
+SET name = $1, description = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $3;
+
+-- name: DeleteDepartment :execrows
+DELETE FROM departments
+WHERE id = $1;
+
+-- name: ListDepartments :many
+SELECT id, name, description, created_at, updated_at
+FROM departments
+ORDER BY name;
+
+-- name: GetDepartmentForUpdate :one
+-- locking the department also holds off employees joining it until the transaction ends
+SELECT id, name, description, created_at, updated_at
+FROM departments
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListDepartmentMembersForUpdate :many
+-- deleted members too, as ON DELETE SET NULL would release them as well
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE department_id = sqlc.arg(department_id)::uuid
+ORDER BY id
+FOR UPDATE;
+
+-- name: ListEmployeesForUpdate :many
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE id = ANY(sqlc.arg(employee_ids)::uuid[]) AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE;
+
+-- name: ReleaseDepartmentMembers :many
+-- run before deleting the department so members get a new version, which
+-- ON DELETE SET NULL alone would not give them
+UPDATE employees
+SET department_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE department_id = sqlc.arg(department_id)::uuid
+RETURNING id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date;
+
+-- name: MoveEmployeesToDepartment :many
+UPDATE employees
+SET department_id = sqlc.arg(department_id)::uuid, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = ANY(sqlc.arg(employee_ids)::uuid[]) AND deleted_at IS NULL
+RETURNING id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date;
+
+-- name: RemoveEmployeeFromDepartment :many
+UPDATE employees
+SET department_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = sqlc.arg(employee_id) AND department_id = sqlc.arg(department_id)::uuid AND deleted_at IS NULL
+RETURNING id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date;
+
+-- name: ListDepartmentCosts :many
+-- each department's current employees and their annual salaries per currency;
+-- a department without employees has one row with a NULL currency
+SELECT d.id, d.name, e.currency, count(e.id)::int AS headcount, COALESCE(sum(e.salary), 0)::numeric AS salaries
+FROM departments d
+LEFT JOIN employees e ON e.department_id = d.id AND e.deleted_at IS NULL
+GROUP BY d.id, d.name, e.currency
+ORDER BY d.name, d.id, e.currency;
@@ -22,9 +22,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/employees": {
+        "/audit": {
             "get": {
-                "description": "Retrieve a list of all employees. No authentication required.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a page of recorded mutations, newest first. Each entry holds the acting user, the action and the changed fields with their before and after values. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -32,20 +37,169 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employees"
+                    "audit"
+                ],
+                "summary": "List audit log entries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Entries to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID of the actor",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "update",
+                            "delete",
+                            "set_manager",
+                            "restore",
+                            "purge",
+                            "apply",
+                            "approve",
+                            "reject",
+                            "cancel",
+                            "recompute",
+                            "finalize"
+                        ],
+                        "type": "string",
+                        "description": "Action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "employee",
+                            "leave_type",
+                            "leave_request",
+                            "payroll_rule",
+                            "payroll_run"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Entity ID",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time, RFC 3339 or YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest time, RFC 3339 or YYYY-MM-DD (inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.AuditPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/departments": {
+            "get": {
+                "description": "Retrieve all departments ordered by name. No authentication required.",
+                "consumes": [
+                    "application/json"
                 ],
-                "summary": "List all employees",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "departments"
+                ],
+                "summary": "List all departments",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.Department"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     }
                 }
@@ -56,7 +210,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new employee record. Requires an ` + "`" + `Authorization` + "`" + ` header with a valid Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "description": "Create a new department. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -64,17 +218,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employees"
+                    "departments"
                 ],
-                "summary": "Create a new employee",
+                "summary": "Create a new department",
                 "parameters": [
                     {
-                        "description": "Employee data",
-                        "name": "employee",
+                        "description": "Department data",
+                        "name": "department",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/database.Employee"
+                            "$ref": "#/definitions/database.Department"
                         }
                     }
                 ],
@@ -82,33 +236,121 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/controller.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.Department"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     }
                 }
             }
         },
-        "/employees/{id}": {
+        "/departments/costs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sum the annual salaries of each department's current employees per currency. With ` + "`" + `currency` + "`" + `, each department's salaries and their grand total are also given in that currency, converted at the exchange rates in effect on ` + "`" + `as_of` + "`" + ` (default today); every currency the employees are paid in then needs a rate. Employees without a department are left out. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "departments"
+                ],
+                "summary": "Report salary costs per department",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to convert the costs to",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Day whose exchange rates are used",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.DepartmentCostReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/departments/{id}": {
             "get": {
-                "description": "Retrieve details of a specific employee. No authentication required.",
+                "description": "Retrieve details of a specific department. No authentication required.",
                 "consumes": [
                     "application/json"
                 ],
@@ -116,14 +358,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employees"
+                    "departments"
                 ],
-                "summary": "Get employee by ID",
+                "summary": "Get department by ID",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Employee ID",
+                        "description": "Department ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -133,19 +375,37 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.Department"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     }
                 }
@@ -156,7 +416,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update details of a specific employee. Requires an ` + "`" + `Authorization` + "`" + ` header with a valid Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "description": "Update the name and description of a department. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -164,25 +424,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employees"
+                    "departments"
                 ],
-                "summary": "Update an employee",
+                "summary": "Update a department",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Employee ID",
+                        "description": "Department ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Employee data",
-                        "name": "employee",
+                        "description": "Department data",
+                        "name": "department",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/database.Employee"
+                            "$ref": "#/definitions/database.Department"
                         }
                     }
                 ],
@@ -190,25 +450,43 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.Department"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     }
                 }
@@ -219,7 +497,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a specific employee. Requires an ` + "`" + `Authorization` + "`" + ` header with a valid Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "description": "Delete a department. Its members stay on record without a department, and each release is recorded in their audit history. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -227,14 +505,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employees"
+                    "departments"
                 ],
-                "summary": "Delete an employee",
+                "summary": "Delete a department",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Employee ID",
+                        "description": "Department ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -247,27 +525,157 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     }
                 }
             }
         },
-        "/login": {
+        "/departments/{id}/employees": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a page of the department's employees. Accepts the same paging, filter and sort parameters as ` + "`" + `GET /employees` + "`" + `, and projects salaries the same way. Authentication is optional.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "departments"
+                ],
+                "summary": "List a department's members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Department ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Rows to skip; ignored when cursor is set",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keyset cursor from a previous page's next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "name",
+                            "position",
+                            "salary",
+                            "hired_date",
+                            "created_at",
+                            "updated_at"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort column",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.EmployeeViewPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Authenticate admin and return a JWT token for use in the Authorization header as ` + "`" + `Bearer \u003ctoken\u003e` + "`" + `.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign the listed employees to this department, moving them out of any department they were in. The response lists the employees that were moved; unknown IDs are skipped. Each move is recorded in the employee's audit history as ` + "`" + `set_department` + "`" + `. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -275,17 +683,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "departments"
                 ],
-                "summary": "Admin login",
+                "summary": "Move employees into a department",
                 "parameters": [
                     {
-                        "description": "Admin credentials",
-                        "name": "credentials",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Department ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Employees to move",
+                        "name": "assignment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/database.Credentials"
+                            "$ref": "#/definitions/database.DepartmentAssignment"
                         }
                     }
                 ],
@@ -293,86 +709,6912 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.DepartmentAssignment"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/customerr.ErrorResponse"
+                            "$ref": "#/definitions/customerr.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controller.Response": {
-            "type": "object",
-            "properties": {
-                "payload": {},
-                "status": {
-                    "type": "string"
-                },
-                "statusCode": {
-                    "type": "integer"
-                }
-            }
-        },
-        "customerr.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Invalid request body"
-                }
-            }
         },
-        "database.Credentials": {
-            "type": "object",
-            "properties": {
-                "email": {
+        "/departments/{id}/employees/{employee_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Leave the employee without a department, recorded in their audit history. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "departments"
+                ],
+                "summary": "Remove an employee from a department",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Department ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "employee_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a page of employees with optional filters and sorting. Use either ` + "`" + `offset` + "`" + ` or the ` + "`" + `next_cursor` + "`" + ` of a previous page as ` + "`" + `cursor` + "`" + `. Authentication is optional: salaries are projected as for ` + "`" + `GET /employees/{id}` + "`" + `, and salary filters or sorting by salary need the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "List employees",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Rows to skip; ignored when cursor is set",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keyset cursor from a previous page's next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact position match",
+                        "name": "position",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Department ID",
+                        "name": "department_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum salary",
+                        "name": "min_salary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum salary",
+                        "name": "max_salary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only employees paid in this ISO 4217 currency",
+                        "name": "salary_currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Earliest hired date",
+                        "name": "hired_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Latest hired date",
+                        "name": "hired_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "name",
+                            "position",
+                            "salary",
+                            "hired_date",
+                            "created_at",
+                            "updated_at"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort column",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.EmployeeViewPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new employee record. Every invalid field is listed in the problem's ` + "`" + `errors` + "`" + `; ` + "`" + `position` + "`" + ` must be one of ` + "`" + `EMPLOYEE_POSITIONS` + "`" + ` when that is set. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Create a new employee",
+                "parameters": [
+                    {
+                        "description": "Employee data",
+                        "name": "employee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.EmployeeCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.Employee"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the new employee"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create up to 500 employees in one transaction. Each item is validated as for ` + "`" + `POST /employees` + "`" + `. In ` + "`" + `all_or_nothing` + "`" + ` mode (the default) nothing is kept when any item fails; in ` + "`" + `best_effort` + "`" + ` mode the valid items are created and the others reported. Every item's outcome is listed in request order; failed items carry a problem in ` + "`" + `error` + "`" + `. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Create employees in bulk",
+                "parameters": [
+                    {
+                        "description": "Employees to create",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.BulkCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.BulkResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-delete up to 500 employees in one transaction, as ` + "`" + `DELETE /employees/{id}` + "`" + ` does for one. A ` + "`" + `version` + "`" + ` makes its item conditional as ` + "`" + `If-Match` + "`" + ` does. The modes and results work as for ` + "`" + `POST /employees/bulk` + "`" + `. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Delete employees in bulk",
+                "parameters": [
+                    {
+                        "description": "Employees to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.BulkDeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.BulkResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply a merge patch to each of up to 500 employees in one transaction. Each ` + "`" + `patch` + "`" + ` follows the rules of ` + "`" + `PATCH /employees/{id}` + "`" + `, and a ` + "`" + `version` + "`" + ` makes its item conditional as ` + "`" + `If-Match` + "`" + ` does. The modes and results work as for ` + "`" + `POST /employees/bulk` + "`" + `. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Patch employees in bulk",
+                "parameters": [
+                    {
+                        "description": "Employees and the patches to apply",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.BulkPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.BulkResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/deleted": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a page of deleted employees that can still be restored, most recently deleted first. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "List soft-deleted employees",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.EmployeePage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download every employee matching the ` + "`" + `GET /employees` + "`" + ` filters as CSV (the default), JSON Lines or an XLSX workbook, in the requested order. Rows are streamed from the database as they are written. Authentication is optional: salaries are shown or left empty exactly as in the listing, and salary filters or sorting by salary need the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role. CSV and XLSX columns use the names ` + "`" + `POST /employees/import` + "`" + ` reads. With ` + "`" + `currency` + "`" + `, the salaries shown are converted to it at the exchange rates in effect on ` + "`" + `as_of` + "`" + ` (default today), and every currency employees are paid in needs a rate.",
+                "produces": [
+                    "text/csv",
+                    "application/jsonl",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Export employees",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact position match",
+                        "name": "position",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Department ID",
+                        "name": "department_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum salary",
+                        "name": "min_salary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum salary",
+                        "name": "max_salary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only employees paid in this ISO 4217 currency",
+                        "name": "salary_currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Earliest hired date",
+                        "name": "hired_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Latest hired date",
+                        "name": "hired_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "name",
+                            "position",
+                            "salary",
+                            "hired_date",
+                            "created_at",
+                            "updated_at"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort column",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to convert salaries to",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Day whose exchange rates are used",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a ` + "`" + `.csv` + "`" + ` or ` + "`" + `.xlsx` + "`" + ` file as the multipart field ` + "`" + `file` + "`" + `. The first row names the columns: ` + "`" + `name` + "`" + `, ` + "`" + `position` + "`" + ` and ` + "`" + `salary` + "`" + ` are required, ` + "`" + `currency` + "`" + `, ` + "`" + `hired_date` + "`" + ` (YYYY-MM-DD), ` + "`" + `department_id` + "`" + ` and ` + "`" + `manager_id` + "`" + ` are optional and other columns are ignored. Each row is validated as for ` + "`" + `POST /employees` + "`" + `. In ` + "`" + `best_effort` + "`" + ` mode (the default) the valid rows are created in one transaction and the others listed in ` + "`" + `errors` + "`" + ` by row number; in ` + "`" + `all_or_nothing` + "`" + ` mode nothing is created unless every row is valid. With ` + "`" + `dry_run=true` + "`" + ` the rows are checked against the database but nothing is kept. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Import employees from a CSV or XLSX sheet",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Sheet to import",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "best_effort",
+                        "description": "all_or_nothing or best_effort",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Report what would be imported without keeping it",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.ImportReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/purge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently remove employees deleted longer ago than the retention period (` + "`" + `SOFT_DELETE_RETENTION` + "`" + `, 30 days by default). Their audit history is kept. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Purge old soft-deleted employees",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.PurgeResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Find employees whose name or position matches ` + "`" + `q` + "`" + `, best match first. Whole words are matched with full-text search, which understands ` + "`" + `\"quoted phrases\"` + "`" + `, ` + "`" + `or` + "`" + ` and ` + "`" + `-excluded` + "`" + ` words; misspelt or partial words are matched by trigram similarity. Name matches rank above position matches. ` + "`" + `highlights` + "`" + ` repeats the name and position as HTML-escaped text with the matched words wrapped in ` + "`" + `\u003cmark\u003e` + "`" + ` tags. Authentication is optional: salaries are projected as for ` + "`" + `GET /employees/{id}` + "`" + `.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Search employees",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search terms (at most 200 characters)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.EmployeeSearchViewPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve details of a specific employee. Authentication is optional: ` + "`" + `salary` + "`" + ` is only included for the ` + "`" + `hr` + "`" + ` and ` + "`" + `admin` + "`" + ` roles, and for a ` + "`" + `manager` + "`" + ` looking at one of their direct or indirect reports. The ` + "`" + `ETag` + "`" + ` names the employee's version; send it back in ` + "`" + `If-None-Match` + "`" + ` to get ` + "`" + `304 Not Modified` + "`" + ` while the employee is unchanged, or in ` + "`" + `If-Match` + "`" + ` to update or delete it safely.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Get employee by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.EmployeeView"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the employee"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The employee has not changed"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace an employee's name, position, salary, currency and hired date; an omitted ` + "`" + `hired_date` + "`" + ` or ` + "`" + `currency` + "`" + ` is left unchanged. The same validation rules as for creation apply. With ` + "`" + `If-Match` + "`" + `, the update only happens while the employee is still at that ` + "`" + `ETag` + "`" + `; otherwise it fails with ` + "`" + `412` + "`" + `. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Update an employee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the update is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Employee data",
+                        "name": "employee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.EmployeeUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.Employee"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the employee"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-delete a specific employee: it disappears from every read and its direct reports lose their manager, but an admin can restore it until it is purged. ` + "`" + `If-Match` + "`" + ` makes the delete conditional as for ` + "`" + `PUT` + "`" + `. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Delete an employee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the delete is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply an RFC 7396 JSON merge patch: only the members present are changed. ` + "`" + `name` + "`" + `, ` + "`" + `position` + "`" + `, ` + "`" + `salary` + "`" + `, ` + "`" + `currency` + "`" + ` and ` + "`" + `hired_date` + "`" + ` may be set, with the same validation as creation; they cannot be removed with ` + "`" + `null` + "`" + `. Returns the full record as stored. ` + "`" + `If-Match` + "`" + ` makes the patch conditional as for ` + "`" + `PUT` + "`" + `. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Partially update an employee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the patch is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.EmployeePatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.Employee"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the employee"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/attendance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the shifts starting from ` + "`" + `from` + "`" + ` to ` + "`" + `to` + "`" + ` inclusive, earliest first, defaulting to the current month. Only the employee, their manager and HR may read them. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "List an employee's shifts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "First day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Last day, at most 366 days after from",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.AttendanceRecord"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/attendance/clock-in": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a shift for the employee, now unless HR gives ` + "`" + `at` + "`" + ` to correct a forgotten punch. Employees clock in themselves; HR may clock in anyone. Fails with 409 when the employee is already clocked in or the shift would overlap a recorded one. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Clock in",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Punch time",
+                        "name": "punch",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/database.Punch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.AttendanceRecord"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/attendance/clock-out": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "End the employee's open shift, now unless HR gives ` + "`" + `at` + "`" + `. Fails with 409 when the employee is not clocked in. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Clock out",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Punch time",
+                        "name": "punch",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/database.Punch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.AttendanceRecord"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the audit entries for one employee, newest first. History stays available after the employee is deleted. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Get an employee's change history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Entries to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID of the actor",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "update",
+                            "delete",
+                            "set_manager",
+                            "restore",
+                            "purge"
+                        ],
+                        "type": "string",
+                        "description": "Action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time, RFC 3339 or YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest time, RFC 3339 or YYYY-MM-DD (inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.AuditPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/leave-balances": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return the days of each leave type the employee has accrued since their hired date, used on approved leave, and holds on pending requests. ` + "`" + `available` + "`" + ` is what may still be requested. Only the employee, their manager and HR may read them. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Get an employee's leave balances",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.LeaveBalance"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/leave-requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the employee's leave requests, latest first. Only the employee, their manager and HR may read them. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "List an employee's leave requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Only requests with this status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.LeaveRequest"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/manager": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the employee's manager, or clear it with ` + "`" + `{\"manager_id\": null}` + "`" + `. Assignments that would create a reporting cycle are rejected. Send the employee's ` + "`" + `ETag` + "`" + ` as ` + "`" + `If-Match` + "`" + ` to make the change conditional on it being unchanged. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hierarchy"
+                ],
+                "summary": "Set an employee's manager",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "New manager",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.ManagerAssignment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/managers": {
+            "get": {
+                "description": "Retrieve the employee's manager, that manager's manager and so on up to the top of the hierarchy, nearest first. No authentication required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hierarchy"
+                ],
+                "summary": "Get an employee's chain of managers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.OrgNode"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/payslips": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the employee's payslips of finalized runs, latest month first. Only the employee and HR may read them. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "List an employee's payslips",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.Payslip"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/reports": {
+            "get": {
+                "description": "Retrieve the employee with their direct and indirect reports nested under ` + "`" + `reports` + "`" + `. No authentication required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hierarchy"
+                ],
+                "summary": "Get an employee's reports as a tree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.OrgNode"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Undo the deletion of an employee that has not been purged yet. Former direct reports are not reattached, and the manager is dropped if it has been deleted since. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Restore a soft-deleted employee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.Employee"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/salary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return the salary record in effect on ` + "`" + `as_of` + "`" + ` (today by default), for back-pay calculations. Fails with ` + "`" + `404` + "`" + ` for dates before the employee's history starts. Only callers who may see the employee's salary may read it. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "salary"
+                ],
+                "summary": "Get an employee's salary on a date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Date to look up",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.SalaryRecord"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/salary-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every salary the employee has had, latest effective date first. Only callers who may see the employee's salary, as for ` + "`" + `GET /employees/{id}` + "`" + `, may read it. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "salary"
+                ],
+                "summary": "Get an employee's compensation timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.SalaryRecord"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Append a salary change with its effective date and reason to the employee's history, approved by the caller. ` + "`" + `effective_date` + "`" + ` may be in the past, for back pay, but not in the future or before the employee was hired. ` + "`" + `currency` + "`" + ` defaults to the employee's. The change becomes the employee's current salary and currency unless a record with a later effective date exists. Changing ` + "`" + `salary` + "`" + ` with ` + "`" + `PUT` + "`" + ` or ` + "`" + `PATCH` + "`" + ` also records a change, effective the day it is made. ` + "`" + `If-Match` + "`" + ` makes the change conditional as for ` + "`" + `PUT` + "`" + `. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "salary"
+                ],
+                "summary": "Record a salary change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Salary change",
+                        "name": "change",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.SalaryChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.SalaryRecord"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/termination": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the last day the employee is employed, or clear it with ` + "`" + `{\"termination_date\": null}` + "`" + `. Payroll pays up to this date; deleting an employee does not end their pay, and a deleted employee without a termination date is left out of payroll runs. The date may lie in the future but not before the hired date. With ` + "`" + `If-Match` + "`" + `, the change only happens while the employee is still at that ` + "`" + `ETag` + "`" + `; otherwise it fails with ` + "`" + `412` + "`" + `. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "employees"
+                ],
+                "summary": "Set an employee's termination date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Termination date",
+                        "name": "termination",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.TerminationAssignment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.Employee"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the employee"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/timesheet": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sum the completed shifts starting from ` + "`" + `from` + "`" + ` to ` + "`" + `to` + "`" + ` inclusive per day, week (from Monday) or month, defaulting to the current month. A shift counts towards the day it starts on, and hours beyond 8 in a day are overtime. Only the employee, their manager and HR may read it. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Get an employee's timesheet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "daily",
+                            "weekly",
+                            "monthly"
+                        ],
+                        "type": "string",
+                        "default": "daily",
+                        "description": "Period to sum over",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "First day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Last day, at most 366 days after from",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.Timesheet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/employees/{id}/timesheet/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the entries of ` + "`" + `GET /employees/{id}/timesheet` + "`" + ` as CSV (the default), JSON Lines or an XLSX workbook. Only the employee, their manager and HR may export it. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "text/csv",
+                    "application/jsonl",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Export an employee's timesheet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "daily",
+                            "weekly",
+                            "monthly"
+                        ],
+                        "type": "string",
+                        "default": "daily",
+                        "description": "Period to sum over",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "First day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Last day, at most 366 days after from",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the stored rates, each the number of units of ` + "`" + `currency` + "`" + ` one USD buys from ` + "`" + `effective_date` + "`" + ` on. With ` + "`" + `as_of` + "`" + `, only the rate of each currency in effect on that day is listed. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange-rates"
+                ],
+                "summary": "List exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only rates of this ISO 4217 currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Only the rates in effect on this date",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.ExchangeRate"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store how many units of ` + "`" + `currency` + "`" + ` one USD buys from ` + "`" + `effective_date` + "`" + ` until the currency's next rate. A rate for a currency and day that is already stored is replaced. USD itself always has a rate of 1 and cannot be stored. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange-rates"
+                ],
+                "summary": "Store an exchange rate",
+                "parameters": [
+                    {
+                        "description": "Exchange rate",
+                        "name": "rate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.ExchangeRate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.ExchangeRate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a ` + "`" + `.csv` + "`" + ` or ` + "`" + `.xlsx` + "`" + ` file as the multipart field ` + "`" + `file` + "`" + ` with the columns ` + "`" + `currency` + "`" + `, ` + "`" + `rate` + "`" + ` and ` + "`" + `effective_date` + "`" + ` (YYYY-MM-DD); other columns are ignored. Each row is stored as by ` + "`" + `POST /exchange-rates` + "`" + `, all in one transaction: when any row is invalid nothing is stored and the invalid rows are listed in ` + "`" + `errors` + "`" + ` by row number. With ` + "`" + `dry_run=true` + "`" + ` the rows are only checked. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange-rates"
+                ],
+                "summary": "Import exchange rates from a CSV or XLSX sheet",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Sheet to import",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Report what would be imported without keeping it",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.ImportReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a stored rate; the currency's previous rate stays in effect in its place. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `admin` + "`" + ` role.",
+                "tags": [
+                    "exchange-rates"
+                ],
+                "summary": "Delete an exchange rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Exchange rate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leave-calendar": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List approved leave overlapping ` + "`" + `from` + "`" + ` to ` + "`" + `to` + "`" + ` for the members of a department or the direct reports of a manager, earliest first; ` + "`" + `include_pending` + "`" + ` adds requests awaiting a decision. Without ` + "`" + `department_id` + "`" + ` or ` + "`" + `manager_id` + "`" + ` the caller's own direct reports are shown. Reasons are left out. HR may view any team; a ` + "`" + `manager` + "`" + ` only sees their own direct reports, so a ` + "`" + `department_id` + "`" + ` is narrowed to them and another manager's ` + "`" + `manager_id` + "`" + ` is refused. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + `, ` + "`" + `admin` + "`" + ` or ` + "`" + `manager` + "`" + ` role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Get a team leave calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "First day",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Last day (at most 366 days after from)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Department whose members are shown",
+                        "name": "department_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Manager whose direct reports are shown",
+                        "name": "manager_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include pending requests",
+                        "name": "include_pending",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.LeaveCalendarEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leave-requests": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Request leave from ` + "`" + `start_date` + "`" + ` to ` + "`" + `end_date` + "`" + ` inclusive. The request counts the working days, Monday to Friday, in the range and is refused with ` + "`" + `409` + "`" + ` when it overlaps another pending or approved request or needs more days than are available; unpaid leave types need no balance. It stays pending until the employee's manager or HR approves or rejects it. ` + "`" + `employee_id` + "`" + ` defaults to the caller's own employee record; only HR may apply for someone else. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Apply for leave",
+                "parameters": [
+                    {
+                        "description": "Leave application",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.LeaveApplication"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.LeaveRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leave-requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a leave request. Only the employee, their manager and HR may read it. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Get a leave request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Leave request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.LeaveRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leave-requests/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approve a pending request, drawing its days from the employee's balance unless the leave type is unpaid. Only the employee's manager, for their direct reports, or HR may approve, and never their own leave. Fails with ` + "`" + `409` + "`" + ` when the request is no longer pending or the days are no longer available. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Approve a leave request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Leave request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note for the employee",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/database.LeaveDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.LeaveRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leave-requests/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw a pending or approved request; cancelling approved leave returns its days to the balance. The employee may cancel until the leave starts, their manager and HR at any time. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Cancel a leave request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Leave request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the cancellation",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/database.LeaveDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.LeaveRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leave-requests/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reject a pending request, releasing the days it held. Only the employee's manager, for their direct reports, or HR may reject, and never their own leave. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Reject a leave request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Leave request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the rejection",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/database.LeaveDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.LeaveRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leave-types": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the kinds of leave employees can request and how many days of each accrue per year worked. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "List leave types",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.LeaveType"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a kind of leave accruing ` + "`" + `days_per_year` + "`" + ` days for every year an employee has worked since their hired date. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Create a leave type",
+                "parameters": [
+                    {
+                        "description": "Leave type",
+                        "name": "leave_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.LeaveType"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.LeaveType"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticate a user and return a short-lived access token, for use in the Authorization header as ` + "`" + `Bearer \u003ctoken\u003e` + "`" + `, and a single-use refresh token for ` + "`" + `POST /refresh` + "`" + `. The access token carries the user's ID and role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log in",
+                "parameters": [
+                    {
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.Credentials"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.TokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke the access token used for this request and, when given, the session of the refresh token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log out",
+                "parameters": [
+                    {
+                        "description": "Refresh token to revoke",
+                        "name": "token",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/database.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/org-chart": {
+            "get": {
+                "description": "Retrieve every employee arranged by reporting line. Each top-level entry has no manager; reports are nested under ` + "`" + `reports` + "`" + `. No authentication required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hierarchy"
+                ],
+                "summary": "Get the organisation chart",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.OrgNode"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the allowances and deductions applied to every payslip. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "List payroll rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.PayrollRule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add an allowance or deduction to every payslip computed from now on: a ` + "`" + `fixed` + "`" + ` amount per month in a ` + "`" + `currency` + "`" + `, pro rata for the days paid and only paid to employees paid in that currency, or a ` + "`" + `percent` + "`" + ` of gross pay. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Create a payroll rule",
+                "parameters": [
+                    {
+                        "description": "Payroll rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.PayrollRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.PayrollRule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-rules/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a rule. Runs already computed keep the amounts they were computed with until a draft is recomputed. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Update a payroll rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Payroll rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payroll rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.PayrollRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.PayrollRule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop applying a rule. Payslips already computed keep its lines. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Delete a payroll rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Payroll rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every payroll run, latest month first. With ` + "`" + `currency` + "`" + `, each run's totals are also summed in that currency as ` + "`" + `converted` + "`" + `, at the exchange rates in effect on ` + "`" + `as_of` + "`" + ` or by default on the last day of the run's month. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "List payroll runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to convert the totals to",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Day whose exchange rates are used",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.PayrollRun"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compute a draft payslip for everyone employed during the month: gross pay pro rata for the hired date, deletion and approved unpaid leave, plus the current allowances and deductions. There is one run per month, and the month may not be later than the current one. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Run payroll for a month",
+                "parameters": [
+                    {
+                        "description": "Month to pay, as YYYY-MM",
+                        "name": "run",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.PayrollRunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.PayrollRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-runs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return a payroll run with its totals per currency. With ` + "`" + `currency` + "`" + `, the totals are also summed in that currency as ` + "`" + `converted` + "`" + `, at the exchange rates in effect on ` + "`" + `as_of` + "`" + ` or by default on the last day of the run's month. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Get a payroll run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Payroll run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency to convert the totals to",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Day whose exchange rates are used",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.PayrollRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-runs/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approve a draft run, freezing its payslips. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `admin` + "`" + ` role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Approve a payroll run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Payroll run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.PayrollRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-runs/{id}/finalize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Finalize an approved run once it has been paid. A finalized run and its payslips never change, and employees can then see their payslips. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `admin` + "`" + ` role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Finalize a payroll run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Payroll run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.PayrollRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-runs/{id}/payslips": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the payslips of a payroll run by employee name. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "List a run's payslips",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Payroll run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.Payslip"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-runs/{id}/recompute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the payslips of a draft run with ones computed from the current employees, salaries, leave and rules. Approved and finalized runs cannot be recomputed. Requires an ` + "`" + `Authorization` + "`" + ` header with a Bearer token (` + "`" + `Bearer \u003ctoken\u003e` + "`" + `) for the ` + "`" + `hr` + "`" + ` or ` + "`" + `admin` + "`" + ` role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Recompute a draft payroll run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Payroll run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.PayrollRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once; presenting a used one ends the whole session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh the access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.TokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all accounts ordered by email. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List user accounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.User"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an account with a role of admin, hr, manager or viewer. Managers should be linked to their own employee record through ` + "`" + `employee_id` + "`" + `. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a user account",
+                "parameters": [
+                    {
+                        "description": "Account data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.NewUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/controller.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/database.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an account. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the account's role and linked employee record. Takes effect on the user's next login. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change a user's role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.RoleUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controller.Response": {
+            "type": "object",
+            "properties": {
+                "payload": {},
+                "status": {
+                    "type": "string"
+                },
+                "statusCode": {
+                    "type": "integer"
+                }
+            }
+        },
+        "customerr.Code": {
+            "type": "string",
+            "enum": [
+                "invalid_request",
+                "invalid_body",
+                "validation_failed",
+                "unauthenticated",
+                "invalid_token",
+                "invalid_credentials",
+                "forbidden",
+                "not_found",
+                "method_not_allowed",
+                "conflict",
+                "unsupported_media_type",
+                "precondition_failed",
+                "internal_error",
+                "service_unavailable"
+            ],
+            "x-enum-varnames": [
+                "CodeInvalidRequest",
+                "CodeInvalidBody",
+                "CodeValidationFailed",
+                "CodeUnauthenticated",
+                "CodeInvalidToken",
+                "CodeInvalidCredentials",
+                "CodeForbidden",
+                "CodeNotFound",
+                "CodeMethodNotAllowed",
+                "CodeConflict",
+                "CodeUnsupportedMedia",
+                "CodePreconditionFailed",
+                "CodeInternal",
+                "CodeUnavailable"
+            ]
+        },
+        "customerr.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "out_of_range"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "limit"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "limit must be between 1 and 100"
+                }
+            }
+        },
+        "customerr.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/customerr.Code"
+                        }
+                    ],
+                    "example": "validation_failed"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "limit must be between 1 and 100"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/customerr.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/employees"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "b0c5fb2a-6a8e-4c0c-9a43-5f1d2f0e7d11"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Validation failed"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "urn:employee-management:problem:validation_failed"
+                }
+            }
+        },
+        "database.AttendanceRecord": {
+            "type": "object",
+            "properties": {
+                "clock_in": {
+                    "type": "string",
+                    "example": "2024-08-05T09:00:00Z"
+                },
+                "clock_out": {
+                    "type": "string",
+                    "example": "2024-08-05T17:30:00Z"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "database.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor_email": {
+                    "type": "string",
+                    "example": "hr@example.com"
+                },
+                "actor_id": {
+                    "description": "ActorID is nil when the change was not made by an authenticated user",
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/database.FieldChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "example": "employee"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "database.AuditPage": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.AuditEntry"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "database.BulkCreateRequest": {
+            "type": "object",
+            "properties": {
+                "employees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.EmployeeCreateRequest"
+                    }
+                },
+                "mode": {
+                    "enum": [
+                        "all_or_nothing",
+                        "best_effort"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.BulkMode"
+                        }
+                    ],
+                    "example": "all_or_nothing"
+                }
+            }
+        },
+        "database.BulkDeleteItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "database.BulkDeleteRequest": {
+            "type": "object",
+            "properties": {
+                "employees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.BulkDeleteItem"
+                    }
+                },
+                "mode": {
+                    "enum": [
+                        "all_or_nothing",
+                        "best_effort"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.BulkMode"
+                        }
+                    ],
+                    "example": "all_or_nothing"
+                }
+            }
+        },
+        "database.BulkItemResult": {
+            "type": "object",
+            "properties": {
+                "employee": {
+                    "description": "Employee is the record as stored after a create or update that was kept",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.Employee"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "Error explains a failed item; it is built from Err",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/customerr.Problem"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "updated",
+                        "deleted",
+                        "failed",
+                        "rolled_back",
+                        "skipped"
+                    ],
+                    "example": "created"
+                }
+            }
+        },
+        "database.BulkMode": {
+            "type": "string",
+            "enum": [
+                "all_or_nothing",
+                "best_effort"
+            ],
+            "x-enum-varnames": [
+                "BulkAllOrNothing",
+                "BulkBestEffort"
+            ]
+        },
+        "database.BulkPatchItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "patch": {
+                    "type": "object"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "database.BulkPatchRequest": {
+            "type": "object",
+            "properties": {
+                "employees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.BulkPatchItem"
+                    }
+                },
+                "mode": {
+                    "enum": [
+                        "all_or_nothing",
+                        "best_effort"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.BulkMode"
+                        }
+                    ],
+                    "example": "best_effort"
+                }
+            }
+        },
+        "database.BulkResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "mode": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.BulkMode"
+                        }
+                    ],
+                    "example": "all_or_nothing"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.BulkItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "database.Credentials": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "admin@gmail.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "database.CurrencyAmount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 950000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "headcount": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "database.Department": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Builds and runs the product"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Engineering"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "database.DepartmentAssignment": {
+            "type": "object",
+            "properties": {
+                "employee_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "database.DepartmentCost": {
+            "type": "object",
+            "properties": {
+                "converted": {
+                    "description": "Converted is the sum of Salaries in the report's currency",
+                    "type": "number",
+                    "example": 1075000
+                },
+                "department_id": {
+                    "type": "string"
+                },
+                "headcount": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Engineering"
+                },
+                "salaries": {
+                    "description": "Salaries has one sum per currency the employees are paid in",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.CurrencyAmount"
+                    }
+                }
+            }
+        },
+        "database.DepartmentCostReport": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency and RateDate are set when the costs were converted",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "departments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.DepartmentCost"
+                    }
+                },
+                "rate_date": {
+                    "type": "string",
+                    "example": "2024-08-31T00:00:00Z"
+                },
+                "total": {
+                    "description": "Total is every department's Converted cost summed",
+                    "type": "number",
+                    "example": 2150000
+                }
+            }
+        },
+        "database.Employee": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt is only set on soft-deleted employees, which are hidden from every other read",
+                    "type": "string"
+                },
+                "department_id": {
+                    "description": "DepartmentID is nil while the employee is not assigned to a department",
+                    "type": "string"
+                },
+                "hired_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "description": "ManagerID is nil for employees at the top of the hierarchy",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "salary": {
+                    "description": "Salary is the annual salary in Currency, an ISO 4217 code",
+                    "type": "number",
+                    "example": 60000
+                },
+                "termination_date": {
+                    "description": "TerminationDate is the last day employed, nil while the employee has not\nleft; unlike deletion it is what ends pay",
+                    "type": "string",
+                    "example": "2025-03-31T00:00:00Z"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is bumped by every change to the record and is served as its ETag",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "database.EmployeeCreateRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "department_id": {
+                    "type": "string"
+                },
+                "hired_date": {
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "manager_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Jane Doe"
+                },
+                "position": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Software Engineer"
+                },
+                "salary": {
+                    "type": "number",
+                    "maximum": 100000000,
+                    "example": 60000
+                }
+            }
+        },
+        "database.EmployeePage": {
+            "type": "object",
+            "properties": {
+                "employees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.Employee"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJzIjoibmFtZSIsInYiOiJKb2huIERvZSJ9"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "database.EmployeePatch": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "hired_date": {
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Jane Doe"
+                },
+                "position": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Staff Engineer"
+                },
+                "salary": {
+                    "type": "number",
+                    "maximum": 100000000,
+                    "example": 90000
+                }
+            }
+        },
+        "database.EmployeeSearchHit": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt is only set on soft-deleted employees, which are hidden from every other read",
+                    "type": "string"
+                },
+                "department_id": {
+                    "description": "DepartmentID is nil while the employee is not assigned to a department",
+                    "type": "string"
+                },
+                "highlights": {
+                    "$ref": "#/definitions/database.Highlights"
+                },
+                "hired_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "description": "ManagerID is nil for employees at the top of the hierarchy",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "rank": {
+                    "description": "Rank orders the results; higher is a better match",
+                    "type": "number",
+                    "example": 0.87
+                },
+                "salary": {
+                    "type": "number",
+                    "example": 60000
+                },
+                "termination_date": {
+                    "description": "TerminationDate is the last day employed, nil while the employee has not\nleft; unlike deletion it is what ends pay",
+                    "type": "string",
+                    "example": "2025-03-31T00:00:00Z"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is bumped by every change to the record and is served as its ETag",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "database.EmployeeSearchViewPage": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.EmployeeSearchHit"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "database.EmployeeUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "hired_date": {
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Jane Doe"
+                },
+                "position": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Senior Software Engineer"
+                },
+                "salary": {
+                    "type": "number",
+                    "maximum": 100000000,
+                    "example": 75000
+                }
+            }
+        },
+        "database.EmployeeView": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt is only set on soft-deleted employees, which are hidden from every other read",
+                    "type": "string"
+                },
+                "department_id": {
+                    "description": "DepartmentID is nil while the employee is not assigned to a department",
+                    "type": "string"
+                },
+                "hired_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "description": "ManagerID is nil for employees at the top of the hierarchy",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "salary": {
+                    "type": "number",
+                    "example": 60000
+                },
+                "termination_date": {
+                    "description": "TerminationDate is the last day employed, nil while the employee has not\nleft; unlike deletion it is what ends pay",
+                    "type": "string",
+                    "example": "2025-03-31T00:00:00Z"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is bumped by every change to the record and is served as its ETag",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "database.EmployeeViewPage": {
+            "type": "object",
+            "properties": {
+                "employees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.EmployeeView"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJzIjoibmFtZSIsInYiOiJKb2huIERvZSJ9"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "database.ExchangeRate": {
+            "type": "object",
+            "required": [
+                "currency",
+                "effective_date"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "effective_date": {
+                    "type": "string",
+                    "example": "2024-08-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 0.9215
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "database.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                }
+            }
+        },
+        "database.Highlights": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "\u003cmark\u003eJane\u003c/mark\u003e Doe"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "Software Engineer"
+                }
+            }
+        },
+        "database.ImportReport": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.ImportRowError"
+                    }
+                },
+                "errors_truncated": {
+                    "description": "ErrorsTruncated is set when there were more errors than are listed",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "ignored_columns": {
+                    "description": "IgnoredColumns are header columns that match no employee field",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "imported": {
+                    "description": "Imported counts the rows committed, or that would have been on a dry run",
+                    "type": "integer",
+                    "example": 118
+                },
+                "mode": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.BulkMode"
+                        }
+                    ],
+                    "example": "best_effort"
+                },
+                "rows": {
+                    "description": "Rows counts the data rows read, leaving out the header and blank rows",
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "database.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "out_of_range"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "salary"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "salary must be greater than 0"
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "database.LeaveApplication": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "leave_type_id",
+                "start_date"
+            ],
+            "properties": {
+                "employee_id": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-08-09T00:00:00Z"
+                },
+                "leave_type_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Family holiday"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-08-05T00:00:00Z"
+                }
+            }
+        },
+        "database.LeaveBalance": {
+            "type": "object",
+            "properties": {
+                "accrued": {
+                    "type": "number",
+                    "example": 13.7
+                },
+                "available": {
+                    "type": "number",
+                    "example": 6.7
+                },
+                "leave_type": {
+                    "type": "string",
+                    "example": "Annual leave"
+                },
+                "leave_type_id": {
+                    "type": "string"
+                },
+                "pending": {
+                    "type": "number",
+                    "example": 2
+                },
+                "used": {
+                    "type": "number",
+                    "example": 5
+                }
+            }
+        },
+        "database.LeaveCalendarEntry": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "number",
+                    "example": 5
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "employee_name": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-08-09T00:00:00Z"
+                },
+                "leave_type": {
+                    "type": "string",
+                    "example": "Annual leave"
+                },
+                "leave_type_id": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-08-05T00:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved"
+                    ],
+                    "example": "approved"
+                }
+            }
+        },
+        "database.LeaveDecision": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Enjoy the break"
+                }
+            }
+        },
+        "database.LeaveRequest": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "days": {
+                    "description": "Days counts the working days, Monday to Friday, the leave takes",
+                    "type": "number",
+                    "example": 5
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "description": "DecidedBy is the user who approved, rejected or cancelled the request",
+                    "type": "string"
+                },
+                "decided_by_email": {
+                    "type": "string",
+                    "example": "manager@example.com"
+                },
+                "decision_note": {
+                    "type": "string",
+                    "example": "Enjoy the break"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-08-09T00:00:00Z"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "leave_type_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Family holiday"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-08-05T00:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected",
+                        "cancelled"
+                    ],
+                    "example": "pending"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "database.LeaveType": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "days_per_year": {
+                    "type": "number",
+                    "maximum": 366,
+                    "minimum": 0,
+                    "example": 20
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Annual leave"
+                },
+                "unpaid": {
+                    "description": "Unpaid leave is deducted from pay by payroll runs instead of drawing on\nan accrued balance",
+                    "type": "boolean",
+                    "example": false
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "database.ManagerAssignment": {
+            "type": "object",
+            "properties": {
+                "manager_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "database.NewUser": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "hr@example.com"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "s3cret-pass"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "hr"
+                }
+            }
+        },
+        "database.OrgNode": {
+            "type": "object",
+            "properties": {
+                "department_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.OrgNode"
+                    }
+                }
+            }
+        },
+        "database.PayrollRule": {
+            "type": "object",
+            "required": [
+                "calculation",
+                "kind"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 20
+                },
+                "calculation": {
                     "type": "string",
-                    "example": "admin@gmail.com"
+                    "enum": [
+                        "fixed",
+                        "percent"
+                    ],
+                    "example": "percent"
                 },
-                "password": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
                     "type": "string",
-                    "example": "password"
+                    "example": "USD"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "allowance",
+                        "deduction"
+                    ],
+                    "example": "deduction"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Income tax"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "database.Employee": {
+        "database.PayrollRun": {
             "type": "object",
             "properties": {
+                "approved_at": {
+                    "type": "string"
+                },
+                "approved_by": {
+                    "type": "string"
+                },
+                "approved_by_email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "computed_at": {
+                    "description": "ComputedAt is when the payslips were last computed",
+                    "type": "string"
+                },
+                "converted": {
+                    "description": "Converted sums Totals in the currency asked for with currency=, at the\nexchange rates in effect on RateDate",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.PayrollTotal"
+                        }
+                    ]
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "hired_date": {
+                "created_by": {
+                    "type": "string"
+                },
+                "created_by_email": {
+                    "type": "string",
+                    "example": "hr@example.com"
+                },
+                "employee_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "finalized_at": {
+                    "type": "string"
+                },
+                "finalized_by": {
                     "type": "string"
                 },
+                "finalized_by_email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "period_end": {
+                    "type": "string",
+                    "example": "2024-08-31T00:00:00Z"
+                },
+                "period_start": {
+                    "type": "string",
+                    "example": "2024-08-01T00:00:00Z"
+                },
+                "rate_date": {
+                    "type": "string",
+                    "example": "2024-08-31T00:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "approved",
+                        "finalized"
+                    ],
+                    "example": "draft"
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.PayrollTotal"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "database.PayrollRunRequest": {
+            "type": "object",
+            "required": [
+                "period"
+            ],
+            "properties": {
+                "period": {
+                    "description": "Period is the calendar month to pay",
+                    "type": "string",
+                    "example": "2024-08"
+                }
+            }
+        },
+        "database.PayrollTotal": {
+            "type": "object",
+            "properties": {
+                "allowances": {
+                    "type": "number",
+                    "example": 4200
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "deductions": {
+                    "type": "number",
+                    "example": 52500
+                },
+                "employee_count": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "gross": {
+                    "type": "number",
+                    "example": 262500
+                },
+                "net": {
+                    "type": "number",
+                    "example": 214200
+                }
+            }
+        },
+        "database.Payslip": {
+            "type": "object",
+            "properties": {
+                "allowances": {
+                    "type": "number",
+                    "example": 100
+                },
+                "annual_salary": {
+                    "description": "AnnualSalary is the salary in effect at the end of the period, or when the employee left",
+                    "type": "number",
+                    "example": 75000
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "deductions": {
+                    "type": "number",
+                    "example": 1136.36
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "employee_name": {
+                    "description": "EmployeeName and Position are copied, so payslips outlive purged employees",
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "gross": {
+                    "type": "number",
+                    "example": 5681.82
+                },
+                "id": {
                     "type": "string"
                 },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.PayslipLine"
+                    }
+                },
+                "net": {
+                    "type": "number",
+                    "example": 4645.46
+                },
+                "paid_days": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "period_end": {
+                    "type": "string",
+                    "example": "2024-08-31T00:00:00Z"
+                },
+                "period_start": {
+                    "type": "string",
+                    "example": "2024-08-01T00:00:00Z"
+                },
                 "position": {
+                    "type": "string",
+                    "example": "Software Engineer"
+                },
+                "run_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "approved",
+                        "finalized"
+                    ],
+                    "example": "finalized"
+                },
+                "unpaid_leave_days": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "working_days": {
+                    "type": "integer",
+                    "example": 22
+                }
+            }
+        },
+        "database.PayslipLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 1250
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "allowance",
+                        "deduction"
+                    ],
+                    "example": "deduction"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Income tax"
+                }
+            }
+        },
+        "database.Punch": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string",
+                    "example": "2024-08-05T17:30:00Z"
+                }
+            }
+        },
+        "database.PurgeResult": {
+            "type": "object",
+            "properties": {
+                "employee_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "purged": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "retention": {
+                    "description": "Retention is how long ago an employee must have been deleted to be purged",
+                    "type": "string",
+                    "example": "720h0m0s"
+                }
+            }
+        },
+        "database.RefreshRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "q3v0bW9yZS1yYW5kb20tYnl0ZXMtaGVyZQ"
+                }
+            }
+        },
+        "database.RoleUpdate": {
+            "type": "object",
+            "properties": {
+                "employee_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "manager"
+                }
+            }
+        },
+        "database.SalaryChangeRequest": {
+            "type": "object",
+            "required": [
+                "effective_date"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "effective_date": {
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Promotion to senior engineer"
+                },
+                "salary": {
+                    "type": "number",
+                    "maximum": 100000000,
+                    "example": 82000
+                }
+            }
+        },
+        "database.SalaryRecord": {
+            "type": "object",
+            "properties": {
+                "approved_by": {
+                    "description": "ApprovedBy is the user who recorded the change, nil when it was not made through the API",
                     "type": "string"
                 },
+                "approved_by_email": {
+                    "type": "string",
+                    "example": "hr@example.com"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "effective_date": {
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Annual review"
+                },
                 "salary": {
-                    "type": "number"
+                    "type": "number",
+                    "example": 75000
+                }
+            }
+        },
+        "database.TerminationAssignment": {
+            "type": "object",
+            "properties": {
+                "termination_date": {
+                    "type": "string",
+                    "example": "2025-03-31T00:00:00Z"
+                }
+            }
+        },
+        "database.Timesheet": {
+            "type": "object",
+            "properties": {
+                "employee_id": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.TimesheetEntry"
+                    }
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-08-01T00:00:00Z"
+                },
+                "period": {
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.TimesheetPeriod"
+                        }
+                    ],
+                    "example": "weekly"
+                },
+                "standard_hours": {
+                    "type": "number",
+                    "example": 8
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-08-31T00:00:00Z"
+                },
+                "total_hours": {
+                    "type": "number",
+                    "example": 170
+                },
+                "total_overtime_hours": {
+                    "type": "number",
+                    "example": 6.5
+                }
+            }
+        },
+        "database.TimesheetEntry": {
+            "type": "object",
+            "properties": {
+                "days_worked": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "hours": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "overtime_hours": {
+                    "type": "number",
+                    "example": 2.5
+                },
+                "period_end": {
+                    "type": "string",
+                    "example": "2024-08-11T00:00:00Z"
+                },
+                "period_start": {
+                    "type": "string",
+                    "example": "2024-08-05T00:00:00Z"
+                },
+                "shifts": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "database.TimesheetPeriod": {
+            "type": "string",
+            "enum": [
+                "daily",
+                "weekly",
+                "monthly"
+            ],
+            "x-enum-varnames": [
+                "TimesheetDaily",
+                "TimesheetWeekly",
+                "TimesheetMonthly"
+            ]
+        },
+        "database.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "expires_in": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "q3v0bW9yZS1yYW5kb20tYnl0ZXMtaGVyZQ"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "database.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "hr@example.com"
+                },
+                "employee_id": {
+                    "description": "EmployeeID links the account to the caller's own employee record",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "hr"
                 },
                 "updated_at": {
                     "type": "string"
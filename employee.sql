@@ -1,22 +1,260 @@
 -- name: CreateEmployee :one
-INSERT INTO employees (id, name, position, salary, hired_date, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO employees (id, name, position, salary, currency, hired_date, department_id, manager_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING id;
 
 -- name: GetEmployeeByID :one
-SELECT id, name, position, salary, hired_date, created_at, updated_at
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
 FROM employees
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
 
--- name: UpdateEmployee :exec
+-- name: GetEmployeeForUpdate :one
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: UpdateEmployee :execrows
+UPDATE employees
+SET name = $1, position = $2, salary = $3, currency = $4, hired_date = $5, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $6 AND deleted_at IS NULL;
+
+-- name: PatchEmployee :execrows
+-- a NULL argument leaves its column unchanged
+UPDATE employees
+SET name = COALESCE(sqlc.narg(name), name),
+    position = COALESCE(sqlc.narg(position), position),
+    salary = COALESCE(sqlc.narg(salary), salary),
+    currency = COALESCE(sqlc.narg(currency), currency),
+    hired_date = COALESCE(sqlc.narg(hired_date), hired_date),
+    updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
+
+-- name: SoftDeleteEmployee :execrows
+UPDATE employees
+SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ClearDirectReportsManager :exec
+-- reports of a deleted manager move to the top of the hierarchy, as ON DELETE SET NULL did for hard deletes
 UPDATE employees
-SET name = $1, position = $2, salary = $3, hired_date = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $5;
+SET manager_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE manager_id = sqlc.arg(manager_id)::uuid;
+
+-- name: GetDeletedEmployeeForUpdate :one
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE id = $1 AND deleted_at IS NOT NULL
+FOR UPDATE;
 
--- name: DeleteEmployee :exec
+-- name: RestoreEmployee :execrows
+-- the manager may have been deleted meanwhile; the restored employee then has none
+UPDATE employees e
+SET deleted_at = NULL,
+    manager_id = (SELECT m.id FROM employees m WHERE m.id = e.manager_id AND m.deleted_at IS NULL),
+    updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE e.id = $1 AND e.deleted_at IS NOT NULL;
+
+-- name: ListDeletedEmployees :many
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountDeletedEmployees :one
+SELECT COUNT(*)
+FROM employees
+WHERE deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedEmployees :many
 DELETE FROM employees
-WHERE id = $1;
+-- compared against the database clock, which also stamped deleted_at
+WHERE deleted_at IS NOT NULL AND deleted_at < CURRENT_TIMESTAMP - sqlc.arg(retention)::interval
+RETURNING id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date;
 
 -- name: ListEmployees :many
-SELECT id, name, position, salary, hired_date, created_at, updated_at
-FROM employees;
\ No newline at end of file
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(position)::text IS NULL OR position = sqlc.narg(position)::text)
+  AND (sqlc.narg(department_id)::uuid IS NULL OR department_id = sqlc.narg(department_id)::uuid)
+  AND (sqlc.narg(min_salary)::numeric IS NULL OR salary >= sqlc.narg(min_salary)::numeric)
+  AND (sqlc.narg(max_salary)::numeric IS NULL OR salary <= sqlc.narg(max_salary)::numeric)
+  AND (sqlc.narg(currency)::text IS NULL OR currency = sqlc.narg(currency)::text)
+  AND (sqlc.narg(hired_from)::date IS NULL OR hired_date >= sqlc.narg(hired_from)::date)
+  AND (sqlc.narg(hired_to)::date IS NULL OR hired_date <= sqlc.narg(hired_to)::date)
+  -- keyset cursor: rows strictly after (sort value, id) of the previous page
+  AND (sqlc.narg(cursor_id)::uuid IS NULL
+    OR (sqlc.arg(sort_by)::text = 'id' AND NOT sqlc.arg(sort_desc)::bool AND id > sqlc.narg(cursor_id)::uuid)
+    OR (sqlc.arg(sort_by)::text = 'id' AND sqlc.arg(sort_desc)::bool AND id < sqlc.narg(cursor_id)::uuid)
+    OR (sqlc.arg(sort_by)::text = 'name' AND NOT sqlc.arg(sort_desc)::bool AND (name, id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'name' AND sqlc.arg(sort_desc)::bool AND (name, id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'position' AND NOT sqlc.arg(sort_desc)::bool AND (position, id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'position' AND sqlc.arg(sort_desc)::bool AND (position, id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'salary' AND NOT sqlc.arg(sort_desc)::bool AND (salary, id) > (sqlc.narg(cursor_number)::numeric, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'salary' AND sqlc.arg(sort_desc)::bool AND (salary, id) < (sqlc.narg(cursor_number)::numeric, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'hired_date' AND NOT sqlc.arg(sort_desc)::bool AND (hired_date, id) > (sqlc.narg(cursor_date)::date, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'hired_date' AND sqlc.arg(sort_desc)::bool AND (hired_date, id) < (sqlc.narg(cursor_date)::date, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'created_at' AND NOT sqlc.arg(sort_desc)::bool AND (created_at, id) > (sqlc.narg(cursor_time)::timestamp, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'created_at' AND sqlc.arg(sort_desc)::bool AND (created_at, id) < (sqlc.narg(cursor_time)::timestamp, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'updated_at' AND NOT sqlc.arg(sort_desc)::bool AND (updated_at, id) > (sqlc.narg(cursor_time)::timestamp, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'updated_at' AND sqlc.arg(sort_desc)::bool AND (updated_at, id) < (sqlc.narg(cursor_time)::timestamp, sqlc.narg(cursor_id)::uuid)))
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by)::text = 'name' AND NOT sqlc.arg(sort_desc)::bool THEN name END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'name' AND sqlc.arg(sort_desc)::bool THEN name END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'position' AND NOT sqlc.arg(sort_desc)::bool THEN position END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'position' AND sqlc.arg(sort_desc)::bool THEN position END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'salary' AND NOT sqlc.arg(sort_desc)::bool THEN salary END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'salary' AND sqlc.arg(sort_desc)::bool THEN salary END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'hired_date' AND NOT sqlc.arg(sort_desc)::bool THEN hired_date END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'hired_date' AND sqlc.arg(sort_desc)::bool THEN hired_date END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND NOT sqlc.arg(sort_desc)::bool THEN created_at END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND sqlc.arg(sort_desc)::bool THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'updated_at' AND NOT sqlc.arg(sort_desc)::bool THEN updated_at END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'updated_at' AND sqlc.arg(sort_desc)::bool THEN updated_at END DESC,
+  CASE WHEN NOT sqlc.arg(sort_desc)::bool THEN id END ASC,
+  CASE WHEN sqlc.arg(sort_desc)::bool THEN id END DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ExportEmployees :many
+-- every employee matching the listing filters, in listing order and without
+-- paging
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(position)::text IS NULL OR position = sqlc.narg(position)::text)
+  AND (sqlc.narg(department_id)::uuid IS NULL OR department_id = sqlc.narg(department_id)::uuid)
+  AND (sqlc.narg(min_salary)::numeric IS NULL OR salary >= sqlc.narg(min_salary)::numeric)
+  AND (sqlc.narg(max_salary)::numeric IS NULL OR salary <= sqlc.narg(max_salary)::numeric)
+  AND (sqlc.narg(currency)::text IS NULL OR currency = sqlc.narg(currency)::text)
+  AND (sqlc.narg(hired_from)::date IS NULL OR hired_date >= sqlc.narg(hired_from)::date)
+  AND (sqlc.narg(hired_to)::date IS NULL OR hired_date <= sqlc.narg(hired_to)::date)
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by)::text = 'name' AND NOT sqlc.arg(sort_desc)::bool THEN name END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'name' AND sqlc.arg(sort_desc)::bool THEN name END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'position' AND NOT sqlc.arg(sort_desc)::bool THEN position END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'position' AND sqlc.arg(sort_desc)::bool THEN position END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'salary' AND NOT sqlc.arg(sort_desc)::bool THEN salary END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'salary' AND sqlc.arg(sort_desc)::bool THEN salary END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'hired_date' AND NOT sqlc.arg(sort_desc)::bool THEN hired_date END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'hired_date' AND sqlc.arg(sort_desc)::bool THEN hired_date END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND NOT sqlc.arg(sort_desc)::bool THEN created_at END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND sqlc.arg(sort_desc)::bool THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'updated_at' AND NOT sqlc.arg(sort_desc)::bool THEN updated_at END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'updated_at' AND sqlc.arg(sort_desc)::bool THEN updated_at END DESC,
+  CASE WHEN NOT sqlc.arg(sort_desc)::bool THEN id END ASC,
+  CASE WHEN sqlc.arg(sort_desc)::bool THEN id END DESC;
+
+-- name: SearchEmployees :many
+-- ranks full-text matches on name (weighted above position) plus trigram word
+-- similarity, so misspelt and partial words are found too. Matched words are
+-- wrapped in chr(2) and chr(3) for the repo to turn into highlights.
+SELECT e.id, e.name, e.position, e.salary, e.hired_date, e.created_at, e.updated_at, e.department_id, e.manager_id, e.deleted_at, e.version, e.currency, e.termination_date,
+  (ts_rank(setweight(to_tsvector('simple', e.name), 'A') || setweight(to_tsvector('simple', e.position), 'B'), q.query)
+    + GREATEST(word_similarity(sqlc.arg(query)::text, e.name), word_similarity(sqlc.arg(query)::text, e.position)))::float8 AS rank,
+  ts_headline('simple', e.name, q.query, q.options)::text AS name_highlight,
+  ts_headline('simple', e.position, q.query, q.options)::text AS position_highlight
+FROM employees e,
+  (SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS query,
+    'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3) AS options) q
+WHERE e.deleted_at IS NULL
+  AND ((setweight(to_tsvector('simple', e.name), 'A') || setweight(to_tsvector('simple', e.position), 'B')) @@ q.query
+    OR sqlc.arg(query)::text <% e.name
+    OR sqlc.arg(query)::text <% e.position)
+ORDER BY rank DESC, e.name, e.id
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountSearchEmployees :one
+SELECT COUNT(*)
+FROM employees e
+WHERE e.deleted_at IS NULL
+  AND ((setweight(to_tsvector('simple', e.name), 'A') || setweight(to_tsvector('simple', e.position), 'B')) @@ websearch_to_tsquery('simple', sqlc.arg(query)::text)
+    OR sqlc.arg(query)::text <% e.name
+    OR sqlc.arg(query)::text <% e.position);
+
+-- name: SetSearchSimilarityThreshold :exec
+-- lowers the word similarity needed by <% for the rest of the transaction
+SELECT set_config('pg_trgm.word_similarity_threshold', sqlc.arg(threshold)::text, true);
+
+-- name: CountEmployees :one
+SELECT COUNT(*)
+FROM employees
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(position)::text IS NULL OR position = sqlc.narg(position)::text)
+  AND (sqlc.narg(department_id)::uuid IS NULL OR department_id = sqlc.narg(department_id)::uuid)
+  AND (sqlc.narg(min_salary)::numeric IS NULL OR salary >= sqlc.narg(min_salary)::numeric)
+  AND (sqlc.narg(max_salary)::numeric IS NULL OR salary <= sqlc.narg(max_salary)::numeric)
+  AND (sqlc.narg(currency)::text IS NULL OR currency = sqlc.narg(currency)::text)
+  AND (sqlc.narg(hired_from)::date IS NULL OR hired_date >= sqlc.narg(hired_from)::date)
+  AND (sqlc.narg(hired_to)::date IS NULL OR hired_date <= sqlc.narg(hired_to)::date);
+
+
+-- name: SetEmployeeManager :execrows
+UPDATE employees
+SET manager_id = sqlc.narg(manager_id), updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
+
+-- name: SetEmployeeTermination :execrows
+UPDATE employees
+SET termination_date = sqlc.narg(termination_date), updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
+
+-- name: LockHierarchy :exec
+-- serializes manager changes until the transaction ends, so two of them
+-- cannot each pass the cycle check and together close a loop
+SELECT pg_advisory_xact_lock(7267103984512001);
+
+-- name: ListEmployeeCurrencies :many
+-- the currencies current employees are paid in
+SELECT DISTINCT currency
+FROM employees
+WHERE deleted_at IS NULL
+ORDER BY currency;
+
+-- name: ListDirectReportIDs :many
+SELECT id
+FROM employees
+WHERE manager_id = sqlc.arg(manager_id)::uuid AND deleted_at IS NULL;
+
+-- name: GetManagerChain :many
+-- walks up from the employee's manager to the top; depth guards against a cycle already in the data
+WITH RECURSIVE chain AS (
+    SELECT m.id, m.name, m.position, m.department_id, m.manager_id, 1 AS depth
+    FROM employees e
+    JOIN employees m ON m.id = e.manager_id
+    WHERE e.id = sqlc.arg(id) AND m.deleted_at IS NULL
+    UNION ALL
+    SELECT m.id, m.name, m.position, m.department_id, m.manager_id, c.depth + 1
+    FROM employees m
+    JOIN chain c ON m.id = c.manager_id
+    WHERE c.depth < 100 AND m.deleted_at IS NULL
+)
+SELECT id, name, position, department_id, manager_id, depth::int AS depth
+FROM chain
+ORDER BY depth;
+
+-- name: GetReportTree :many
+-- every direct and indirect report below the employee, shallowest first
+WITH RECURSIVE reports AS (
+    SELECT e.id, e.name, e.position, e.department_id, e.manager_id, 1 AS depth
+    FROM employees e
+    WHERE e.manager_id = sqlc.arg(id)::uuid AND e.deleted_at IS NULL
+    UNION ALL
+    SELECT e.id, e.name, e.position, e.department_id, e.manager_id, r.depth + 1
+    FROM employees e
+    JOIN reports r ON e.manager_id = r.id
+    WHERE r.depth < 100 AND e.deleted_at IS NULL
+)
+SELECT id, name, position, department_id, manager_id, depth::int AS depth
+FROM reports
+ORDER BY depth, name;
+
+-- name: ListOrgChart :many
+SELECT id, name, position, department_id, manager_id
+FROM employees
+WHERE deleted_at IS NULL
+ORDER BY name;
@@ -0,0 +1,33 @@
+-- name: GetExchangeRate :one
+SELECT id, currency, rate, effective_date, created_at, updated_at
+FROM exchange_rates
+WHERE id = $1;
+
+-- name: GetExchangeRateOn :one
+SELECT id, currency, rate, effective_date, created_at, updated_at
+FROM exchange_rates
+WHERE currency = $1 AND effective_date = $2;
+
+-- name: UpsertExchangeRate :one
+-- a second rate for the same currency and day replaces the first
+INSERT INTO exchange_rates (id, currency, rate, effective_date, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $5)
+ON CONFLICT (currency, effective_date) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at
+RETURNING id, currency, rate, effective_date, created_at, updated_at;
+
+-- name: DeleteExchangeRate :execrows
+DELETE FROM exchange_rates WHERE id = $1;
+
+-- name: ListExchangeRates :many
+-- newest first per currency
+SELECT id, currency, rate, effective_date, created_at, updated_at
+FROM exchange_rates
+WHERE sqlc.narg(currency)::text IS NULL OR currency = sqlc.narg(currency)::text
+ORDER BY currency, effective_date DESC;
+
+-- name: ListExchangeRatesOn :many
+-- the rate in effect on a date for each currency: its latest one effective on or before it
+SELECT DISTINCT ON (currency) id, currency, rate, effective_date, created_at, updated_at
+FROM exchange_rates
+WHERE effective_date <= sqlc.arg(as_of)::date
+ORDER BY currency, effective_date DESC;
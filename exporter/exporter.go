@@ -0,0 +1,139 @@
+// Package exporter writes employees and timesheets as CSV, JSON Lines or XLSX
+// one row at a time, so an export never holds the whole table in memory.
+// Employee CSV and XLSX use the column names the importer reads, so an export
+// can be imported again.
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+// Format is a file format employees and timesheets can be exported as
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	FormatXLSX  Format = "xlsx"
+)
+
+// ParseFormat reads the format query parameter, defaulting to CSV
+func ParseFormat(v string) (Format, error) {
+	switch Format(v) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatJSONL, FormatXLSX:
+		return Format(v), nil
+	}
+	return "", customerr.InvalidField("format", customerr.FieldInvalidValue, "format must be csv, jsonl or xlsx")
+}
+
+// ContentType is the media type of an export in format f
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSONL:
+		return "application/jsonl"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// header names the CSV and XLSX columns in the order record fills them
+var header = []string{"id", "name", "position", "salary", "currency", "hired_date", "department_id", "manager_id", "version", "created_at", "updated_at"}
+
+// Writer writes one employee per row. Flush completes the output after the
+// last row; Close releases the writer whether or not it was flushed.
+type Writer interface {
+	Write(emp database.EmployeeView) error
+	Flush() error
+	Close() error
+}
+
+// NewWriter returns a Writer for format writing to w. Output is buffered, so
+// nothing reaches w before the first rows have been written.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		out := csv.NewWriter(w)
+		if err := out.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvWriter{out: out}, nil
+	case FormatJSONL:
+		buf := bufio.NewWriter(w)
+		return &jsonlWriter{buf: buf, enc: json.NewEncoder(buf)}, nil
+	case FormatXLSX:
+		//IDs are the widest column
+		return newXLSXWriter(w, "Employees", header, 38)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+type csvWriter struct {
+	out *csv.Writer
+}
+
+func (c *csvWriter) Write(emp database.EmployeeView) error {
+	salary := ""
+	if emp.Salary != nil {
+		salary = emp.Salary.String()
+	}
+	return c.out.Write([]string{
+		emp.ID.String(),
+		emp.Name,
+		emp.Position,
+		salary,
+		emp.Currency,
+		emp.HiredDate.Format(time.DateOnly),
+		uuidString(emp.DepartmentID),
+		uuidString(emp.ManagerID),
+		strconv.FormatInt(emp.Version, 10),
+		emp.CreatedAt.Format(time.RFC3339),
+		emp.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	c.out.Flush()
+	return c.out.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return nil
+}
+
+// jsonlWriter writes each employee as it appears in the API, one per line
+type jsonlWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (j *jsonlWriter) Write(emp database.EmployeeView) error {
+	return j.enc.Encode(emp)
+}
+
+func (j *jsonlWriter) Flush() error {
+	return j.buf.Flush()
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
+
+func uuidString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,85 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/xuri/excelize/v2"
+)
+
+// timesheetHeader names the CSV and XLSX columns of a timesheet
+var timesheetHeader = []string{"period_start", "period_end", "days_worked", "shifts", "hours", "overtime_hours"}
+
+// TimesheetWriter writes one timesheet period per row, with the same Flush
+// and Close contract as Writer
+type TimesheetWriter interface {
+	Write(entry database.TimesheetEntry) error
+	Flush() error
+	Close() error
+}
+
+// NewTimesheetWriter returns a TimesheetWriter for format writing to w
+func NewTimesheetWriter(w io.Writer, format Format) (TimesheetWriter, error) {
+	switch format {
+	case FormatCSV:
+		out := csv.NewWriter(w)
+		if err := out.Write(timesheetHeader); err != nil {
+			return nil, err
+		}
+		return &timesheetCSV{&csvWriter{out: out}}, nil
+	case FormatJSONL:
+		buf := bufio.NewWriter(w)
+		return &timesheetJSONL{&jsonlWriter{buf: buf, enc: json.NewEncoder(buf)}}, nil
+	case FormatXLSX:
+		x, err := newXLSXWriter(w, "Timesheet", timesheetHeader, 12)
+		if err != nil {
+			return nil, err
+		}
+		return &timesheetXLSX{x}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+type timesheetCSV struct {
+	*csvWriter
+}
+
+func (c *timesheetCSV) Write(entry database.TimesheetEntry) error {
+	return c.out.Write([]string{
+		entry.PeriodStart.Format(time.DateOnly),
+		entry.PeriodEnd.Format(time.DateOnly),
+		strconv.FormatInt(entry.DaysWorked, 10),
+		strconv.FormatInt(entry.Shifts, 10),
+		strconv.FormatFloat(entry.Hours, 'f', -1, 64),
+		strconv.FormatFloat(entry.OvertimeHours, 'f', -1, 64),
+	})
+}
+
+type timesheetJSONL struct {
+	*jsonlWriter
+}
+
+func (j *timesheetJSONL) Write(entry database.TimesheetEntry) error {
+	return j.enc.Encode(entry)
+}
+
+type timesheetXLSX struct {
+	*xlsxWriter
+}
+
+func (x *timesheetXLSX) Write(entry database.TimesheetEntry) error {
+	return x.setRow([]interface{}{
+		excelize.Cell{StyleID: x.dateStyle, Value: entry.PeriodStart},
+		excelize.Cell{StyleID: x.dateStyle, Value: entry.PeriodEnd},
+		entry.DaysWorked,
+		entry.Shifts,
+		entry.Hours,
+		entry.OvertimeHours,
+	})
+}
@@ -0,0 +1,106 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxWriter streams rows into a workbook, which excelize spills to a
+// temporary file once it grows large. A workbook is a zip archive that can
+// only be written whole, so it reaches the output on Flush.
+type xlsxWriter struct {
+	out       io.Writer
+	file      *excelize.File
+	sheet     *excelize.StreamWriter
+	row       int
+	dateStyle int
+	timeStyle int
+}
+
+// newXLSXWriter starts a workbook with a single sheet whose first row is
+// header, widening the first column to firstWidth characters
+func newXLSXWriter(out io.Writer, sheet string, header []string, firstWidth float64) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	x := &xlsxWriter{out: out, file: file, row: 1}
+	if err := x.init(sheet, header, firstWidth); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) init(sheet string, header []string, firstWidth float64) error {
+	if err := x.file.SetSheetName(x.file.GetSheetName(0), sheet); err != nil {
+		return err
+	}
+	dateFormat, timeFormat := "yyyy-mm-dd", "yyyy-mm-dd hh:mm:ss"
+	var err error
+	if x.dateStyle, err = x.file.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat}); err != nil {
+		return err
+	}
+	if x.timeStyle, err = x.file.NewStyle(&excelize.Style{CustomNumFmt: &timeFormat}); err != nil {
+		return err
+	}
+	if x.sheet, err = x.file.NewStreamWriter(sheet); err != nil {
+		return err
+	}
+	if err := x.sheet.SetColWidth(1, 1, firstWidth); err != nil {
+		return err
+	}
+
+	cells := make([]interface{}, len(header))
+	for i, name := range header {
+		cells[i] = name
+	}
+	return x.setRow(cells)
+}
+
+func (x *xlsxWriter) Write(emp database.EmployeeView) error {
+	//a hidden salary is left as an empty cell rather than zero; spreadsheets
+	//keep numbers as floats, so the cell is too
+	var salary interface{}
+	if emp.Salary != nil {
+		salary = emp.Salary.Float64()
+	}
+	return x.setRow([]interface{}{
+		emp.ID.String(),
+		emp.Name,
+		emp.Position,
+		salary,
+		emp.Currency,
+		excelize.Cell{StyleID: x.dateStyle, Value: emp.HiredDate},
+		uuidString(emp.DepartmentID),
+		uuidString(emp.ManagerID),
+		emp.Version,
+		excelize.Cell{StyleID: x.timeStyle, Value: emp.CreatedAt},
+		excelize.Cell{StyleID: x.timeStyle, Value: emp.UpdatedAt},
+	})
+}
+
+func (x *xlsxWriter) setRow(cells []interface{}) error {
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	if err := x.sheet.SetRow(cell, cells); err != nil {
+		return fmt.Errorf("failed to write row %d: %w", x.row, err)
+	}
+	x.row++
+	return nil
+}
+
+func (x *xlsxWriter) Flush() error {
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
+
+// Close removes the temporary files of the workbook
+func (x *xlsxWriter) Close() error {
+	return x.file.Close()
+}
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/xuri/excelize/v2"
+)
+
+// Header names, matched after lower-casing and turning spaces and dashes into
+// underscores, so "Hired Date" maps to hired_date
+const (
+	colName         = "name"
+	colPosition     = "position"
+	colSalary       = "salary"
+	colCurrency     = "currency"
+	colHiredDate    = "hired_date"
+	colDepartmentID = "department_id"
+	colManagerID    = "manager_id"
+)
+
+var knownColumns = map[string]bool{
+	colName:         true,
+	colPosition:     true,
+	colSalary:       true,
+	colCurrency:     true,
+	colHiredDate:    true,
+	colDepartmentID: true,
+	colManagerID:    true,
+}
+
+// requiredColumns must be in every header; the others may be left out
+var requiredColumns = []string{colName, colPosition, colSalary}
+
+// columns maps the header of a sheet onto the fields of its rows
+type columns struct {
+	index   map[string]int
+	ignored []string
+}
+
+// parseHeader finds the known columns in header, every required one included
+func parseHeader(header []string, known map[string]bool, required []string) (*columns, error) {
+	cols := &columns{index: make(map[string]int)}
+	for i, raw := range header {
+		name := normalizeHeader(raw)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			cols.ignored = append(cols.ignored, strings.TrimSpace(raw))
+			continue
+		}
+		if _, dup := cols.index[name]; dup {
+			return nil, customerr.InvalidField(name, customerr.FieldInvalid, fmt.Sprintf("the header has more than one %s column", name))
+		}
+		cols.index[name] = i
+	}
+
+	var missing []customerr.FieldError
+	for _, name := range required {
+		if _, ok := cols.index[name]; !ok {
+			missing = append(missing, customerr.FieldError{Field: name, Code: customerr.FieldRequired, Message: "the header has no " + name + " column"})
+		}
+	}
+	if len(missing) > 0 {
+		return nil, customerr.InvalidFields(missing...)
+	}
+	return cols, nil
+}
+
+func normalizeHeader(raw string) string {
+	//spreadsheet programs often start CSV exports with a byte order mark
+	name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(raw, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+// value is the trimmed cell of row in the named column, empty when the
+// column or cell is missing
+func (c *columns) value(row []string, name string) string {
+	i, ok := c.index[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// decode maps row onto a create request. Cells that cannot be parsed are
+// reported as field errors and left at their zero value. serialDates accepts
+// dates stored as spreadsheet serial numbers.
+func (c *columns) decode(row []string, serialDates bool) (database.EmployeeCreateRequest, []customerr.FieldError) {
+	req := database.EmployeeCreateRequest{
+		Name:     c.value(row, colName),
+		Position: c.value(row, colPosition),
+		Currency: c.value(row, colCurrency),
+	}
+	var fields []customerr.FieldError
+
+	if v := c.value(row, colSalary); v != "" {
+		salary, err := money.Parse(v)
+		if err != nil {
+			fields = append(fields, customerr.FieldError{Field: colSalary, Code: customerr.FieldInvalid, Message: "salary must be a number"})
+		}
+		req.Salary = salary
+	}
+	if v := c.value(row, colHiredDate); v != "" {
+		hired, err := parseDate(v, serialDates)
+		if err != nil {
+			fields = append(fields, customerr.FieldError{Field: colHiredDate, Code: customerr.FieldInvalid, Message: "hired_date must be a date such as 2024-06-01"})
+		}
+		req.HiredDate = hired
+	}
+	for _, col := range []struct {
+		name string
+		dest **uuid.UUID
+	}{{colDepartmentID, &req.DepartmentID}, {colManagerID, &req.ManagerID}} {
+		v := c.value(row, col.name)
+		if v == "" {
+			continue
+		}
+		id, err := uuid.Parse(v)
+		if err != nil {
+			fields = append(fields, customerr.FieldError{Field: col.name, Code: customerr.FieldInvalidUUID, Message: col.name + " must be a valid UUID"})
+			continue
+		}
+		*col.dest = &id
+	}
+	return req, fields
+}
+
+// parseDate accepts YYYY-MM-DD, an RFC 3339 timestamp or, with serial set, a
+// spreadsheet date serial number
+func parseDate(v string, serial bool) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if serial {
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return excelize.ExcelDateToTime(n, false)
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q", v)
+}
+
+// blank reports whether every cell of row is empty
+func blank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+// Package importer loads employees from CSV and XLSX sheets. The first row is
+// a header naming employee fields; every following row is validated with the
+// API's rules and the valid ones are created through the employee service in
+// one transaction. Exchange rate sheets are read the same way by RateImporter.
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/service"
+)
+
+// maxReportedErrors caps the row errors listed in a report
+const maxReportedErrors = 1000
+
+// Validator checks a decoded row; the API's request validator implements it
+type Validator interface {
+	Validate(i interface{}) error
+}
+
+// Options controls one import
+type Options struct {
+	//Mode defaults to best effort: valid rows are kept when others fail
+	Mode database.BulkMode
+	//DryRun reports what would happen, including database errors, and keeps nothing
+	DryRun bool
+	Actor  *auth.Claims
+}
+
+type Importer struct {
+	service   service.EmployeeService
+	validator Validator
+}
+
+func New(service service.EmployeeService, validator Validator) *Importer {
+	return &Importer{service: service, validator: validator}
+}
+
+// Import reads the sheet in r row by row and creates an employee for each
+// valid row. Problems with the file itself, such as a missing column, are
+// returned as errors; problems with single rows are listed in the report.
+func (im *Importer) Import(ctx context.Context, r io.Reader, format Format, opts Options) (*database.ImportReport, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = database.BulkBestEffort
+	}
+	if mode != database.BulkAllOrNothing && mode != database.BulkBestEffort {
+		return nil, customerr.InvalidField("mode", customerr.FieldInvalidValue, "mode must be all_or_nothing or best_effort")
+	}
+
+	rows, err := NewRowReader(r, format)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	header, _, err := rows.Next()
+	if errors.Is(err, io.EOF) {
+		return nil, customerr.InvalidField("file", customerr.FieldRequired, "file has no header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols, err := parseHeader(header, knownColumns, requiredColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &database.ImportReport{
+		DryRun:         opts.DryRun,
+		Mode:           mode,
+		IgnoredColumns: cols.ignored,
+		Errors:         []database.ImportRowError{},
+	}
+	var emps []database.Employee
+	//rowNumbers[i] is the sheet row emps[i] was read from
+	var rowNumbers []int
+	for {
+		row, rowNumber, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blank(row) {
+			continue
+		}
+		report.Rows++
+
+		req, fields := cols.decode(row, format == FormatXLSX)
+		if fields, err = validateRow(im.validator, &req, fields); err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			report.Failed++
+			addRowError(report, rowNumber, customerr.InvalidFields(fields...))
+			continue
+		}
+		emps = append(emps, req.Employee())
+		rowNumbers = append(rowNumbers, rowNumber)
+	}
+
+	//an all-or-nothing import with an invalid row would only be rolled back
+	if len(emps) == 0 || (mode == database.BulkAllOrNothing && report.Failed > 0) {
+		return report, nil
+	}
+
+	result, err := im.service.ImportEmployees(ctx, emps, mode, opts.DryRun, opts.Actor)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range result.Results {
+		switch item.Status {
+		case database.BulkStatusCreated:
+			report.Imported++
+		case database.BulkStatusFailed:
+			report.Failed++
+			addRowError(report, rowNumbers[item.Index], item.Err)
+		}
+	}
+	return report, nil
+}
+
+// validateRow runs the API's rules over req and adds their field errors to
+// fields, except for fields whose cells could not be parsed at all
+func validateRow(validator Validator, req interface{}, fields []customerr.FieldError) ([]customerr.FieldError, error) {
+	err := validator.Validate(req)
+	if err == nil {
+		return fields, nil
+	}
+	var domainErr *customerr.Error
+	if !errors.As(err, &domainErr) || !errors.Is(err, customerr.ErrValidation) {
+		return nil, err
+	}
+
+	unparsed := make(map[string]bool, len(fields))
+	for _, fe := range fields {
+		unparsed[fe.Field] = true
+	}
+	for _, fe := range domainErr.Fields {
+		if !unparsed[fe.Field] {
+			fields = append(fields, fe)
+		}
+	}
+	return fields, nil
+}
+
+// addRowError lists err against row, one entry per invalid field
+func addRowError(report *database.ImportReport, row int, err error) {
+	problem := customerr.ProblemOf(err)
+	entries := []database.ImportRowError{{Row: row, Code: string(problem.Code), Message: problem.Detail}}
+	if len(problem.Errors) > 0 {
+		entries = entries[:0]
+		for _, fe := range problem.Errors {
+			entries = append(entries, database.ImportRowError{Row: row, Field: fe.Field, Code: fe.Code, Message: fe.Message})
+		}
+	}
+
+	for _, entry := range entries {
+		if len(report.Errors) == maxReportedErrors {
+			report.ErrorsTruncated = true
+			return
+		}
+		report.Errors = append(report.Errors, entry)
+	}
+}
@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/lijuuu/EmployeeManagement/service"
+)
+
+// Header names of an exchange rate sheet
+const (
+	colRate          = "rate"
+	colEffectiveDate = "effective_date"
+)
+
+// rateColumns are all required
+var rateColumns = []string{colCurrency, colRate, colEffectiveDate}
+
+var knownRateColumns = map[string]bool{
+	colCurrency:      true,
+	colRate:          true,
+	colEffectiveDate: true,
+}
+
+// RateImporter loads exchange rates from a sheet with currency, rate and
+// effective_date columns. Rates are few and must be right, so a sheet is
+// stored whole or not at all.
+type RateImporter struct {
+	service   service.ExchangeRateService
+	validator Validator
+}
+
+func NewRateImporter(service service.ExchangeRateService, validator Validator) *RateImporter {
+	return &RateImporter{service: service, validator: validator}
+}
+
+// Import reads the sheet in r and, when every row is valid, stores its rates
+// in one transaction; a dry run only checks them
+func (im *RateImporter) Import(ctx context.Context, r io.Reader, format Format, dryRun bool, actor *auth.Claims) (*database.ImportReport, error) {
+	rows, err := NewRowReader(r, format)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	header, _, err := rows.Next()
+	if errors.Is(err, io.EOF) {
+		return nil, customerr.InvalidField("file", customerr.FieldRequired, "file has no header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols, err := parseHeader(header, knownRateColumns, rateColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &database.ImportReport{
+		DryRun:         dryRun,
+		Mode:           database.BulkAllOrNothing,
+		IgnoredColumns: cols.ignored,
+		Errors:         []database.ImportRowError{},
+	}
+	var rates []database.ExchangeRate
+	for {
+		row, rowNumber, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blank(row) {
+			continue
+		}
+		report.Rows++
+
+		rate, fields := cols.decodeRate(row, format == FormatXLSX)
+		if fields, err = validateRow(im.validator, &rate, fields); err != nil {
+			return nil, err
+		}
+		rowErr := service.CheckExchangeRate(&rate)
+		if len(fields) > 0 {
+			rowErr = customerr.InvalidFields(fields...)
+		}
+		if rowErr != nil {
+			report.Failed++
+			addRowError(report, rowNumber, rowErr)
+			continue
+		}
+		rates = append(rates, rate)
+	}
+
+	if len(rates) == 0 || report.Failed > 0 {
+		return report, nil
+	}
+	if !dryRun {
+		if err := im.service.ImportRates(ctx, rates, actor); err != nil {
+			return nil, err
+		}
+	}
+	report.Imported = len(rates)
+	return report, nil
+}
+
+// decodeRate maps row onto an exchange rate, reporting cells that cannot be
+// parsed as field errors
+func (c *columns) decodeRate(row []string, serialDates bool) (database.ExchangeRate, []customerr.FieldError) {
+	rate := database.ExchangeRate{Currency: c.value(row, colCurrency)}
+	var fields []customerr.FieldError
+
+	if v := c.value(row, colRate); v != "" {
+		parsed, err := money.ParseRate(v)
+		if err != nil {
+			fields = append(fields, customerr.FieldError{Field: colRate, Code: customerr.FieldInvalid, Message: "rate must be a number with at most 10 decimal places"})
+		}
+		rate.Rate = parsed
+	}
+	if v := c.value(row, colEffectiveDate); v != "" {
+		effective, err := parseDate(v, serialDates)
+		if err != nil {
+			fields = append(fields, customerr.FieldError{Field: colEffectiveDate, Code: customerr.FieldInvalid, Message: "effective_date must be a date such as 2024-06-01"})
+		}
+		rate.EffectiveDate = effective
+	}
+	return rate, fields
+}
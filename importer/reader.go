@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/xuri/excelize/v2"
+)
+
+// Format is a sheet format the importer reads
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// FormatOf picks the format from a file name's extension
+func FormatOf(filename string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", customerr.InvalidField("file", customerr.FieldInvalidValue, "file must be a .csv or .xlsx sheet")
+}
+
+// RowReader yields the rows of a sheet one at a time along with their
+// 1-based row number in the sheet. Next returns io.EOF after the last row.
+type RowReader interface {
+	Next() ([]string, int, error)
+	Close() error
+}
+
+// NewRowReader reads the rows of r in the given format. CSV is read as it
+// arrives; an XLSX workbook has to be unzipped first, but its rows are still
+// decoded one at a time.
+func NewRowReader(r io.Reader, format Format) (RowReader, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		//rows may leave out trailing empty cells
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return &csvRows{reader: reader}, nil
+	case FormatXLSX:
+		return openXLSX(r)
+	}
+	return nil, fmt.Errorf("unsupported sheet format %q", format)
+}
+
+type csvRows struct {
+	reader *csv.Reader
+}
+
+func (c *csvRows) Next() ([]string, int, error) {
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		return nil, 0, err
+	}
+	if err != nil {
+		return nil, 0, customerr.InvalidField("file", customerr.FieldInvalid, err.Error())
+	}
+	//the reader skips empty lines, so count lines rather than records
+	line, _ := c.reader.FieldPos(0)
+	return record, line, nil
+}
+
+func (c *csvRows) Close() error {
+	return nil
+}
+
+type xlsxRows struct {
+	file *excelize.File
+	rows *excelize.Rows
+	line int
+}
+
+// openXLSX reads the first sheet of the workbook
+func openXLSX(r io.Reader) (*xlsxRows, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, customerr.InvalidField("file", customerr.FieldInvalid, "file is not a readable XLSX workbook")
+	}
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		file.Close()
+		return nil, customerr.InvalidField("file", customerr.FieldInvalid, "workbook has no sheets")
+	}
+	rows, err := file.Rows(sheets[0])
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read sheet %q: %w", sheets[0], err)
+	}
+	return &xlsxRows{file: file, rows: rows}, nil
+}
+
+func (x *xlsxRows) Next() ([]string, int, error) {
+	if !x.rows.Next() {
+		if err := x.rows.Error(); err != nil {
+			return nil, 0, customerr.InvalidField("file", customerr.FieldInvalid, err.Error())
+		}
+		return nil, 0, io.EOF
+	}
+	//the iterator yields missing rows as empty ones, so counting keeps step
+	x.line++
+	//raw values keep dates as serial numbers instead of the cell's display format
+	row, err := x.rows.Columns(excelize.Options{RawCellValue: true})
+	return row, x.line, err
+}
+
+func (x *xlsxRows) Close() error {
+	if err := x.rows.Close(); err != nil {
+		x.file.Close()
+		return err
+	}
+	return x.file.Close()
+}
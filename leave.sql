@@ -0,0 +1,102 @@
+-- name: CreateLeaveType :exec
+INSERT INTO leave_types (id, name, days_per_year, unpaid, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetLeaveType :one
+SELECT id, name, days_per_year, created_at, updated_at, unpaid
+FROM leave_types
+WHERE id = $1;
+
+-- name: ListLeaveTypes :many
+SELECT id, name, days_per_year, created_at, updated_at, unpaid
+FROM leave_types
+ORDER BY name;
+
+-- name: UpsertLeaveAccrual :exec
+-- sets the days accrued so far, creating the balance on first use
+INSERT INTO leave_balances (employee_id, leave_type_id, accrued, updated_at)
+VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+ON CONFLICT (employee_id, leave_type_id)
+DO UPDATE SET accrued = EXCLUDED.accrued, updated_at = EXCLUDED.updated_at;
+
+-- name: ListLeaveBalances :many
+-- pending sums the days of requests still awaiting a decision
+SELECT b.leave_type_id, t.name AS leave_type, b.accrued, b.used,
+       COALESCE((SELECT SUM(r.days) FROM leave_requests r
+                 WHERE r.employee_id = b.employee_id AND r.leave_type_id = b.leave_type_id
+                   AND r.status = 'pending'), 0)::float8 AS pending
+FROM leave_balances b
+JOIN leave_types t ON t.id = b.leave_type_id
+WHERE b.employee_id = $1
+ORDER BY t.name;
+
+-- name: GetLeaveBalanceForUpdate :one
+SELECT b.leave_type_id, t.name AS leave_type, b.accrued, b.used,
+       COALESCE((SELECT SUM(r.days) FROM leave_requests r
+                 WHERE r.employee_id = b.employee_id AND r.leave_type_id = b.leave_type_id
+                   AND r.status = 'pending'), 0)::float8 AS pending
+FROM leave_balances b
+JOIN leave_types t ON t.id = b.leave_type_id
+WHERE b.employee_id = $1 AND b.leave_type_id = $2
+FOR UPDATE OF b;
+
+-- name: AddLeaveUsed :execrows
+UPDATE leave_balances
+SET used = used + sqlc.arg(days), updated_at = CURRENT_TIMESTAMP
+WHERE employee_id = sqlc.arg(employee_id) AND leave_type_id = sqlc.arg(leave_type_id);
+
+-- name: CreateLeaveRequest :exec
+INSERT INTO leave_requests (id, employee_id, leave_type_id, start_date, end_date, days, reason, status, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: GetLeaveRequest :one
+SELECT id, employee_id, leave_type_id, start_date, end_date, days, reason, status,
+       decided_by, decided_by_email, decided_at, decision_note, created_at, updated_at
+FROM leave_requests
+WHERE id = $1;
+
+-- name: GetLeaveRequestForUpdate :one
+SELECT id, employee_id, leave_type_id, start_date, end_date, days, reason, status,
+       decided_by, decided_by_email, decided_at, decision_note, created_at, updated_at
+FROM leave_requests
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateLeaveRequestStatus :execrows
+UPDATE leave_requests
+SET status = $2, decided_by = $3, decided_by_email = $4, decided_at = $5, decision_note = $6, updated_at = $7
+WHERE id = $1;
+
+-- name: CountOverlappingLeave :one
+-- requests that still hold days and share at least one date with the range
+SELECT COUNT(*)
+FROM leave_requests
+WHERE employee_id = sqlc.arg(employee_id)
+  AND status IN ('pending', 'approved')
+  AND start_date <= sqlc.arg(end_date)::date
+  AND end_date >= sqlc.arg(start_date)::date;
+
+-- name: ListEmployeeLeaveRequests :many
+-- newest first; a null status lists every request
+SELECT id, employee_id, leave_type_id, start_date, end_date, days, reason, status,
+       decided_by, decided_by_email, decided_at, decision_note, created_at, updated_at
+FROM leave_requests
+WHERE employee_id = sqlc.arg(employee_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+ORDER BY start_date DESC, created_at DESC, id DESC;
+
+-- name: ListLeaveCalendar :many
+-- leave overlapping the range for the employees in a department or reporting
+-- to a manager; pending requests are included on request
+SELECT r.id, r.employee_id, e.name AS employee_name, r.leave_type_id, t.name AS leave_type,
+       r.start_date, r.end_date, r.days, r.status
+FROM leave_requests r
+JOIN employees e ON e.id = r.employee_id
+JOIN leave_types t ON t.id = r.leave_type_id
+WHERE e.deleted_at IS NULL
+  AND r.start_date <= sqlc.arg(to_date)::date
+  AND r.end_date >= sqlc.arg(from_date)::date
+  AND (r.status = 'approved' OR (sqlc.arg(include_pending)::bool AND r.status = 'pending'))
+  AND (sqlc.narg(department_id)::uuid IS NULL OR e.department_id = sqlc.narg(department_id)::uuid)
+  AND (sqlc.narg(manager_id)::uuid IS NULL OR e.manager_id = sqlc.narg(manager_id)::uuid)
+ORDER BY r.start_date, e.name, r.id;
@@ -1,57 +1,122 @@
 package middleware
 
 import (
+	"context"
 	"log"
-	"net/http"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
 	"github.com/lijuuu/EmployeeManagement/config"
 	customerr "github.com/lijuuu/EmployeeManagement/customerr"
 )
 
-//JWTAuthMiddleware validates JWT tokens for protected routes
-func JWTAuthMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+// claimsKey is the echo.Context key under which the caller's claims are stored
+const claimsKey = "claims"
+
+// RevocationChecker reports whether a token ID has been denylisted by logout
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+//JWTAuthMiddleware validates JWT tokens for protected routes, rejects revoked ones
+//and stores the caller's claims on the context
+func JWTAuthMiddleware(cfg *config.Config, revocations RevocationChecker) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			tokenString := c.Request().Header.Get("Authorization")
-			if tokenString == "" {
-				return customerr.NewError(c, http.StatusUnauthorized, "Missing Authorization header")
+			claims, err := authenticate(c, cfg, revocations)
+			if err != nil {
+				return customerr.Respond(c, err)
 			}
+			c.Set(claimsKey, claims)
+			return next(c)
+		}
+	}
+}
 
-			if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
-				tokenString = tokenString[7:]
-			} else {
-				return customerr.NewError(c, http.StatusUnauthorized, "Invalid Authorization header format")
+//OptionalJWTAuthMiddleware lets requests without an Authorization header through
+//anonymously; a header that is present must still carry a valid, unrevoked token
+func OptionalJWTAuthMiddleware(cfg *config.Config, revocations RevocationChecker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("Authorization") == "" {
+				return next(c)
+			}
+			claims, err := authenticate(c, cfg, revocations)
+			if err != nil {
+				return customerr.Respond(c, err)
 			}
+			c.Set(claimsKey, claims)
+			return next(c)
+		}
+	}
+}
+
+//authenticate checks the bearer token; on failure it returns the error to respond with
+func authenticate(c echo.Context, cfg *config.Config, revocations RevocationChecker) (*auth.Claims, error) {
+	tokenString := c.Request().Header.Get("Authorization")
+	if tokenString == "" {
+		return nil, customerr.New(customerr.CodeUnauthenticated, "Missing Authorization header")
+	}
 
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, customerr.NewError(c, http.StatusUnauthorized, "Invalid signing method")
-				}
-				return []byte(cfg.JWTSecret), nil
-			})
+	if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
+		tokenString = tokenString[7:]
+	} else {
+		return nil, customerr.New(customerr.CodeInvalidToken, "Invalid Authorization header format")
+	}
 
-			if err != nil || !token.Valid {
-				return customerr.NewError(c, http.StatusUnauthorized, "Invalid or expired token")
-			}
+	claims, err := auth.ParseToken(cfg.JWTSecret, tokenString)
+	if err != nil {
+		return nil, customerr.New(customerr.CodeInvalidToken, "Invalid or expired token")
+	}
+
+	revoked, err := revocations.IsRevoked(c.Request().Context(), claims.Id)
+	if err != nil {
+		log.Printf("Error: checking token revocation [%s]: %v", customerr.RequestID(c), err)
+		return nil, customerr.Wrap(customerr.ErrUnavailable, "Unable to verify token", err)
+	}
+	if revoked {
+		return nil, customerr.New(customerr.CodeInvalidToken, "Token has been revoked")
+	}
+	return claims, nil
+}
 
+//RequirePermission rejects callers whose role lacks perm; it must run after JWTAuthMiddleware
+func RequirePermission(perm auth.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := ClaimsFrom(c)
+			if claims == nil {
+				return customerr.Respond(c, customerr.New(customerr.CodeUnauthenticated, "Missing Authorization header"))
+			}
+			if !auth.Can(claims.Role, perm) {
+				return customerr.Respond(c, customerr.New(customerr.CodeForbidden, "Insufficient permissions"))
+			}
 			return next(c)
 		}
 	}
 }
 
+//ClaimsFrom returns the claims stored by JWTAuthMiddleware, or nil for anonymous requests
+func ClaimsFrom(c echo.Context) *auth.Claims {
+	claims, _ := c.Get(claimsKey).(*auth.Claims)
+	return claims
+}
+
 //RequestLoggerMiddleware logs incoming requests
 func RequestLoggerMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			start := time.Now()
-			log.Printf("Request: %s %s from %s", c.Request().Method, c.Request().URL.Path, c.RealIP())
-			err := next(c)
-			log.Printf("Response: %s %s - Status: %d, Duration: %v",
-				c.Request().Method, c.Request().URL.Path, c.Response().Status, time.Since(start))
-			return err
+			requestID := customerr.RequestID(c)
+			log.Printf("Request: %s %s from %s [%s]", c.Request().Method, c.Request().URL.Path, c.RealIP(), requestID)
+			//handle the error here so the logged status is the one sent
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			log.Printf("Response: %s %s [%s] - Status: %d, Duration: %v",
+				c.Request().Method, c.Request().URL.Path, requestID, c.Response().Status, time.Since(start))
+			return nil
 		}
 	}
 }
@@ -0,0 +1,3 @@
+ALTER TABLE employees DROP COLUMN IF EXISTS department_id;
+
+DROP TABLE IF EXISTS departments;
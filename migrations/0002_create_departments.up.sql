@@ -0,0 +1,12 @@
+CREATE TABLE departments (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE employees
+    ADD COLUMN department_id UUID REFERENCES departments (id) ON DELETE SET NULL;
+
+CREATE INDEX employees_department_id_idx ON employees (department_id);
@@ -0,0 +1 @@
+ALTER TABLE employees DROP COLUMN IF EXISTS manager_id;
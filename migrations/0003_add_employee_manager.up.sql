@@ -0,0 +1,5 @@
+ALTER TABLE employees
+    ADD COLUMN manager_id UUID REFERENCES employees (id) ON DELETE SET NULL,
+    ADD CONSTRAINT employees_manager_not_self CHECK (manager_id <> id);
+
+CREATE INDEX employees_manager_id_idx ON employees (manager_id);
@@ -0,0 +1,16 @@
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY,
+    -- actor is copied from the JWT rather than referenced, so entries outlive deleted users
+    actor_id UUID,
+    actor_email TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    -- changed fields only: {"field": {"before": ..., "after": ...}}
+    changes JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entity_type, entity_id, created_at);
+CREATE INDEX idx_audit_log_actor ON audit_log (actor_id, created_at);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
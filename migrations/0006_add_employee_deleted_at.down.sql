@@ -0,0 +1,4 @@
+-- soft-deleted rows would reappear once the column is gone, so purge them first
+DELETE FROM employees WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE employees DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,4 @@
+-- soft delete: rows with deleted_at set are hidden from every read until restored or purged
+ALTER TABLE employees ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX employees_deleted_at_idx ON employees (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- version is bumped by every write to a row and exposed as the employee's ETag
+ALTER TABLE employees ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS employees_position_trgm_idx;
+DROP INDEX IF EXISTS employees_name_trgm_idx;
+DROP INDEX IF EXISTS employees_search_idx;
+
+-- pg_trgm is left installed, as other schemas in the database may rely on it
@@ -0,0 +1,10 @@
+-- full-text and trigram search over employee names and positions
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- the indexed expression must stay identical to the one in SearchEmployees
+CREATE INDEX employees_search_idx ON employees USING GIN (
+    (setweight(to_tsvector('simple', name), 'A') || setweight(to_tsvector('simple', position), 'B'))
+) WHERE deleted_at IS NULL;
+
+CREATE INDEX employees_name_trgm_idx ON employees USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX employees_position_trgm_idx ON employees USING GIN (position gin_trgm_ops) WHERE deleted_at IS NULL;
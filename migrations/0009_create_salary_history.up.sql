@@ -0,0 +1,21 @@
+-- one row per salary an employee has had, from its effective date until the
+-- next row; employees.salary always equals the latest row
+CREATE TABLE salary_history (
+    id UUID PRIMARY KEY,
+    employee_id UUID NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    salary DOUBLE PRECISION NOT NULL,
+    effective_date DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    -- the approver is copied from the JWT like audit_log's actor, so records outlive deleted users
+    approved_by UUID,
+    approved_by_email TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_salary_history_employee ON salary_history (employee_id, effective_date, created_at);
+
+-- earlier salaries were never recorded, so existing employees start their
+-- history with the current salary as of today
+INSERT INTO salary_history (id, employee_id, salary, effective_date, reason)
+SELECT gen_random_uuid(), id, salary, CURRENT_DATE, 'Salary when history tracking began'
+FROM employees;
@@ -0,0 +1,46 @@
+-- kinds of leave and how many days of each an employee accrues per year worked
+CREATE TABLE leave_types (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    days_per_year DOUBLE PRECISION NOT NULL CHECK (days_per_year >= 0 AND days_per_year <= 366),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- accrued is recomputed from the employee's hired_date whenever the balance
+-- is read or drawn on; used counts the days of approved requests
+CREATE TABLE leave_balances (
+    employee_id UUID NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    leave_type_id UUID NOT NULL REFERENCES leave_types (id) ON DELETE CASCADE,
+    accrued DOUBLE PRECISION NOT NULL DEFAULT 0,
+    used DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (employee_id, leave_type_id)
+);
+
+CREATE TABLE leave_requests (
+    id UUID PRIMARY KEY,
+    employee_id UUID NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    leave_type_id UUID NOT NULL REFERENCES leave_types (id) ON DELETE RESTRICT,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    -- working days (Monday to Friday) between start_date and end_date inclusive
+    days DOUBLE PRECISION NOT NULL CHECK (days > 0),
+    reason TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    -- the decider is copied from the JWT like audit_log's actor, so requests outlive deleted users
+    decided_by UUID,
+    decided_by_email TEXT NOT NULL DEFAULT '',
+    decided_at TIMESTAMP,
+    decision_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_leave_requests_employee ON leave_requests (employee_id, start_date);
+CREATE INDEX idx_leave_requests_dates ON leave_requests (start_date, end_date) WHERE status IN ('pending', 'approved');
+
+INSERT INTO leave_types (id, name, days_per_year) VALUES
+    (gen_random_uuid(), 'Annual leave', 20),
+    (gen_random_uuid(), 'Sick leave', 10);
@@ -0,0 +1,14 @@
+-- one row per shift, opened by a clock-in and closed by a clock-out; times are UTC
+CREATE TABLE attendance_records (
+    id UUID PRIMARY KEY,
+    employee_id UUID NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    clock_in TIMESTAMP NOT NULL,
+    clock_out TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (clock_out > clock_in)
+);
+
+CREATE INDEX idx_attendance_employee ON attendance_records (employee_id, clock_in);
+-- an employee has at most one open shift
+CREATE UNIQUE INDEX idx_attendance_open_shift ON attendance_records (employee_id) WHERE clock_out IS NULL;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS payslips;
+DROP TABLE IF EXISTS payroll_runs;
+DROP TABLE IF EXISTS payroll_rules;
+DROP FUNCTION IF EXISTS payslips_guard();
+DROP FUNCTION IF EXISTS payroll_runs_guard();
+-- unpaid leave types that were requested stay, as ordinary leave
+DELETE FROM leave_types t
+WHERE t.unpaid AND NOT EXISTS (SELECT 1 FROM leave_requests r WHERE r.leave_type_id = t.id);
+ALTER TABLE leave_types DROP COLUMN IF EXISTS unpaid;
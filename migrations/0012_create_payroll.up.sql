@@ -0,0 +1,113 @@
+-- days of unpaid leave are deducted from pay by payroll runs
+ALTER TABLE leave_types ADD COLUMN unpaid BOOLEAN NOT NULL DEFAULT false;
+
+INSERT INTO leave_types (id, name, days_per_year, unpaid)
+VALUES (gen_random_uuid(), 'Unpaid leave', 30, true)
+ON CONFLICT (name) DO NOTHING;
+
+-- allowances added to and deductions taken from every payslip: a fixed
+-- amount per period, pro rata for the days paid, or a percent of gross pay
+CREATE TABLE payroll_rules (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    kind TEXT NOT NULL CHECK (kind IN ('allowance', 'deduction')),
+    calculation TEXT NOT NULL CHECK (calculation IN ('fixed', 'percent')),
+    amount DOUBLE PRECISION NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (calculation = 'fixed' OR amount <= 100)
+);
+
+-- one run per calendar month; the actors are copied from the JWT like
+-- audit_log's actor, so runs outlive deleted users
+CREATE TABLE payroll_runs (
+    id UUID PRIMARY KEY,
+    period_start DATE NOT NULL UNIQUE,
+    period_end DATE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'approved', 'finalized')),
+    employee_count INTEGER NOT NULL DEFAULT 0,
+    total_gross DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_allowances DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_deductions DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_net DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_by UUID,
+    created_by_email TEXT NOT NULL DEFAULT '',
+    approved_by UUID,
+    approved_by_email TEXT NOT NULL DEFAULT '',
+    approved_at TIMESTAMP,
+    finalized_by UUID,
+    finalized_by_email TEXT NOT NULL DEFAULT '',
+    finalized_at TIMESTAMP,
+    computed_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (period_end >= period_start)
+);
+
+-- employee_id has no foreign key and the name and position are copied, so
+-- payslips outlive purged employees
+CREATE TABLE payslips (
+    id UUID PRIMARY KEY,
+    run_id UUID NOT NULL REFERENCES payroll_runs (id),
+    employee_id UUID NOT NULL,
+    employee_name TEXT NOT NULL,
+    position TEXT NOT NULL,
+    annual_salary DOUBLE PRECISION NOT NULL,
+    working_days INTEGER NOT NULL,
+    paid_days INTEGER NOT NULL,
+    unpaid_leave_days INTEGER NOT NULL,
+    gross DOUBLE PRECISION NOT NULL,
+    allowances DOUBLE PRECISION NOT NULL,
+    deductions DOUBLE PRECISION NOT NULL,
+    net DOUBLE PRECISION NOT NULL,
+    lines JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (run_id, employee_id)
+);
+
+CREATE INDEX idx_payslips_employee ON payslips (employee_id);
+
+-- a finalized run and its payslips never change, and payslips are only
+-- written while their run is a draft; the service checks first, these stop
+-- anything that bypasses it
+CREATE FUNCTION payroll_runs_guard() RETURNS trigger AS $$
+BEGIN
+    IF OLD.status = 'finalized' THEN
+        RAISE EXCEPTION 'payroll run % is finalized', OLD.id USING ERRCODE = '55000';
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER payroll_runs_immutable
+BEFORE UPDATE OR DELETE ON payroll_runs
+FOR EACH ROW EXECUTE FUNCTION payroll_runs_guard();
+
+CREATE FUNCTION payslips_guard() RETURNS trigger AS $$
+DECLARE
+    run_status TEXT;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        RAISE EXCEPTION 'payslips cannot be changed' USING ERRCODE = '55000';
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        SELECT status INTO run_status FROM payroll_runs WHERE id = OLD.run_id;
+    ELSE
+        SELECT status INTO run_status FROM payroll_runs WHERE id = NEW.run_id;
+    END IF;
+    IF run_status <> 'draft' THEN
+        RAISE EXCEPTION 'payroll run is %, only draft runs can be recomputed', run_status USING ERRCODE = '55000';
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER payslips_immutable
+BEFORE INSERT OR UPDATE OR DELETE ON payslips
+FOR EACH ROW EXECUTE FUNCTION payslips_guard();
@@ -0,0 +1,38 @@
+-- currencies are dropped with their columns; amounts go back to doubles
+ALTER TABLE payroll_runs
+    ADD COLUMN total_gross DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN total_allowances DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN total_deductions DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN total_net DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- finalized runs are otherwise immutable
+ALTER TABLE payroll_runs DISABLE TRIGGER payroll_runs_immutable;
+UPDATE payroll_runs r
+SET total_gross = t.gross, total_allowances = t.allowances, total_deductions = t.deductions, total_net = t.net
+FROM (
+    SELECT run_id, SUM(gross)::float8 AS gross, SUM(allowances)::float8 AS allowances,
+        SUM(deductions)::float8 AS deductions, SUM(net)::float8 AS net
+    FROM payslips
+    GROUP BY run_id
+) t
+WHERE t.run_id = r.id;
+ALTER TABLE payroll_runs ENABLE TRIGGER payroll_runs_immutable;
+
+ALTER TABLE payslips
+    DROP COLUMN currency,
+    ALTER COLUMN annual_salary TYPE DOUBLE PRECISION,
+    ALTER COLUMN gross TYPE DOUBLE PRECISION,
+    ALTER COLUMN allowances TYPE DOUBLE PRECISION,
+    ALTER COLUMN deductions TYPE DOUBLE PRECISION,
+    ALTER COLUMN net TYPE DOUBLE PRECISION;
+
+ALTER TABLE payroll_rules DROP COLUMN currency;
+ALTER TABLE payroll_rules ALTER COLUMN amount TYPE DOUBLE PRECISION;
+
+ALTER TABLE salary_history
+    DROP COLUMN currency,
+    ALTER COLUMN salary TYPE DOUBLE PRECISION;
+
+ALTER TABLE employees
+    DROP COLUMN currency,
+    ALTER COLUMN salary TYPE DOUBLE PRECISION;
@@ -0,0 +1,66 @@
+-- salaries and pay become exact decimals in a currency. Each double is
+-- converted through its text form, the shortest one that reads back as the
+-- same double, so 72500.1 becomes 72500.1 rather than the binary value's
+-- long expansion. NUMERIC(18,4) holds the minor units of every currency;
+-- rather than round a value with more decimal places, the migration stops.
+DO $$
+DECLARE
+    col RECORD;
+    lossy BIGINT;
+BEGIN
+    FOR col IN SELECT * FROM (VALUES
+        ('employees', 'salary'),
+        ('salary_history', 'salary'),
+        ('payroll_rules', 'amount'),
+        ('payslips', 'annual_salary'),
+        ('payslips', 'gross'),
+        ('payslips', 'allowances'),
+        ('payslips', 'deductions'),
+        ('payslips', 'net')
+    ) AS c (tbl, name) LOOP
+        EXECUTE format('SELECT count(*) FROM %I WHERE %I::text::numeric <> round(%I::text::numeric, 4)', col.tbl, col.name, col.name)
+        INTO lossy;
+        IF lossy > 0 THEN
+            RAISE EXCEPTION '% %.% values have more than 4 decimal places; round them before migrating', lossy, col.tbl, col.name;
+        END IF;
+    END LOOP;
+END;
+$$;
+
+-- everything recorded so far was paid in the default currency
+ALTER TABLE employees
+    ALTER COLUMN salary TYPE NUMERIC(18, 4) USING salary::text::numeric,
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE salary_history
+    ALTER COLUMN salary TYPE NUMERIC(18, 4) USING salary::text::numeric,
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE salary_history ALTER COLUMN currency DROP DEFAULT;
+
+-- a fixed amount is in a currency and only paid to employees paid in it; a
+-- percentage applies to everyone
+ALTER TABLE payroll_rules
+    ALTER COLUMN amount TYPE NUMERIC(18, 4) USING amount::text::numeric,
+    ADD COLUMN currency TEXT NOT NULL DEFAULT '';
+UPDATE payroll_rules SET currency = 'USD' WHERE calculation = 'fixed';
+ALTER TABLE payroll_rules
+    ADD CHECK ((calculation = 'fixed' AND currency ~ '^[A-Z]{3}$') OR (calculation = 'percent' AND currency = ''));
+
+-- changing column types fires no row triggers, so finalized payslips can be
+-- converted too
+ALTER TABLE payslips
+    ALTER COLUMN annual_salary TYPE NUMERIC(18, 4) USING annual_salary::text::numeric,
+    ALTER COLUMN gross TYPE NUMERIC(18, 4) USING gross::text::numeric,
+    ALTER COLUMN allowances TYPE NUMERIC(18, 4) USING allowances::text::numeric,
+    ALTER COLUMN deductions TYPE NUMERIC(18, 4) USING deductions::text::numeric,
+    ALTER COLUMN net TYPE NUMERIC(18, 4) USING net::text::numeric,
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD' CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE payslips ALTER COLUMN currency DROP DEFAULT;
+
+-- a run's totals are summed from its payslips per currency, as one total
+-- across currencies means nothing
+ALTER TABLE payroll_runs
+    DROP COLUMN total_gross,
+    DROP COLUMN total_allowances,
+    DROP COLUMN total_deductions,
+    DROP COLUMN total_net;
@@ -0,0 +1,12 @@
+-- how many units of a currency one USD buys, from its effective date until
+-- the next rate for the currency; rates are loaded by hand or from a sheet,
+-- never from a live feed. USD itself is always 1 and has no rows.
+CREATE TABLE exchange_rates (
+    id UUID PRIMARY KEY,
+    currency TEXT NOT NULL CHECK (currency ~ '^[A-Z]{3}$' AND currency <> 'USD'),
+    rate NUMERIC(18, 10) NOT NULL CHECK (rate > 0),
+    effective_date DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (currency, effective_date)
+);
@@ -0,0 +1,5 @@
+-- the backfilled records go back to taking effect on the day 0009 ran
+UPDATE salary_history
+SET effective_date = created_at::date
+WHERE reason = 'Salary when history tracking began'
+  AND approved_by IS NULL;
@@ -0,0 +1,11 @@
+-- 0009 started the history of existing employees on the day it ran, so
+-- as-of lookups and payroll found no salary for the time they had already
+-- been employed. Their first record now takes effect on the hired date: the
+-- salary they had when tracking began is the best record of what came before.
+UPDATE salary_history h
+SET effective_date = e.hired_date
+FROM employees e
+WHERE e.id = h.employee_id
+  AND h.reason = 'Salary when history tracking began'
+  AND h.approved_by IS NULL
+  AND e.hired_date < h.effective_date;
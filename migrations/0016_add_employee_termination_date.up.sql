@@ -0,0 +1,9 @@
+-- Payroll used to treat the soft-delete timestamp as the day an employee left,
+-- so deleting a record by mistake cut their pay. termination_date records the
+-- last day employed explicitly; employees already deleted keep being paid up
+-- to their deletion, as before.
+ALTER TABLE employees ADD COLUMN termination_date DATE;
+
+UPDATE employees
+SET termination_date = GREATEST(deleted_at::date, hired_date)
+WHERE deleted_at IS NOT NULL;
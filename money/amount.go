@@ -0,0 +1,304 @@
+// Package money holds the exact decimal amounts salaries and pay are kept
+// in, and the ISO 4217 currencies they are paid in.
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Scale is the number of decimal places an Amount keeps: enough for the minor
+// units of every ISO 4217 currency
+const Scale = 4
+
+// MaxIntegerDigits is the number of digits an Amount may have before the
+// decimal point, as for a NUMERIC(18,4) column
+const MaxIntegerDigits = 14
+
+const unit = 10000
+
+var (
+	ErrSyntax     = errors.New("not a decimal number")
+	ErrPrecision  = errors.New("too many decimal places")
+	ErrOutOfRange = errors.New("too many digits before the decimal point")
+)
+
+// Amount is an exact decimal number with up to Scale decimal places; the zero
+// value is 0. It is stored as NUMERIC and written to JSON as a number in its
+// shortest exact form, so it never passes through a float on the way.
+type Amount struct {
+	//units is the value in 10^-Scale
+	units int64
+}
+
+// FromInt returns the whole amount n
+func FromInt(n int64) Amount {
+	return Amount{units: n * unit}
+}
+
+// Parse reads a decimal such as "-1234.5" or "1.25e3"
+func Parse(s string) (Amount, error) {
+	units, err := parseUnits(s, Scale, MaxIntegerDigits)
+	return Amount{units: units}, err
+}
+
+// parseUnits reads the decimal s in units of 10^-scale, allowing at most
+// intDigits digits before the decimal point
+func parseUnits(s string, scale, intDigits int) (int64, error) {
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || e > 100 || e < -100 {
+			return 0, ErrSyntax
+		}
+		exp, s = e, s[:i]
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, ErrSyntax
+	}
+
+	//move the decimal point by the exponent
+	digits := intPart + fracPart
+	point := len(intPart) + exp
+	if point < 0 {
+		digits = strings.Repeat("0", -point) + digits
+		point = 0
+	}
+	if point > len(digits) {
+		digits += strings.Repeat("0", point-len(digits))
+	}
+	intPart = strings.TrimLeft(digits[:point], "0")
+	fracPart = strings.TrimRight(digits[point:], "0")
+	if len(fracPart) > scale {
+		return 0, ErrPrecision
+	}
+	if len(intPart) > intDigits {
+		return 0, ErrOutOfRange
+	}
+
+	units, _ := strconv.ParseInt("0"+intPart+fracPart+strings.Repeat("0", scale-len(fracPart)), 10, 64)
+	if neg {
+		units = -units
+	}
+	return units, nil
+}
+
+// MustParse is Parse for constants; it panics on an invalid amount
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("money: parse %q: %v", s, err))
+	}
+	return a
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the shortest exact form, such as "72500.5" or "-3"
+func (a Amount) String() string {
+	return formatUnits(a.units, Scale)
+}
+
+// formatUnits writes units of 10^-scale in their shortest exact form
+func formatUnits(units int64, scale int) string {
+	sign := ""
+	if units < 0 {
+		sign, units = "-", -units
+	}
+	pow := int64(math.Pow10(scale))
+	s := strconv.FormatInt(units/pow, 10)
+	if frac := units % pow; frac != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%0*d", scale, frac), "0")
+	}
+	return sign + s
+}
+
+// StringFixed returns the amount with exactly places decimal places,
+// rounding half away from zero when it has more
+func (a Amount) StringFixed(places int) string {
+	rounded := a.Round(places)
+	s := rounded.String()
+	if places == 0 {
+		return s
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	return whole + "." + frac + strings.Repeat("0", places-len(frac))
+}
+
+// Float64 returns the nearest float, for display formats that need one
+func (a Amount) Float64() float64 {
+	f, _ := strconv.ParseFloat(a.String(), 64)
+	return f
+}
+
+// Places returns the number of decimal places needed to write the amount
+func (a Amount) Places() int {
+	frac := a.units % unit
+	places := Scale
+	for places > 0 && frac%10 == 0 {
+		frac /= 10
+		places--
+	}
+	return places
+}
+
+func (a Amount) IsZero() bool { return a.units == 0 }
+
+// Sign returns -1, 0 or 1
+func (a Amount) Sign() int {
+	switch {
+	case a.units < 0:
+		return -1
+	case a.units > 0:
+		return 1
+	}
+	return 0
+}
+
+// Cmp returns -1, 0 or 1 as a is less than, equal to or greater than b
+func (a Amount) Cmp(b Amount) int {
+	return Amount{units: a.units - b.units}.Sign()
+}
+
+func (a Amount) Add(b Amount) Amount { return Amount{units: a.units + b.units} }
+func (a Amount) Sub(b Amount) Amount { return Amount{units: a.units - b.units} }
+func (a Amount) Neg() Amount         { return Amount{units: -a.units} }
+
+// Round rounds to places decimal places, half away from zero
+func (a Amount) Round(places int) Amount {
+	return a.MulFrac(1, 1, places)
+}
+
+// MulFrac returns a × num / den rounded to places decimal places, half away
+// from zero. The product is exact, however large.
+func (a Amount) MulFrac(num, den int64, places int) Amount {
+	n := new(big.Int).Mul(big.NewInt(a.units), big.NewInt(num))
+	return rounded(n, big.NewInt(den), places)
+}
+
+// Percent returns p percent of a rounded to places decimal places, half away
+// from zero
+func (a Amount) Percent(p Amount, places int) Amount {
+	n := new(big.Int).Mul(big.NewInt(a.units), big.NewInt(p.units))
+	return rounded(n, big.NewInt(100*unit), places)
+}
+
+// Mul returns a × b rounded to places decimal places, half away from zero
+func (a Amount) Mul(b Amount, places int) Amount {
+	n := new(big.Int).Mul(big.NewInt(a.units), big.NewInt(b.units))
+	return rounded(n, big.NewInt(unit), places)
+}
+
+// rounded returns the amount of num / den units rounded to places decimal places
+func rounded(num, den *big.Int, places int) Amount {
+	places = min(max(places, 0), Scale)
+	step := big.NewInt(int64(math.Pow10(Scale - places)))
+	den = new(big.Int).Mul(den, step)
+	if den.Sign() < 0 {
+		num, den = new(big.Int).Neg(num), new(big.Int).Neg(den)
+	}
+
+	//half away from zero: add half the divisor to the magnitude, then truncate
+	q := new(big.Int).Abs(num)
+	q.Add(q.Mul(q, big.NewInt(2)), den)
+	q.Quo(q, new(big.Int).Mul(den, big.NewInt(2)))
+	if num.Sign() < 0 {
+		q.Neg(q)
+	}
+	return Amount{units: q.Mul(q, step).Int64()}
+}
+
+// MarshalJSON writes the amount as a JSON number
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number or a string holding one; null leaves the
+// amount unchanged
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	units, err := unmarshalUnits(data, Scale, MaxIntegerDigits)
+	if err != nil {
+		return fmt.Errorf("invalid amount %s: %w", data, err)
+	}
+	a.units = units
+	return nil
+}
+
+// unmarshalUnits reads a JSON number, or a string holding one, in units of
+// 10^-scale
+func unmarshalUnits(data []byte, scale, intDigits int) (int64, error) {
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return 0, err
+		}
+	}
+	return parseUnits(s, scale, intDigits)
+}
+
+// ScanNumeric reads a NUMERIC column
+func (a *Amount) ScanNumeric(n pgtype.Numeric) error {
+	units, err := scanUnits(n, Scale)
+	if err != nil {
+		return fmt.Errorf("cannot scan into money.Amount: %w", err)
+	}
+	a.units = units
+	return nil
+}
+
+// scanUnits reads a NUMERIC value in units of 10^-scale
+func scanUnits(n pgtype.Numeric, scale int) (int64, error) {
+	if !n.Valid {
+		return 0, errors.New("NULL")
+	}
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return 0, errors.New("not a finite number")
+	}
+
+	units := new(big.Int).Set(n.Int)
+	if exp := int(n.Exp) + scale; exp >= 0 {
+		units.Mul(units, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	} else {
+		var rem big.Int
+		units.QuoRem(units, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil), &rem)
+		if rem.Sign() != 0 {
+			return 0, fmt.Errorf("numeric %s: %w", n.Int, ErrPrecision)
+		}
+	}
+	if !units.IsInt64() {
+		return 0, ErrOutOfRange
+	}
+	return units.Int64(), nil
+}
+
+// NumericValue writes the amount to a NUMERIC column
+func (a Amount) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(a.units), Exp: -Scale, Valid: true}, nil
+}
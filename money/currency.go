@@ -0,0 +1,46 @@
+package money
+
+import "strings"
+
+// DefaultCurrency is the currency of employees created without one, and of
+// every salary recorded before currencies were
+const DefaultCurrency = "USD"
+
+// currencies maps the active ISO 4217 currency codes to the decimal places of
+// their minor unit. Funds and precious metals are left out: nobody is paid in
+// them.
+var currencies = map[string]int{}
+
+func init() {
+	for places, codes := range []string{
+		0: "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF",
+		2: "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BRL BSD BTN BWP BYN BZD " +
+			"CAD CDF CHF CNY COP CRC CUP CVE CZK DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD " +
+			"GTQ GYD HKD HNL HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL MAD " +
+			"MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN PGK PHP " +
+			"PKR PLN QAR RON RSD RUB SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS " +
+			"TMT TOP TRY TTD TWD TZS UAH USD UYU UZS VED VES WST XCD XCG YER ZAR ZMW ZWG",
+		3: "BHD IQD JOD KWD LYD OMR TND",
+		4: "CLF UYW",
+	} {
+		for _, code := range strings.Fields(codes) {
+			currencies[code] = places
+		}
+	}
+}
+
+// ValidCurrency reports whether code is an active ISO 4217 currency code, in
+// upper case
+func ValidCurrency(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
+
+// MinorUnits returns the decimal places of currency's minor unit, such as 2
+// for USD cents or 0 for JPY
+func MinorUnits(currency string) int {
+	if places, ok := currencies[currency]; ok {
+		return places
+	}
+	return 2
+}
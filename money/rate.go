@@ -0,0 +1,125 @@
+package money
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RateScale is the number of decimal places a Rate keeps
+const RateScale = 10
+
+// maxRateDigits is the number of digits a Rate may have before the decimal
+// point, as for a NUMERIC(18,10) column
+const maxRateDigits = 8
+
+const rateUnit = 10000000000
+
+// ErrNoRate is returned when converting from or to a currency without a rate
+var ErrNoRate = errors.New("no exchange rate")
+
+// Rate is how many units of a currency one unit of DefaultCurrency buys, such
+// as 0.9215 for EUR, kept exactly like an Amount
+type Rate struct {
+	//units is the rate in 10^-RateScale
+	units int64
+}
+
+// ParseRate reads a decimal rate such as "0.9215" or "149.62"
+func ParseRate(s string) (Rate, error) {
+	units, err := parseUnits(s, RateScale, maxRateDigits)
+	return Rate{units: units}, err
+}
+
+// MustParseRate is ParseRate for constants; it panics on an invalid rate
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(fmt.Sprintf("money: parse rate %q: %v", s, err))
+	}
+	return r
+}
+
+// String returns the shortest exact form, such as "0.9215"
+func (r Rate) String() string {
+	return formatUnits(r.units, RateScale)
+}
+
+// Float64 returns the nearest float, for validation rules that need one
+func (r Rate) Float64() float64 {
+	f, _ := strconv.ParseFloat(r.String(), 64)
+	return f
+}
+
+func (r Rate) IsZero() bool { return r.units == 0 }
+
+// MarshalJSON writes the rate as a JSON number
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number or a string holding one; null leaves the
+// rate unchanged
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	units, err := unmarshalUnits(data, RateScale, maxRateDigits)
+	if err != nil {
+		return fmt.Errorf("invalid rate %s: %w", data, err)
+	}
+	r.units = units
+	return nil
+}
+
+// ScanNumeric reads a NUMERIC column
+func (r *Rate) ScanNumeric(n pgtype.Numeric) error {
+	units, err := scanUnits(n, RateScale)
+	if err != nil {
+		return fmt.Errorf("cannot scan into money.Rate: %w", err)
+	}
+	r.units = units
+	return nil
+}
+
+// NumericValue writes the rate to a NUMERIC column
+func (r Rate) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(r.units), Exp: -RateScale, Valid: true}, nil
+}
+
+// Rates holds the rate of each currency against DefaultCurrency in effect on
+// one day; DefaultCurrency itself is always 1
+type Rates map[string]Rate
+
+// Convert returns a, which is in currency from, in currency to. The result
+// keeps Scale decimal places, so sums of converted amounts are rounded once
+// at the end.
+func (r Rates) Convert(a Amount, from, to string) (Amount, error) {
+	if from == to {
+		return a, nil
+	}
+	fromRate, err := r.rate(from)
+	if err != nil {
+		return Amount{}, err
+	}
+	toRate, err := r.rate(to)
+	if err != nil {
+		return Amount{}, err
+	}
+	n := new(big.Int).Mul(big.NewInt(a.units), big.NewInt(toRate.units))
+	return rounded(n, big.NewInt(fromRate.units), Scale), nil
+}
+
+func (r Rates) rate(currency string) (Rate, error) {
+	if currency == DefaultCurrency {
+		return Rate{units: rateUnit}, nil
+	}
+	if rate, ok := r[currency]; ok && rate.units > 0 {
+		return rate, nil
+	}
+	return Rate{}, fmt.Errorf("%w for %s", ErrNoRate, currency)
+}
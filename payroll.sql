@@ -0,0 +1,133 @@
+-- name: CreatePayrollRule :exec
+INSERT INTO payroll_rules (id, name, kind, calculation, amount, created_at, updated_at, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetPayrollRule :one
+SELECT id, name, kind, calculation, amount, created_at, updated_at, currency
+FROM payroll_rules
+WHERE id = $1;
+
+-- name: ListPayrollRules :many
+SELECT id, name, kind, calculation, amount, created_at, updated_at, currency
+FROM payroll_rules
+ORDER BY kind, name;
+
+-- name: UpdatePayrollRule :execrows
+UPDATE payroll_rules
+SET name = $2, kind = $3, calculation = $4, amount = $5, currency = $6, updated_at = $7
+WHERE id = $1;
+
+-- name: DeletePayrollRule :execrows
+DELETE FROM payroll_rules
+WHERE id = $1;
+
+-- name: ListPayrollEmployees :many
+-- everyone employed for part of the period. Deletion does not end employment:
+-- deleted employees are paid up to their termination date, and left out
+-- altogether when they have none, as records deleted without having left.
+SELECT id, name, position, salary, currency, hired_date, termination_date
+FROM employees
+WHERE hired_date <= sqlc.arg(period_end)::date
+  AND (termination_date IS NULL OR termination_date >= sqlc.arg(period_start)::date)
+  AND (deleted_at IS NULL OR termination_date IS NOT NULL)
+ORDER BY name, id;
+
+-- name: ListPayrollSalaries :many
+-- the salary records in effect by the end of the period, oldest first per employee
+SELECT h.employee_id, h.salary, h.currency, h.effective_date
+FROM salary_history h
+JOIN employees e ON e.id = h.employee_id
+WHERE h.effective_date <= sqlc.arg(period_end)::date
+  AND e.hired_date <= sqlc.arg(period_end)::date
+  AND (e.termination_date IS NULL OR e.termination_date >= sqlc.arg(period_start)::date)
+  AND (e.deleted_at IS NULL OR e.termination_date IS NOT NULL)
+ORDER BY h.employee_id, h.effective_date, h.created_at, h.id;
+
+-- name: ListUnpaidLeave :many
+-- approved unpaid leave overlapping the period
+SELECT r.employee_id, r.start_date, r.end_date
+FROM leave_requests r
+JOIN leave_types t ON t.id = r.leave_type_id
+WHERE t.unpaid AND r.status = 'approved'
+  AND r.start_date <= sqlc.arg(period_end)::date
+  AND r.end_date >= sqlc.arg(period_start)::date
+ORDER BY r.employee_id, r.start_date;
+
+-- name: CreatePayrollRun :exec
+INSERT INTO payroll_runs (id, period_start, period_end, status, employee_count, created_by, created_by_email,
+                          computed_at, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: GetPayrollRun :one
+SELECT id, period_start, period_end, status, employee_count, created_by, created_by_email, approved_by,
+       approved_by_email, approved_at, finalized_by, finalized_by_email, finalized_at, computed_at, created_at,
+       updated_at
+FROM payroll_runs
+WHERE id = $1;
+
+-- name: GetPayrollRunForUpdate :one
+SELECT id, period_start, period_end, status, employee_count, created_by, created_by_email, approved_by,
+       approved_by_email, approved_at, finalized_by, finalized_by_email, finalized_at, computed_at, created_at,
+       updated_at
+FROM payroll_runs
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListPayrollRuns :many
+SELECT id, period_start, period_end, status, employee_count, created_by, created_by_email, approved_by,
+       approved_by_email, approved_at, finalized_by, finalized_by_email, finalized_at, computed_at, created_at,
+       updated_at
+FROM payroll_runs
+ORDER BY period_start DESC;
+
+-- name: UpdatePayrollRunComputed :execrows
+UPDATE payroll_runs
+SET employee_count = $2, computed_at = $3, updated_at = $3
+WHERE id = $1 AND status = 'draft';
+
+-- name: ListPayrollRunTotals :many
+-- the payslips of each run summed per currency
+SELECT run_id, currency, COUNT(*)::int AS employee_count, SUM(gross)::numeric AS gross,
+       SUM(allowances)::numeric AS allowances, SUM(deductions)::numeric AS deductions, SUM(net)::numeric AS net
+FROM payslips
+WHERE run_id = ANY(sqlc.arg(run_ids)::uuid[])
+GROUP BY run_id, currency
+ORDER BY run_id, currency;
+
+-- name: ApprovePayrollRun :execrows
+UPDATE payroll_runs
+SET status = 'approved', approved_by = $2, approved_by_email = $3, approved_at = $4, updated_at = $4
+WHERE id = $1 AND status = 'draft';
+
+-- name: FinalizePayrollRun :execrows
+UPDATE payroll_runs
+SET status = 'finalized', finalized_by = $2, finalized_by_email = $3, finalized_at = $4, updated_at = $4
+WHERE id = $1 AND status = 'approved';
+
+-- name: CreatePayslip :exec
+INSERT INTO payslips (id, run_id, employee_id, employee_name, position, annual_salary, working_days, paid_days,
+                      unpaid_leave_days, gross, allowances, deductions, net, lines, created_at, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16);
+
+-- name: DeletePayslips :exec
+DELETE FROM payslips
+WHERE run_id = $1;
+
+-- name: ListRunPayslips :many
+SELECT p.id, p.run_id, r.period_start, r.period_end, r.status, p.employee_id, p.employee_name, p.position,
+       p.annual_salary, p.working_days, p.paid_days, p.unpaid_leave_days, p.gross, p.allowances,
+       p.deductions, p.net, p.lines, p.created_at, p.currency
+FROM payslips p
+JOIN payroll_runs r ON r.id = p.run_id
+WHERE p.run_id = $1
+ORDER BY p.employee_name, p.employee_id;
+
+-- name: ListEmployeePayslips :many
+-- only finalized runs, whose payslips have been paid out
+SELECT p.id, p.run_id, r.period_start, r.period_end, r.status, p.employee_id, p.employee_name, p.position,
+       p.annual_salary, p.working_days, p.paid_days, p.unpaid_leave_days, p.gross, p.allowances,
+       p.deductions, p.net, p.lines, p.created_at, p.currency
+FROM payslips p
+JOIN payroll_runs r ON r.id = p.run_id
+WHERE p.employee_id = $1 AND r.status = 'finalized'
+ORDER BY r.period_start DESC;
@@ -0,0 +1,151 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+type AttendanceRepo interface {
+	//ClockIn opens a shift for the employee starting at rec.ClockIn
+	ClockIn(ctx context.Context, rec *database.AttendanceRecord) error
+	//GetOpenShiftForUpdate returns the employee's open shift, locked until the transaction ends, or nil when there is none
+	GetOpenShiftForUpdate(ctx context.Context, employeeID uuid.UUID) (*database.AttendanceRecord, error)
+	//CountShiftsEndingAfter counts the employee's shifts that are open or end after at
+	CountShiftsEndingAfter(ctx context.Context, employeeID uuid.UUID, at time.Time) (int64, error)
+	//ClockOut closes the shift at rec.ClockOut
+	ClockOut(ctx context.Context, rec *database.AttendanceRecord) error
+	//ListAttendance returns the shifts starting from from up to but excluding to, earliest first
+	ListAttendance(ctx context.Context, employeeID uuid.UUID, from, to time.Time) ([]database.AttendanceRecord, error)
+	//AggregateTimesheet sums the completed shifts starting from from up to but excluding to per period
+	AggregateTimesheet(ctx context.Context, employeeID uuid.UUID, period string, standardHours float64, from, to time.Time) ([]database.TimesheetEntry, error)
+
+	//WithTx returns a repo whose queries run inside tx
+	WithTx(tx pgx.Tx) AttendanceRepo
+}
+
+type attendanceRepo struct {
+	queries *Queries
+}
+
+func NewAttendanceRepo(db DBTX) AttendanceRepo {
+	return &attendanceRepo{
+		queries: New(db),
+	}
+}
+
+func (r *attendanceRepo) WithTx(tx pgx.Tx) AttendanceRepo {
+	return &attendanceRepo{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
+func (r *attendanceRepo) ClockIn(ctx context.Context, rec *database.AttendanceRecord) error {
+	rec.ID = uuid.New()
+	err := r.queries.CreateClockIn(ctx, CreateClockInParams{
+		ID:         rec.ID,
+		EmployeeID: rec.EmployeeID,
+		ClockIn:    pgtype.Timestamp{Time: rec.ClockIn, Valid: true},
+		CreatedAt:  pgtype.Timestamp{Time: rec.CreatedAt, Valid: true},
+		UpdatedAt:  pgtype.Timestamp{Time: rec.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "create", "attendance record")
+	}
+	return nil
+}
+
+func (r *attendanceRepo) GetOpenShiftForUpdate(ctx context.Context, employeeID uuid.UUID) (*database.AttendanceRecord, error) {
+	row, err := r.queries.GetOpenShiftForUpdate(ctx, employeeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, dbError(err, "get", "attendance record")
+	}
+	rec := toAttendanceRecord(row)
+	return &rec, nil
+}
+
+func (r *attendanceRepo) CountShiftsEndingAfter(ctx context.Context, employeeID uuid.UUID, at time.Time) (int64, error) {
+	count, err := r.queries.CountShiftsEndingAfter(ctx, CountShiftsEndingAfterParams{
+		EmployeeID: employeeID,
+		At:         pgtype.Timestamp{Time: at, Valid: true},
+	})
+	if err != nil {
+		return 0, dbError(err, "count", "attendance records")
+	}
+	return count, nil
+}
+
+func (r *attendanceRepo) ClockOut(ctx context.Context, rec *database.AttendanceRecord) error {
+	rows, err := r.queries.SetClockOut(ctx, SetClockOutParams{
+		ID:        rec.ID,
+		ClockOut:  pgtype.Timestamp{Time: *rec.ClockOut, Valid: true},
+		UpdatedAt: pgtype.Timestamp{Time: rec.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "update", "attendance record")
+	}
+	if rows == 0 {
+		return customerr.NotFound("attendance record not found")
+	}
+	return nil
+}
+
+func (r *attendanceRepo) ListAttendance(ctx context.Context, employeeID uuid.UUID, from, to time.Time) ([]database.AttendanceRecord, error) {
+	rows, err := r.queries.ListAttendance(ctx, ListAttendanceParams{
+		EmployeeID: employeeID,
+		FromTime:   pgtype.Timestamp{Time: from, Valid: true},
+		ToTime:     pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, dbError(err, "list", "attendance records")
+	}
+	records := make([]database.AttendanceRecord, len(rows))
+	for i, row := range rows {
+		records[i] = toAttendanceRecord(row)
+	}
+	return records, nil
+}
+
+func (r *attendanceRepo) AggregateTimesheet(ctx context.Context, employeeID uuid.UUID, period string, standardHours float64, from, to time.Time) ([]database.TimesheetEntry, error) {
+	rows, err := r.queries.AggregateTimesheet(ctx, AggregateTimesheetParams{
+		Period:        period,
+		StandardHours: standardHours,
+		EmployeeID:    employeeID,
+		FromTime:      pgtype.Timestamp{Time: from, Valid: true},
+		ToTime:        pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, dbError(err, "aggregate", "timesheet")
+	}
+	entries := make([]database.TimesheetEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = database.TimesheetEntry{
+			PeriodStart:   row.PeriodStart.Time,
+			DaysWorked:    row.DaysWorked,
+			Shifts:        row.Shifts,
+			Hours:         row.Hours,
+			OvertimeHours: row.OvertimeHours,
+		}
+	}
+	return entries, nil
+}
+
+func toAttendanceRecord(row AttendanceRecord) database.AttendanceRecord {
+	return database.AttendanceRecord{
+		ID:         row.ID,
+		EmployeeID: row.EmployeeID,
+		ClockIn:    row.ClockIn.Time,
+		ClockOut:   timePtr(row.ClockOut),
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,213 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: attendance.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const aggregateTimesheet = `-- name: AggregateTimesheet :many
+WITH days AS (
+    SELECT clock_in::date AS day,
+           COUNT(*) AS shifts,
+           (SUM(EXTRACT(EPOCH FROM clock_out - clock_in)) / 3600)::float8 AS hours
+    FROM attendance_records
+    WHERE employee_id = $3
+      AND clock_out IS NOT NULL
+      AND clock_in >= $4::timestamp
+      AND clock_in < $5::timestamp
+    GROUP BY clock_in::date
+)
+SELECT date_trunc($1::text, day)::date AS period_start,
+       COUNT(*) AS days_worked,
+       SUM(shifts)::bigint AS shifts,
+       ROUND(SUM(hours)::numeric, 2)::float8 AS hours,
+       ROUND(SUM(GREATEST(hours - $2::float8, 0))::numeric, 2)::float8 AS overtime_hours
+FROM days
+GROUP BY 1
+ORDER BY 1
+`
+
+type AggregateTimesheetParams struct {
+	Period        string           `json:"period"`
+	StandardHours float64          `json:"standard_hours"`
+	EmployeeID    uuid.UUID        `json:"employee_id"`
+	FromTime      pgtype.Timestamp `json:"from_time"`
+	ToTime        pgtype.Timestamp `json:"to_time"`
+}
+
+type AggregateTimesheetRow struct {
+	PeriodStart   pgtype.Date `json:"period_start"`
+	DaysWorked    int64       `json:"days_worked"`
+	Shifts        int64       `json:"shifts"`
+	Hours         float64     `json:"hours"`
+	OvertimeHours float64     `json:"overtime_hours"`
+}
+
+// completed shifts are summed per day they start on, then per day, week
+// (from Monday) or month; overtime is each day's hours beyond standard_hours
+func (q *Queries) AggregateTimesheet(ctx context.Context, arg AggregateTimesheetParams) ([]AggregateTimesheetRow, error) {
+	rows, err := q.db.Query(ctx, aggregateTimesheet,
+		arg.Period,
+		arg.StandardHours,
+		arg.EmployeeID,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AggregateTimesheetRow
+	for rows.Next() {
+		var i AggregateTimesheetRow
+		if err := rows.Scan(
+			&i.PeriodStart,
+			&i.DaysWorked,
+			&i.Shifts,
+			&i.Hours,
+			&i.OvertimeHours,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countShiftsEndingAfter = `-- name: CountShiftsEndingAfter :one
+SELECT COUNT(*)
+FROM attendance_records
+WHERE employee_id = $1
+  AND (clock_out IS NULL OR clock_out > $2::timestamp)
+`
+
+type CountShiftsEndingAfterParams struct {
+	EmployeeID uuid.UUID        `json:"employee_id"`
+	At         pgtype.Timestamp `json:"at"`
+}
+
+// shifts still open or ending after a time, which a shift starting then would overlap
+func (q *Queries) CountShiftsEndingAfter(ctx context.Context, arg CountShiftsEndingAfterParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countShiftsEndingAfter, arg.EmployeeID, arg.At)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createClockIn = `-- name: CreateClockIn :exec
+INSERT INTO attendance_records (id, employee_id, clock_in, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateClockInParams struct {
+	ID         uuid.UUID        `json:"id"`
+	EmployeeID uuid.UUID        `json:"employee_id"`
+	ClockIn    pgtype.Timestamp `json:"clock_in"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) CreateClockIn(ctx context.Context, arg CreateClockInParams) error {
+	_, err := q.db.Exec(ctx, createClockIn,
+		arg.ID,
+		arg.EmployeeID,
+		arg.ClockIn,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const getOpenShiftForUpdate = `-- name: GetOpenShiftForUpdate :one
+SELECT id, employee_id, clock_in, clock_out, created_at, updated_at
+FROM attendance_records
+WHERE employee_id = $1 AND clock_out IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetOpenShiftForUpdate(ctx context.Context, employeeID uuid.UUID) (AttendanceRecord, error) {
+	row := q.db.QueryRow(ctx, getOpenShiftForUpdate, employeeID)
+	var i AttendanceRecord
+	err := row.Scan(
+		&i.ID,
+		&i.EmployeeID,
+		&i.ClockIn,
+		&i.ClockOut,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listAttendance = `-- name: ListAttendance :many
+SELECT id, employee_id, clock_in, clock_out, created_at, updated_at
+FROM attendance_records
+WHERE employee_id = $1
+  AND clock_in >= $2::timestamp
+  AND clock_in < $3::timestamp
+ORDER BY clock_in
+`
+
+type ListAttendanceParams struct {
+	EmployeeID uuid.UUID        `json:"employee_id"`
+	FromTime   pgtype.Timestamp `json:"from_time"`
+	ToTime     pgtype.Timestamp `json:"to_time"`
+}
+
+// shifts starting on or after from_time and before to_time, earliest first
+func (q *Queries) ListAttendance(ctx context.Context, arg ListAttendanceParams) ([]AttendanceRecord, error) {
+	rows, err := q.db.Query(ctx, listAttendance, arg.EmployeeID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AttendanceRecord
+	for rows.Next() {
+		var i AttendanceRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.EmployeeID,
+			&i.ClockIn,
+			&i.ClockOut,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setClockOut = `-- name: SetClockOut :execrows
+UPDATE attendance_records
+SET clock_out = $2, updated_at = $3
+WHERE id = $1
+`
+
+type SetClockOutParams struct {
+	ID        uuid.UUID        `json:"id"`
+	ClockOut  pgtype.Timestamp `json:"clock_out"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) SetClockOut(ctx context.Context, arg SetClockOutParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setClockOut, arg.ID, arg.ClockOut, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+type AuditRepo interface {
+	CreateEntry(ctx context.Context, entry *database.AuditEntry) error
+	ListEntries(ctx context.Context, filter database.AuditFilter) (*database.AuditPage, error)
+
+	//WithTx returns a repo whose queries run inside tx
+	WithTx(tx pgx.Tx) AuditRepo
+}
+
+type auditRepo struct {
+	queries *Queries
+}
+
+func NewAuditRepo(db DBTX) AuditRepo {
+	return &auditRepo{
+		queries: New(db),
+	}
+}
+
+func (r *auditRepo) WithTx(tx pgx.Tx) AuditRepo {
+	return &auditRepo{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
+func (r *auditRepo) CreateEntry(ctx context.Context, entry *database.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit changes: %v", err)
+	}
+
+	entry.ID = uuid.New()
+	err = r.queries.CreateAuditEntry(ctx, CreateAuditEntryParams{
+		ID:         entry.ID,
+		ActorID:    pgUUID(entry.ActorID),
+		ActorEmail: entry.ActorEmail,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Changes:    changes,
+		CreatedAt:  pgtype.Timestamp{Time: entry.CreatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "create", "audit entry")
+	}
+	return nil
+}
+
+func (r *auditRepo) ListEntries(ctx context.Context, filter database.AuditFilter) (*database.AuditPage, error) {
+	params := ListAuditEntriesParams{
+		ActorID:    pgUUID(filter.ActorID),
+		Action:     pgText(filter.Action),
+		EntityType: pgText(filter.EntityType),
+		EntityID:   pgUUID(filter.EntityID),
+		PageLimit:  int32(filter.Limit),
+		PageOffset: int32(filter.Offset),
+	}
+	if filter.From != nil {
+		params.CreatedFrom = pgtype.Timestamp{Time: *filter.From, Valid: true}
+	}
+	if filter.To != nil {
+		params.CreatedTo = pgtype.Timestamp{Time: *filter.To, Valid: true}
+	}
+
+	rows, err := r.queries.ListAuditEntries(ctx, params)
+	if err != nil {
+		return nil, dbError(err, "list", "audit entries")
+	}
+
+	total, err := r.queries.CountAuditEntries(ctx, CountAuditEntriesParams{
+		ActorID:     params.ActorID,
+		Action:      params.Action,
+		EntityType:  params.EntityType,
+		EntityID:    params.EntityID,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+	})
+	if err != nil {
+		return nil, dbError(err, "count", "audit entries")
+	}
+
+	entries := make([]database.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		var changes map[string]database.FieldChange
+		if err := json.Unmarshal(row.Changes, &changes); err != nil {
+			return nil, fmt.Errorf("failed to decode audit changes: %v", err)
+		}
+		entries = append(entries, database.AuditEntry{
+			ID:         row.ID,
+			ActorID:    uuidPtr(row.ActorID),
+			ActorEmail: row.ActorEmail,
+			Action:     row.Action,
+			EntityType: row.EntityType,
+			EntityID:   row.EntityID,
+			Changes:    changes,
+			CreatedAt:  row.CreatedAt.Time,
+		})
+	}
+	return &database.AuditPage{Entries: entries, Total: total}, nil
+}
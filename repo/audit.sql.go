@@ -0,0 +1,140 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: audit.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countAuditEntries = `-- name: CountAuditEntries :one
+SELECT COUNT(*)
+FROM audit_log
+WHERE ($1::uuid IS NULL OR actor_id = $1::uuid)
+  AND ($2::text IS NULL OR action = $2::text)
+  AND ($3::text IS NULL OR entity_type = $3::text)
+  AND ($4::uuid IS NULL OR entity_id = $4::uuid)
+  AND ($5::timestamp IS NULL OR created_at >= $5::timestamp)
+  AND ($6::timestamp IS NULL OR created_at <= $6::timestamp)
+`
+
+type CountAuditEntriesParams struct {
+	ActorID     pgtype.UUID      `json:"actor_id"`
+	Action      pgtype.Text      `json:"action"`
+	EntityType  pgtype.Text      `json:"entity_type"`
+	EntityID    pgtype.UUID      `json:"entity_id"`
+	CreatedFrom pgtype.Timestamp `json:"created_from"`
+	CreatedTo   pgtype.Timestamp `json:"created_to"`
+}
+
+func (q *Queries) CountAuditEntries(ctx context.Context, arg CountAuditEntriesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countAuditEntries,
+		arg.ActorID,
+		arg.Action,
+		arg.EntityType,
+		arg.EntityID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAuditEntry = `-- name: CreateAuditEntry :exec
+INSERT INTO audit_log (id, actor_id, actor_email, action, entity_type, entity_id, changes, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type CreateAuditEntryParams struct {
+	ID         uuid.UUID        `json:"id"`
+	ActorID    pgtype.UUID      `json:"actor_id"`
+	ActorEmail string           `json:"actor_email"`
+	Action     string           `json:"action"`
+	EntityType string           `json:"entity_type"`
+	EntityID   uuid.UUID        `json:"entity_id"`
+	Changes    []byte           `json:"changes"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+func (q *Queries) CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) error {
+	_, err := q.db.Exec(ctx, createAuditEntry,
+		arg.ID,
+		arg.ActorID,
+		arg.ActorEmail,
+		arg.Action,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Changes,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const listAuditEntries = `-- name: ListAuditEntries :many
+SELECT id, actor_id, actor_email, action, entity_type, entity_id, changes, created_at
+FROM audit_log
+WHERE ($1::uuid IS NULL OR actor_id = $1::uuid)
+  AND ($2::text IS NULL OR action = $2::text)
+  AND ($3::text IS NULL OR entity_type = $3::text)
+  AND ($4::uuid IS NULL OR entity_id = $4::uuid)
+  AND ($5::timestamp IS NULL OR created_at >= $5::timestamp)
+  AND ($6::timestamp IS NULL OR created_at <= $6::timestamp)
+ORDER BY created_at DESC, id DESC
+LIMIT $8
+OFFSET $7
+`
+
+type ListAuditEntriesParams struct {
+	ActorID     pgtype.UUID      `json:"actor_id"`
+	Action      pgtype.Text      `json:"action"`
+	EntityType  pgtype.Text      `json:"entity_type"`
+	EntityID    pgtype.UUID      `json:"entity_id"`
+	CreatedFrom pgtype.Timestamp `json:"created_from"`
+	CreatedTo   pgtype.Timestamp `json:"created_to"`
+	PageOffset  int32            `json:"page_offset"`
+	PageLimit   int32            `json:"page_limit"`
+}
+
+func (q *Queries) ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, listAuditEntries,
+		arg.ActorID,
+		arg.Action,
+		arg.EntityType,
+		arg.EntityID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.ActorEmail,
+			&i.Action,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Changes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
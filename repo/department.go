@@ -0,0 +1,215 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+type DepartmentRepo interface {
+	CreateDepartment(ctx context.Context, dept *database.Department) (uuid.UUID, error)
+	GetDepartmentByID(ctx context.Context, id uuid.UUID) (*database.Department, error)
+	UpdateDepartment(ctx context.Context, id uuid.UUID, dept *database.Department) error
+	//DeleteDepartment returns the members it left without a department. It and
+	//the other membership changes must run in a transaction, as they lock the
+	//rows they read before changing them.
+	DeleteDepartment(ctx context.Context, id uuid.UUID) ([]MemberChange, error)
+	ListDepartments(ctx context.Context) ([]database.Department, error)
+	//MoveEmployees returns the employees that exist and were moved
+	MoveEmployees(ctx context.Context, departmentID uuid.UUID, employeeIDs []uuid.UUID) ([]MemberChange, error)
+	RemoveEmployee(ctx context.Context, departmentID, employeeID uuid.UUID) (*MemberChange, error)
+	//ListDepartmentCosts sums the annual salaries of every department's current employees per currency
+	ListDepartmentCosts(ctx context.Context) ([]database.DepartmentCost, error)
+
+	//WithTx returns a repo whose queries run inside tx
+	WithTx(tx pgx.Tx) DepartmentRepo
+}
+
+// MemberChange is an employee before and after its department changed
+type MemberChange struct {
+	Before database.Employee
+	After  database.Employee
+}
+
+type departmentRepo struct {
+	queries *Queries
+}
+
+func NewDepartmentRepo(db DBTX) DepartmentRepo {
+	return &departmentRepo{
+		queries: New(db),
+	}
+}
+
+func (r *departmentRepo) WithTx(tx pgx.Tx) DepartmentRepo {
+	return &departmentRepo{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
+func (r *departmentRepo) CreateDepartment(ctx context.Context, dept *database.Department) (uuid.UUID, error) {
+	id := uuid.New()
+	_, err := r.queries.CreateDepartment(ctx, CreateDepartmentParams{
+		ID:          id,
+		Name:        dept.Name,
+		Description: dept.Description,
+		CreatedAt:   pgtype.Timestamp{Time: dept.CreatedAt, Valid: true},
+		UpdatedAt:   pgtype.Timestamp{Time: dept.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return uuid.Nil, dbError(err, "create", "department")
+	}
+	dept.ID = id
+	return id, nil
+}
+
+func (r *departmentRepo) GetDepartmentByID(ctx context.Context, id uuid.UUID) (*database.Department, error) {
+	dbDept, err := r.queries.GetDepartmentByID(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "department")
+	}
+
+	dept := toDepartment(dbDept)
+	return &dept, nil
+}
+
+func (r *departmentRepo) UpdateDepartment(ctx context.Context, id uuid.UUID, dept *database.Department) error {
+	rows, err := r.queries.UpdateDepartment(ctx, UpdateDepartmentParams{
+		Name:        dept.Name,
+		Description: dept.Description,
+		ID:          id,
+	})
+	if err != nil {
+		return dbError(err, "update", "department")
+	}
+	if rows == 0 {
+		return customerr.NotFound("department not found")
+	}
+	dept.ID = id
+	return nil
+}
+
+func (r *departmentRepo) DeleteDepartment(ctx context.Context, id uuid.UUID) ([]MemberChange, error) {
+	if _, err := r.queries.GetDepartmentForUpdate(ctx, id); err != nil {
+		return nil, dbError(err, "get", "department")
+	}
+	before, err := r.queries.ListDepartmentMembersForUpdate(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "list", "department members")
+	}
+	//release the members first, bumping their versions, which ON DELETE SET NULL would not
+	after, err := r.queries.ReleaseDepartmentMembers(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "release", "department members")
+	}
+
+	rows, err := r.queries.DeleteDepartment(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "delete", "department")
+	}
+	if rows == 0 {
+		return nil, customerr.NotFound("department not found")
+	}
+	return memberChanges(before, after), nil
+}
+
+func (r *departmentRepo) ListDepartments(ctx context.Context) ([]database.Department, error) {
+	dbDepts, err := r.queries.ListDepartments(ctx)
+	if err != nil {
+		return nil, dbError(err, "list", "departments")
+	}
+
+	depts := make([]database.Department, len(dbDepts))
+	for i, dbDept := range dbDepts {
+		depts[i] = toDepartment(dbDept)
+	}
+	return depts, nil
+}
+
+func (r *departmentRepo) MoveEmployees(ctx context.Context, departmentID uuid.UUID, employeeIDs []uuid.UUID) ([]MemberChange, error) {
+	before, err := r.queries.ListEmployeesForUpdate(ctx, employeeIDs)
+	if err != nil {
+		return nil, dbError(err, "get", "employees")
+	}
+	after, err := r.queries.MoveEmployeesToDepartment(ctx, MoveEmployeesToDepartmentParams{
+		DepartmentID: departmentID,
+		EmployeeIds:  employeeIDs,
+	})
+	if err != nil {
+		return nil, dbError(err, "move", "employees")
+	}
+	return memberChanges(before, after), nil
+}
+
+func (r *departmentRepo) RemoveEmployee(ctx context.Context, departmentID, employeeID uuid.UUID) (*MemberChange, error) {
+	before, err := r.queries.ListEmployeesForUpdate(ctx, []uuid.UUID{employeeID})
+	if err != nil {
+		return nil, dbError(err, "get", "employee")
+	}
+	after, err := r.queries.RemoveEmployeeFromDepartment(ctx, RemoveEmployeeFromDepartmentParams{
+		EmployeeID:   employeeID,
+		DepartmentID: departmentID,
+	})
+	if err != nil {
+		return nil, dbError(err, "remove", "department member")
+	}
+	changes := memberChanges(before, after)
+	if len(changes) == 0 {
+		return nil, customerr.NotFound("employee is not a member of this department")
+	}
+	return &changes[0], nil
+}
+
+// memberChanges pairs the rows read before a membership change with the rows
+// it returned; the rows were locked in between, so every changed row has one
+func memberChanges(before, after []Employee) []MemberChange {
+	byID := make(map[uuid.UUID]Employee, len(before))
+	for _, row := range before {
+		byID[row.ID] = row
+	}
+	changes := make([]MemberChange, 0, len(after))
+	for _, row := range after {
+		changes = append(changes, MemberChange{Before: toEmployee(byID[row.ID]), After: toEmployee(row)})
+	}
+	return changes
+}
+
+func (r *departmentRepo) ListDepartmentCosts(ctx context.Context) ([]database.DepartmentCost, error) {
+	rows, err := r.queries.ListDepartmentCosts(ctx)
+	if err != nil {
+		return nil, dbError(err, "list", "department costs")
+	}
+
+	//rows come ordered by department, one per currency
+	costs := []database.DepartmentCost{}
+	for _, row := range rows {
+		if len(costs) == 0 || costs[len(costs)-1].DepartmentID != row.ID {
+			costs = append(costs, database.DepartmentCost{DepartmentID: row.ID, Name: row.Name, Salaries: []database.CurrencyAmount{}})
+		}
+		if !row.Currency.Valid {
+			continue
+		}
+		cost := &costs[len(costs)-1]
+		cost.Headcount += int(row.Headcount)
+		cost.Salaries = append(cost.Salaries, database.CurrencyAmount{
+			Currency:  row.Currency.String,
+			Headcount: int(row.Headcount),
+			Amount:    row.Salaries,
+		})
+	}
+	return costs, nil
+}
+
+func toDepartment(dbDept Department) database.Department {
+	return database.Department{
+		ID:          dbDept.ID,
+		Name:        dbDept.Name,
+		Description: dbDept.Description,
+		CreatedAt:   dbDept.CreatedAt.Time,
+		UpdatedAt:   dbDept.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,410 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: department.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/money"
+)
+
+const createDepartment = `-- name: CreateDepartment :one
+INSERT INTO departments (id, name, description, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id
+`
+
+type CreateDepartmentParams struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) CreateDepartment(ctx context.Context, arg CreateDepartmentParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createDepartment,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteDepartment = `-- name: DeleteDepartment :execrows
+DELETE FROM departments
+WHERE id = $1
+`
+
+func (q *Queries) DeleteDepartment(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDepartment, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getDepartmentByID = `-- name: GetDepartmentByID :one
+SELECT id, name, description, created_at, updated_at
+FROM departments
+WHERE id = $1
+`
+
+func (q *Queries) GetDepartmentByID(ctx context.Context, id uuid.UUID) (Department, error) {
+	row := q.db.QueryRow(ctx, getDepartmentByID, id)
+	var i Department
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getDepartmentForUpdate = `-- name: GetDepartmentForUpdate :one
+SELECT id, name, description, created_at, updated_at
+FROM departments
+WHERE id = $1
+FOR UPDATE
+`
+
+// locking the department also holds off employees joining it until the transaction ends
+func (q *Queries) GetDepartmentForUpdate(ctx context.Context, id uuid.UUID) (Department, error) {
+	row := q.db.QueryRow(ctx, getDepartmentForUpdate, id)
+	var i Department
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listDepartmentCosts = `-- name: ListDepartmentCosts :many
+SELECT d.id, d.name, e.currency, count(e.id)::int AS headcount, COALESCE(sum(e.salary), 0)::numeric AS salaries
+FROM departments d
+LEFT JOIN employees e ON e.department_id = d.id AND e.deleted_at IS NULL
+GROUP BY d.id, d.name, e.currency
+ORDER BY d.name, d.id, e.currency
+`
+
+type ListDepartmentCostsRow struct {
+	ID        uuid.UUID    `json:"id"`
+	Name      string       `json:"name"`
+	Currency  pgtype.Text  `json:"currency"`
+	Headcount int32        `json:"headcount"`
+	Salaries  money.Amount `json:"salaries"`
+}
+
+// each department's current employees and their annual salaries per currency;
+// a department without employees has one row with a NULL currency
+func (q *Queries) ListDepartmentCosts(ctx context.Context) ([]ListDepartmentCostsRow, error) {
+	rows, err := q.db.Query(ctx, listDepartmentCosts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDepartmentCostsRow
+	for rows.Next() {
+		var i ListDepartmentCostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Currency,
+			&i.Headcount,
+			&i.Salaries,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDepartmentMembersForUpdate = `-- name: ListDepartmentMembersForUpdate :many
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE department_id = $1::uuid
+ORDER BY id
+FOR UPDATE
+`
+
+// deleted members too, as ON DELETE SET NULL would release them as well
+func (q *Queries) ListDepartmentMembersForUpdate(ctx context.Context, departmentID uuid.UUID) ([]Employee, error) {
+	rows, err := q.db.Query(ctx, listDepartmentMembersForUpdate, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Employee
+	for rows.Next() {
+		var i Employee
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.HiredDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDepartments = `-- name: ListDepartments :many
+SELECT id, name, description, created_at, updated_at
+FROM departments
+ORDER BY name
+`
+
+func (q *Queries) ListDepartments(ctx context.Context) ([]Department, error) {
+	rows, err := q.db.Query(ctx, listDepartments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Department
+	for rows.Next() {
+		var i Department
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEmployeesForUpdate = `-- name: ListEmployeesForUpdate :many
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) ListEmployeesForUpdate(ctx context.Context, employeeIds []uuid.UUID) ([]Employee, error) {
+	rows, err := q.db.Query(ctx, listEmployeesForUpdate, employeeIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Employee
+	for rows.Next() {
+		var i Employee
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.HiredDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveEmployeesToDepartment = `-- name: MoveEmployeesToDepartment :many
+UPDATE employees
+SET department_id = $1::uuid, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = ANY($2::uuid[]) AND deleted_at IS NULL
+RETURNING id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+`
+
+type MoveEmployeesToDepartmentParams struct {
+	DepartmentID uuid.UUID   `json:"department_id"`
+	EmployeeIds  []uuid.UUID `json:"employee_ids"`
+}
+
+func (q *Queries) MoveEmployeesToDepartment(ctx context.Context, arg MoveEmployeesToDepartmentParams) ([]Employee, error) {
+	rows, err := q.db.Query(ctx, moveEmployeesToDepartment, arg.DepartmentID, arg.EmployeeIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Employee
+	for rows.Next() {
+		var i Employee
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.HiredDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseDepartmentMembers = `-- name: ReleaseDepartmentMembers :many
+UPDATE employees
+SET department_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE department_id = $1::uuid
+RETURNING id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+`
+
+// run before deleting the department so members get a new version, which
+// ON DELETE SET NULL alone would not give them
+func (q *Queries) ReleaseDepartmentMembers(ctx context.Context, departmentID uuid.UUID) ([]Employee, error) {
+	rows, err := q.db.Query(ctx, releaseDepartmentMembers, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Employee
+	for rows.Next() {
+		var i Employee
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.HiredDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeEmployeeFromDepartment = `-- name: RemoveEmployeeFromDepartment :many
+UPDATE employees
+SET department_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND department_id = $2::uuid AND deleted_at IS NULL
+RETURNING id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+`
+
+type RemoveEmployeeFromDepartmentParams struct {
+	EmployeeID   uuid.UUID `json:"employee_id"`
+	DepartmentID uuid.UUID `json:"department_id"`
+}
+
+func (q *Queries) RemoveEmployeeFromDepartment(ctx context.Context, arg RemoveEmployeeFromDepartmentParams) ([]Employee, error) {
+	rows, err := q.db.Query(ctx, removeEmployeeFromDepartment, arg.EmployeeID, arg.DepartmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Employee
+	for rows.Next() {
+		var i Employee
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.HiredDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateDepartment = `-- name: UpdateDepartment :execrows
+UPDATE departments
+SET name = $1, description = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $3
+`
+
+type UpdateDepartmentParams struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ID          uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateDepartment(ctx context.Context, arg UpdateDepartmentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateDepartment, arg.Name, arg.Description, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
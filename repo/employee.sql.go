@@ -10,22 +10,105 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/money"
 )
 
+const clearDirectReportsManager = `-- name: ClearDirectReportsManager :exec
+UPDATE employees
+SET manager_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE manager_id = $1::uuid
+`
+
+// reports of a deleted manager move to the top of the hierarchy, as ON DELETE SET NULL did for hard deletes
+func (q *Queries) ClearDirectReportsManager(ctx context.Context, managerID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, clearDirectReportsManager, managerID)
+	return err
+}
+
+const countDeletedEmployees = `-- name: CountDeletedEmployees :one
+SELECT COUNT(*)
+FROM employees
+WHERE deleted_at IS NOT NULL
+`
+
+func (q *Queries) CountDeletedEmployees(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countDeletedEmployees)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countEmployees = `-- name: CountEmployees :one
+SELECT COUNT(*)
+FROM employees
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR position = $1::text)
+  AND ($2::uuid IS NULL OR department_id = $2::uuid)
+  AND ($3::numeric IS NULL OR salary >= $3::numeric)
+  AND ($4::numeric IS NULL OR salary <= $4::numeric)
+  AND ($5::text IS NULL OR currency = $5::text)
+  AND ($6::date IS NULL OR hired_date >= $6::date)
+  AND ($7::date IS NULL OR hired_date <= $7::date)
+`
+
+type CountEmployeesParams struct {
+	Position     pgtype.Text   `json:"position"`
+	DepartmentID pgtype.UUID   `json:"department_id"`
+	MinSalary    *money.Amount `json:"min_salary"`
+	MaxSalary    *money.Amount `json:"max_salary"`
+	Currency     pgtype.Text   `json:"currency"`
+	HiredFrom    pgtype.Date   `json:"hired_from"`
+	HiredTo      pgtype.Date   `json:"hired_to"`
+}
+
+func (q *Queries) CountEmployees(ctx context.Context, arg CountEmployeesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countEmployees,
+		arg.Position,
+		arg.DepartmentID,
+		arg.MinSalary,
+		arg.MaxSalary,
+		arg.Currency,
+		arg.HiredFrom,
+		arg.HiredTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchEmployees = `-- name: CountSearchEmployees :one
+SELECT COUNT(*)
+FROM employees e
+WHERE e.deleted_at IS NULL
+  AND ((setweight(to_tsvector('simple', e.name), 'A') || setweight(to_tsvector('simple', e.position), 'B')) @@ websearch_to_tsquery('simple', $1::text)
+    OR $1::text <% e.name
+    OR $1::text <% e.position)
+`
+
+func (q *Queries) CountSearchEmployees(ctx context.Context, query string) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchEmployees, query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createEmployee = `-- name: CreateEmployee :one
-INSERT INTO employees (id, name, position, salary, hired_date, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO employees (id, name, position, salary, currency, hired_date, department_id, manager_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING id
 `
 
 type CreateEmployeeParams struct {
-	ID        uuid.UUID        `json:"id"`
-	Name      string           `json:"name"`
-	Position  string           `json:"position"`
-	Salary    float64          `json:"salary"`
-	HiredDate pgtype.Date      `json:"hired_date"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
-	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	ID           uuid.UUID        `json:"id"`
+	Name         string           `json:"name"`
+	Position     string           `json:"position"`
+	Salary       money.Amount     `json:"salary"`
+	Currency     string           `json:"currency"`
+	HiredDate    pgtype.Date      `json:"hired_date"`
+	DepartmentID pgtype.UUID      `json:"department_id"`
+	ManagerID    pgtype.UUID      `json:"manager_id"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 }
 
 func (q *Queries) CreateEmployee(ctx context.Context, arg CreateEmployeeParams) (uuid.UUID, error) {
@@ -34,7 +117,10 @@ func (q *Queries) CreateEmployee(ctx context.Context, arg CreateEmployeeParams)
 		arg.Name,
 		arg.Position,
 		arg.Salary,
+		arg.Currency,
 		arg.HiredDate,
+		arg.DepartmentID,
+		arg.ManagerID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -43,20 +129,124 @@ func (q *Queries) CreateEmployee(ctx context.Context, arg CreateEmployeeParams)
 	return id, err
 }
 
-const deleteEmployee = `-- name: DeleteEmployee :exec
-DELETE FROM employees
-WHERE id = $1
+const exportEmployees = `-- name: ExportEmployees :many
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR position = $1::text)
+  AND ($2::uuid IS NULL OR department_id = $2::uuid)
+  AND ($3::numeric IS NULL OR salary >= $3::numeric)
+  AND ($4::numeric IS NULL OR salary <= $4::numeric)
+  AND ($5::text IS NULL OR currency = $5::text)
+  AND ($6::date IS NULL OR hired_date >= $6::date)
+  AND ($7::date IS NULL OR hired_date <= $7::date)
+ORDER BY
+  CASE WHEN $8::text = 'name' AND NOT $9::bool THEN name END ASC,
+  CASE WHEN $8::text = 'name' AND $9::bool THEN name END DESC,
+  CASE WHEN $8::text = 'position' AND NOT $9::bool THEN position END ASC,
+  CASE WHEN $8::text = 'position' AND $9::bool THEN position END DESC,
+  CASE WHEN $8::text = 'salary' AND NOT $9::bool THEN salary END ASC,
+  CASE WHEN $8::text = 'salary' AND $9::bool THEN salary END DESC,
+  CASE WHEN $8::text = 'hired_date' AND NOT $9::bool THEN hired_date END ASC,
+  CASE WHEN $8::text = 'hired_date' AND $9::bool THEN hired_date END DESC,
+  CASE WHEN $8::text = 'created_at' AND NOT $9::bool THEN created_at END ASC,
+  CASE WHEN $8::text = 'created_at' AND $9::bool THEN created_at END DESC,
+  CASE WHEN $8::text = 'updated_at' AND NOT $9::bool THEN updated_at END ASC,
+  CASE WHEN $8::text = 'updated_at' AND $9::bool THEN updated_at END DESC,
+  CASE WHEN NOT $9::bool THEN id END ASC,
+  CASE WHEN $9::bool THEN id END DESC
 `
 
-func (q *Queries) DeleteEmployee(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteEmployee, id)
-	return err
+type ExportEmployeesParams struct {
+	Position     pgtype.Text   `json:"position"`
+	DepartmentID pgtype.UUID   `json:"department_id"`
+	MinSalary    *money.Amount `json:"min_salary"`
+	MaxSalary    *money.Amount `json:"max_salary"`
+	Currency     pgtype.Text   `json:"currency"`
+	HiredFrom    pgtype.Date   `json:"hired_from"`
+	HiredTo      pgtype.Date   `json:"hired_to"`
+	SortBy       string        `json:"sort_by"`
+	SortDesc     bool          `json:"sort_desc"`
+}
+
+// every employee matching the listing filters, in listing order and without
+// paging
+func (q *Queries) ExportEmployees(ctx context.Context, arg ExportEmployeesParams) ([]Employee, error) {
+	rows, err := q.db.Query(ctx, exportEmployees,
+		arg.Position,
+		arg.DepartmentID,
+		arg.MinSalary,
+		arg.MaxSalary,
+		arg.Currency,
+		arg.HiredFrom,
+		arg.HiredTo,
+		arg.SortBy,
+		arg.SortDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Employee
+	for rows.Next() {
+		var i Employee
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.HiredDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletedEmployeeForUpdate = `-- name: GetDeletedEmployeeForUpdate :one
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE id = $1 AND deleted_at IS NOT NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetDeletedEmployeeForUpdate(ctx context.Context, id uuid.UUID) (Employee, error) {
+	row := q.db.QueryRow(ctx, getDeletedEmployeeForUpdate, id)
+	var i Employee
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Position,
+		&i.Salary,
+		&i.HiredDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DepartmentID,
+		&i.ManagerID,
+		&i.DeletedAt,
+		&i.Version,
+		&i.Currency,
+		&i.TerminationDate,
+	)
+	return i, err
 }
 
 const getEmployeeByID = `-- name: GetEmployeeByID :one
-SELECT id, name, position, salary, hired_date, created_at, updated_at
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
 FROM employees
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetEmployeeByID(ctx context.Context, id uuid.UUID) (Employee, error) {
@@ -70,17 +260,337 @@ func (q *Queries) GetEmployeeByID(ctx context.Context, id uuid.UUID) (Employee,
 		&i.HiredDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DepartmentID,
+		&i.ManagerID,
+		&i.DeletedAt,
+		&i.Version,
+		&i.Currency,
+		&i.TerminationDate,
+	)
+	return i, err
+}
+
+const getEmployeeForUpdate = `-- name: GetEmployeeForUpdate :one
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetEmployeeForUpdate(ctx context.Context, id uuid.UUID) (Employee, error) {
+	row := q.db.QueryRow(ctx, getEmployeeForUpdate, id)
+	var i Employee
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Position,
+		&i.Salary,
+		&i.HiredDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DepartmentID,
+		&i.ManagerID,
+		&i.DeletedAt,
+		&i.Version,
+		&i.Currency,
+		&i.TerminationDate,
 	)
 	return i, err
 }
 
+const getManagerChain = `-- name: GetManagerChain :many
+WITH RECURSIVE chain AS (
+    SELECT m.id, m.name, m.position, m.department_id, m.manager_id, 1 AS depth
+    FROM employees e
+    JOIN employees m ON m.id = e.manager_id
+    WHERE e.id = $1 AND m.deleted_at IS NULL
+    UNION ALL
+    SELECT m.id, m.name, m.position, m.department_id, m.manager_id, c.depth + 1
+    FROM employees m
+    JOIN chain c ON m.id = c.manager_id
+    WHERE c.depth < 100 AND m.deleted_at IS NULL
+)
+SELECT id, name, position, department_id, manager_id, depth::int AS depth
+FROM chain
+ORDER BY depth
+`
+
+type GetManagerChainRow struct {
+	ID           uuid.UUID   `json:"id"`
+	Name         string      `json:"name"`
+	Position     string      `json:"position"`
+	DepartmentID pgtype.UUID `json:"department_id"`
+	ManagerID    pgtype.UUID `json:"manager_id"`
+	Depth        int32       `json:"depth"`
+}
+
+// walks up from the employee's manager to the top; depth guards against a cycle already in the data
+func (q *Queries) GetManagerChain(ctx context.Context, id uuid.UUID) ([]GetManagerChainRow, error) {
+	rows, err := q.db.Query(ctx, getManagerChain, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetManagerChainRow
+	for rows.Next() {
+		var i GetManagerChainRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.Depth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReportTree = `-- name: GetReportTree :many
+WITH RECURSIVE reports AS (
+    SELECT e.id, e.name, e.position, e.department_id, e.manager_id, 1 AS depth
+    FROM employees e
+    WHERE e.manager_id = $1::uuid AND e.deleted_at IS NULL
+    UNION ALL
+    SELECT e.id, e.name, e.position, e.department_id, e.manager_id, r.depth + 1
+    FROM employees e
+    JOIN reports r ON e.manager_id = r.id
+    WHERE r.depth < 100 AND e.deleted_at IS NULL
+)
+SELECT id, name, position, department_id, manager_id, depth::int AS depth
+FROM reports
+ORDER BY depth, name
+`
+
+type GetReportTreeRow struct {
+	ID           uuid.UUID   `json:"id"`
+	Name         string      `json:"name"`
+	Position     string      `json:"position"`
+	DepartmentID pgtype.UUID `json:"department_id"`
+	ManagerID    pgtype.UUID `json:"manager_id"`
+	Depth        int32       `json:"depth"`
+}
+
+// every direct and indirect report below the employee, shallowest first
+func (q *Queries) GetReportTree(ctx context.Context, id uuid.UUID) ([]GetReportTreeRow, error) {
+	rows, err := q.db.Query(ctx, getReportTree, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReportTreeRow
+	for rows.Next() {
+		var i GetReportTreeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.Depth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeletedEmployees = `-- name: ListDeletedEmployees :many
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+FROM employees
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+LIMIT $2
+OFFSET $1
+`
+
+type ListDeletedEmployeesParams struct {
+	PageOffset int32 `json:"page_offset"`
+	PageLimit  int32 `json:"page_limit"`
+}
+
+func (q *Queries) ListDeletedEmployees(ctx context.Context, arg ListDeletedEmployeesParams) ([]Employee, error) {
+	rows, err := q.db.Query(ctx, listDeletedEmployees, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Employee
+	for rows.Next() {
+		var i Employee
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.HiredDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDirectReportIDs = `-- name: ListDirectReportIDs :many
+SELECT id
+FROM employees
+WHERE manager_id = $1::uuid AND deleted_at IS NULL
+`
+
+func (q *Queries) ListDirectReportIDs(ctx context.Context, managerID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listDirectReportIDs, managerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEmployeeCurrencies = `-- name: ListEmployeeCurrencies :many
+SELECT DISTINCT currency
+FROM employees
+WHERE deleted_at IS NULL
+ORDER BY currency
+`
+
+// the currencies current employees are paid in
+func (q *Queries) ListEmployeeCurrencies(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listEmployeeCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var currency string
+		if err := rows.Scan(&currency); err != nil {
+			return nil, err
+		}
+		items = append(items, currency)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEmployees = `-- name: ListEmployees :many
-SELECT id, name, position, salary, hired_date, created_at, updated_at
+SELECT id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
 FROM employees
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR position = $1::text)
+  AND ($2::uuid IS NULL OR department_id = $2::uuid)
+  AND ($3::numeric IS NULL OR salary >= $3::numeric)
+  AND ($4::numeric IS NULL OR salary <= $4::numeric)
+  AND ($5::text IS NULL OR currency = $5::text)
+  AND ($6::date IS NULL OR hired_date >= $6::date)
+  AND ($7::date IS NULL OR hired_date <= $7::date)
+  -- keyset cursor: rows strictly after (sort value, id) of the previous page
+  AND ($8::uuid IS NULL
+    OR ($9::text = 'id' AND NOT $10::bool AND id > $8::uuid)
+    OR ($9::text = 'id' AND $10::bool AND id < $8::uuid)
+    OR ($9::text = 'name' AND NOT $10::bool AND (name, id) > ($11::text, $8::uuid))
+    OR ($9::text = 'name' AND $10::bool AND (name, id) < ($11::text, $8::uuid))
+    OR ($9::text = 'position' AND NOT $10::bool AND (position, id) > ($11::text, $8::uuid))
+    OR ($9::text = 'position' AND $10::bool AND (position, id) < ($11::text, $8::uuid))
+    OR ($9::text = 'salary' AND NOT $10::bool AND (salary, id) > ($12::numeric, $8::uuid))
+    OR ($9::text = 'salary' AND $10::bool AND (salary, id) < ($12::numeric, $8::uuid))
+    OR ($9::text = 'hired_date' AND NOT $10::bool AND (hired_date, id) > ($13::date, $8::uuid))
+    OR ($9::text = 'hired_date' AND $10::bool AND (hired_date, id) < ($13::date, $8::uuid))
+    OR ($9::text = 'created_at' AND NOT $10::bool AND (created_at, id) > ($14::timestamp, $8::uuid))
+    OR ($9::text = 'created_at' AND $10::bool AND (created_at, id) < ($14::timestamp, $8::uuid))
+    OR ($9::text = 'updated_at' AND NOT $10::bool AND (updated_at, id) > ($14::timestamp, $8::uuid))
+    OR ($9::text = 'updated_at' AND $10::bool AND (updated_at, id) < ($14::timestamp, $8::uuid)))
+ORDER BY
+  CASE WHEN $9::text = 'name' AND NOT $10::bool THEN name END ASC,
+  CASE WHEN $9::text = 'name' AND $10::bool THEN name END DESC,
+  CASE WHEN $9::text = 'position' AND NOT $10::bool THEN position END ASC,
+  CASE WHEN $9::text = 'position' AND $10::bool THEN position END DESC,
+  CASE WHEN $9::text = 'salary' AND NOT $10::bool THEN salary END ASC,
+  CASE WHEN $9::text = 'salary' AND $10::bool THEN salary END DESC,
+  CASE WHEN $9::text = 'hired_date' AND NOT $10::bool THEN hired_date END ASC,
+  CASE WHEN $9::text = 'hired_date' AND $10::bool THEN hired_date END DESC,
+  CASE WHEN $9::text = 'created_at' AND NOT $10::bool THEN created_at END ASC,
+  CASE WHEN $9::text = 'created_at' AND $10::bool THEN created_at END DESC,
+  CASE WHEN $9::text = 'updated_at' AND NOT $10::bool THEN updated_at END ASC,
+  CASE WHEN $9::text = 'updated_at' AND $10::bool THEN updated_at END DESC,
+  CASE WHEN NOT $10::bool THEN id END ASC,
+  CASE WHEN $10::bool THEN id END DESC
+LIMIT $16
+OFFSET $15
 `
 
-func (q *Queries) ListEmployees(ctx context.Context) ([]Employee, error) {
-	rows, err := q.db.Query(ctx, listEmployees)
+type ListEmployeesParams struct {
+	Position     pgtype.Text      `json:"position"`
+	DepartmentID pgtype.UUID      `json:"department_id"`
+	MinSalary    *money.Amount    `json:"min_salary"`
+	MaxSalary    *money.Amount    `json:"max_salary"`
+	Currency     pgtype.Text      `json:"currency"`
+	HiredFrom    pgtype.Date      `json:"hired_from"`
+	HiredTo      pgtype.Date      `json:"hired_to"`
+	CursorID     pgtype.UUID      `json:"cursor_id"`
+	SortBy       string           `json:"sort_by"`
+	SortDesc     bool             `json:"sort_desc"`
+	CursorText   pgtype.Text      `json:"cursor_text"`
+	CursorNumber *money.Amount    `json:"cursor_number"`
+	CursorDate   pgtype.Date      `json:"cursor_date"`
+	CursorTime   pgtype.Timestamp `json:"cursor_time"`
+	PageOffset   int32            `json:"page_offset"`
+	PageLimit    int32            `json:"page_limit"`
+}
+
+func (q *Queries) ListEmployees(ctx context.Context, arg ListEmployeesParams) ([]Employee, error) {
+	rows, err := q.db.Query(ctx, listEmployees,
+		arg.Position,
+		arg.DepartmentID,
+		arg.MinSalary,
+		arg.MaxSalary,
+		arg.Currency,
+		arg.HiredFrom,
+		arg.HiredTo,
+		arg.CursorID,
+		arg.SortBy,
+		arg.SortDesc,
+		arg.CursorText,
+		arg.CursorNumber,
+		arg.CursorDate,
+		arg.CursorTime,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +606,53 @@ func (q *Queries) ListEmployees(ctx context.Context) ([]Employee, error) {
 			&i.HiredDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrgChart = `-- name: ListOrgChart :many
+SELECT id, name, position, department_id, manager_id
+FROM employees
+WHERE deleted_at IS NULL
+ORDER BY name
+`
+
+type ListOrgChartRow struct {
+	ID           uuid.UUID   `json:"id"`
+	Name         string      `json:"name"`
+	Position     string      `json:"position"`
+	DepartmentID pgtype.UUID `json:"department_id"`
+	ManagerID    pgtype.UUID `json:"manager_id"`
+}
+
+func (q *Queries) ListOrgChart(ctx context.Context) ([]ListOrgChartRow, error) {
+	rows, err := q.db.Query(ctx, listOrgChart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrgChartRow
+	for rows.Next() {
+		var i ListOrgChartRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.DepartmentID,
+			&i.ManagerID,
 		); err != nil {
 			return nil, err
 		}
@@ -107,27 +664,282 @@ func (q *Queries) ListEmployees(ctx context.Context) ([]Employee, error) {
 	return items, nil
 }
 
-const updateEmployee = `-- name: UpdateEmployee :exec
+const lockHierarchy = `-- name: LockHierarchy :exec
+SELECT pg_advisory_xact_lock(7267103984512001)
+`
+
+// serializes manager changes until the transaction ends, so two of them
+// cannot each pass the cycle check and together close a loop
+func (q *Queries) LockHierarchy(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockHierarchy)
+	return err
+}
+
+const patchEmployee = `-- name: PatchEmployee :execrows
 UPDATE employees
-SET name = $1, position = $2, salary = $3, hired_date = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $5
+SET name = COALESCE($1, name),
+    position = COALESCE($2, position),
+    salary = COALESCE($3, salary),
+    currency = COALESCE($4, currency),
+    hired_date = COALESCE($5, hired_date),
+    updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $6 AND deleted_at IS NULL
 `
 
-type UpdateEmployeeParams struct {
-	Name      string      `json:"name"`
-	Position  string      `json:"position"`
-	Salary    float64     `json:"salary"`
-	HiredDate pgtype.Date `json:"hired_date"`
-	ID        uuid.UUID   `json:"id"`
+type PatchEmployeeParams struct {
+	Name      pgtype.Text   `json:"name"`
+	Position  pgtype.Text   `json:"position"`
+	Salary    *money.Amount `json:"salary"`
+	Currency  pgtype.Text   `json:"currency"`
+	HiredDate pgtype.Date   `json:"hired_date"`
+	ID        uuid.UUID     `json:"id"`
 }
 
-func (q *Queries) UpdateEmployee(ctx context.Context, arg UpdateEmployeeParams) error {
-	_, err := q.db.Exec(ctx, updateEmployee,
+// a NULL argument leaves its column unchanged
+func (q *Queries) PatchEmployee(ctx context.Context, arg PatchEmployeeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, patchEmployee,
 		arg.Name,
 		arg.Position,
 		arg.Salary,
+		arg.Currency,
 		arg.HiredDate,
 		arg.ID,
 	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeDeletedEmployees = `-- name: PurgeDeletedEmployees :many
+DELETE FROM employees
+WHERE deleted_at IS NOT NULL AND deleted_at < CURRENT_TIMESTAMP - $1::interval
+RETURNING id, name, position, salary, hired_date, created_at, updated_at, department_id, manager_id, deleted_at, version, currency, termination_date
+`
+
+// compared against the database clock, which also stamped deleted_at
+func (q *Queries) PurgeDeletedEmployees(ctx context.Context, retention pgtype.Interval) ([]Employee, error) {
+	rows, err := q.db.Query(ctx, purgeDeletedEmployees, retention)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Employee
+	for rows.Next() {
+		var i Employee
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.HiredDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreEmployee = `-- name: RestoreEmployee :execrows
+UPDATE employees e
+SET deleted_at = NULL,
+    manager_id = (SELECT m.id FROM employees m WHERE m.id = e.manager_id AND m.deleted_at IS NULL),
+    updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE e.id = $1 AND e.deleted_at IS NOT NULL
+`
+
+// the manager may have been deleted meanwhile; the restored employee then has none
+func (q *Queries) RestoreEmployee(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreEmployee, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const searchEmployees = `-- name: SearchEmployees :many
+SELECT e.id, e.name, e.position, e.salary, e.hired_date, e.created_at, e.updated_at, e.department_id, e.manager_id, e.deleted_at, e.version, e.currency, e.termination_date,
+  (ts_rank(setweight(to_tsvector('simple', e.name), 'A') || setweight(to_tsvector('simple', e.position), 'B'), q.query)
+    + GREATEST(word_similarity($1::text, e.name), word_similarity($1::text, e.position)))::float8 AS rank,
+  ts_headline('simple', e.name, q.query, q.options)::text AS name_highlight,
+  ts_headline('simple', e.position, q.query, q.options)::text AS position_highlight
+FROM employees e,
+  (SELECT websearch_to_tsquery('simple', $1::text) AS query,
+    'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3) AS options) q
+WHERE e.deleted_at IS NULL
+  AND ((setweight(to_tsvector('simple', e.name), 'A') || setweight(to_tsvector('simple', e.position), 'B')) @@ q.query
+    OR $1::text <% e.name
+    OR $1::text <% e.position)
+ORDER BY rank DESC, e.name, e.id
+LIMIT $3
+OFFSET $2
+`
+
+type SearchEmployeesParams struct {
+	Query      string `json:"query"`
+	PageOffset int32  `json:"page_offset"`
+	PageLimit  int32  `json:"page_limit"`
+}
+
+type SearchEmployeesRow struct {
+	ID                uuid.UUID        `json:"id"`
+	Name              string           `json:"name"`
+	Position          string           `json:"position"`
+	Salary            money.Amount     `json:"salary"`
+	HiredDate         pgtype.Date      `json:"hired_date"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+	UpdatedAt         pgtype.Timestamp `json:"updated_at"`
+	DepartmentID      pgtype.UUID      `json:"department_id"`
+	ManagerID         pgtype.UUID      `json:"manager_id"`
+	DeletedAt         pgtype.Timestamp `json:"deleted_at"`
+	Version           int64            `json:"version"`
+	Currency          string           `json:"currency"`
+	TerminationDate   pgtype.Date      `json:"termination_date"`
+	Rank              float64          `json:"rank"`
+	NameHighlight     string           `json:"name_highlight"`
+	PositionHighlight string           `json:"position_highlight"`
+}
+
+// ranks full-text matches on name (weighted above position) plus trigram word
+// similarity, so misspelt and partial words are found too. Matched words are
+// wrapped in chr(2) and chr(3) for the repo to turn into highlights.
+func (q *Queries) SearchEmployees(ctx context.Context, arg SearchEmployeesParams) ([]SearchEmployeesRow, error) {
+	rows, err := q.db.Query(ctx, searchEmployees, arg.Query, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchEmployeesRow
+	for rows.Next() {
+		var i SearchEmployeesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.HiredDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DepartmentID,
+			&i.ManagerID,
+			&i.DeletedAt,
+			&i.Version,
+			&i.Currency,
+			&i.TerminationDate,
+			&i.Rank,
+			&i.NameHighlight,
+			&i.PositionHighlight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setEmployeeManager = `-- name: SetEmployeeManager :execrows
+UPDATE employees
+SET manager_id = $1, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $2 AND deleted_at IS NULL
+`
+
+type SetEmployeeManagerParams struct {
+	ManagerID pgtype.UUID `json:"manager_id"`
+	ID        uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) SetEmployeeManager(ctx context.Context, arg SetEmployeeManagerParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setEmployeeManager, arg.ManagerID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setEmployeeTermination = `-- name: SetEmployeeTermination :execrows
+UPDATE employees
+SET termination_date = $1, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $2 AND deleted_at IS NULL
+`
+
+type SetEmployeeTerminationParams struct {
+	TerminationDate pgtype.Date `json:"termination_date"`
+	ID              uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) SetEmployeeTermination(ctx context.Context, arg SetEmployeeTerminationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setEmployeeTermination, arg.TerminationDate, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setSearchSimilarityThreshold = `-- name: SetSearchSimilarityThreshold :exec
+SELECT set_config('pg_trgm.word_similarity_threshold', $1::text, true)
+`
+
+// lowers the word similarity needed by <% for the rest of the transaction
+func (q *Queries) SetSearchSimilarityThreshold(ctx context.Context, threshold string) error {
+	_, err := q.db.Exec(ctx, setSearchSimilarityThreshold, threshold)
 	return err
 }
+
+const softDeleteEmployee = `-- name: SoftDeleteEmployee :execrows
+UPDATE employees
+SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteEmployee(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteEmployee, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateEmployee = `-- name: UpdateEmployee :execrows
+UPDATE employees
+SET name = $1, position = $2, salary = $3, currency = $4, hired_date = $5, updated_at = CURRENT_TIMESTAMP, version = version + 1
+WHERE id = $6 AND deleted_at IS NULL
+`
+
+type UpdateEmployeeParams struct {
+	Name      string       `json:"name"`
+	Position  string       `json:"position"`
+	Salary    money.Amount `json:"salary"`
+	Currency  string       `json:"currency"`
+	HiredDate pgtype.Date  `json:"hired_date"`
+	ID        uuid.UUID    `json:"id"`
+}
+
+func (q *Queries) UpdateEmployee(ctx context.Context, arg UpdateEmployeeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateEmployee,
+		arg.Name,
+		arg.Position,
+		arg.Salary,
+		arg.Currency,
+		arg.HiredDate,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+)
+
+// dbError classifies a failed query on entity into one of the customerr kinds,
+// keeping the original error as the cause
+func dbError(err error, action, entity string) error {
+	var pgErr *pgconn.PgError
+	var connErr *pgconn.ConnectError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return customerr.NotFound(entity + " not found")
+	case errors.As(err, &pgErr):
+		switch {
+		case pgErr.Code == "23505":
+			return customerr.Wrap(customerr.ErrConflict, entity+" already exists", err)
+		case pgErr.Code == "23503":
+			return customerr.Wrap(customerr.ErrValidation, "a referenced record does not exist", err)
+		//raised by the triggers guarding finalized payroll runs
+		case pgErr.Code == "55000":
+			return customerr.Wrap(customerr.ErrConflict, pgErr.Message, err)
+		case pgErr.Code == "23514", strings.HasPrefix(pgErr.Code, "22"):
+			return customerr.Wrap(customerr.ErrValidation, "invalid "+entity+" data", err)
+		//connection exceptions, insufficient resources and operator intervention
+		case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "53"), strings.HasPrefix(pgErr.Code, "57"):
+			return customerr.Wrap(customerr.ErrUnavailable, "database unavailable", err)
+		}
+	case errors.As(err, &connErr), errors.As(err, &netErr), pgconn.Timeout(err):
+		return customerr.Wrap(customerr.ErrUnavailable, "database unavailable", err)
+	}
+	return fmt.Errorf("failed to %s %s: %w", action, entity, err)
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+)
+
+type ExchangeRateRepo interface {
+	GetRate(ctx context.Context, id uuid.UUID) (*database.ExchangeRate, error)
+	//GetRateOn returns the currency's rate that takes effect on the day
+	GetRateOn(ctx context.Context, currency string, effective time.Time) (*database.ExchangeRate, error)
+	//UpsertRate stores the rate, replacing the currency's rate for the same day
+	UpsertRate(ctx context.Context, rate *database.ExchangeRate) error
+	DeleteRate(ctx context.Context, id uuid.UUID) error
+	//ListRates returns every rate, or only those of currency when it is set
+	ListRates(ctx context.Context, currency string) ([]database.ExchangeRate, error)
+	//ListRatesOn returns the rate of each currency in effect on the day
+	ListRatesOn(ctx context.Context, asOf time.Time) ([]database.ExchangeRate, error)
+	//RatesOn is ListRatesOn keyed by currency for converting amounts
+	RatesOn(ctx context.Context, asOf time.Time) (money.Rates, error)
+
+	//WithTx returns a repo whose queries run inside tx
+	WithTx(tx pgx.Tx) ExchangeRateRepo
+}
+
+type exchangeRateRepo struct {
+	queries *Queries
+}
+
+func NewExchangeRateRepo(db DBTX) ExchangeRateRepo {
+	return &exchangeRateRepo{
+		queries: New(db),
+	}
+}
+
+func (r *exchangeRateRepo) WithTx(tx pgx.Tx) ExchangeRateRepo {
+	return &exchangeRateRepo{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
+func (r *exchangeRateRepo) GetRate(ctx context.Context, id uuid.UUID) (*database.ExchangeRate, error) {
+	row, err := r.queries.GetExchangeRate(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "exchange rate")
+	}
+	rate := toExchangeRate(row)
+	return &rate, nil
+}
+
+func (r *exchangeRateRepo) GetRateOn(ctx context.Context, currency string, effective time.Time) (*database.ExchangeRate, error) {
+	row, err := r.queries.GetExchangeRateOn(ctx, GetExchangeRateOnParams{
+		Currency:      currency,
+		EffectiveDate: pgtype.Date{Time: effective, Valid: true},
+	})
+	if err != nil {
+		return nil, dbError(err, "get", "exchange rate")
+	}
+	rate := toExchangeRate(row)
+	return &rate, nil
+}
+
+func (r *exchangeRateRepo) UpsertRate(ctx context.Context, rate *database.ExchangeRate) error {
+	row, err := r.queries.UpsertExchangeRate(ctx, UpsertExchangeRateParams{
+		ID:            uuid.New(),
+		Currency:      rate.Currency,
+		Rate:          rate.Rate,
+		EffectiveDate: pgtype.Date{Time: rate.EffectiveDate, Valid: true},
+		CreatedAt:     pgtype.Timestamp{Time: rate.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "store", "exchange rate")
+	}
+	*rate = toExchangeRate(row)
+	return nil
+}
+
+func (r *exchangeRateRepo) DeleteRate(ctx context.Context, id uuid.UUID) error {
+	rows, err := r.queries.DeleteExchangeRate(ctx, id)
+	if err != nil {
+		return dbError(err, "delete", "exchange rate")
+	}
+	if rows == 0 {
+		return customerr.NotFound("exchange rate not found")
+	}
+	return nil
+}
+
+func (r *exchangeRateRepo) ListRates(ctx context.Context, currency string) ([]database.ExchangeRate, error) {
+	rows, err := r.queries.ListExchangeRates(ctx, pgtype.Text{String: currency, Valid: currency != ""})
+	if err != nil {
+		return nil, dbError(err, "list", "exchange rates")
+	}
+	return toExchangeRates(rows), nil
+}
+
+func (r *exchangeRateRepo) ListRatesOn(ctx context.Context, asOf time.Time) ([]database.ExchangeRate, error) {
+	rows, err := r.queries.ListExchangeRatesOn(ctx, pgtype.Date{Time: asOf, Valid: true})
+	if err != nil {
+		return nil, dbError(err, "list", "exchange rates")
+	}
+	return toExchangeRates(rows), nil
+}
+
+func (r *exchangeRateRepo) RatesOn(ctx context.Context, asOf time.Time) (money.Rates, error) {
+	rates, err := r.ListRatesOn(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+	byCurrency := make(money.Rates, len(rates))
+	for _, rate := range rates {
+		byCurrency[rate.Currency] = rate.Rate
+	}
+	return byCurrency, nil
+}
+
+func toExchangeRates(rows []ExchangeRate) []database.ExchangeRate {
+	rates := make([]database.ExchangeRate, len(rows))
+	for i, row := range rows {
+		rates[i] = toExchangeRate(row)
+	}
+	return rates
+}
+
+func toExchangeRate(row ExchangeRate) database.ExchangeRate {
+	return database.ExchangeRate{
+		ID:            row.ID,
+		Currency:      row.Currency,
+		Rate:          row.Rate,
+		EffectiveDate: row.EffectiveDate.Time,
+		CreatedAt:     row.CreatedAt.Time,
+		UpdatedAt:     row.UpdatedAt.Time,
+	}
+}
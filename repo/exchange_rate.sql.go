@@ -0,0 +1,177 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: exchange_rate.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/money"
+)
+
+const deleteExchangeRate = `-- name: DeleteExchangeRate :execrows
+DELETE FROM exchange_rates WHERE id = $1
+`
+
+func (q *Queries) DeleteExchangeRate(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExchangeRate, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getExchangeRate = `-- name: GetExchangeRate :one
+SELECT id, currency, rate, effective_date, created_at, updated_at
+FROM exchange_rates
+WHERE id = $1
+`
+
+func (q *Queries) GetExchangeRate(ctx context.Context, id uuid.UUID) (ExchangeRate, error) {
+	row := q.db.QueryRow(ctx, getExchangeRate, id)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Rate,
+		&i.EffectiveDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getExchangeRateOn = `-- name: GetExchangeRateOn :one
+SELECT id, currency, rate, effective_date, created_at, updated_at
+FROM exchange_rates
+WHERE currency = $1 AND effective_date = $2
+`
+
+type GetExchangeRateOnParams struct {
+	Currency      string      `json:"currency"`
+	EffectiveDate pgtype.Date `json:"effective_date"`
+}
+
+func (q *Queries) GetExchangeRateOn(ctx context.Context, arg GetExchangeRateOnParams) (ExchangeRate, error) {
+	row := q.db.QueryRow(ctx, getExchangeRateOn, arg.Currency, arg.EffectiveDate)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Rate,
+		&i.EffectiveDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listExchangeRates = `-- name: ListExchangeRates :many
+SELECT id, currency, rate, effective_date, created_at, updated_at
+FROM exchange_rates
+WHERE $1::text IS NULL OR currency = $1::text
+ORDER BY currency, effective_date DESC
+`
+
+// newest first per currency
+func (q *Queries) ListExchangeRates(ctx context.Context, currency pgtype.Text) ([]ExchangeRate, error) {
+	rows, err := q.db.Query(ctx, listExchangeRates, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExchangeRate
+	for rows.Next() {
+		var i ExchangeRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Rate,
+			&i.EffectiveDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExchangeRatesOn = `-- name: ListExchangeRatesOn :many
+SELECT DISTINCT ON (currency) id, currency, rate, effective_date, created_at, updated_at
+FROM exchange_rates
+WHERE effective_date <= $1::date
+ORDER BY currency, effective_date DESC
+`
+
+// the rate in effect on a date for each currency: its latest one effective on or before it
+func (q *Queries) ListExchangeRatesOn(ctx context.Context, asOf pgtype.Date) ([]ExchangeRate, error) {
+	rows, err := q.db.Query(ctx, listExchangeRatesOn, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExchangeRate
+	for rows.Next() {
+		var i ExchangeRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Rate,
+			&i.EffectiveDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertExchangeRate = `-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (id, currency, rate, effective_date, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $5)
+ON CONFLICT (currency, effective_date) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at
+RETURNING id, currency, rate, effective_date, created_at, updated_at
+`
+
+type UpsertExchangeRateParams struct {
+	ID            uuid.UUID        `json:"id"`
+	Currency      string           `json:"currency"`
+	Rate          money.Rate       `json:"rate"`
+	EffectiveDate pgtype.Date      `json:"effective_date"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+// a second rate for the same currency and day replaces the first
+func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error) {
+	row := q.db.QueryRow(ctx, upsertExchangeRate,
+		arg.ID,
+		arg.Currency,
+		arg.Rate,
+		arg.EffectiveDate,
+		arg.CreatedAt,
+	)
+	var i ExchangeRate
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Rate,
+		&i.EffectiveDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,301 @@
+package repo
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+type LeaveRepo interface {
+	CreateLeaveType(ctx context.Context, leaveType *database.LeaveType) error
+	GetLeaveType(ctx context.Context, id uuid.UUID) (*database.LeaveType, error)
+	ListLeaveTypes(ctx context.Context) ([]database.LeaveType, error)
+	//UpsertLeaveAccrual sets the days the employee has accrued, creating the balance on first use
+	UpsertLeaveAccrual(ctx context.Context, employeeID, leaveTypeID uuid.UUID, accrued float64) error
+	ListLeaveBalances(ctx context.Context, employeeID uuid.UUID) ([]database.LeaveBalance, error)
+	//GetLeaveBalanceForUpdate reads the balance and locks it until the transaction ends
+	GetLeaveBalanceForUpdate(ctx context.Context, employeeID, leaveTypeID uuid.UUID) (*database.LeaveBalance, error)
+	//AddLeaveUsed adds days, which may be negative, to the days used from the balance
+	AddLeaveUsed(ctx context.Context, employeeID, leaveTypeID uuid.UUID, days float64) error
+	CreateLeaveRequest(ctx context.Context, req *database.LeaveRequest) error
+	GetLeaveRequest(ctx context.Context, id uuid.UUID) (*database.LeaveRequest, error)
+	//GetLeaveRequestForUpdate reads the request and locks it until the transaction ends
+	GetLeaveRequestForUpdate(ctx context.Context, id uuid.UUID) (*database.LeaveRequest, error)
+	//UpdateLeaveRequestStatus stores the request's status and decision fields
+	UpdateLeaveRequestStatus(ctx context.Context, req *database.LeaveRequest) error
+	//CountOverlappingLeave counts the employee's pending and approved requests sharing a date with start to end
+	CountOverlappingLeave(ctx context.Context, employeeID uuid.UUID, start, end time.Time) (int64, error)
+	//ListEmployeeLeaveRequests returns the employee's requests, latest first; a nil status lists them all
+	ListEmployeeLeaveRequests(ctx context.Context, employeeID uuid.UUID, status *string) ([]database.LeaveRequest, error)
+	ListLeaveCalendar(ctx context.Context, filter database.LeaveCalendarFilter) ([]database.LeaveCalendarEntry, error)
+
+	//WithTx returns a repo whose queries run inside tx
+	WithTx(tx pgx.Tx) LeaveRepo
+}
+
+type leaveRepo struct {
+	queries *Queries
+}
+
+func NewLeaveRepo(db DBTX) LeaveRepo {
+	return &leaveRepo{
+		queries: New(db),
+	}
+}
+
+func (r *leaveRepo) WithTx(tx pgx.Tx) LeaveRepo {
+	return &leaveRepo{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
+func (r *leaveRepo) CreateLeaveType(ctx context.Context, leaveType *database.LeaveType) error {
+	leaveType.ID = uuid.New()
+	err := r.queries.CreateLeaveType(ctx, CreateLeaveTypeParams{
+		ID:          leaveType.ID,
+		Name:        leaveType.Name,
+		DaysPerYear: leaveType.DaysPerYear,
+		Unpaid:      leaveType.Unpaid,
+		CreatedAt:   pgtype.Timestamp{Time: leaveType.CreatedAt, Valid: true},
+		UpdatedAt:   pgtype.Timestamp{Time: leaveType.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "create", "leave type")
+	}
+	return nil
+}
+
+func (r *leaveRepo) GetLeaveType(ctx context.Context, id uuid.UUID) (*database.LeaveType, error) {
+	row, err := r.queries.GetLeaveType(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "leave type")
+	}
+	leaveType := toLeaveType(row)
+	return &leaveType, nil
+}
+
+func (r *leaveRepo) ListLeaveTypes(ctx context.Context) ([]database.LeaveType, error) {
+	rows, err := r.queries.ListLeaveTypes(ctx)
+	if err != nil {
+		return nil, dbError(err, "list", "leave types")
+	}
+	leaveTypes := make([]database.LeaveType, len(rows))
+	for i, row := range rows {
+		leaveTypes[i] = toLeaveType(row)
+	}
+	return leaveTypes, nil
+}
+
+func (r *leaveRepo) UpsertLeaveAccrual(ctx context.Context, employeeID, leaveTypeID uuid.UUID, accrued float64) error {
+	err := r.queries.UpsertLeaveAccrual(ctx, UpsertLeaveAccrualParams{
+		EmployeeID:  employeeID,
+		LeaveTypeID: leaveTypeID,
+		Accrued:     accrued,
+	})
+	if err != nil {
+		return dbError(err, "accrue", "leave balance")
+	}
+	return nil
+}
+
+func (r *leaveRepo) ListLeaveBalances(ctx context.Context, employeeID uuid.UUID) ([]database.LeaveBalance, error) {
+	rows, err := r.queries.ListLeaveBalances(ctx, employeeID)
+	if err != nil {
+		return nil, dbError(err, "list", "leave balances")
+	}
+	balances := make([]database.LeaveBalance, len(rows))
+	for i, row := range rows {
+		balances[i] = toLeaveBalance(row.LeaveTypeID, row.LeaveType, row.Accrued, row.Used, row.Pending)
+	}
+	return balances, nil
+}
+
+func (r *leaveRepo) GetLeaveBalanceForUpdate(ctx context.Context, employeeID, leaveTypeID uuid.UUID) (*database.LeaveBalance, error) {
+	row, err := r.queries.GetLeaveBalanceForUpdate(ctx, GetLeaveBalanceForUpdateParams{
+		EmployeeID:  employeeID,
+		LeaveTypeID: leaveTypeID,
+	})
+	if err != nil {
+		return nil, dbError(err, "get", "leave balance")
+	}
+	balance := toLeaveBalance(row.LeaveTypeID, row.LeaveType, row.Accrued, row.Used, row.Pending)
+	return &balance, nil
+}
+
+func (r *leaveRepo) AddLeaveUsed(ctx context.Context, employeeID, leaveTypeID uuid.UUID, days float64) error {
+	rows, err := r.queries.AddLeaveUsed(ctx, AddLeaveUsedParams{
+		Days:        days,
+		EmployeeID:  employeeID,
+		LeaveTypeID: leaveTypeID,
+	})
+	if err != nil {
+		return dbError(err, "update", "leave balance")
+	}
+	if rows == 0 {
+		return customerr.NotFound("leave balance not found")
+	}
+	return nil
+}
+
+func (r *leaveRepo) CreateLeaveRequest(ctx context.Context, req *database.LeaveRequest) error {
+	req.ID = uuid.New()
+	err := r.queries.CreateLeaveRequest(ctx, CreateLeaveRequestParams{
+		ID:          req.ID,
+		EmployeeID:  req.EmployeeID,
+		LeaveTypeID: req.LeaveTypeID,
+		StartDate:   pgtype.Date{Time: req.StartDate, Valid: true},
+		EndDate:     pgtype.Date{Time: req.EndDate, Valid: true},
+		Days:        req.Days,
+		Reason:      req.Reason,
+		Status:      req.Status,
+		CreatedAt:   pgtype.Timestamp{Time: req.CreatedAt, Valid: true},
+		UpdatedAt:   pgtype.Timestamp{Time: req.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "create", "leave request")
+	}
+	return nil
+}
+
+func (r *leaveRepo) GetLeaveRequest(ctx context.Context, id uuid.UUID) (*database.LeaveRequest, error) {
+	row, err := r.queries.GetLeaveRequest(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "leave request")
+	}
+	req := toLeaveRequest(row)
+	return &req, nil
+}
+
+func (r *leaveRepo) GetLeaveRequestForUpdate(ctx context.Context, id uuid.UUID) (*database.LeaveRequest, error) {
+	row, err := r.queries.GetLeaveRequestForUpdate(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "leave request")
+	}
+	req := toLeaveRequest(row)
+	return &req, nil
+}
+
+func (r *leaveRepo) UpdateLeaveRequestStatus(ctx context.Context, req *database.LeaveRequest) error {
+	params := UpdateLeaveRequestStatusParams{
+		ID:             req.ID,
+		Status:         req.Status,
+		DecidedBy:      pgUUID(req.DecidedBy),
+		DecidedByEmail: req.DecidedByEmail,
+		DecisionNote:   req.DecisionNote,
+		UpdatedAt:      pgtype.Timestamp{Time: req.UpdatedAt, Valid: true},
+	}
+	if req.DecidedAt != nil {
+		params.DecidedAt = pgtype.Timestamp{Time: *req.DecidedAt, Valid: true}
+	}
+	rows, err := r.queries.UpdateLeaveRequestStatus(ctx, params)
+	if err != nil {
+		return dbError(err, "update", "leave request")
+	}
+	if rows == 0 {
+		return customerr.NotFound("leave request not found")
+	}
+	return nil
+}
+
+func (r *leaveRepo) CountOverlappingLeave(ctx context.Context, employeeID uuid.UUID, start, end time.Time) (int64, error) {
+	count, err := r.queries.CountOverlappingLeave(ctx, CountOverlappingLeaveParams{
+		EmployeeID: employeeID,
+		StartDate:  pgtype.Date{Time: start, Valid: true},
+		EndDate:    pgtype.Date{Time: end, Valid: true},
+	})
+	if err != nil {
+		return 0, dbError(err, "count", "leave requests")
+	}
+	return count, nil
+}
+
+func (r *leaveRepo) ListEmployeeLeaveRequests(ctx context.Context, employeeID uuid.UUID, status *string) ([]database.LeaveRequest, error) {
+	rows, err := r.queries.ListEmployeeLeaveRequests(ctx, ListEmployeeLeaveRequestsParams{
+		EmployeeID: employeeID,
+		Status:     pgText(status),
+	})
+	if err != nil {
+		return nil, dbError(err, "list", "leave requests")
+	}
+	requests := make([]database.LeaveRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = toLeaveRequest(row)
+	}
+	return requests, nil
+}
+
+func (r *leaveRepo) ListLeaveCalendar(ctx context.Context, filter database.LeaveCalendarFilter) ([]database.LeaveCalendarEntry, error) {
+	rows, err := r.queries.ListLeaveCalendar(ctx, ListLeaveCalendarParams{
+		FromDate:       pgtype.Date{Time: filter.From, Valid: true},
+		ToDate:         pgtype.Date{Time: filter.To, Valid: true},
+		IncludePending: filter.IncludePending,
+		DepartmentID:   pgUUID(filter.DepartmentID),
+		ManagerID:      pgUUID(filter.ManagerID),
+	})
+	if err != nil {
+		return nil, dbError(err, "list", "leave calendar")
+	}
+	entries := make([]database.LeaveCalendarEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = database.LeaveCalendarEntry{
+			RequestID:    row.ID,
+			EmployeeID:   row.EmployeeID,
+			EmployeeName: row.EmployeeName,
+			LeaveTypeID:  row.LeaveTypeID,
+			LeaveType:    row.LeaveType,
+			StartDate:    row.StartDate.Time,
+			EndDate:      row.EndDate.Time,
+			Days:         row.Days,
+			Status:       row.Status,
+		}
+	}
+	return entries, nil
+}
+
+func toLeaveType(row LeaveType) database.LeaveType {
+	return database.LeaveType{
+		ID:          row.ID,
+		Name:        row.Name,
+		DaysPerYear: row.DaysPerYear,
+		Unpaid:      row.Unpaid,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
+
+func toLeaveBalance(leaveTypeID uuid.UUID, leaveType string, accrued, used, pending float64) database.LeaveBalance {
+	return database.LeaveBalance{
+		LeaveTypeID: leaveTypeID,
+		LeaveType:   leaveType,
+		Accrued:     accrued,
+		Used:        used,
+		Pending:     pending,
+		//rounded so that float sums of day fractions read cleanly
+		Available: math.Round((accrued-used-pending)*100) / 100,
+	}
+}
+
+func toLeaveRequest(row LeaveRequest) database.LeaveRequest {
+	return database.LeaveRequest{
+		ID:             row.ID,
+		EmployeeID:     row.EmployeeID,
+		LeaveTypeID:    row.LeaveTypeID,
+		StartDate:      row.StartDate.Time,
+		EndDate:        row.EndDate.Time,
+		Days:           row.Days,
+		Reason:         row.Reason,
+		Status:         row.Status,
+		DecidedBy:      uuidPtr(row.DecidedBy),
+		DecidedByEmail: row.DecidedByEmail,
+		DecidedAt:      timePtr(row.DecidedAt),
+		DecisionNote:   row.DecisionNote,
+		CreatedAt:      row.CreatedAt.Time,
+		UpdatedAt:      row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,485 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: leave.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addLeaveUsed = `-- name: AddLeaveUsed :execrows
+UPDATE leave_balances
+SET used = used + $1, updated_at = CURRENT_TIMESTAMP
+WHERE employee_id = $2 AND leave_type_id = $3
+`
+
+type AddLeaveUsedParams struct {
+	Days        float64   `json:"days"`
+	EmployeeID  uuid.UUID `json:"employee_id"`
+	LeaveTypeID uuid.UUID `json:"leave_type_id"`
+}
+
+func (q *Queries) AddLeaveUsed(ctx context.Context, arg AddLeaveUsedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, addLeaveUsed, arg.Days, arg.EmployeeID, arg.LeaveTypeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countOverlappingLeave = `-- name: CountOverlappingLeave :one
+SELECT COUNT(*)
+FROM leave_requests
+WHERE employee_id = $1
+  AND status IN ('pending', 'approved')
+  AND start_date <= $2::date
+  AND end_date >= $3::date
+`
+
+type CountOverlappingLeaveParams struct {
+	EmployeeID uuid.UUID   `json:"employee_id"`
+	EndDate    pgtype.Date `json:"end_date"`
+	StartDate  pgtype.Date `json:"start_date"`
+}
+
+// requests that still hold days and share at least one date with the range
+func (q *Queries) CountOverlappingLeave(ctx context.Context, arg CountOverlappingLeaveParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countOverlappingLeave, arg.EmployeeID, arg.EndDate, arg.StartDate)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createLeaveRequest = `-- name: CreateLeaveRequest :exec
+INSERT INTO leave_requests (id, employee_id, leave_type_id, start_date, end_date, days, reason, status, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+type CreateLeaveRequestParams struct {
+	ID          uuid.UUID        `json:"id"`
+	EmployeeID  uuid.UUID        `json:"employee_id"`
+	LeaveTypeID uuid.UUID        `json:"leave_type_id"`
+	StartDate   pgtype.Date      `json:"start_date"`
+	EndDate     pgtype.Date      `json:"end_date"`
+	Days        float64          `json:"days"`
+	Reason      string           `json:"reason"`
+	Status      string           `json:"status"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) CreateLeaveRequest(ctx context.Context, arg CreateLeaveRequestParams) error {
+	_, err := q.db.Exec(ctx, createLeaveRequest,
+		arg.ID,
+		arg.EmployeeID,
+		arg.LeaveTypeID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Days,
+		arg.Reason,
+		arg.Status,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const createLeaveType = `-- name: CreateLeaveType :exec
+INSERT INTO leave_types (id, name, days_per_year, unpaid, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateLeaveTypeParams struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	DaysPerYear float64          `json:"days_per_year"`
+	Unpaid      bool             `json:"unpaid"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) CreateLeaveType(ctx context.Context, arg CreateLeaveTypeParams) error {
+	_, err := q.db.Exec(ctx, createLeaveType,
+		arg.ID,
+		arg.Name,
+		arg.DaysPerYear,
+		arg.Unpaid,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const getLeaveBalanceForUpdate = `-- name: GetLeaveBalanceForUpdate :one
+SELECT b.leave_type_id, t.name AS leave_type, b.accrued, b.used,
+       COALESCE((SELECT SUM(r.days) FROM leave_requests r
+                 WHERE r.employee_id = b.employee_id AND r.leave_type_id = b.leave_type_id
+                   AND r.status = 'pending'), 0)::float8 AS pending
+FROM leave_balances b
+JOIN leave_types t ON t.id = b.leave_type_id
+WHERE b.employee_id = $1 AND b.leave_type_id = $2
+FOR UPDATE OF b
+`
+
+type GetLeaveBalanceForUpdateParams struct {
+	EmployeeID  uuid.UUID `json:"employee_id"`
+	LeaveTypeID uuid.UUID `json:"leave_type_id"`
+}
+
+type GetLeaveBalanceForUpdateRow struct {
+	LeaveTypeID uuid.UUID `json:"leave_type_id"`
+	LeaveType   string    `json:"leave_type"`
+	Accrued     float64   `json:"accrued"`
+	Used        float64   `json:"used"`
+	Pending     float64   `json:"pending"`
+}
+
+func (q *Queries) GetLeaveBalanceForUpdate(ctx context.Context, arg GetLeaveBalanceForUpdateParams) (GetLeaveBalanceForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getLeaveBalanceForUpdate, arg.EmployeeID, arg.LeaveTypeID)
+	var i GetLeaveBalanceForUpdateRow
+	err := row.Scan(
+		&i.LeaveTypeID,
+		&i.LeaveType,
+		&i.Accrued,
+		&i.Used,
+		&i.Pending,
+	)
+	return i, err
+}
+
+const getLeaveRequest = `-- name: GetLeaveRequest :one
+SELECT id, employee_id, leave_type_id, start_date, end_date, days, reason, status,
+       decided_by, decided_by_email, decided_at, decision_note, created_at, updated_at
+FROM leave_requests
+WHERE id = $1
+`
+
+func (q *Queries) GetLeaveRequest(ctx context.Context, id uuid.UUID) (LeaveRequest, error) {
+	row := q.db.QueryRow(ctx, getLeaveRequest, id)
+	var i LeaveRequest
+	err := row.Scan(
+		&i.ID,
+		&i.EmployeeID,
+		&i.LeaveTypeID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Days,
+		&i.Reason,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedByEmail,
+		&i.DecidedAt,
+		&i.DecisionNote,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getLeaveRequestForUpdate = `-- name: GetLeaveRequestForUpdate :one
+SELECT id, employee_id, leave_type_id, start_date, end_date, days, reason, status,
+       decided_by, decided_by_email, decided_at, decision_note, created_at, updated_at
+FROM leave_requests
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetLeaveRequestForUpdate(ctx context.Context, id uuid.UUID) (LeaveRequest, error) {
+	row := q.db.QueryRow(ctx, getLeaveRequestForUpdate, id)
+	var i LeaveRequest
+	err := row.Scan(
+		&i.ID,
+		&i.EmployeeID,
+		&i.LeaveTypeID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Days,
+		&i.Reason,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedByEmail,
+		&i.DecidedAt,
+		&i.DecisionNote,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getLeaveType = `-- name: GetLeaveType :one
+SELECT id, name, days_per_year, created_at, updated_at, unpaid
+FROM leave_types
+WHERE id = $1
+`
+
+func (q *Queries) GetLeaveType(ctx context.Context, id uuid.UUID) (LeaveType, error) {
+	row := q.db.QueryRow(ctx, getLeaveType, id)
+	var i LeaveType
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DaysPerYear,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Unpaid,
+	)
+	return i, err
+}
+
+const listEmployeeLeaveRequests = `-- name: ListEmployeeLeaveRequests :many
+SELECT id, employee_id, leave_type_id, start_date, end_date, days, reason, status,
+       decided_by, decided_by_email, decided_at, decision_note, created_at, updated_at
+FROM leave_requests
+WHERE employee_id = $1
+  AND ($2::text IS NULL OR status = $2::text)
+ORDER BY start_date DESC, created_at DESC, id DESC
+`
+
+type ListEmployeeLeaveRequestsParams struct {
+	EmployeeID uuid.UUID   `json:"employee_id"`
+	Status     pgtype.Text `json:"status"`
+}
+
+// newest first; a null status lists every request
+func (q *Queries) ListEmployeeLeaveRequests(ctx context.Context, arg ListEmployeeLeaveRequestsParams) ([]LeaveRequest, error) {
+	rows, err := q.db.Query(ctx, listEmployeeLeaveRequests, arg.EmployeeID, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LeaveRequest
+	for rows.Next() {
+		var i LeaveRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.EmployeeID,
+			&i.LeaveTypeID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Days,
+			&i.Reason,
+			&i.Status,
+			&i.DecidedBy,
+			&i.DecidedByEmail,
+			&i.DecidedAt,
+			&i.DecisionNote,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLeaveBalances = `-- name: ListLeaveBalances :many
+SELECT b.leave_type_id, t.name AS leave_type, b.accrued, b.used,
+       COALESCE((SELECT SUM(r.days) FROM leave_requests r
+                 WHERE r.employee_id = b.employee_id AND r.leave_type_id = b.leave_type_id
+                   AND r.status = 'pending'), 0)::float8 AS pending
+FROM leave_balances b
+JOIN leave_types t ON t.id = b.leave_type_id
+WHERE b.employee_id = $1
+ORDER BY t.name
+`
+
+type ListLeaveBalancesRow struct {
+	LeaveTypeID uuid.UUID `json:"leave_type_id"`
+	LeaveType   string    `json:"leave_type"`
+	Accrued     float64   `json:"accrued"`
+	Used        float64   `json:"used"`
+	Pending     float64   `json:"pending"`
+}
+
+// pending sums the days of requests still awaiting a decision
+func (q *Queries) ListLeaveBalances(ctx context.Context, employeeID uuid.UUID) ([]ListLeaveBalancesRow, error) {
+	rows, err := q.db.Query(ctx, listLeaveBalances, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLeaveBalancesRow
+	for rows.Next() {
+		var i ListLeaveBalancesRow
+		if err := rows.Scan(
+			&i.LeaveTypeID,
+			&i.LeaveType,
+			&i.Accrued,
+			&i.Used,
+			&i.Pending,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLeaveCalendar = `-- name: ListLeaveCalendar :many
+SELECT r.id, r.employee_id, e.name AS employee_name, r.leave_type_id, t.name AS leave_type,
+       r.start_date, r.end_date, r.days, r.status
+FROM leave_requests r
+JOIN employees e ON e.id = r.employee_id
+JOIN leave_types t ON t.id = r.leave_type_id
+WHERE e.deleted_at IS NULL
+  AND r.start_date <= $1::date
+  AND r.end_date >= $2::date
+  AND (r.status = 'approved' OR ($3::bool AND r.status = 'pending'))
+  AND ($4::uuid IS NULL OR e.department_id = $4::uuid)
+  AND ($5::uuid IS NULL OR e.manager_id = $5::uuid)
+ORDER BY r.start_date, e.name, r.id
+`
+
+type ListLeaveCalendarParams struct {
+	ToDate         pgtype.Date `json:"to_date"`
+	FromDate       pgtype.Date `json:"from_date"`
+	IncludePending bool        `json:"include_pending"`
+	DepartmentID   pgtype.UUID `json:"department_id"`
+	ManagerID      pgtype.UUID `json:"manager_id"`
+}
+
+type ListLeaveCalendarRow struct {
+	ID           uuid.UUID   `json:"id"`
+	EmployeeID   uuid.UUID   `json:"employee_id"`
+	EmployeeName string      `json:"employee_name"`
+	LeaveTypeID  uuid.UUID   `json:"leave_type_id"`
+	LeaveType    string      `json:"leave_type"`
+	StartDate    pgtype.Date `json:"start_date"`
+	EndDate      pgtype.Date `json:"end_date"`
+	Days         float64     `json:"days"`
+	Status       string      `json:"status"`
+}
+
+// leave overlapping the range for the employees in a department or reporting
+// to a manager; pending requests are included on request
+func (q *Queries) ListLeaveCalendar(ctx context.Context, arg ListLeaveCalendarParams) ([]ListLeaveCalendarRow, error) {
+	rows, err := q.db.Query(ctx, listLeaveCalendar,
+		arg.ToDate,
+		arg.FromDate,
+		arg.IncludePending,
+		arg.DepartmentID,
+		arg.ManagerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLeaveCalendarRow
+	for rows.Next() {
+		var i ListLeaveCalendarRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EmployeeID,
+			&i.EmployeeName,
+			&i.LeaveTypeID,
+			&i.LeaveType,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Days,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLeaveTypes = `-- name: ListLeaveTypes :many
+SELECT id, name, days_per_year, created_at, updated_at, unpaid
+FROM leave_types
+ORDER BY name
+`
+
+func (q *Queries) ListLeaveTypes(ctx context.Context) ([]LeaveType, error) {
+	rows, err := q.db.Query(ctx, listLeaveTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LeaveType
+	for rows.Next() {
+		var i LeaveType
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.DaysPerYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Unpaid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateLeaveRequestStatus = `-- name: UpdateLeaveRequestStatus :execrows
+UPDATE leave_requests
+SET status = $2, decided_by = $3, decided_by_email = $4, decided_at = $5, decision_note = $6, updated_at = $7
+WHERE id = $1
+`
+
+type UpdateLeaveRequestStatusParams struct {
+	ID             uuid.UUID        `json:"id"`
+	Status         string           `json:"status"`
+	DecidedBy      pgtype.UUID      `json:"decided_by"`
+	DecidedByEmail string           `json:"decided_by_email"`
+	DecidedAt      pgtype.Timestamp `json:"decided_at"`
+	DecisionNote   string           `json:"decision_note"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) UpdateLeaveRequestStatus(ctx context.Context, arg UpdateLeaveRequestStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateLeaveRequestStatus,
+		arg.ID,
+		arg.Status,
+		arg.DecidedBy,
+		arg.DecidedByEmail,
+		arg.DecidedAt,
+		arg.DecisionNote,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertLeaveAccrual = `-- name: UpsertLeaveAccrual :exec
+INSERT INTO leave_balances (employee_id, leave_type_id, accrued, updated_at)
+VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+ON CONFLICT (employee_id, leave_type_id)
+DO UPDATE SET accrued = EXCLUDED.accrued, updated_at = EXCLUDED.updated_at
+`
+
+type UpsertLeaveAccrualParams struct {
+	EmployeeID  uuid.UUID `json:"employee_id"`
+	LeaveTypeID uuid.UUID `json:"leave_type_id"`
+	Accrued     float64   `json:"accrued"`
+}
+
+// sets the days accrued so far, creating the balance on first use
+func (q *Queries) UpsertLeaveAccrual(ctx context.Context, arg UpsertLeaveAccrualParams) error {
+	_, err := q.db.Exec(ctx, upsertLeaveAccrual, arg.EmployeeID, arg.LeaveTypeID, arg.Accrued)
+	return err
+}
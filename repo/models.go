@@ -7,14 +7,163 @@ package repo
 import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/money"
 )
 
+type AttendanceRecord struct {
+	ID         uuid.UUID        `json:"id"`
+	EmployeeID uuid.UUID        `json:"employee_id"`
+	ClockIn    pgtype.Timestamp `json:"clock_in"`
+	ClockOut   pgtype.Timestamp `json:"clock_out"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
+
+type AuditLog struct {
+	ID         uuid.UUID        `json:"id"`
+	ActorID    pgtype.UUID      `json:"actor_id"`
+	ActorEmail string           `json:"actor_email"`
+	Action     string           `json:"action"`
+	EntityType string           `json:"entity_type"`
+	EntityID   uuid.UUID        `json:"entity_id"`
+	Changes    []byte           `json:"changes"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type Department struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
 type Employee struct {
-	ID        uuid.UUID        `json:"id"`
-	Name      string           `json:"name"`
-	Position  string           `json:"position"`
-	Salary    float64          `json:"salary"`
-	HiredDate pgtype.Date      `json:"hired_date"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
-	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	ID              uuid.UUID        `json:"id"`
+	Name            string           `json:"name"`
+	Position        string           `json:"position"`
+	Salary          money.Amount     `json:"salary"`
+	HiredDate       pgtype.Date      `json:"hired_date"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+	DepartmentID    pgtype.UUID      `json:"department_id"`
+	ManagerID       pgtype.UUID      `json:"manager_id"`
+	DeletedAt       pgtype.Timestamp `json:"deleted_at"`
+	Version         int64            `json:"version"`
+	Currency        string           `json:"currency"`
+	TerminationDate pgtype.Date      `json:"termination_date"`
+}
+
+type ExchangeRate struct {
+	ID            uuid.UUID        `json:"id"`
+	Currency      string           `json:"currency"`
+	Rate          money.Rate       `json:"rate"`
+	EffectiveDate pgtype.Date      `json:"effective_date"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+}
+
+type LeaveBalance struct {
+	EmployeeID  uuid.UUID        `json:"employee_id"`
+	LeaveTypeID uuid.UUID        `json:"leave_type_id"`
+	Accrued     float64          `json:"accrued"`
+	Used        float64          `json:"used"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+type LeaveRequest struct {
+	ID             uuid.UUID        `json:"id"`
+	EmployeeID     uuid.UUID        `json:"employee_id"`
+	LeaveTypeID    uuid.UUID        `json:"leave_type_id"`
+	StartDate      pgtype.Date      `json:"start_date"`
+	EndDate        pgtype.Date      `json:"end_date"`
+	Days           float64          `json:"days"`
+	Reason         string           `json:"reason"`
+	Status         string           `json:"status"`
+	DecidedBy      pgtype.UUID      `json:"decided_by"`
+	DecidedByEmail string           `json:"decided_by_email"`
+	DecidedAt      pgtype.Timestamp `json:"decided_at"`
+	DecisionNote   string           `json:"decision_note"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+type LeaveType struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	DaysPerYear float64          `json:"days_per_year"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	Unpaid      bool             `json:"unpaid"`
+}
+
+type PayrollRule struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Kind        string           `json:"kind"`
+	Calculation string           `json:"calculation"`
+	Amount      money.Amount     `json:"amount"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	Currency    string           `json:"currency"`
+}
+
+type PayrollRun struct {
+	ID               uuid.UUID        `json:"id"`
+	PeriodStart      pgtype.Date      `json:"period_start"`
+	PeriodEnd        pgtype.Date      `json:"period_end"`
+	Status           string           `json:"status"`
+	EmployeeCount    int32            `json:"employee_count"`
+	CreatedBy        pgtype.UUID      `json:"created_by"`
+	CreatedByEmail   string           `json:"created_by_email"`
+	ApprovedBy       pgtype.UUID      `json:"approved_by"`
+	ApprovedByEmail  string           `json:"approved_by_email"`
+	ApprovedAt       pgtype.Timestamp `json:"approved_at"`
+	FinalizedBy      pgtype.UUID      `json:"finalized_by"`
+	FinalizedByEmail string           `json:"finalized_by_email"`
+	FinalizedAt      pgtype.Timestamp `json:"finalized_at"`
+	ComputedAt       pgtype.Timestamp `json:"computed_at"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+}
+
+type Payslip struct {
+	ID              uuid.UUID        `json:"id"`
+	RunID           uuid.UUID        `json:"run_id"`
+	EmployeeID      uuid.UUID        `json:"employee_id"`
+	EmployeeName    string           `json:"employee_name"`
+	Position        string           `json:"position"`
+	AnnualSalary    money.Amount     `json:"annual_salary"`
+	WorkingDays     int32            `json:"working_days"`
+	PaidDays        int32            `json:"paid_days"`
+	UnpaidLeaveDays int32            `json:"unpaid_leave_days"`
+	Gross           money.Amount     `json:"gross"`
+	Allowances      money.Amount     `json:"allowances"`
+	Deductions      money.Amount     `json:"deductions"`
+	Net             money.Amount     `json:"net"`
+	Lines           []byte           `json:"lines"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	Currency        string           `json:"currency"`
+}
+
+type SalaryHistory struct {
+	ID              uuid.UUID        `json:"id"`
+	EmployeeID      uuid.UUID        `json:"employee_id"`
+	Salary          money.Amount     `json:"salary"`
+	EffectiveDate   pgtype.Date      `json:"effective_date"`
+	Reason          string           `json:"reason"`
+	ApprovedBy      pgtype.UUID      `json:"approved_by"`
+	ApprovedByEmail string           `json:"approved_by_email"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	Currency        string           `json:"currency"`
+}
+
+type User struct {
+	ID           uuid.UUID        `json:"id"`
+	Email        string           `json:"email"`
+	PasswordHash string           `json:"password_hash"`
+	Role         string           `json:"role"`
+	EmployeeID   pgtype.UUID      `json:"employee_id"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 }
@@ -0,0 +1,456 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+type PayrollRepo interface {
+	CreateRule(ctx context.Context, rule *database.PayrollRule) error
+	GetRule(ctx context.Context, id uuid.UUID) (*database.PayrollRule, error)
+	ListRules(ctx context.Context) ([]database.PayrollRule, error)
+	UpdateRule(ctx context.Context, rule *database.PayrollRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+
+	//ListPayrollEmployees returns everyone employed for part of the period with
+	//their salary records and approved unpaid leave
+	ListPayrollEmployees(ctx context.Context, start, end time.Time) ([]database.PayrollEmployee, error)
+
+	CreateRun(ctx context.Context, run *database.PayrollRun) error
+	//GetRun, GetRunForUpdate and ListRuns sum each run's payslips into its totals
+	GetRun(ctx context.Context, id uuid.UUID) (*database.PayrollRun, error)
+	//GetRunForUpdate locks the run until the transaction ends
+	GetRunForUpdate(ctx context.Context, id uuid.UUID) (*database.PayrollRun, error)
+	ListRuns(ctx context.Context) ([]database.PayrollRun, error)
+	//UpdateRunComputed stores the employee count and time of a draft run's recomputed payslips
+	UpdateRunComputed(ctx context.Context, run *database.PayrollRun) error
+	//ApproveRun moves a draft run to approved
+	ApproveRun(ctx context.Context, run *database.PayrollRun) error
+	//FinalizeRun moves an approved run to finalized
+	FinalizeRun(ctx context.Context, run *database.PayrollRun) error
+
+	CreatePayslip(ctx context.Context, slip *database.Payslip) error
+	//DeletePayslips removes the payslips of a draft run before it is recomputed
+	DeletePayslips(ctx context.Context, runID uuid.UUID) error
+	ListRunPayslips(ctx context.Context, runID uuid.UUID) ([]database.Payslip, error)
+	//ListEmployeePayslips returns the employee's payslips of finalized runs, latest first
+	ListEmployeePayslips(ctx context.Context, employeeID uuid.UUID) ([]database.Payslip, error)
+
+	//WithTx returns a repo whose queries run inside tx
+	WithTx(tx pgx.Tx) PayrollRepo
+}
+
+type payrollRepo struct {
+	queries *Queries
+}
+
+func NewPayrollRepo(db DBTX) PayrollRepo {
+	return &payrollRepo{
+		queries: New(db),
+	}
+}
+
+func (r *payrollRepo) WithTx(tx pgx.Tx) PayrollRepo {
+	return &payrollRepo{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
+func (r *payrollRepo) CreateRule(ctx context.Context, rule *database.PayrollRule) error {
+	rule.ID = uuid.New()
+	err := r.queries.CreatePayrollRule(ctx, CreatePayrollRuleParams{
+		ID:          rule.ID,
+		Name:        rule.Name,
+		Kind:        rule.Kind,
+		Calculation: rule.Calculation,
+		Amount:      rule.Amount,
+		Currency:    rule.Currency,
+		CreatedAt:   pgtype.Timestamp{Time: rule.CreatedAt, Valid: true},
+		UpdatedAt:   pgtype.Timestamp{Time: rule.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "create", "payroll rule")
+	}
+	return nil
+}
+
+func (r *payrollRepo) GetRule(ctx context.Context, id uuid.UUID) (*database.PayrollRule, error) {
+	row, err := r.queries.GetPayrollRule(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "payroll rule")
+	}
+	rule := toPayrollRule(row)
+	return &rule, nil
+}
+
+func (r *payrollRepo) ListRules(ctx context.Context) ([]database.PayrollRule, error) {
+	rows, err := r.queries.ListPayrollRules(ctx)
+	if err != nil {
+		return nil, dbError(err, "list", "payroll rules")
+	}
+	rules := make([]database.PayrollRule, len(rows))
+	for i, row := range rows {
+		rules[i] = toPayrollRule(row)
+	}
+	return rules, nil
+}
+
+func (r *payrollRepo) UpdateRule(ctx context.Context, rule *database.PayrollRule) error {
+	rows, err := r.queries.UpdatePayrollRule(ctx, UpdatePayrollRuleParams{
+		ID:          rule.ID,
+		Name:        rule.Name,
+		Kind:        rule.Kind,
+		Calculation: rule.Calculation,
+		Amount:      rule.Amount,
+		Currency:    rule.Currency,
+		UpdatedAt:   pgtype.Timestamp{Time: rule.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "update", "payroll rule")
+	}
+	if rows == 0 {
+		return customerr.NotFound("payroll rule not found")
+	}
+	return nil
+}
+
+func (r *payrollRepo) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	rows, err := r.queries.DeletePayrollRule(ctx, id)
+	if err != nil {
+		return dbError(err, "delete", "payroll rule")
+	}
+	if rows == 0 {
+		return customerr.NotFound("payroll rule not found")
+	}
+	return nil
+}
+
+func (r *payrollRepo) ListPayrollEmployees(ctx context.Context, start, end time.Time) ([]database.PayrollEmployee, error) {
+	rows, err := r.queries.ListPayrollEmployees(ctx, ListPayrollEmployeesParams{
+		PeriodStart: pgtype.Date{Time: start, Valid: true},
+		PeriodEnd:   pgtype.Date{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, dbError(err, "list", "payroll employees")
+	}
+	employees := make([]database.PayrollEmployee, len(rows))
+	byID := make(map[uuid.UUID]*database.PayrollEmployee, len(rows))
+	for i, row := range rows {
+		employees[i] = database.PayrollEmployee{
+			ID:              row.ID,
+			Name:            row.Name,
+			Position:        row.Position,
+			Salary:          row.Salary,
+			Currency:        row.Currency,
+			HiredDate:       row.HiredDate.Time,
+			TerminationDate: datePtr(row.TerminationDate),
+		}
+		byID[row.ID] = &employees[i]
+	}
+
+	salaries, err := r.queries.ListPayrollSalaries(ctx, ListPayrollSalariesParams{
+		PeriodStart: pgtype.Date{Time: start, Valid: true},
+		PeriodEnd:   pgtype.Date{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, dbError(err, "list", "payroll salaries")
+	}
+	for _, row := range salaries {
+		if emp, ok := byID[row.EmployeeID]; ok {
+			emp.Salaries = append(emp.Salaries, database.SalaryRecord{
+				EmployeeID:    row.EmployeeID,
+				Salary:        row.Salary,
+				Currency:      row.Currency,
+				EffectiveDate: row.EffectiveDate.Time,
+			})
+		}
+	}
+
+	leave, err := r.queries.ListUnpaidLeave(ctx, ListUnpaidLeaveParams{
+		PeriodStart: pgtype.Date{Time: start, Valid: true},
+		PeriodEnd:   pgtype.Date{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, dbError(err, "list", "unpaid leave")
+	}
+	for _, row := range leave {
+		if emp, ok := byID[row.EmployeeID]; ok {
+			emp.UnpaidLeave = append(emp.UnpaidLeave, database.LeaveRequest{
+				EmployeeID: row.EmployeeID,
+				StartDate:  row.StartDate.Time,
+				EndDate:    row.EndDate.Time,
+				Status:     database.LeaveStatusApproved,
+			})
+		}
+	}
+	return employees, nil
+}
+
+func (r *payrollRepo) CreateRun(ctx context.Context, run *database.PayrollRun) error {
+	run.ID = uuid.New()
+	err := r.queries.CreatePayrollRun(ctx, CreatePayrollRunParams{
+		ID:             run.ID,
+		PeriodStart:    pgtype.Date{Time: run.PeriodStart, Valid: true},
+		PeriodEnd:      pgtype.Date{Time: run.PeriodEnd, Valid: true},
+		Status:         run.Status,
+		EmployeeCount:  int32(run.EmployeeCount),
+		CreatedBy:      pgUUID(run.CreatedBy),
+		CreatedByEmail: run.CreatedByEmail,
+		ComputedAt:     pgtype.Timestamp{Time: run.ComputedAt, Valid: true},
+		CreatedAt:      pgtype.Timestamp{Time: run.CreatedAt, Valid: true},
+		UpdatedAt:      pgtype.Timestamp{Time: run.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "create", "payroll run")
+	}
+	return nil
+}
+
+func (r *payrollRepo) GetRun(ctx context.Context, id uuid.UUID) (*database.PayrollRun, error) {
+	row, err := r.queries.GetPayrollRun(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "payroll run")
+	}
+	runs := []database.PayrollRun{toPayrollRun(row)}
+	if err := r.withTotals(ctx, runs); err != nil {
+		return nil, err
+	}
+	return &runs[0], nil
+}
+
+func (r *payrollRepo) GetRunForUpdate(ctx context.Context, id uuid.UUID) (*database.PayrollRun, error) {
+	row, err := r.queries.GetPayrollRunForUpdate(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "payroll run")
+	}
+	runs := []database.PayrollRun{toPayrollRun(row)}
+	if err := r.withTotals(ctx, runs); err != nil {
+		return nil, err
+	}
+	return &runs[0], nil
+}
+
+func (r *payrollRepo) ListRuns(ctx context.Context) ([]database.PayrollRun, error) {
+	rows, err := r.queries.ListPayrollRuns(ctx)
+	if err != nil {
+		return nil, dbError(err, "list", "payroll runs")
+	}
+	runs := make([]database.PayrollRun, len(rows))
+	for i, row := range rows {
+		runs[i] = toPayrollRun(row)
+	}
+	if err := r.withTotals(ctx, runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// withTotals sums the payslips of runs into their totals, one per currency
+func (r *payrollRepo) withTotals(ctx context.Context, runs []database.PayrollRun) error {
+	ids := make([]uuid.UUID, len(runs))
+	byID := make(map[uuid.UUID]*database.PayrollRun, len(runs))
+	for i := range runs {
+		ids[i] = runs[i].ID
+		runs[i].Totals = []database.PayrollTotal{}
+		byID[runs[i].ID] = &runs[i]
+	}
+
+	rows, err := r.queries.ListPayrollRunTotals(ctx, ids)
+	if err != nil {
+		return dbError(err, "list", "payroll run totals")
+	}
+	for _, row := range rows {
+		if run, ok := byID[row.RunID]; ok {
+			run.Totals = append(run.Totals, database.PayrollTotal{
+				Currency:      row.Currency,
+				EmployeeCount: int(row.EmployeeCount),
+				Gross:         row.Gross,
+				Allowances:    row.Allowances,
+				Deductions:    row.Deductions,
+				Net:           row.Net,
+			})
+		}
+	}
+	return nil
+}
+
+func (r *payrollRepo) UpdateRunComputed(ctx context.Context, run *database.PayrollRun) error {
+	rows, err := r.queries.UpdatePayrollRunComputed(ctx, UpdatePayrollRunComputedParams{
+		ID:            run.ID,
+		EmployeeCount: int32(run.EmployeeCount),
+		ComputedAt:    pgtype.Timestamp{Time: run.ComputedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "update", "payroll run")
+	}
+	if rows == 0 {
+		return customerr.Conflict("only draft payroll runs can be recomputed")
+	}
+	return nil
+}
+
+func (r *payrollRepo) ApproveRun(ctx context.Context, run *database.PayrollRun) error {
+	rows, err := r.queries.ApprovePayrollRun(ctx, ApprovePayrollRunParams{
+		ID:              run.ID,
+		ApprovedBy:      pgUUID(run.ApprovedBy),
+		ApprovedByEmail: run.ApprovedByEmail,
+		ApprovedAt:      pgtype.Timestamp{Time: *run.ApprovedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "update", "payroll run")
+	}
+	if rows == 0 {
+		return customerr.Conflict("only draft payroll runs can be approved")
+	}
+	return nil
+}
+
+func (r *payrollRepo) FinalizeRun(ctx context.Context, run *database.PayrollRun) error {
+	rows, err := r.queries.FinalizePayrollRun(ctx, FinalizePayrollRunParams{
+		ID:               run.ID,
+		FinalizedBy:      pgUUID(run.FinalizedBy),
+		FinalizedByEmail: run.FinalizedByEmail,
+		FinalizedAt:      pgtype.Timestamp{Time: *run.FinalizedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "update", "payroll run")
+	}
+	if rows == 0 {
+		return customerr.Conflict("only approved payroll runs can be finalized")
+	}
+	return nil
+}
+
+func (r *payrollRepo) CreatePayslip(ctx context.Context, slip *database.Payslip) error {
+	slip.ID = uuid.New()
+	lines, err := json.Marshal(slip.Lines)
+	if err != nil {
+		return fmt.Errorf("failed to encode payslip lines: %w", err)
+	}
+	err = r.queries.CreatePayslip(ctx, CreatePayslipParams{
+		ID:              slip.ID,
+		RunID:           slip.RunID,
+		EmployeeID:      slip.EmployeeID,
+		EmployeeName:    slip.EmployeeName,
+		Position:        slip.Position,
+		AnnualSalary:    slip.AnnualSalary,
+		WorkingDays:     int32(slip.WorkingDays),
+		PaidDays:        int32(slip.PaidDays),
+		UnpaidLeaveDays: int32(slip.UnpaidLeaveDays),
+		Gross:           slip.Gross,
+		Allowances:      slip.Allowances,
+		Deductions:      slip.Deductions,
+		Net:             slip.Net,
+		Lines:           lines,
+		CreatedAt:       pgtype.Timestamp{Time: slip.CreatedAt, Valid: true},
+		Currency:        slip.Currency,
+	})
+	if err != nil {
+		return dbError(err, "create", "payslip")
+	}
+	return nil
+}
+
+func (r *payrollRepo) DeletePayslips(ctx context.Context, runID uuid.UUID) error {
+	if err := r.queries.DeletePayslips(ctx, runID); err != nil {
+		return dbError(err, "delete", "payslips")
+	}
+	return nil
+}
+
+func (r *payrollRepo) ListRunPayslips(ctx context.Context, runID uuid.UUID) ([]database.Payslip, error) {
+	rows, err := r.queries.ListRunPayslips(ctx, runID)
+	if err != nil {
+		return nil, dbError(err, "list", "payslips")
+	}
+	slips := make([]database.Payslip, len(rows))
+	for i, row := range rows {
+		if slips[i], err = toPayslip(ListEmployeePayslipsRow(row)); err != nil {
+			return nil, err
+		}
+	}
+	return slips, nil
+}
+
+func (r *payrollRepo) ListEmployeePayslips(ctx context.Context, employeeID uuid.UUID) ([]database.Payslip, error) {
+	rows, err := r.queries.ListEmployeePayslips(ctx, employeeID)
+	if err != nil {
+		return nil, dbError(err, "list", "payslips")
+	}
+	slips := make([]database.Payslip, len(rows))
+	for i, row := range rows {
+		if slips[i], err = toPayslip(row); err != nil {
+			return nil, err
+		}
+	}
+	return slips, nil
+}
+
+func toPayrollRule(row PayrollRule) database.PayrollRule {
+	return database.PayrollRule{
+		ID:          row.ID,
+		Name:        row.Name,
+		Kind:        row.Kind,
+		Calculation: row.Calculation,
+		Amount:      row.Amount,
+		Currency:    row.Currency,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
+
+func toPayrollRun(row PayrollRun) database.PayrollRun {
+	return database.PayrollRun{
+		ID:               row.ID,
+		PeriodStart:      row.PeriodStart.Time,
+		PeriodEnd:        row.PeriodEnd.Time,
+		Status:           row.Status,
+		EmployeeCount:    int(row.EmployeeCount),
+		CreatedBy:        uuidPtr(row.CreatedBy),
+		CreatedByEmail:   row.CreatedByEmail,
+		ApprovedBy:       uuidPtr(row.ApprovedBy),
+		ApprovedByEmail:  row.ApprovedByEmail,
+		ApprovedAt:       timePtr(row.ApprovedAt),
+		FinalizedBy:      uuidPtr(row.FinalizedBy),
+		FinalizedByEmail: row.FinalizedByEmail,
+		FinalizedAt:      timePtr(row.FinalizedAt),
+		ComputedAt:       row.ComputedAt.Time,
+		CreatedAt:        row.CreatedAt.Time,
+		UpdatedAt:        row.UpdatedAt.Time,
+	}
+}
+
+func toPayslip(row ListEmployeePayslipsRow) (database.Payslip, error) {
+	slip := database.Payslip{
+		ID:              row.ID,
+		RunID:           row.RunID,
+		PeriodStart:     row.PeriodStart.Time,
+		PeriodEnd:       row.PeriodEnd.Time,
+		Status:          row.Status,
+		EmployeeID:      row.EmployeeID,
+		EmployeeName:    row.EmployeeName,
+		Position:        row.Position,
+		Currency:        row.Currency,
+		AnnualSalary:    row.AnnualSalary,
+		WorkingDays:     int(row.WorkingDays),
+		PaidDays:        int(row.PaidDays),
+		UnpaidLeaveDays: int(row.UnpaidLeaveDays),
+		Gross:           row.Gross,
+		Allowances:      row.Allowances,
+		Deductions:      row.Deductions,
+		Net:             row.Net,
+		CreatedAt:       row.CreatedAt.Time,
+	}
+	if err := json.Unmarshal(row.Lines, &slip.Lines); err != nil {
+		return slip, fmt.Errorf("failed to decode payslip lines: %w", err)
+	}
+	return slip, nil
+}
@@ -0,0 +1,759 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: payroll.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/money"
+)
+
+const approvePayrollRun = `-- name: ApprovePayrollRun :execrows
+UPDATE payroll_runs
+SET status = 'approved', approved_by = $2, approved_by_email = $3, approved_at = $4, updated_at = $4
+WHERE id = $1 AND status = 'draft'
+`
+
+type ApprovePayrollRunParams struct {
+	ID              uuid.UUID        `json:"id"`
+	ApprovedBy      pgtype.UUID      `json:"approved_by"`
+	ApprovedByEmail string           `json:"approved_by_email"`
+	ApprovedAt      pgtype.Timestamp `json:"approved_at"`
+}
+
+func (q *Queries) ApprovePayrollRun(ctx context.Context, arg ApprovePayrollRunParams) (int64, error) {
+	result, err := q.db.Exec(ctx, approvePayrollRun,
+		arg.ID,
+		arg.ApprovedBy,
+		arg.ApprovedByEmail,
+		arg.ApprovedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createPayrollRule = `-- name: CreatePayrollRule :exec
+INSERT INTO payroll_rules (id, name, kind, calculation, amount, created_at, updated_at, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type CreatePayrollRuleParams struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Kind        string           `json:"kind"`
+	Calculation string           `json:"calculation"`
+	Amount      money.Amount     `json:"amount"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	Currency    string           `json:"currency"`
+}
+
+func (q *Queries) CreatePayrollRule(ctx context.Context, arg CreatePayrollRuleParams) error {
+	_, err := q.db.Exec(ctx, createPayrollRule,
+		arg.ID,
+		arg.Name,
+		arg.Kind,
+		arg.Calculation,
+		arg.Amount,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Currency,
+	)
+	return err
+}
+
+const createPayrollRun = `-- name: CreatePayrollRun :exec
+INSERT INTO payroll_runs (id, period_start, period_end, status, employee_count, created_by, created_by_email,
+                          computed_at, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+type CreatePayrollRunParams struct {
+	ID             uuid.UUID        `json:"id"`
+	PeriodStart    pgtype.Date      `json:"period_start"`
+	PeriodEnd      pgtype.Date      `json:"period_end"`
+	Status         string           `json:"status"`
+	EmployeeCount  int32            `json:"employee_count"`
+	CreatedBy      pgtype.UUID      `json:"created_by"`
+	CreatedByEmail string           `json:"created_by_email"`
+	ComputedAt     pgtype.Timestamp `json:"computed_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) CreatePayrollRun(ctx context.Context, arg CreatePayrollRunParams) error {
+	_, err := q.db.Exec(ctx, createPayrollRun,
+		arg.ID,
+		arg.PeriodStart,
+		arg.PeriodEnd,
+		arg.Status,
+		arg.EmployeeCount,
+		arg.CreatedBy,
+		arg.CreatedByEmail,
+		arg.ComputedAt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const createPayslip = `-- name: CreatePayslip :exec
+INSERT INTO payslips (id, run_id, employee_id, employee_name, position, annual_salary, working_days, paid_days,
+                      unpaid_leave_days, gross, allowances, deductions, net, lines, created_at, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+`
+
+type CreatePayslipParams struct {
+	ID              uuid.UUID        `json:"id"`
+	RunID           uuid.UUID        `json:"run_id"`
+	EmployeeID      uuid.UUID        `json:"employee_id"`
+	EmployeeName    string           `json:"employee_name"`
+	Position        string           `json:"position"`
+	AnnualSalary    money.Amount     `json:"annual_salary"`
+	WorkingDays     int32            `json:"working_days"`
+	PaidDays        int32            `json:"paid_days"`
+	UnpaidLeaveDays int32            `json:"unpaid_leave_days"`
+	Gross           money.Amount     `json:"gross"`
+	Allowances      money.Amount     `json:"allowances"`
+	Deductions      money.Amount     `json:"deductions"`
+	Net             money.Amount     `json:"net"`
+	Lines           []byte           `json:"lines"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	Currency        string           `json:"currency"`
+}
+
+func (q *Queries) CreatePayslip(ctx context.Context, arg CreatePayslipParams) error {
+	_, err := q.db.Exec(ctx, createPayslip,
+		arg.ID,
+		arg.RunID,
+		arg.EmployeeID,
+		arg.EmployeeName,
+		arg.Position,
+		arg.AnnualSalary,
+		arg.WorkingDays,
+		arg.PaidDays,
+		arg.UnpaidLeaveDays,
+		arg.Gross,
+		arg.Allowances,
+		arg.Deductions,
+		arg.Net,
+		arg.Lines,
+		arg.CreatedAt,
+		arg.Currency,
+	)
+	return err
+}
+
+const deletePayrollRule = `-- name: DeletePayrollRule :execrows
+DELETE FROM payroll_rules
+WHERE id = $1
+`
+
+func (q *Queries) DeletePayrollRule(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePayrollRule, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deletePayslips = `-- name: DeletePayslips :exec
+DELETE FROM payslips
+WHERE run_id = $1
+`
+
+func (q *Queries) DeletePayslips(ctx context.Context, runID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deletePayslips, runID)
+	return err
+}
+
+const finalizePayrollRun = `-- name: FinalizePayrollRun :execrows
+UPDATE payroll_runs
+SET status = 'finalized', finalized_by = $2, finalized_by_email = $3, finalized_at = $4, updated_at = $4
+WHERE id = $1 AND status = 'approved'
+`
+
+type FinalizePayrollRunParams struct {
+	ID               uuid.UUID        `json:"id"`
+	FinalizedBy      pgtype.UUID      `json:"finalized_by"`
+	FinalizedByEmail string           `json:"finalized_by_email"`
+	FinalizedAt      pgtype.Timestamp `json:"finalized_at"`
+}
+
+func (q *Queries) FinalizePayrollRun(ctx context.Context, arg FinalizePayrollRunParams) (int64, error) {
+	result, err := q.db.Exec(ctx, finalizePayrollRun,
+		arg.ID,
+		arg.FinalizedBy,
+		arg.FinalizedByEmail,
+		arg.FinalizedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getPayrollRule = `-- name: GetPayrollRule :one
+SELECT id, name, kind, calculation, amount, created_at, updated_at, currency
+FROM payroll_rules
+WHERE id = $1
+`
+
+func (q *Queries) GetPayrollRule(ctx context.Context, id uuid.UUID) (PayrollRule, error) {
+	row := q.db.QueryRow(ctx, getPayrollRule, id)
+	var i PayrollRule
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Kind,
+		&i.Calculation,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const getPayrollRun = `-- name: GetPayrollRun :one
+SELECT id, period_start, period_end, status, employee_count, created_by, created_by_email, approved_by,
+       approved_by_email, approved_at, finalized_by, finalized_by_email, finalized_at, computed_at, created_at,
+       updated_at
+FROM payroll_runs
+WHERE id = $1
+`
+
+func (q *Queries) GetPayrollRun(ctx context.Context, id uuid.UUID) (PayrollRun, error) {
+	row := q.db.QueryRow(ctx, getPayrollRun, id)
+	var i PayrollRun
+	err := row.Scan(
+		&i.ID,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.Status,
+		&i.EmployeeCount,
+		&i.CreatedBy,
+		&i.CreatedByEmail,
+		&i.ApprovedBy,
+		&i.ApprovedByEmail,
+		&i.ApprovedAt,
+		&i.FinalizedBy,
+		&i.FinalizedByEmail,
+		&i.FinalizedAt,
+		&i.ComputedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPayrollRunForUpdate = `-- name: GetPayrollRunForUpdate :one
+SELECT id, period_start, period_end, status, employee_count, created_by, created_by_email, approved_by,
+       approved_by_email, approved_at, finalized_by, finalized_by_email, finalized_at, computed_at, created_at,
+       updated_at
+FROM payroll_runs
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetPayrollRunForUpdate(ctx context.Context, id uuid.UUID) (PayrollRun, error) {
+	row := q.db.QueryRow(ctx, getPayrollRunForUpdate, id)
+	var i PayrollRun
+	err := row.Scan(
+		&i.ID,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.Status,
+		&i.EmployeeCount,
+		&i.CreatedBy,
+		&i.CreatedByEmail,
+		&i.ApprovedBy,
+		&i.ApprovedByEmail,
+		&i.ApprovedAt,
+		&i.FinalizedBy,
+		&i.FinalizedByEmail,
+		&i.FinalizedAt,
+		&i.ComputedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listEmployeePayslips = `-- name: ListEmployeePayslips :many
+SELECT p.id, p.run_id, r.period_start, r.period_end, r.status, p.employee_id, p.employee_name, p.position,
+       p.annual_salary, p.working_days, p.paid_days, p.unpaid_leave_days, p.gross, p.allowances,
+       p.deductions, p.net, p.lines, p.created_at, p.currency
+FROM payslips p
+JOIN payroll_runs r ON r.id = p.run_id
+WHERE p.employee_id = $1 AND r.status = 'finalized'
+ORDER BY r.period_start DESC
+`
+
+type ListEmployeePayslipsRow struct {
+	ID              uuid.UUID        `json:"id"`
+	RunID           uuid.UUID        `json:"run_id"`
+	PeriodStart     pgtype.Date      `json:"period_start"`
+	PeriodEnd       pgtype.Date      `json:"period_end"`
+	Status          string           `json:"status"`
+	EmployeeID      uuid.UUID        `json:"employee_id"`
+	EmployeeName    string           `json:"employee_name"`
+	Position        string           `json:"position"`
+	AnnualSalary    money.Amount     `json:"annual_salary"`
+	WorkingDays     int32            `json:"working_days"`
+	PaidDays        int32            `json:"paid_days"`
+	UnpaidLeaveDays int32            `json:"unpaid_leave_days"`
+	Gross           money.Amount     `json:"gross"`
+	Allowances      money.Amount     `json:"allowances"`
+	Deductions      money.Amount     `json:"deductions"`
+	Net             money.Amount     `json:"net"`
+	Lines           []byte           `json:"lines"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	Currency        string           `json:"currency"`
+}
+
+// only finalized runs, whose payslips have been paid out
+func (q *Queries) ListEmployeePayslips(ctx context.Context, employeeID uuid.UUID) ([]ListEmployeePayslipsRow, error) {
+	rows, err := q.db.Query(ctx, listEmployeePayslips, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEmployeePayslipsRow
+	for rows.Next() {
+		var i ListEmployeePayslipsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.Status,
+			&i.EmployeeID,
+			&i.EmployeeName,
+			&i.Position,
+			&i.AnnualSalary,
+			&i.WorkingDays,
+			&i.PaidDays,
+			&i.UnpaidLeaveDays,
+			&i.Gross,
+			&i.Allowances,
+			&i.Deductions,
+			&i.Net,
+			&i.Lines,
+			&i.CreatedAt,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPayrollEmployees = `-- name: ListPayrollEmployees :many
+SELECT id, name, position, salary, currency, hired_date, termination_date
+FROM employees
+WHERE hired_date <= $1::date
+  AND (termination_date IS NULL OR termination_date >= $2::date)
+  AND (deleted_at IS NULL OR termination_date IS NOT NULL)
+ORDER BY name, id
+`
+
+type ListPayrollEmployeesParams struct {
+	PeriodEnd   pgtype.Date `json:"period_end"`
+	PeriodStart pgtype.Date `json:"period_start"`
+}
+
+type ListPayrollEmployeesRow struct {
+	ID              uuid.UUID    `json:"id"`
+	Name            string       `json:"name"`
+	Position        string       `json:"position"`
+	Salary          money.Amount `json:"salary"`
+	Currency        string       `json:"currency"`
+	HiredDate       pgtype.Date  `json:"hired_date"`
+	TerminationDate pgtype.Date  `json:"termination_date"`
+}
+
+// everyone employed for part of the period. Deletion does not end employment:
+// deleted employees are paid up to their termination date, and left out
+// altogether when they have none, as records deleted without having left.
+func (q *Queries) ListPayrollEmployees(ctx context.Context, arg ListPayrollEmployeesParams) ([]ListPayrollEmployeesRow, error) {
+	rows, err := q.db.Query(ctx, listPayrollEmployees, arg.PeriodEnd, arg.PeriodStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPayrollEmployeesRow
+	for rows.Next() {
+		var i ListPayrollEmployeesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Position,
+			&i.Salary,
+			&i.Currency,
+			&i.HiredDate,
+			&i.TerminationDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPayrollRules = `-- name: ListPayrollRules :many
+SELECT id, name, kind, calculation, amount, created_at, updated_at, currency
+FROM payroll_rules
+ORDER BY kind, name
+`
+
+func (q *Queries) ListPayrollRules(ctx context.Context) ([]PayrollRule, error) {
+	rows, err := q.db.Query(ctx, listPayrollRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PayrollRule
+	for rows.Next() {
+		var i PayrollRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Kind,
+			&i.Calculation,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPayrollRunTotals = `-- name: ListPayrollRunTotals :many
+SELECT run_id, currency, COUNT(*)::int AS employee_count, SUM(gross)::numeric AS gross,
+       SUM(allowances)::numeric AS allowances, SUM(deductions)::numeric AS deductions, SUM(net)::numeric AS net
+FROM payslips
+WHERE run_id = ANY($1::uuid[])
+GROUP BY run_id, currency
+ORDER BY run_id, currency
+`
+
+type ListPayrollRunTotalsRow struct {
+	RunID         uuid.UUID    `json:"run_id"`
+	Currency      string       `json:"currency"`
+	EmployeeCount int32        `json:"employee_count"`
+	Gross         money.Amount `json:"gross"`
+	Allowances    money.Amount `json:"allowances"`
+	Deductions    money.Amount `json:"deductions"`
+	Net           money.Amount `json:"net"`
+}
+
+// the payslips of each run summed per currency
+func (q *Queries) ListPayrollRunTotals(ctx context.Context, runIds []uuid.UUID) ([]ListPayrollRunTotalsRow, error) {
+	rows, err := q.db.Query(ctx, listPayrollRunTotals, runIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPayrollRunTotalsRow
+	for rows.Next() {
+		var i ListPayrollRunTotalsRow
+		if err := rows.Scan(
+			&i.RunID,
+			&i.Currency,
+			&i.EmployeeCount,
+			&i.Gross,
+			&i.Allowances,
+			&i.Deductions,
+			&i.Net,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPayrollRuns = `-- name: ListPayrollRuns :many
+SELECT id, period_start, period_end, status, employee_count, created_by, created_by_email, approved_by,
+       approved_by_email, approved_at, finalized_by, finalized_by_email, finalized_at, computed_at, created_at,
+       updated_at
+FROM payroll_runs
+ORDER BY period_start DESC
+`
+
+func (q *Queries) ListPayrollRuns(ctx context.Context) ([]PayrollRun, error) {
+	rows, err := q.db.Query(ctx, listPayrollRuns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PayrollRun
+	for rows.Next() {
+		var i PayrollRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.Status,
+			&i.EmployeeCount,
+			&i.CreatedBy,
+			&i.CreatedByEmail,
+			&i.ApprovedBy,
+			&i.ApprovedByEmail,
+			&i.ApprovedAt,
+			&i.FinalizedBy,
+			&i.FinalizedByEmail,
+			&i.FinalizedAt,
+			&i.ComputedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPayrollSalaries = `-- name: ListPayrollSalaries :many
+SELECT h.employee_id, h.salary, h.currency, h.effective_date
+FROM salary_history h
+JOIN employees e ON e.id = h.employee_id
+WHERE h.effective_date <= $1::date
+  AND e.hired_date <= $1::date
+  AND (e.termination_date IS NULL OR e.termination_date >= $2::date)
+  AND (e.deleted_at IS NULL OR e.termination_date IS NOT NULL)
+ORDER BY h.employee_id, h.effective_date, h.created_at, h.id
+`
+
+type ListPayrollSalariesParams struct {
+	PeriodEnd   pgtype.Date `json:"period_end"`
+	PeriodStart pgtype.Date `json:"period_start"`
+}
+
+type ListPayrollSalariesRow struct {
+	EmployeeID    uuid.UUID    `json:"employee_id"`
+	Salary        money.Amount `json:"salary"`
+	Currency      string       `json:"currency"`
+	EffectiveDate pgtype.Date  `json:"effective_date"`
+}
+
+// the salary records in effect by the end of the period, oldest first per employee
+func (q *Queries) ListPayrollSalaries(ctx context.Context, arg ListPayrollSalariesParams) ([]ListPayrollSalariesRow, error) {
+	rows, err := q.db.Query(ctx, listPayrollSalaries, arg.PeriodEnd, arg.PeriodStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPayrollSalariesRow
+	for rows.Next() {
+		var i ListPayrollSalariesRow
+		if err := rows.Scan(
+			&i.EmployeeID,
+			&i.Salary,
+			&i.Currency,
+			&i.EffectiveDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRunPayslips = `-- name: ListRunPayslips :many
+SELECT p.id, p.run_id, r.period_start, r.period_end, r.status, p.employee_id, p.employee_name, p.position,
+       p.annual_salary, p.working_days, p.paid_days, p.unpaid_leave_days, p.gross, p.allowances,
+       p.deductions, p.net, p.lines, p.created_at, p.currency
+FROM payslips p
+JOIN payroll_runs r ON r.id = p.run_id
+WHERE p.run_id = $1
+ORDER BY p.employee_name, p.employee_id
+`
+
+type ListRunPayslipsRow struct {
+	ID              uuid.UUID        `json:"id"`
+	RunID           uuid.UUID        `json:"run_id"`
+	PeriodStart     pgtype.Date      `json:"period_start"`
+	PeriodEnd       pgtype.Date      `json:"period_end"`
+	Status          string           `json:"status"`
+	EmployeeID      uuid.UUID        `json:"employee_id"`
+	EmployeeName    string           `json:"employee_name"`
+	Position        string           `json:"position"`
+	AnnualSalary    money.Amount     `json:"annual_salary"`
+	WorkingDays     int32            `json:"working_days"`
+	PaidDays        int32            `json:"paid_days"`
+	UnpaidLeaveDays int32            `json:"unpaid_leave_days"`
+	Gross           money.Amount     `json:"gross"`
+	Allowances      money.Amount     `json:"allowances"`
+	Deductions      money.Amount     `json:"deductions"`
+	Net             money.Amount     `json:"net"`
+	Lines           []byte           `json:"lines"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	Currency        string           `json:"currency"`
+}
+
+func (q *Queries) ListRunPayslips(ctx context.Context, runID uuid.UUID) ([]ListRunPayslipsRow, error) {
+	rows, err := q.db.Query(ctx, listRunPayslips, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRunPayslipsRow
+	for rows.Next() {
+		var i ListRunPayslipsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.Status,
+			&i.EmployeeID,
+			&i.EmployeeName,
+			&i.Position,
+			&i.AnnualSalary,
+			&i.WorkingDays,
+			&i.PaidDays,
+			&i.UnpaidLeaveDays,
+			&i.Gross,
+			&i.Allowances,
+			&i.Deductions,
+			&i.Net,
+			&i.Lines,
+			&i.CreatedAt,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnpaidLeave = `-- name: ListUnpaidLeave :many
+SELECT r.employee_id, r.start_date, r.end_date
+FROM leave_requests r
+JOIN leave_types t ON t.id = r.leave_type_id
+WHERE t.unpaid AND r.status = 'approved'
+  AND r.start_date <= $1::date
+  AND r.end_date >= $2::date
+ORDER BY r.employee_id, r.start_date
+`
+
+type ListUnpaidLeaveParams struct {
+	PeriodEnd   pgtype.Date `json:"period_end"`
+	PeriodStart pgtype.Date `json:"period_start"`
+}
+
+type ListUnpaidLeaveRow struct {
+	EmployeeID uuid.UUID   `json:"employee_id"`
+	StartDate  pgtype.Date `json:"start_date"`
+	EndDate    pgtype.Date `json:"end_date"`
+}
+
+// approved unpaid leave overlapping the period
+func (q *Queries) ListUnpaidLeave(ctx context.Context, arg ListUnpaidLeaveParams) ([]ListUnpaidLeaveRow, error) {
+	rows, err := q.db.Query(ctx, listUnpaidLeave, arg.PeriodEnd, arg.PeriodStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnpaidLeaveRow
+	for rows.Next() {
+		var i ListUnpaidLeaveRow
+		if err := rows.Scan(&i.EmployeeID, &i.StartDate, &i.EndDate); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePayrollRule = `-- name: UpdatePayrollRule :execrows
+UPDATE payroll_rules
+SET name = $2, kind = $3, calculation = $4, amount = $5, currency = $6, updated_at = $7
+WHERE id = $1
+`
+
+type UpdatePayrollRuleParams struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Kind        string           `json:"kind"`
+	Calculation string           `json:"calculation"`
+	Amount      money.Amount     `json:"amount"`
+	Currency    string           `json:"currency"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) UpdatePayrollRule(ctx context.Context, arg UpdatePayrollRuleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updatePayrollRule,
+		arg.ID,
+		arg.Name,
+		arg.Kind,
+		arg.Calculation,
+		arg.Amount,
+		arg.Currency,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updatePayrollRunComputed = `-- name: UpdatePayrollRunComputed :execrows
+UPDATE payroll_runs
+SET employee_count = $2, computed_at = $3, updated_at = $3
+WHERE id = $1 AND status = 'draft'
+`
+
+type UpdatePayrollRunComputedParams struct {
+	ID            uuid.UUID        `json:"id"`
+	EmployeeCount int32            `json:"employee_count"`
+	ComputedAt    pgtype.Timestamp `json:"computed_at"`
+}
+
+func (q *Queries) UpdatePayrollRunComputed(ctx context.Context, arg UpdatePayrollRunComputedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updatePayrollRunComputed, arg.ID, arg.EmployeeCount, arg.ComputedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
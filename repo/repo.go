@@ -8,27 +8,80 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lijuuu/EmployeeManagement/customerr"
 	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
 )
 
 type EmployeeRepo interface {
 	CreateEmployee(ctx context.Context, emp *database.Employee) (uuid.UUID, error)
 	GetEmployeeByID(ctx context.Context, id uuid.UUID) (*database.Employee, error)
+	//GetEmployeeForUpdate reads the employee and locks the row until the transaction ends
+	GetEmployeeForUpdate(ctx context.Context, id uuid.UUID) (*database.Employee, error)
 	UpdateEmployee(ctx context.Context, id uuid.UUID, emp *database.Employee) error
+	//PatchEmployee updates only the fields set in patch
+	PatchEmployee(ctx context.Context, id uuid.UUID, patch *database.EmployeePatch) error
+	//DeleteEmployee soft-deletes the employee; it stays restorable until purged
 	DeleteEmployee(ctx context.Context, id uuid.UUID) error
-	ListEmployees(ctx context.Context) ([]database.Employee, error)
+	//ClearReportsManager detaches the manager's direct reports, deleted or not
+	ClearReportsManager(ctx context.Context, managerID uuid.UUID) error
+	GetDeletedEmployeeForUpdate(ctx context.Context, id uuid.UUID) (*database.Employee, error)
+	RestoreEmployee(ctx context.Context, id uuid.UUID) error
+	ListDeletedEmployees(ctx context.Context, limit, offset int) (*database.EmployeePage, error)
+	//PurgeDeletedEmployees permanently removes employees soft-deleted longer ago than retention and returns them
+	PurgeDeletedEmployees(ctx context.Context, retention time.Duration) ([]database.Employee, error)
+	ListEmployees(ctx context.Context, filter database.EmployeeFilter) (*database.EmployeePage, error)
+	//CreateSalaryRecord appends to the employee's salary history; it does not change employees.salary
+	CreateSalaryRecord(ctx context.Context, rec *database.SalaryRecord) error
+	//ListSalaryHistory returns the employee's salary records, latest effective date first
+	ListSalaryHistory(ctx context.Context, employeeID uuid.UUID) ([]database.SalaryRecord, error)
+	//GetSalaryAsOf returns the record in effect on asOf, a not found error before the first one
+	GetSalaryAsOf(ctx context.Context, employeeID uuid.UUID, asOf time.Time) (*database.SalaryRecord, error)
+	//SearchEmployees ranks employees by how well their name and position match the query; it must run in a transaction
+	SearchEmployees(ctx context.Context, search database.EmployeeSearch) (*database.EmployeeSearchPage, error)
+	//ExportEmployees calls fn with every employee matching filter, ignoring its paging, reading rows as fn consumes them
+	ExportEmployees(ctx context.Context, filter database.EmployeeFilter, fn func(database.Employee) error) error
+	SetManager(ctx context.Context, id uuid.UUID, managerID *uuid.UUID) error
+	//SetTermination sets the last day employed, or clears it when terminationDate is nil
+	SetTermination(ctx context.Context, id uuid.UUID, terminationDate *time.Time) error
+	//LockHierarchy holds the lock on manager changes until the transaction ends
+	LockHierarchy(ctx context.Context) error
+	ListDirectReportIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	//ListCurrencies returns the currencies current employees are paid in
+	ListCurrencies(ctx context.Context) ([]string, error)
+	//GetManagerChain returns the employee's managers, nearest first
+	GetManagerChain(ctx context.Context, id uuid.UUID) ([]database.OrgNode, error)
+	//GetReports returns every direct and indirect report as a flat list, shallowest first
+	GetReports(ctx context.Context, id uuid.UUID) ([]database.OrgNode, error)
+	ListOrgNodes(ctx context.Context) ([]database.OrgNode, error)
+
+	//WithTx returns a repo whose queries run inside tx
+	WithTx(tx pgx.Tx) EmployeeRepo
 }
 
+//DBTX is satisfied by both the shared pool and a transaction started from it
+var (
+	_ DBTX = (*pgxpool.Pool)(nil)
+	_ DBTX = (pgx.Tx)(nil)
+)
+
 type employeeRepo struct {
 	queries *Queries 
 }
 
-func NewEmployeeRepo(db *pgx.Conn) EmployeeRepo {
+func NewEmployeeRepo(db DBTX) EmployeeRepo {
 	return &employeeRepo{
 		queries: New(db), 
 	}
 }
 
+func (r *employeeRepo) WithTx(tx pgx.Tx) EmployeeRepo {
+	return &employeeRepo{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
 func (r *employeeRepo) CreateEmployee(ctx context.Context, emp *database.Employee) (uuid.UUID, error) {
 		id := uuid.New() 
 	_, err := r.queries.CreateEmployee(ctx, CreateEmployeeParams{
@@ -36,12 +89,15 @@ func (r *employeeRepo) CreateEmployee(ctx context.Context, emp *database.Employe
 		Name:      emp.Name,
 		Position:  emp.Position,
 		Salary:    emp.Salary,
+		Currency:  emp.Currency,
 		HiredDate: pgtype.Date{Time: emp.HiredDate, Valid: true},
+		DepartmentID: pgUUID(emp.DepartmentID),
+		ManagerID: pgUUID(emp.ManagerID),
 		CreatedAt: pgtype.Timestamp{Time:emp.CreatedAt,Valid: true},
 		UpdatedAt: pgtype.Timestamp{Time: emp.UpdatedAt,Valid: true},
 	})
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed to create employee: %v", err)
+		return uuid.Nil, dbError(err, "create", "employee")
 	}
 	emp.ID = id
 	return id, nil
@@ -50,68 +106,490 @@ func (r *employeeRepo) CreateEmployee(ctx context.Context, emp *database.Employe
 func (r *employeeRepo) GetEmployeeByID(ctx context.Context, id uuid.UUID) (*database.Employee, error) {
 	dbEmp, err := r.queries.GetEmployeeByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get employee: %v", err)
+		return nil, dbError(err, "get", "employee")
 	}
 
-	emp := &database.Employee{
-		ID:        dbEmp.ID,
-		Name:      dbEmp.Name,
-		Position:  dbEmp.Position,
-		Salary:    dbEmp.Salary,
-		HiredDate: dbEmp.HiredDate.Time,
-		CreatedAt: dbEmp.CreatedAt.Time,
-		UpdatedAt: dbEmp.UpdatedAt.Time,
+	emp := toEmployee(dbEmp)
+	return &emp, nil
+}
+
+func (r *employeeRepo) GetEmployeeForUpdate(ctx context.Context, id uuid.UUID) (*database.Employee, error) {
+	dbEmp, err := r.queries.GetEmployeeForUpdate(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "employee")
+	}
+
+	emp := toEmployee(dbEmp)
+	return &emp, nil
+}
+
+// toEmployee maps a row onto the API model
+func toEmployee(dbEmp Employee) database.Employee {
+	return database.Employee{
+		ID:              dbEmp.ID,
+		Name:            dbEmp.Name,
+		Position:        dbEmp.Position,
+		Salary:          dbEmp.Salary,
+		Currency:        dbEmp.Currency,
+		HiredDate:       dbEmp.HiredDate.Time,
+		DepartmentID:    uuidPtr(dbEmp.DepartmentID),
+		ManagerID:       uuidPtr(dbEmp.ManagerID),
+		CreatedAt:       dbEmp.CreatedAt.Time,
+		UpdatedAt:       dbEmp.UpdatedAt.Time,
+		DeletedAt:       timePtr(dbEmp.DeletedAt),
+		Version:         dbEmp.Version,
+		TerminationDate: datePtr(dbEmp.TerminationDate),
 	}
-	return emp, nil
 }
 
 func (r *employeeRepo) UpdateEmployee(ctx context.Context, id uuid.UUID, emp *database.Employee) error {
-	err := r.queries.UpdateEmployee(ctx, UpdateEmployeeParams{
+	rows, err := r.queries.UpdateEmployee(ctx, UpdateEmployeeParams{
 		Name:      emp.Name,
 		Position:  emp.Position,
 		Salary:    emp.Salary,
+		Currency:  emp.Currency,
 		HiredDate: pgtype.Date{Time: emp.HiredDate, Valid: true},
 		ID:        id,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to update employee: %v", err)
+		return dbError(err, "update", "employee")
+	}
+	if rows == 0 {
+		return customerr.NotFound("employee not found")
 	}
 	emp.ID = id
 	return nil
 }
 
+func (r *employeeRepo) PatchEmployee(ctx context.Context, id uuid.UUID, patch *database.EmployeePatch) error {
+	params := PatchEmployeeParams{
+		Name:     pgText(patch.Name),
+		Position: pgText(patch.Position),
+		Salary:   patch.Salary,
+		Currency: pgText(patch.Currency),
+		ID:       id,
+	}
+	if patch.HiredDate != nil {
+		params.HiredDate = pgtype.Date{Time: *patch.HiredDate, Valid: true}
+	}
+
+	rows, err := r.queries.PatchEmployee(ctx, params)
+	if err != nil {
+		return dbError(err, "patch", "employee")
+	}
+	if rows == 0 {
+		return customerr.NotFound("employee not found")
+	}
+	return nil
+}
+
 func (r *employeeRepo) DeleteEmployee(ctx context.Context, id uuid.UUID) error {
-	err := r.queries.DeleteEmployee(ctx, id)
+	rows, err := r.queries.SoftDeleteEmployee(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete employee: %v", err)
+		return dbError(err, "delete", "employee")
+	}
+	if rows == 0 {
+		return customerr.NotFound("employee not found")
 	}
 	return nil
 }
 
-func (r *employeeRepo) ListEmployees(ctx context.Context) ([]database.Employee, error) {
-	dbEmployees, err := r.queries.ListEmployees(ctx)
+func (r *employeeRepo) ClearReportsManager(ctx context.Context, managerID uuid.UUID) error {
+	if err := r.queries.ClearDirectReportsManager(ctx, managerID); err != nil {
+		return dbError(err, "clear manager of", "reports")
+	}
+	return nil
+}
+
+func (r *employeeRepo) GetDeletedEmployeeForUpdate(ctx context.Context, id uuid.UUID) (*database.Employee, error) {
+	dbEmp, err := r.queries.GetDeletedEmployeeForUpdate(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list employees: %v", err)
+		return nil, dbError(err, "get", "deleted employee")
+	}
+
+	emp := toEmployee(dbEmp)
+	return &emp, nil
+}
+
+func (r *employeeRepo) RestoreEmployee(ctx context.Context, id uuid.UUID) error {
+	rows, err := r.queries.RestoreEmployee(ctx, id)
+	if err != nil {
+		return dbError(err, "restore", "employee")
+	}
+	if rows == 0 {
+		return customerr.NotFound("deleted employee not found")
+	}
+	return nil
+}
+
+func (r *employeeRepo) ListDeletedEmployees(ctx context.Context, limit, offset int) (*database.EmployeePage, error) {
+	dbEmployees, err := r.queries.ListDeletedEmployees(ctx, ListDeletedEmployeesParams{
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return nil, dbError(err, "list", "deleted employees")
+	}
+
+	total, err := r.queries.CountDeletedEmployees(ctx)
+	if err != nil {
+		return nil, dbError(err, "count", "deleted employees")
 	}
 
 	employees := make([]database.Employee, len(dbEmployees))
 	for i, dbEmp := range dbEmployees {
-		var hiredDate time.Time
-		if dbEmp.HiredDate.Valid {
-			hiredDate = dbEmp.HiredDate.Time
-		} else {
-			return nil, fmt.Errorf("invalid hired_date for employee ID %s", dbEmp.ID.String())
+		employees[i] = toEmployee(dbEmp)
+	}
+	return &database.EmployeePage{Employees: employees, Total: total}, nil
+}
+
+func (r *employeeRepo) PurgeDeletedEmployees(ctx context.Context, retention time.Duration) ([]database.Employee, error) {
+	dbEmployees, err := r.queries.PurgeDeletedEmployees(ctx, pgtype.Interval{Microseconds: retention.Microseconds(), Valid: true})
+	if err != nil {
+		return nil, dbError(err, "purge", "employees")
+	}
+
+	employees := make([]database.Employee, len(dbEmployees))
+	for i, dbEmp := range dbEmployees {
+		employees[i] = toEmployee(dbEmp)
+	}
+	return employees, nil
+}
+
+func (r *employeeRepo) ListEmployees(ctx context.Context, filter database.EmployeeFilter) (*database.EmployeePage, error) {
+	params := ListEmployeesParams{
+		SortBy:     filter.SortBy,
+		SortDesc:   filter.SortDesc,
+		PageLimit:  int32(filter.Limit),
+		PageOffset: int32(filter.Offset),
+	}
+	countParams := CountEmployeesParams{}
+	if filter.Position != "" {
+		params.Position = pgtype.Text{String: filter.Position, Valid: true}
+		countParams.Position = params.Position
+	}
+	if filter.DepartmentID != nil {
+		params.DepartmentID = pgUUID(filter.DepartmentID)
+		countParams.DepartmentID = params.DepartmentID
+	}
+	params.MinSalary, countParams.MinSalary = filter.MinSalary, filter.MinSalary
+	params.MaxSalary, countParams.MaxSalary = filter.MaxSalary, filter.MaxSalary
+	if filter.Currency != "" {
+		params.Currency = pgtype.Text{String: filter.Currency, Valid: true}
+		countParams.Currency = params.Currency
+	}
+	if filter.HiredFrom != nil {
+		params.HiredFrom = pgtype.Date{Time: *filter.HiredFrom, Valid: true}
+		countParams.HiredFrom = params.HiredFrom
+	}
+	if filter.HiredTo != nil {
+		params.HiredTo = pgtype.Date{Time: *filter.HiredTo, Valid: true}
+		countParams.HiredTo = params.HiredTo
+	}
+	if filter.Cursor != nil {
+		if err := applyCursor(&params, filter.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	dbEmployees, err := r.queries.ListEmployees(ctx, params)
+	if err != nil {
+		return nil, dbError(err, "list", "employees")
+	}
+
+	total, err := r.queries.CountEmployees(ctx, countParams)
+	if err != nil {
+		return nil, dbError(err, "count", "employees")
+	}
+
+	employees := make([]database.Employee, len(dbEmployees))
+	for i, dbEmp := range dbEmployees {
+		employees[i] = toEmployee(dbEmp)
+	}
+
+	page := &database.EmployeePage{Employees: employees, Total: total}
+	//a full page means there may be more rows after it
+	if filter.Limit > 0 && len(employees) == filter.Limit {
+		page.NextCursor = cursorFor(employees[len(employees)-1], filter.SortBy).Encode()
+	}
+	return page, nil
+}
+
+func (r *employeeRepo) ExportEmployees(ctx context.Context, filter database.EmployeeFilter, fn func(database.Employee) error) error {
+	params := ExportEmployeesParams{
+		SortBy:   filter.SortBy,
+		SortDesc: filter.SortDesc,
+	}
+	if filter.Position != "" {
+		params.Position = pgtype.Text{String: filter.Position, Valid: true}
+	}
+	params.DepartmentID = pgUUID(filter.DepartmentID)
+	params.MinSalary = filter.MinSalary
+	params.MaxSalary = filter.MaxSalary
+	if filter.Currency != "" {
+		params.Currency = pgtype.Text{String: filter.Currency, Valid: true}
+	}
+	if filter.HiredFrom != nil {
+		params.HiredFrom = pgtype.Date{Time: *filter.HiredFrom, Valid: true}
+	}
+	if filter.HiredTo != nil {
+		params.HiredTo = pgtype.Date{Time: *filter.HiredTo, Valid: true}
+	}
+
+	//the generated ExportEmployees collects every row into a slice, so the
+	//query is run here and each row handed on as soon as it is scanned
+	rows, err := r.queries.db.Query(ctx, exportEmployees,
+		params.Position,
+		params.DepartmentID,
+		params.MinSalary,
+		params.MaxSalary,
+		params.Currency,
+		params.HiredFrom,
+		params.HiredTo,
+		params.SortBy,
+		params.SortDesc,
+	)
+	if err != nil {
+		return dbError(err, "export", "employees")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dbEmp Employee
+		if err := rows.Scan(
+			&dbEmp.ID,
+			&dbEmp.Name,
+			&dbEmp.Position,
+			&dbEmp.Salary,
+			&dbEmp.HiredDate,
+			&dbEmp.CreatedAt,
+			&dbEmp.UpdatedAt,
+			&dbEmp.DepartmentID,
+			&dbEmp.ManagerID,
+			&dbEmp.DeletedAt,
+			&dbEmp.Version,
+			&dbEmp.Currency,
+			&dbEmp.TerminationDate,
+		); err != nil {
+			return dbError(err, "export", "employees")
+		}
+		if err := fn(toEmployee(dbEmp)); err != nil {
+			return err
 		}
+	}
+	if err := rows.Err(); err != nil {
+		return dbError(err, "export", "employees")
+	}
+	return nil
+}
+
+func (r *employeeRepo) SetManager(ctx context.Context, id uuid.UUID, managerID *uuid.UUID) error {
+	rows, err := r.queries.SetEmployeeManager(ctx, SetEmployeeManagerParams{
+		ManagerID: pgUUID(managerID),
+		ID:        id,
+	})
+	if err != nil {
+		return dbError(err, "set manager of", "employee")
+	}
+	if rows == 0 {
+		return customerr.NotFound("employee not found")
+	}
+	return nil
+}
+
+func (r *employeeRepo) SetTermination(ctx context.Context, id uuid.UUID, terminationDate *time.Time) error {
+	rows, err := r.queries.SetEmployeeTermination(ctx, SetEmployeeTerminationParams{
+		TerminationDate: pgDate(terminationDate),
+		ID:              id,
+	})
+	if err != nil {
+		return dbError(err, "set termination date of", "employee")
+	}
+	if rows == 0 {
+		return customerr.NotFound("employee not found")
+	}
+	return nil
+}
+
+func (r *employeeRepo) LockHierarchy(ctx context.Context) error {
+	if err := r.queries.LockHierarchy(ctx); err != nil {
+		return dbError(err, "lock", "reporting lines")
+	}
+	return nil
+}
+
+func (r *employeeRepo) ListDirectReportIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := r.queries.ListDirectReportIDs(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "list", "direct reports")
+	}
+	return ids, nil
+}
 
-		employees[i] = database.Employee{
-			ID:        dbEmp.ID,
-			Name:      dbEmp.Name,
-			Position:  dbEmp.Position,
-			Salary:    dbEmp.Salary,
-			HiredDate: hiredDate,
-			CreatedAt: dbEmp.CreatedAt.Time,
-			UpdatedAt: dbEmp.UpdatedAt.Time,
+func (r *employeeRepo) ListCurrencies(ctx context.Context) ([]string, error) {
+	currencies, err := r.queries.ListEmployeeCurrencies(ctx)
+	if err != nil {
+		return nil, dbError(err, "list", "employee currencies")
+	}
+	return currencies, nil
+}
+
+func (r *employeeRepo) GetManagerChain(ctx context.Context, id uuid.UUID) ([]database.OrgNode, error) {
+	rows, err := r.queries.GetManagerChain(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "manager chain")
+	}
+
+	chain := make([]database.OrgNode, len(rows))
+	for i, row := range rows {
+		chain[i] = database.OrgNode{
+			ID:           row.ID,
+			Name:         row.Name,
+			Position:     row.Position,
+			DepartmentID: uuidPtr(row.DepartmentID),
+			ManagerID:    uuidPtr(row.ManagerID),
 		}
 	}
-	return employees, nil
+	return chain, nil
+}
+
+func (r *employeeRepo) GetReports(ctx context.Context, id uuid.UUID) ([]database.OrgNode, error) {
+	rows, err := r.queries.GetReportTree(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "reports")
+	}
+
+	reports := make([]database.OrgNode, len(rows))
+	for i, row := range rows {
+		reports[i] = database.OrgNode{
+			ID:           row.ID,
+			Name:         row.Name,
+			Position:     row.Position,
+			DepartmentID: uuidPtr(row.DepartmentID),
+			ManagerID:    uuidPtr(row.ManagerID),
+		}
+	}
+	return reports, nil
+}
+
+func (r *employeeRepo) ListOrgNodes(ctx context.Context) ([]database.OrgNode, error) {
+	rows, err := r.queries.ListOrgChart(ctx)
+	if err != nil {
+		return nil, dbError(err, "list", "org chart")
+	}
+
+	nodes := make([]database.OrgNode, len(rows))
+	for i, row := range rows {
+		nodes[i] = database.OrgNode{
+			ID:           row.ID,
+			Name:         row.Name,
+			Position:     row.Position,
+			DepartmentID: uuidPtr(row.DepartmentID),
+			ManagerID:    uuidPtr(row.ManagerID),
+		}
+	}
+	return nodes, nil
+}
+
+// applyCursor sets the typed keyset params matching the cursor's sort column
+func applyCursor(params *ListEmployeesParams, cursor *database.EmployeeCursor) error {
+	params.CursorID = pgtype.UUID{Bytes: cursor.ID, Valid: true}
+	//the offset is meaningless once a cursor positions the page
+	params.PageOffset = 0
+
+	switch cursor.SortBy {
+	case "id":
+	case "name", "position":
+		params.CursorText = pgtype.Text{String: cursor.Value, Valid: true}
+	case "salary":
+		salary, err := money.Parse(cursor.Value)
+		if err != nil {
+			return fmt.Errorf("invalid cursor value: %v", err)
+		}
+		params.CursorNumber = &salary
+	case "hired_date":
+		date, err := time.Parse(time.DateOnly, cursor.Value)
+		if err != nil {
+			return fmt.Errorf("invalid cursor value: %v", err)
+		}
+		params.CursorDate = pgtype.Date{Time: date, Valid: true}
+	case "created_at", "updated_at":
+		ts, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return fmt.Errorf("invalid cursor value: %v", err)
+		}
+		params.CursorTime = pgtype.Timestamp{Time: ts, Valid: true}
+	default:
+		return fmt.Errorf("invalid cursor sort column %q", cursor.SortBy)
+	}
+	return nil
+}
+
+// cursorFor builds the cursor pointing just past emp for the given sort column
+func cursorFor(emp database.Employee, sortBy string) database.EmployeeCursor {
+	cursor := database.EmployeeCursor{SortBy: sortBy, ID: emp.ID}
+	switch sortBy {
+	case "name":
+		cursor.Value = emp.Name
+	case "position":
+		cursor.Value = emp.Position
+	case "salary":
+		cursor.Value = emp.Salary.String()
+	case "hired_date":
+		cursor.Value = emp.HiredDate.Format(time.DateOnly)
+	case "created_at":
+		cursor.Value = emp.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		cursor.Value = emp.UpdatedAt.Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+// pgUUID converts an optional id into its nullable column form
+func pgUUID(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
+
+// pgText converts an optional string into its nullable column form
+func pgText(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+// uuidPtr converts a nullable uuid column into an optional id
+func uuidPtr(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	u := uuid.UUID(id.Bytes)
+	return &u
+}
+
+// timePtr converts a nullable timestamp column into an optional time
+func timePtr(t pgtype.Timestamp) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// datePtr converts a nullable date column into an optional time
+func datePtr(d pgtype.Date) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Time
+}
+
+// pgDate converts an optional time into a nullable date argument
+func pgDate(t *time.Time) pgtype.Date {
+	if t == nil {
+		return pgtype.Date{}
+	}
+	return pgtype.Date{Time: *t, Valid: true}
 }
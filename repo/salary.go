@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+func (r *employeeRepo) CreateSalaryRecord(ctx context.Context, rec *database.SalaryRecord) error {
+	rec.ID = uuid.New()
+	err := r.queries.CreateSalaryRecord(ctx, CreateSalaryRecordParams{
+		ID:              rec.ID,
+		EmployeeID:      rec.EmployeeID,
+		Salary:          rec.Salary,
+		Currency:        rec.Currency,
+		EffectiveDate:   pgtype.Date{Time: rec.EffectiveDate, Valid: true},
+		Reason:          rec.Reason,
+		ApprovedBy:      pgUUID(rec.ApprovedBy),
+		ApprovedByEmail: rec.ApprovedByEmail,
+		CreatedAt:       pgtype.Timestamp{Time: rec.CreatedAt, Valid: true},
+	})
+	if err != nil {
+		return dbError(err, "create", "salary record")
+	}
+	return nil
+}
+
+func (r *employeeRepo) ListSalaryHistory(ctx context.Context, employeeID uuid.UUID) ([]database.SalaryRecord, error) {
+	rows, err := r.queries.ListSalaryHistory(ctx, employeeID)
+	if err != nil {
+		return nil, dbError(err, "list", "salary history")
+	}
+	records := make([]database.SalaryRecord, len(rows))
+	for i, row := range rows {
+		records[i] = toSalaryRecord(row)
+	}
+	return records, nil
+}
+
+func (r *employeeRepo) GetSalaryAsOf(ctx context.Context, employeeID uuid.UUID, asOf time.Time) (*database.SalaryRecord, error) {
+	row, err := r.queries.GetSalaryAsOf(ctx, GetSalaryAsOfParams{
+		EmployeeID: employeeID,
+		AsOf:       pgtype.Date{Time: asOf, Valid: true},
+	})
+	if err != nil {
+		return nil, dbError(err, "get", "salary record")
+	}
+	rec := toSalaryRecord(row)
+	return &rec, nil
+}
+
+func toSalaryRecord(row SalaryHistory) database.SalaryRecord {
+	return database.SalaryRecord{
+		ID:              row.ID,
+		EmployeeID:      row.EmployeeID,
+		Salary:          row.Salary,
+		Currency:        row.Currency,
+		EffectiveDate:   row.EffectiveDate.Time,
+		Reason:          row.Reason,
+		ApprovedBy:      uuidPtr(row.ApprovedBy),
+		ApprovedByEmail: row.ApprovedByEmail,
+		CreatedAt:       row.CreatedAt.Time,
+	}
+}
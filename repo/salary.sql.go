@@ -0,0 +1,115 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: salary.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/money"
+)
+
+const createSalaryRecord = `-- name: CreateSalaryRecord :exec
+INSERT INTO salary_history (id, employee_id, salary, effective_date, reason, approved_by, approved_by_email, created_at, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type CreateSalaryRecordParams struct {
+	ID              uuid.UUID        `json:"id"`
+	EmployeeID      uuid.UUID        `json:"employee_id"`
+	Salary          money.Amount     `json:"salary"`
+	EffectiveDate   pgtype.Date      `json:"effective_date"`
+	Reason          string           `json:"reason"`
+	ApprovedBy      pgtype.UUID      `json:"approved_by"`
+	ApprovedByEmail string           `json:"approved_by_email"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	Currency        string           `json:"currency"`
+}
+
+func (q *Queries) CreateSalaryRecord(ctx context.Context, arg CreateSalaryRecordParams) error {
+	_, err := q.db.Exec(ctx, createSalaryRecord,
+		arg.ID,
+		arg.EmployeeID,
+		arg.Salary,
+		arg.EffectiveDate,
+		arg.Reason,
+		arg.ApprovedBy,
+		arg.ApprovedByEmail,
+		arg.CreatedAt,
+		arg.Currency,
+	)
+	return err
+}
+
+const getSalaryAsOf = `-- name: GetSalaryAsOf :one
+SELECT id, employee_id, salary, effective_date, reason, approved_by, approved_by_email, created_at, currency
+FROM salary_history
+WHERE employee_id = $1 AND effective_date <= $2::date
+ORDER BY effective_date DESC, created_at DESC, id DESC
+LIMIT 1
+`
+
+type GetSalaryAsOfParams struct {
+	EmployeeID uuid.UUID   `json:"employee_id"`
+	AsOf       pgtype.Date `json:"as_of"`
+}
+
+// the record in effect on a date: the latest one effective on or before it
+func (q *Queries) GetSalaryAsOf(ctx context.Context, arg GetSalaryAsOfParams) (SalaryHistory, error) {
+	row := q.db.QueryRow(ctx, getSalaryAsOf, arg.EmployeeID, arg.AsOf)
+	var i SalaryHistory
+	err := row.Scan(
+		&i.ID,
+		&i.EmployeeID,
+		&i.Salary,
+		&i.EffectiveDate,
+		&i.Reason,
+		&i.ApprovedBy,
+		&i.ApprovedByEmail,
+		&i.CreatedAt,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const listSalaryHistory = `-- name: ListSalaryHistory :many
+SELECT id, employee_id, salary, effective_date, reason, approved_by, approved_by_email, created_at, currency
+FROM salary_history
+WHERE employee_id = $1
+ORDER BY effective_date DESC, created_at DESC, id DESC
+`
+
+// newest effective date first; records on the same date in the order they were made
+func (q *Queries) ListSalaryHistory(ctx context.Context, employeeID uuid.UUID) ([]SalaryHistory, error) {
+	rows, err := q.db.Query(ctx, listSalaryHistory, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SalaryHistory
+	for rows.Next() {
+		var i SalaryHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.EmployeeID,
+			&i.Salary,
+			&i.EffectiveDate,
+			&i.Reason,
+			&i.ApprovedBy,
+			&i.ApprovedByEmail,
+			&i.CreatedAt,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
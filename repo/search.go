@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"context"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+// searchSimilarityThreshold is the trigram word similarity a name or position
+// needs to match a query without sharing a whole word with it. pg_trgm's
+// default of 0.6 misses most single typos.
+const searchSimilarityThreshold = 0.3
+
+// SearchEmployees must run in a transaction: the similarity threshold it sets
+// only lasts until the transaction ends
+func (r *employeeRepo) SearchEmployees(ctx context.Context, search database.EmployeeSearch) (*database.EmployeeSearchPage, error) {
+	if err := r.queries.SetSearchSimilarityThreshold(ctx, strconv.FormatFloat(searchSimilarityThreshold, 'f', -1, 64)); err != nil {
+		return nil, dbError(err, "search", "employees")
+	}
+
+	rows, err := r.queries.SearchEmployees(ctx, SearchEmployeesParams{
+		Query:      search.Query,
+		PageLimit:  int32(search.Limit),
+		PageOffset: int32(search.Offset),
+	})
+	if err != nil {
+		return nil, dbError(err, "search", "employees")
+	}
+	total, err := r.queries.CountSearchEmployees(ctx, search.Query)
+	if err != nil {
+		return nil, dbError(err, "count", "employees")
+	}
+
+	page := &database.EmployeeSearchPage{
+		Results: make([]database.EmployeeSearchResult, len(rows)),
+		Total:   total,
+	}
+	for i, row := range rows {
+		page.Results[i] = database.EmployeeSearchResult{
+			Employee: toEmployee(Employee{
+				ID:              row.ID,
+				Name:            row.Name,
+				Position:        row.Position,
+				Salary:          row.Salary,
+				HiredDate:       row.HiredDate,
+				CreatedAt:       row.CreatedAt,
+				UpdatedAt:       row.UpdatedAt,
+				DepartmentID:    row.DepartmentID,
+				ManagerID:       row.ManagerID,
+				DeletedAt:       row.DeletedAt,
+				Version:         row.Version,
+				Currency:        row.Currency,
+				TerminationDate: row.TerminationDate,
+			}),
+			Rank: row.Rank,
+			Highlights: database.Highlights{
+				Name:     highlightHTML(row.NameHighlight),
+				Position: highlightHTML(row.PositionHighlight),
+			},
+		}
+	}
+	return page, nil
+}
+
+// highlightHTML turns ts_headline output, with matches between \x02 and \x03,
+// into HTML with the matches in <mark> tags. The text itself is escaped, as
+// names are user input.
+func highlightHTML(headline string) string {
+	var b strings.Builder
+	for _, r := range headline {
+		switch r {
+		case '\x02':
+			b.WriteString("<mark>")
+		case '\x03':
+			b.WriteString("</mark>")
+		default:
+			b.WriteString(html.EscapeString(string(r)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TxBeginner starts transactions; the shared pool satisfies it
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+var _ TxBeginner = (*pgxpool.Pool)(nil)
+
+// RunInTx runs fn inside a transaction, committing when fn succeeds and
+// rolling back otherwise
+func RunInTx(ctx context.Context, db TxBeginner, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return dbError(err, "begin", "transaction")
+	}
+	//a no-op once the transaction has been committed
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return dbError(err, "commit", "transaction")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+)
+
+type UserRepo interface {
+	CreateUser(ctx context.Context, user *database.User) (uuid.UUID, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*database.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*database.User, error)
+	ListUsers(ctx context.Context) ([]database.User, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, role string, employeeID *uuid.UUID) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+
+	//WithTx returns a repo whose queries run inside tx
+	WithTx(tx pgx.Tx) UserRepo
+}
+
+type userRepo struct {
+	queries *Queries
+}
+
+func NewUserRepo(db DBTX) UserRepo {
+	return &userRepo{
+		queries: New(db),
+	}
+}
+
+func (r *userRepo) WithTx(tx pgx.Tx) UserRepo {
+	return &userRepo{
+		queries: r.queries.WithTx(tx),
+	}
+}
+
+func (r *userRepo) CreateUser(ctx context.Context, user *database.User) (uuid.UUID, error) {
+	id := uuid.New()
+	_, err := r.queries.CreateUser(ctx, CreateUserParams{
+		ID:           id,
+		Email:        user.Email,
+		PasswordHash: user.PasswordHash,
+		Role:         user.Role,
+		EmployeeID:   pgUUID(user.EmployeeID),
+		CreatedAt:    pgtype.Timestamp{Time: user.CreatedAt, Valid: true},
+		UpdatedAt:    pgtype.Timestamp{Time: user.UpdatedAt, Valid: true},
+	})
+	if err != nil {
+		return uuid.Nil, dbError(err, "create", "user")
+	}
+	user.ID = id
+	return id, nil
+}
+
+func (r *userRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*database.User, error) {
+	dbUser, err := r.queries.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, dbError(err, "get", "user")
+	}
+
+	user := toUser(dbUser)
+	return &user, nil
+}
+
+func (r *userRepo) GetUserByEmail(ctx context.Context, email string) (*database.User, error) {
+	dbUser, err := r.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, dbError(err, "get", "user")
+	}
+
+	user := toUser(dbUser)
+	return &user, nil
+}
+
+func (r *userRepo) ListUsers(ctx context.Context) ([]database.User, error) {
+	dbUsers, err := r.queries.ListUsers(ctx)
+	if err != nil {
+		return nil, dbError(err, "list", "users")
+	}
+
+	users := make([]database.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = toUser(dbUser)
+	}
+	return users, nil
+}
+
+func (r *userRepo) UpdateRole(ctx context.Context, id uuid.UUID, role string, employeeID *uuid.UUID) error {
+	rows, err := r.queries.UpdateUserRole(ctx, UpdateUserRoleParams{
+		Role:       role,
+		EmployeeID: pgUUID(employeeID),
+		ID:         id,
+	})
+	if err != nil {
+		return dbError(err, "update role of", "user")
+	}
+	if rows == 0 {
+		return customerr.NotFound("user not found")
+	}
+	return nil
+}
+
+func (r *userRepo) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	rows, err := r.queries.DeleteUser(ctx, id)
+	if err != nil {
+		return dbError(err, "delete", "user")
+	}
+	if rows == 0 {
+		return customerr.NotFound("user not found")
+	}
+	return nil
+}
+
+func toUser(dbUser User) database.User {
+	return database.User{
+		ID:           dbUser.ID,
+		Email:        dbUser.Email,
+		Role:         dbUser.Role,
+		EmployeeID:   uuidPtr(dbUser.EmployeeID),
+		PasswordHash: dbUser.PasswordHash,
+		CreatedAt:    dbUser.CreatedAt.Time,
+		UpdatedAt:    dbUser.UpdatedAt.Time,
+	}
+}
@@ -2,13 +2,14 @@ package routes
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
 	"github.com/lijuuu/EmployeeManagement/config"
 	"github.com/lijuuu/EmployeeManagement/controller"
 	"github.com/lijuuu/EmployeeManagement/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
-func SetupRoutes(e *echo.Echo, ctrl *controller.EmployeeController, cfg *config.Config) {
+func SetupRoutes(e *echo.Echo, ctrl *controller.EmployeeController, deptCtrl *controller.DepartmentController, userCtrl *controller.UserController, auditCtrl *controller.AuditController, leaveCtrl *controller.LeaveController, attendanceCtrl *controller.AttendanceController, payrollCtrl *controller.PayrollController, rateCtrl *controller.ExchangeRateController, revocations middleware.RevocationChecker, cfg *config.Config) {
 	//Swagger route
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
@@ -16,16 +17,97 @@ func SetupRoutes(e *echo.Echo, ctrl *controller.EmployeeController, cfg *config.
 		return c.JSON(200, map[string]string{"status": "ok"})
 	})
 
-	e.POST("/login", ctrl.Login)
+	//authn identifies the caller; can(perm) then checks their role per route
+	authn := middleware.JWTAuthMiddleware(cfg, revocations)
+	//maybeAuthn identifies the caller when a token is sent, so reads can be projected for them
+	maybeAuthn := middleware.OptionalJWTAuthMiddleware(cfg, revocations)
+	can := middleware.RequirePermission
 
-	protected := e.Group("/employees")
-	protected.Use(middleware.JWTAuthMiddleware(cfg))
+	e.POST("/login", userCtrl.Login)
+	e.POST("/refresh", userCtrl.Refresh)
+	e.POST("/logout", userCtrl.Logout, authn)
 
-	protected.POST("", ctrl.CreateEmployee)
-	protected.PUT("/:id", ctrl.UpdateEmployee)
-	protected.DELETE("/:id", ctrl.DeleteEmployee)
+	e.POST("/employees", ctrl.CreateEmployee, authn, can(auth.PermEmployeesWrite))
+	e.PUT("/employees/:id", ctrl.UpdateEmployee, authn, can(auth.PermEmployeesWrite))
+	e.PATCH("/employees/:id", ctrl.PatchEmployee, authn, can(auth.PermEmployeesWrite))
+	e.DELETE("/employees/:id", ctrl.DeleteEmployee, authn, can(auth.PermEmployeesWrite))
+	e.POST("/employees/bulk", ctrl.BulkCreateEmployees, authn, can(auth.PermEmployeesWrite))
+	e.PATCH("/employees/bulk", ctrl.BulkPatchEmployees, authn, can(auth.PermEmployeesWrite))
+	e.DELETE("/employees/bulk", ctrl.BulkDeleteEmployees, authn, can(auth.PermEmployeesWrite))
+	e.POST("/employees/import", ctrl.ImportEmployees, authn, can(auth.PermEmployeesWrite))
+	e.GET("/employees/deleted", ctrl.ListDeletedEmployees, authn, can(auth.PermDeletedManage))
+	e.POST("/employees/:id/restore", ctrl.RestoreEmployee, authn, can(auth.PermDeletedManage))
+	e.POST("/employees/purge", ctrl.PurgeEmployees, authn, can(auth.PermDeletedManage))
+	e.PUT("/employees/:id/manager", ctrl.SetManager, authn, can(auth.PermHierarchyWrite))
+	e.PUT("/employees/:id/termination", ctrl.SetTermination, authn, can(auth.PermEmployeesWrite))
+	e.POST("/employees/:id/salary-history", ctrl.ChangeSalary, authn, can(auth.PermEmployeesWrite))
+	//salary reads check per employee whether the caller may see the salary
+	e.GET("/employees/:id/salary-history", ctrl.ListSalaryHistory, authn)
+	e.GET("/employees/:id/salary", ctrl.GetSalaryAsOf, authn)
 
-	//Non-protected read routes
-	e.GET("/employees", ctrl.ListEmployees)
-	e.GET("/employees/:id", ctrl.GetEmployee)
+	e.GET("/audit", auditCtrl.ListAudit, authn, can(auth.PermAuditRead))
+	e.GET("/employees/:id/history", auditCtrl.GetEmployeeHistory, authn, can(auth.PermAuditRead))
+
+	//Non-protected read routes; salaries are only shown to callers allowed to see them
+	e.GET("/employees", ctrl.ListEmployees, maybeAuthn)
+	e.GET("/employees/export", ctrl.ExportEmployees, maybeAuthn)
+	e.GET("/employees/search", ctrl.SearchEmployees, maybeAuthn)
+	e.GET("/employees/:id", ctrl.GetEmployee, maybeAuthn)
+	e.GET("/employees/:id/managers", ctrl.GetManagerChain)
+	e.GET("/employees/:id/reports", ctrl.GetReports)
+	e.GET("/org-chart", ctrl.GetOrgChart)
+
+	e.POST("/departments", deptCtrl.CreateDepartment, authn, can(auth.PermDepartmentsWrite))
+	e.PUT("/departments/:id", deptCtrl.UpdateDepartment, authn, can(auth.PermDepartmentsWrite))
+	e.DELETE("/departments/:id", deptCtrl.DeleteDepartment, authn, can(auth.PermDepartmentsWrite))
+	e.POST("/departments/:id/employees", deptCtrl.MoveEmployees, authn, can(auth.PermDepartmentsWrite))
+	e.DELETE("/departments/:id/employees/:employee_id", deptCtrl.RemoveEmployee, authn, can(auth.PermDepartmentsWrite))
+
+	e.GET("/departments", deptCtrl.ListDepartments)
+	e.GET("/departments/costs", deptCtrl.GetDepartmentCosts, authn, can(auth.PermSalaryReadAll))
+	e.GET("/departments/:id", deptCtrl.GetDepartment)
+	e.GET("/departments/:id/employees", deptCtrl.ListDepartmentEmployees, maybeAuthn)
+
+	e.GET("/leave-types", leaveCtrl.ListLeaveTypes, authn)
+	e.POST("/leave-types", leaveCtrl.CreateLeaveType, authn, can(auth.PermLeaveManage))
+	//leave checks per employee whether the caller is the employee, their manager or HR
+	e.POST("/leave-requests", leaveCtrl.ApplyLeave, authn)
+	e.GET("/leave-requests/:id", leaveCtrl.GetLeaveRequest, authn)
+	e.POST("/leave-requests/:id/approve", leaveCtrl.ApproveLeave, authn)
+	e.POST("/leave-requests/:id/reject", leaveCtrl.RejectLeave, authn)
+	e.POST("/leave-requests/:id/cancel", leaveCtrl.CancelLeave, authn)
+	e.GET("/employees/:id/leave-balances", leaveCtrl.GetLeaveBalances, authn)
+	e.GET("/employees/:id/leave-requests", leaveCtrl.ListEmployeeLeaveRequests, authn)
+	e.GET("/leave-calendar", leaveCtrl.GetLeaveCalendar, authn)
+
+	//attendance checks per employee whether the caller is the employee, their manager or HR
+	e.POST("/employees/:id/attendance/clock-in", attendanceCtrl.ClockIn, authn)
+	e.POST("/employees/:id/attendance/clock-out", attendanceCtrl.ClockOut, authn)
+	e.GET("/employees/:id/attendance", attendanceCtrl.ListAttendance, authn)
+	e.GET("/employees/:id/timesheet", attendanceCtrl.GetTimesheet, authn)
+	e.GET("/employees/:id/timesheet/export", attendanceCtrl.ExportTimesheet, authn)
+
+	e.GET("/payroll-rules", payrollCtrl.ListPayrollRules, authn, can(auth.PermPayrollManage))
+	e.POST("/payroll-rules", payrollCtrl.CreatePayrollRule, authn, can(auth.PermPayrollManage))
+	e.PUT("/payroll-rules/:id", payrollCtrl.UpdatePayrollRule, authn, can(auth.PermPayrollManage))
+	e.DELETE("/payroll-rules/:id", payrollCtrl.DeletePayrollRule, authn, can(auth.PermPayrollManage))
+	e.GET("/payroll-runs", payrollCtrl.ListPayrollRuns, authn, can(auth.PermPayrollManage))
+	e.POST("/payroll-runs", payrollCtrl.CreatePayrollRun, authn, can(auth.PermPayrollManage))
+	e.GET("/payroll-runs/:id", payrollCtrl.GetPayrollRun, authn, can(auth.PermPayrollManage))
+	e.GET("/payroll-runs/:id/payslips", payrollCtrl.ListPayrollRunPayslips, authn, can(auth.PermPayrollManage))
+	e.POST("/payroll-runs/:id/recompute", payrollCtrl.RecomputePayrollRun, authn, can(auth.PermPayrollManage))
+	e.POST("/payroll-runs/:id/approve", payrollCtrl.ApprovePayrollRun, authn, can(auth.PermPayrollApprove))
+	e.POST("/payroll-runs/:id/finalize", payrollCtrl.FinalizePayrollRun, authn, can(auth.PermPayrollApprove))
+	//payslips check whether the caller is the employee or HR
+	e.GET("/employees/:id/payslips", payrollCtrl.ListEmployeePayslips, authn)
+
+	e.GET("/exchange-rates", rateCtrl.ListExchangeRates, authn)
+	e.POST("/exchange-rates", rateCtrl.SetExchangeRate, authn, can(auth.PermExchangeRatesManage))
+	e.POST("/exchange-rates/import", rateCtrl.ImportExchangeRates, authn, can(auth.PermExchangeRatesManage))
+	e.DELETE("/exchange-rates/:id", rateCtrl.DeleteExchangeRate, authn, can(auth.PermExchangeRatesManage))
+
+	e.POST("/users", userCtrl.CreateUser, authn, can(auth.PermUsersManage))
+	e.GET("/users", userCtrl.ListUsers, authn, can(auth.PermUsersManage))
+	e.PUT("/users/:id/role", userCtrl.UpdateUserRole, authn, can(auth.PermUsersManage))
+	e.DELETE("/users/:id", userCtrl.DeleteUser, authn, can(auth.PermUsersManage))
 }
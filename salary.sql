@@ -0,0 +1,18 @@
+-- name: CreateSalaryRecord :exec
+INSERT INTO salary_history (id, employee_id, salary, effective_date, reason, approved_by, approved_by_email, created_at, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListSalaryHistory :many
+-- newest effective date first; records on the same date in the order they were made
+SELECT id, employee_id, salary, effective_date, reason, approved_by, approved_by_email, created_at, currency
+FROM salary_history
+WHERE employee_id = $1
+ORDER BY effective_date DESC, created_at DESC, id DESC;
+
+-- name: GetSalaryAsOf :one
+-- the record in effect on a date: the latest one effective on or before it
+SELECT id, employee_id, salary, effective_date, reason, approved_by, approved_by_email, created_at, currency
+FROM salary_history
+WHERE employee_id = sqlc.arg(employee_id) AND effective_date <= sqlc.arg(as_of)::date
+ORDER BY effective_date DESC, created_at DESC, id DESC
+LIMIT 1;
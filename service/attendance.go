@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+// StandardDailyHours is the working day; hours beyond it count as overtime
+const StandardDailyHours = 8
+
+var (
+	// ErrAttendanceForbidden is returned when the caller is neither the
+	// employee, their manager nor HR
+	ErrAttendanceForbidden = customerr.New(customerr.CodeForbidden, "you may not view or record this employee's attendance")
+	// ErrPunchTimeForbidden is returned when someone other than HR backdates a punch
+	ErrPunchTimeForbidden = customerr.New(customerr.CodeForbidden, "only HR may record a punch at a time other than now")
+)
+
+// timesheetTrunc maps each timesheet period to its PostgreSQL date_trunc field
+var timesheetTrunc = map[database.TimesheetPeriod]string{
+	database.TimesheetDaily:   "day",
+	database.TimesheetWeekly:  "week",
+	database.TimesheetMonthly: "month",
+}
+
+type AttendanceService interface {
+	ClockIn(ctx context.Context, employeeID uuid.UUID, punch *database.Punch, caller *auth.Claims) (*database.AttendanceRecord, error)
+	ClockOut(ctx context.Context, employeeID uuid.UUID, punch *database.Punch, caller *auth.Claims) (*database.AttendanceRecord, error)
+	//ListAttendance returns the shifts starting from from to to inclusive
+	ListAttendance(ctx context.Context, employeeID uuid.UUID, from, to time.Time, caller *auth.Claims) ([]database.AttendanceRecord, error)
+	GetTimesheet(ctx context.Context, employeeID uuid.UUID, query database.TimesheetQuery, caller *auth.Claims) (*database.Timesheet, error)
+}
+
+type attendanceService struct {
+	db        repo.TxBeginner
+	repo      repo.AttendanceRepo
+	employees repo.EmployeeRepo
+}
+
+func NewAttendanceService(db repo.TxBeginner, repo repo.AttendanceRepo, employees repo.EmployeeRepo) AttendanceService {
+	return &attendanceService{
+		db:        db,
+		repo:      repo,
+		employees: employees,
+	}
+}
+
+func (s *attendanceService) ClockIn(ctx context.Context, employeeID uuid.UUID, punch *database.Punch, caller *auth.Claims) (*database.AttendanceRecord, error) {
+	at, err := punchTime(punch, employeeID, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &database.AttendanceRecord{
+		EmployeeID: employeeID,
+		ClockIn:    at,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	err = repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		//locking the employee serializes their punches, so two cannot open overlapping shifts
+		emp, err := s.employees.WithTx(tx).GetEmployeeForUpdate(ctx, employeeID)
+		if err != nil {
+			return err
+		}
+		if at.Before(dateOf(emp.HiredDate)) {
+			return customerr.InvalidField("at", customerr.FieldOutOfRange, "at must not be before the employee's hired_date")
+		}
+
+		open, err := txRepo.GetOpenShiftForUpdate(ctx, employeeID)
+		if err != nil {
+			return err
+		}
+		if open != nil {
+			return customerr.Conflict(fmt.Sprintf("already clocked in since %s", open.ClockIn.Format(time.RFC3339)))
+		}
+		later, err := txRepo.CountShiftsEndingAfter(ctx, employeeID, at)
+		if err != nil {
+			return err
+		}
+		if later > 0 {
+			return customerr.Conflict("the shift would overlap one that ends after it starts")
+		}
+		return txRepo.ClockIn(ctx, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (s *attendanceService) ClockOut(ctx context.Context, employeeID uuid.UUID, punch *database.Punch, caller *auth.Claims) (*database.AttendanceRecord, error) {
+	at, err := punchTime(punch, employeeID, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	var rec *database.AttendanceRecord
+	err = repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		if _, err := s.employees.WithTx(tx).GetEmployeeForUpdate(ctx, employeeID); err != nil {
+			return err
+		}
+		open, err := txRepo.GetOpenShiftForUpdate(ctx, employeeID)
+		if err != nil {
+			return err
+		}
+		if open == nil {
+			return customerr.Conflict("not clocked in")
+		}
+		if !at.After(open.ClockIn) {
+			return customerr.InvalidField("at", customerr.FieldOutOfRange, "at must be after the shift's clock_in")
+		}
+
+		open.ClockOut = &at
+		open.UpdatedAt = time.Now()
+		if err := txRepo.ClockOut(ctx, open); err != nil {
+			return err
+		}
+		rec = open
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (s *attendanceService) ListAttendance(ctx context.Context, employeeID uuid.UUID, from, to time.Time, caller *auth.Claims) ([]database.AttendanceRecord, error) {
+	if err := s.checkAttendanceVisible(ctx, employeeID, caller); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAttendance(ctx, employeeID, dateOf(from), dateOf(to).AddDate(0, 0, 1))
+}
+
+func (s *attendanceService) GetTimesheet(ctx context.Context, employeeID uuid.UUID, query database.TimesheetQuery, caller *auth.Claims) (*database.Timesheet, error) {
+	trunc, ok := timesheetTrunc[query.Period]
+	if !ok {
+		return nil, customerr.InvalidField("period", customerr.FieldInvalidValue, "period must be daily, weekly or monthly")
+	}
+	if err := s.checkAttendanceVisible(ctx, employeeID, caller); err != nil {
+		return nil, err
+	}
+
+	from, to := dateOf(query.From), dateOf(query.To)
+	entries, err := s.repo.AggregateTimesheet(ctx, employeeID, trunc, StandardDailyHours, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &database.Timesheet{
+		EmployeeID:    employeeID,
+		Period:        query.Period,
+		From:          from,
+		To:            to,
+		StandardHours: StandardDailyHours,
+		Entries:       entries,
+	}
+	for i := range entries {
+		entries[i].PeriodEnd = periodEnd(query.Period, entries[i].PeriodStart)
+		sheet.TotalHours += entries[i].Hours
+		sheet.TotalOvertimeHours += entries[i].OvertimeHours
+	}
+	//each entry is rounded to a hundredth, so the sums are too
+	sheet.TotalHours = math.Round(sheet.TotalHours*100) / 100
+	sheet.TotalOvertimeHours = math.Round(sheet.TotalOvertimeHours*100) / 100
+	return sheet, nil
+}
+
+// checkAttendanceVisible fails unless the employee exists and caller is the
+// employee, their direct manager or HR
+func (s *attendanceService) checkAttendanceVisible(ctx context.Context, employeeID uuid.UUID, caller *auth.Claims) error {
+	emp, err := s.employees.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return err
+	}
+	if caller == nil {
+		return ErrAttendanceForbidden
+	}
+	if isEmployee(caller, emp.ID) || auth.Can(caller.Role, auth.PermAttendanceManage) {
+		return nil
+	}
+	if auth.Can(caller.Role, auth.PermAttendanceReadReports) && caller.EmployeeID != nil &&
+		emp.ManagerID != nil && *emp.ManagerID == *caller.EmployeeID {
+		return nil
+	}
+	return ErrAttendanceForbidden
+}
+
+// punchTime returns when a punch by caller for employeeID happens: now, or
+// the time HR gave. Employees may only punch for themselves.
+func punchTime(punch *database.Punch, employeeID uuid.UUID, caller *auth.Claims) (time.Time, error) {
+	manage := caller != nil && auth.Can(caller.Role, auth.PermAttendanceManage)
+	if !manage && !isEmployee(caller, employeeID) {
+		return time.Time{}, ErrAttendanceForbidden
+	}
+	if punch.At == nil {
+		return time.Now().UTC(), nil
+	}
+	if !manage {
+		return time.Time{}, ErrPunchTimeForbidden
+	}
+	return punch.At.UTC(), nil
+}
+
+// isEmployee reports whether caller is linked to the employee id
+func isEmployee(caller *auth.Claims, id uuid.UUID) bool {
+	return caller != nil && caller.EmployeeID != nil && *caller.EmployeeID == id
+}
+
+// periodEnd is the last day of the timesheet period starting on start
+func periodEnd(period database.TimesheetPeriod, start time.Time) time.Time {
+	switch period {
+	case database.TimesheetWeekly:
+		return start.AddDate(0, 0, 6)
+	case database.TimesheetMonthly:
+		return start.AddDate(0, 1, -1)
+	}
+	return start
+}
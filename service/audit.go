@@ -0,0 +1,137 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+// Audited actions and entity types
+const (
+	AuditActionCreate     = "create"
+	AuditActionUpdate     = "update"
+	AuditActionDelete     = "delete"
+	AuditActionSetManager = "set_manager"
+	//AuditActionSetDepartment records an employee moved into or out of a department
+	AuditActionSetDepartment = "set_department"
+	AuditActionRestore       = "restore"
+	AuditActionPurge         = "purge"
+	AuditActionApply         = "apply"
+	AuditActionApprove       = "approve"
+	AuditActionReject        = "reject"
+	AuditActionCancel        = "cancel"
+	AuditActionRecompute     = "recompute"
+	AuditActionFinalize      = "finalize"
+
+	AuditEntityEmployee     = "employee"
+	AuditEntityLeaveType    = "leave_type"
+	AuditEntityLeaveRequest = "leave_request"
+	AuditEntityPayrollRule  = "payroll_rule"
+	AuditEntityPayrollRun   = "payroll_run"
+	AuditEntityExchangeRate = "exchange_rate"
+)
+
+type AuditService interface {
+	ListEntries(ctx context.Context, filter database.AuditFilter) (*database.AuditPage, error)
+	//EmployeeHistory lists the entries for one employee, including ones recorded before it was deleted
+	EmployeeHistory(ctx context.Context, id uuid.UUID, filter database.AuditFilter) (*database.AuditPage, error)
+}
+
+type auditService struct {
+	repo repo.AuditRepo
+}
+
+func NewAuditService(repo repo.AuditRepo) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) ListEntries(ctx context.Context, filter database.AuditFilter) (*database.AuditPage, error) {
+	return s.repo.ListEntries(ctx, filter)
+}
+
+func (s *auditService) EmployeeHistory(ctx context.Context, id uuid.UUID, filter database.AuditFilter) (*database.AuditPage, error) {
+	entityType := AuditEntityEmployee
+	filter.EntityType = &entityType
+	filter.EntityID = &id
+	return s.repo.ListEntries(ctx, filter)
+}
+
+// recordAudit writes an entry for the change from before to after; either may
+// be nil for creations and deletions. Pass a repo bound to the mutation's
+// transaction so the entry commits or rolls back with it.
+func recordAudit(ctx context.Context, audit repo.AuditRepo, actor *auth.Claims, action, entityType string, entityID uuid.UUID, before, after interface{}) error {
+	changes, err := diffFields(before, after)
+	if err != nil {
+		return err
+	}
+
+	entry := &database.AuditEntry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		CreatedAt:  time.Now(),
+	}
+	if actor != nil {
+		entry.ActorEmail = actor.Email
+		if userID, err := actor.UserID(); err == nil {
+			entry.ActorID = &userID
+		}
+	}
+	return audit.CreateEntry(ctx, entry)
+}
+
+// diffFields compares the JSON forms of before and after field by field and
+// returns the fields whose values differ
+func diffFields(before, after interface{}) (map[string]database.FieldChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	null := json.RawMessage("null")
+	changes := make(map[string]database.FieldChange)
+	for name, value := range beforeFields {
+		if next, ok := afterFields[name]; !ok || !bytes.Equal(value, next) {
+			change := database.FieldChange{Before: value, After: null}
+			if ok {
+				change.After = next
+			}
+			changes[name] = change
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = database.FieldChange{Before: null, After: value}
+		}
+	}
+	return changes, nil
+}
+
+// jsonFields splits v's JSON object into its fields; nil has none
+func jsonFields(v interface{}) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if v == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit snapshot: %w", err)
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to split audit snapshot: %w", err)
+	}
+	return fields, nil
+}
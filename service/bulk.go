@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+var (
+	//errBulkAborted rolls back an all-or-nothing batch after one of its items failed
+	errBulkAborted = errors.New("bulk request aborted")
+	//errBulkDryRun rolls back a batch that was only run to see what would fail
+	errBulkDryRun = errors.New("bulk request dry run")
+)
+
+func (s *employeeService) BulkCreateEmployees(ctx context.Context, emps []database.Employee, mode database.BulkMode, actor *auth.Claims) (*database.BulkResult, error) {
+	return s.createEmployees(ctx, emps, mode, false, actor)
+}
+
+func (s *employeeService) ImportEmployees(ctx context.Context, emps []database.Employee, mode database.BulkMode, dryRun bool, actor *auth.Claims) (*database.BulkResult, error) {
+	return s.createEmployees(ctx, emps, mode, dryRun, actor)
+}
+
+func (s *employeeService) createEmployees(ctx context.Context, emps []database.Employee, mode database.BulkMode, dryRun bool, actor *auth.Claims) (*database.BulkResult, error) {
+	return s.runBulk(ctx, mode, dryRun, len(emps), func(tx pgx.Tx, i int) (database.BulkItemResult, []uuid.UUID, error) {
+		id, err := s.createEmployeeTx(ctx, tx, &emps[i], actor)
+		if err != nil {
+			return database.BulkItemResult{}, nil, err
+		}
+		emps[i].ID = id
+		return database.BulkItemResult{Status: database.BulkStatusCreated, ID: &id, Employee: &emps[i]}, []uuid.UUID{id}, nil
+	})
+}
+
+func (s *employeeService) BulkPatchEmployees(ctx context.Context, patches []database.BulkPatch, mode database.BulkMode, actor *auth.Claims) (*database.BulkResult, error) {
+	return s.runBulk(ctx, mode, false, len(patches), func(tx pgx.Tx, i int) (database.BulkItemResult, []uuid.UUID, error) {
+		p := patches[i]
+		emp, err := s.patchEmployeeTx(ctx, tx, p.ID, p.Patch, p.Pre, actor)
+		if err != nil {
+			return database.BulkItemResult{}, nil, err
+		}
+		return database.BulkItemResult{Status: database.BulkStatusUpdated, ID: &emp.ID, Employee: emp}, []uuid.UUID{emp.ID}, nil
+	})
+}
+
+func (s *employeeService) BulkDeleteEmployees(ctx context.Context, deletes []database.BulkDelete, mode database.BulkMode, actor *auth.Claims) (*database.BulkResult, error) {
+	return s.runBulk(ctx, mode, false, len(deletes), func(tx pgx.Tx, i int) (database.BulkItemResult, []uuid.UUID, error) {
+		id := deletes[i].ID
+		reports, err := s.deleteEmployeeTx(ctx, tx, id, deletes[i].Pre, actor)
+		if err != nil {
+			return database.BulkItemResult{}, nil, err
+		}
+		return database.BulkItemResult{Status: database.BulkStatusDeleted, ID: &id}, append(reports, id), nil
+	})
+}
+
+// runBulk applies n items in one transaction, each in its own savepoint so a
+// failed item leaves the others intact. apply returns the item's result and
+// the employees whose cached records it made stale. In all-or-nothing mode the
+// first failure rolls the whole batch back; in best-effort mode only the failed
+// item is undone. Server-side failures abort the batch in either mode. A dry
+// run reports the same outcomes but rolls everything back at the end.
+//
+// The cache is evicted once, after the transaction commits.
+func (s *employeeService) runBulk(ctx context.Context, mode database.BulkMode, dryRun bool, n int, apply func(tx pgx.Tx, i int) (database.BulkItemResult, []uuid.UUID, error)) (*database.BulkResult, error) {
+	results := make([]database.BulkItemResult, n)
+	var stale []uuid.UUID
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		for i := 0; i < n; i++ {
+			//a transaction begun on a transaction is a savepoint
+			err := repo.RunInTx(ctx, tx, func(sp pgx.Tx) error {
+				result, evict, err := apply(sp, i)
+				if err != nil {
+					return err
+				}
+				results[i] = result
+				stale = append(stale, evict...)
+				return nil
+			})
+			if err == nil {
+				continue
+			}
+			if customerr.StatusOf(err) >= http.StatusInternalServerError {
+				return err
+			}
+			results[i] = database.BulkItemResult{Status: database.BulkStatusFailed, Err: err}
+			if mode == database.BulkAllOrNothing {
+				return errBulkAborted
+			}
+		}
+		if dryRun {
+			return errBulkDryRun
+		}
+		return nil
+	})
+	aborted := errors.Is(err, errBulkAborted)
+	if err != nil && !aborted && !errors.Is(err, errBulkDryRun) {
+		return nil, err
+	}
+
+	for i := range results {
+		item := &results[i]
+		item.Index = i
+		switch {
+		case item.Status == "":
+			item.Status = database.BulkStatusSkipped
+		case aborted && item.Status != database.BulkStatusFailed:
+			//a rolled back insert leaves no employee to point at
+			if item.Status == database.BulkStatusCreated {
+				item.ID = nil
+			}
+			item.Status = database.BulkStatusRolledBack
+			item.Employee = nil
+		}
+	}
+	result := &database.BulkResult{Mode: mode, Results: results}
+	result.Tally()
+	if aborted || dryRun || result.Succeeded == 0 {
+		return result, nil
+	}
+
+	if err := evictEmployees(ctx, s.redis, stale); err != nil {
+		return result, err
+	}
+	return result, nil
+}
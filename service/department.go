@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/lijuuu/EmployeeManagement/repo"
+	"github.com/redis/go-redis/v9"
+)
+
+type DepartmentService interface {
+	CreateDepartment(ctx context.Context, dept *database.Department) (uuid.UUID, error)
+	GetDepartmentByID(ctx context.Context, id uuid.UUID) (*database.Department, error)
+	UpdateDepartment(ctx context.Context, id uuid.UUID, dept *database.Department) error
+	//membership changes, including members released by DeleteDepartment, are
+	//recorded in each employee's audit history with actor
+	DeleteDepartment(ctx context.Context, id uuid.UUID, actor *auth.Claims) error
+	ListDepartments(ctx context.Context) ([]database.Department, error)
+	ListMembers(ctx context.Context, id uuid.UUID, filter database.EmployeeFilter, caller *auth.Claims) (*database.EmployeeViewPage, error)
+	MoveEmployees(ctx context.Context, id uuid.UUID, employeeIDs []uuid.UUID, actor *auth.Claims) ([]uuid.UUID, error)
+	RemoveEmployee(ctx context.Context, id uuid.UUID, employeeID uuid.UUID, actor *auth.Claims) error
+	//CostReport sums each department's annual salaries; with conv they are also
+	//converted to its currency, by default at today's rates
+	CostReport(ctx context.Context, conv *database.Conversion) (*database.DepartmentCostReport, error)
+}
+
+type departmentService struct {
+	db        repo.TxBeginner
+	repo      repo.DepartmentRepo
+	audit     repo.AuditRepo
+	employees EmployeeService
+	rates     repo.ExchangeRateRepo
+	redis     *redis.Client
+}
+
+func NewDepartmentService(db repo.TxBeginner, repo repo.DepartmentRepo, audit repo.AuditRepo, employees EmployeeService, rates repo.ExchangeRateRepo, redis *redis.Client) DepartmentService {
+	return &departmentService{
+		db:        db,
+		repo:      repo,
+		audit:     audit,
+		employees: employees,
+		rates:     rates,
+		redis:     redis,
+	}
+}
+
+func (s *departmentService) CreateDepartment(ctx context.Context, dept *database.Department) (uuid.UUID, error) {
+	dept.CreatedAt = time.Now()
+	dept.UpdatedAt = time.Now()
+
+	id, err := s.repo.CreateDepartment(ctx, dept)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := s.cacheDepartment(ctx, dept); err != nil {
+		return id, err
+	}
+	if err := s.redis.Del(ctx, "departments:list").Err(); err != nil {
+		return id, fmt.Errorf("failed to invalidate department list cache: %w", err)
+	}
+	return id, nil
+}
+
+func (s *departmentService) GetDepartmentByID(ctx context.Context, id uuid.UUID) (*database.Department, error) {
+	cacheKey := fmt.Sprintf("department:%s", id.String())
+	if cached, err := s.redis.Get(ctx, cacheKey).Result(); err == nil {
+		var dept database.Department
+		if err := json.Unmarshal([]byte(cached), &dept); err == nil {
+			return &dept, nil
+		}
+	}
+
+	dept, err := s.repo.GetDepartmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cacheDepartment(ctx, dept); err != nil {
+		return dept, err
+	}
+	return dept, nil
+}
+
+func (s *departmentService) UpdateDepartment(ctx context.Context, id uuid.UUID, dept *database.Department) error {
+	if err := s.repo.UpdateDepartment(ctx, id, dept); err != nil {
+		return err
+	}
+
+	//read back so the cache holds the stored timestamps rather than the request body
+	stored, err := s.repo.GetDepartmentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	*dept = *stored
+
+	if err := s.cacheDepartment(ctx, dept); err != nil {
+		return err
+	}
+	if err := s.redis.Del(ctx, "departments:list").Err(); err != nil {
+		return fmt.Errorf("failed to invalidate department list cache: %w", err)
+	}
+	return nil
+}
+
+func (s *departmentService) DeleteDepartment(ctx context.Context, id uuid.UUID, actor *auth.Claims) error {
+	var released []uuid.UUID
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		changes, err := s.repo.WithTx(tx).DeleteDepartment(ctx, id)
+		if err != nil {
+			return err
+		}
+		released, err = s.auditMembers(ctx, tx, changes, actor)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	cacheKey := fmt.Sprintf("department:%s", id.String())
+	if err := s.redis.Del(ctx, cacheKey).Err(); err != nil {
+		return fmt.Errorf("failed to delete department cache: %w", err)
+	}
+	if err := s.redis.Del(ctx, "departments:list").Err(); err != nil {
+		return fmt.Errorf("failed to invalidate department list cache: %w", err)
+	}
+	return evictEmployees(ctx, s.redis, released)
+}
+
+func (s *departmentService) ListDepartments(ctx context.Context) ([]database.Department, error) {
+	cacheKey := "departments:list"
+	if cached, err := s.redis.Get(ctx, cacheKey).Result(); err == nil {
+		var depts []database.Department
+		if err := json.Unmarshal([]byte(cached), &depts); err == nil {
+			return depts, nil
+		}
+	}
+
+	depts, err := s.repo.ListDepartments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deptJSON, err := json.Marshal(depts)
+	if err != nil {
+		return depts, fmt.Errorf("failed to marshal departments: %w", err)
+	}
+	if err := s.redis.Set(ctx, cacheKey, deptJSON, 5*time.Minute).Err(); err != nil {
+		return depts, fmt.Errorf("failed to cache departments: %w", err)
+	}
+	return depts, nil
+}
+
+func (s *departmentService) ListMembers(ctx context.Context, id uuid.UUID, filter database.EmployeeFilter, caller *auth.Claims) (*database.EmployeeViewPage, error) {
+	if _, err := s.GetDepartmentByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	//members are an employee listing scoped to the department, cached alongside other listings
+	filter.DepartmentID = &id
+	return s.employees.ListEmployees(ctx, filter, caller)
+}
+
+func (s *departmentService) MoveEmployees(ctx context.Context, id uuid.UUID, employeeIDs []uuid.UUID, actor *auth.Claims) ([]uuid.UUID, error) {
+	if _, err := s.GetDepartmentByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var moved []uuid.UUID
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		changes, err := s.repo.WithTx(tx).MoveEmployees(ctx, id, employeeIDs)
+		if err != nil {
+			return err
+		}
+		moved, err = s.auditMembers(ctx, tx, changes, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, evictEmployees(ctx, s.redis, moved)
+}
+
+func (s *departmentService) RemoveEmployee(ctx context.Context, id uuid.UUID, employeeID uuid.UUID, actor *auth.Claims) error {
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		change, err := s.repo.WithTx(tx).RemoveEmployee(ctx, id, employeeID)
+		if err != nil {
+			return err
+		}
+		_, err = s.auditMembers(ctx, tx, []repo.MemberChange{*change}, actor)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return evictEmployees(ctx, s.redis, []uuid.UUID{employeeID})
+}
+
+// auditMembers records each membership change in the employee's history and
+// returns the ids of the changed employees
+func (s *departmentService) auditMembers(ctx context.Context, tx pgx.Tx, changes []repo.MemberChange, actor *auth.Claims) ([]uuid.UUID, error) {
+	txAudit := s.audit.WithTx(tx)
+	ids := make([]uuid.UUID, len(changes))
+	for i, change := range changes {
+		if err := recordAudit(ctx, txAudit, actor, AuditActionSetDepartment, AuditEntityEmployee, change.After.ID, change.Before, change.After); err != nil {
+			return nil, err
+		}
+		ids[i] = change.After.ID
+	}
+	return ids, nil
+}
+
+func (s *departmentService) CostReport(ctx context.Context, conv *database.Conversion) (*database.DepartmentCostReport, error) {
+	costs, err := s.repo.ListDepartmentCosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &database.DepartmentCostReport{Departments: costs}
+	if conv == nil {
+		return report, nil
+	}
+
+	asOf := conv.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now().UTC()
+	}
+	c, err := newConverter(ctx, s.rates, conv.Currency, asOf)
+	if err != nil {
+		return nil, err
+	}
+	var total money.Amount
+	for i := range report.Departments {
+		cost := &report.Departments[i]
+		var sum money.Amount
+		for _, salaries := range cost.Salaries {
+			converted, err := c.convert(salaries.Amount, salaries.Currency)
+			if err != nil {
+				return nil, err
+			}
+			sum = sum.Add(converted)
+		}
+		sum = c.round(sum)
+		cost.Converted = &sum
+		total = total.Add(sum)
+	}
+	report.Currency, report.RateDate, report.Total = c.currency, &c.asOf, &total
+	return report, nil
+}
+
+func (s *departmentService) cacheDepartment(ctx context.Context, dept *database.Department) error {
+	deptJSON, err := json.Marshal(dept)
+	if err != nil {
+		return fmt.Errorf("failed to marshal department: %w", err)
+	}
+	cacheKey := fmt.Sprintf("department:%s", dept.ID.String())
+	if err := s.redis.Set(ctx, cacheKey, deptJSON, 5*time.Minute).Err(); err != nil {
+		return fmt.Errorf("failed to cache department: %w", err)
+	}
+	return nil
+}
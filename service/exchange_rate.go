@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+type ExchangeRateService interface {
+	ListRates(ctx context.Context, filter database.ExchangeRateFilter) ([]database.ExchangeRate, error)
+	//SetRate stores a rate, replacing the currency's rate for the same day
+	SetRate(ctx context.Context, rate *database.ExchangeRate, actor *auth.Claims) error
+	//ImportRates stores every rate as SetRate does, in one transaction
+	ImportRates(ctx context.Context, rates []database.ExchangeRate, actor *auth.Claims) error
+	DeleteRate(ctx context.Context, id uuid.UUID, actor *auth.Claims) error
+}
+
+type exchangeRateService struct {
+	db    repo.TxBeginner
+	repo  repo.ExchangeRateRepo
+	audit repo.AuditRepo
+}
+
+func NewExchangeRateService(db repo.TxBeginner, repo repo.ExchangeRateRepo, audit repo.AuditRepo) ExchangeRateService {
+	return &exchangeRateService{
+		db:    db,
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+func (s *exchangeRateService) ListRates(ctx context.Context, filter database.ExchangeRateFilter) ([]database.ExchangeRate, error) {
+	if filter.AsOf == nil {
+		return s.repo.ListRates(ctx, filter.Currency)
+	}
+	rates, err := s.repo.ListRatesOn(ctx, dateOf(*filter.AsOf))
+	if err != nil || filter.Currency == "" {
+		return rates, err
+	}
+	for _, rate := range rates {
+		if rate.Currency == filter.Currency {
+			return []database.ExchangeRate{rate}, nil
+		}
+	}
+	return []database.ExchangeRate{}, nil
+}
+
+func (s *exchangeRateService) SetRate(ctx context.Context, rate *database.ExchangeRate, actor *auth.Claims) error {
+	return s.ImportRates(ctx, []database.ExchangeRate{*rate}, actor)
+}
+
+func (s *exchangeRateService) ImportRates(ctx context.Context, rates []database.ExchangeRate, actor *auth.Claims) error {
+	for _, rate := range rates {
+		if err := CheckExchangeRate(&rate); err != nil {
+			return err
+		}
+	}
+	return repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		txAudit := s.audit.WithTx(tx)
+		for i := range rates {
+			rate := &rates[i]
+			rate.EffectiveDate = dateOf(rate.EffectiveDate)
+			rate.UpdatedAt = time.Now()
+
+			before, err := txRepo.GetRateOn(ctx, rate.Currency, rate.EffectiveDate)
+			if err != nil && !errors.Is(err, customerr.ErrNotFound) {
+				return err
+			}
+			if err := txRepo.UpsertRate(ctx, rate); err != nil {
+				return err
+			}
+			action := AuditActionUpdate
+			if before == nil {
+				action = AuditActionCreate
+			}
+			if err := recordAudit(ctx, txAudit, actor, action, AuditEntityExchangeRate, rate.ID, before, rate); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *exchangeRateService) DeleteRate(ctx context.Context, id uuid.UUID, actor *auth.Claims) error {
+	return repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		before, err := txRepo.GetRate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := txRepo.DeleteRate(ctx, id); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionDelete, AuditEntityExchangeRate, id, before, nil)
+	})
+}
+
+// CheckExchangeRate applies the rules the validator cannot express; the rate
+// importer runs it on every row
+func CheckExchangeRate(rate *database.ExchangeRate) error {
+	if rate.Currency == money.DefaultCurrency {
+		return customerr.InvalidField("currency", customerr.FieldInvalidValue, "rates are against "+money.DefaultCurrency+", whose rate is always 1")
+	}
+	return nil
+}
+
+// converter converts amounts into one currency at the rates in effect on one day
+type converter struct {
+	currency string
+	asOf     time.Time
+	rates    money.Rates
+}
+
+// newConverter loads the rates in effect on asOf for converting to currency
+func newConverter(ctx context.Context, rates repo.ExchangeRateRepo, currency string, asOf time.Time) (*converter, error) {
+	asOf = dateOf(asOf)
+	byCurrency, err := rates.RatesOn(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return &converter{currency: currency, asOf: asOf, rates: byCurrency}, nil
+}
+
+// convert returns amount, which is in currency from, in the converter's
+// currency. Sums of converted amounts should be rounded once with round.
+func (c *converter) convert(amount money.Amount, from string) (money.Amount, error) {
+	converted, err := c.rates.Convert(amount, from, c.currency)
+	if errors.Is(err, money.ErrNoRate) {
+		return money.Amount{}, customerr.InvalidField("currency", customerr.FieldInvalidValue,
+			fmt.Sprintf("%v in effect on %s", err, c.asOf.Format(time.DateOnly)))
+	}
+	return converted, err
+}
+
+// round rounds a converted amount to the minor unit of the converter's currency
+func (c *converter) round(amount money.Amount) money.Amount {
+	return amount.Round(money.MinorUnits(c.currency))
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+var (
+	ErrSelfManager  = customerr.Validation("an employee cannot be their own manager")
+	ErrManagerCycle = customerr.Conflict("manager assignment would create a reporting cycle")
+)
+
+func (s *employeeService) SetManager(ctx context.Context, id uuid.UUID, managerID *uuid.UUID, pre *database.Precondition, actor *auth.Claims) error {
+	if managerID != nil && *managerID == id {
+		return ErrSelfManager
+	}
+
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		//the cycle check only holds while no other manager change commits in between
+		if err := txRepo.LockHierarchy(ctx); err != nil {
+			return err
+		}
+		before, err := txRepo.GetEmployeeForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkPrecondition(pre, before); err != nil {
+			return err
+		}
+
+		if managerID != nil {
+			if _, err := txRepo.GetEmployeeByID(ctx, *managerID); err != nil {
+				return err
+			}
+			//the employee must not already sit above the new manager
+			chain, err := txRepo.GetManagerChain(ctx, *managerID)
+			if err != nil {
+				return err
+			}
+			for _, node := range chain {
+				if node.ID == id {
+					return ErrManagerCycle
+				}
+			}
+		}
+
+		if err := txRepo.SetManager(ctx, id, managerID); err != nil {
+			return err
+		}
+		after, err := txRepo.GetEmployeeByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionSetManager, AuditEntityEmployee, id, before, after)
+	})
+	if err != nil {
+		return err
+	}
+
+	return evictEmployees(ctx, s.redis, []uuid.UUID{id})
+}
+
+func (s *employeeService) GetManagerChain(ctx context.Context, id uuid.UUID) ([]database.OrgNode, error) {
+	if _, err := s.getEmployee(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetManagerChain(ctx, id)
+}
+
+func (s *employeeService) GetReportTree(ctx context.Context, id uuid.UUID) (*database.OrgNode, error) {
+	emp, err := s.repo.GetEmployeeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.repo.GetReports(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := append([]database.OrgNode{{
+		ID:           emp.ID,
+		Name:         emp.Name,
+		Position:     emp.Position,
+		DepartmentID: emp.DepartmentID,
+		ManagerID:    emp.ManagerID,
+	}}, reports...)
+	buildOrgTree(nodes)
+	return &nodes[0], nil
+}
+
+func (s *employeeService) GetOrgChart(ctx context.Context) ([]*database.OrgNode, error) {
+	//keyed on the list version so any employee mutation retires it
+	version, err := s.listVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := fmt.Sprintf("employees:org:%d", version)
+	if cached, err := s.redis.Get(ctx, cacheKey).Result(); err == nil {
+		var roots []*database.OrgNode
+		if err := json.Unmarshal([]byte(cached), &roots); err == nil {
+			return roots, nil
+		}
+	}
+
+	nodes, err := s.repo.ListOrgNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roots := buildOrgTree(nodes)
+
+	chartJSON, err := json.Marshal(roots)
+	if err != nil {
+		return roots, fmt.Errorf("failed to marshal org chart: %w", err)
+	}
+	if err := s.redis.Set(ctx, cacheKey, chartJSON, 5*time.Minute).Err(); err != nil {
+		return roots, fmt.Errorf("failed to cache org chart: %w", err)
+	}
+	return roots, nil
+}
+
+// buildOrgTree links nodes to their managers in place and returns the nodes
+// whose manager is not in the set. Reports keep the order of nodes.
+func buildOrgTree(nodes []database.OrgNode) []*database.OrgNode {
+	byID := make(map[uuid.UUID]*database.OrgNode, len(nodes))
+	for i := range nodes {
+		byID[nodes[i].ID] = &nodes[i]
+	}
+
+	roots := []*database.OrgNode{}
+	for i := range nodes {
+		node := &nodes[i]
+		if node.ManagerID != nil {
+			if manager, ok := byID[*node.ManagerID]; ok {
+				manager.Reports = append(manager.Reports, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+// maxLeaveSpan caps how far apart a request's start and end dates may be
+const maxLeaveSpan = 366 * 24 * time.Hour
+
+var (
+	// ErrLeaveForbidden is returned when the caller is neither the employee,
+	// their manager nor HR
+	ErrLeaveForbidden = customerr.New(customerr.CodeForbidden, "you may not view or manage this employee's leave")
+	// ErrLeaveSelfDecision is returned when a caller decides their own request
+	ErrLeaveSelfDecision = customerr.New(customerr.CodeForbidden, "you may not approve or reject your own leave request")
+)
+
+type LeaveService interface {
+	CreateLeaveType(ctx context.Context, leaveType *database.LeaveType, actor *auth.Claims) error
+	ListLeaveTypes(ctx context.Context) ([]database.LeaveType, error)
+	//GetBalances accrues the employee's balances up to today and returns them
+	GetBalances(ctx context.Context, employeeID uuid.UUID, caller *auth.Claims) ([]database.LeaveBalance, error)
+	Apply(ctx context.Context, app *database.LeaveApplication, caller *auth.Claims) (*database.LeaveRequest, error)
+	GetRequest(ctx context.Context, id uuid.UUID, caller *auth.Claims) (*database.LeaveRequest, error)
+	ListEmployeeRequests(ctx context.Context, employeeID uuid.UUID, status *string, caller *auth.Claims) ([]database.LeaveRequest, error)
+	Approve(ctx context.Context, id uuid.UUID, decision *database.LeaveDecision, caller *auth.Claims) (*database.LeaveRequest, error)
+	Reject(ctx context.Context, id uuid.UUID, decision *database.LeaveDecision, caller *auth.Claims) (*database.LeaveRequest, error)
+	Cancel(ctx context.Context, id uuid.UUID, decision *database.LeaveDecision, caller *auth.Claims) (*database.LeaveRequest, error)
+	//TeamCalendar lists the leave a department or a manager's direct reports take in a date range
+	TeamCalendar(ctx context.Context, filter database.LeaveCalendarFilter, caller *auth.Claims) ([]database.LeaveCalendarEntry, error)
+}
+
+type leaveService struct {
+	db        repo.TxBeginner
+	repo      repo.LeaveRepo
+	employees repo.EmployeeRepo
+	audit     repo.AuditRepo
+}
+
+func NewLeaveService(db repo.TxBeginner, repo repo.LeaveRepo, employees repo.EmployeeRepo, audit repo.AuditRepo) LeaveService {
+	return &leaveService{
+		db:        db,
+		repo:      repo,
+		employees: employees,
+		audit:     audit,
+	}
+}
+
+func (s *leaveService) CreateLeaveType(ctx context.Context, leaveType *database.LeaveType, actor *auth.Claims) error {
+	leaveType.CreatedAt = time.Now()
+	leaveType.UpdatedAt = time.Now()
+
+	return repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		if err := s.repo.WithTx(tx).CreateLeaveType(ctx, leaveType); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionCreate, AuditEntityLeaveType, leaveType.ID, nil, leaveType)
+	})
+}
+
+func (s *leaveService) ListLeaveTypes(ctx context.Context) ([]database.LeaveType, error) {
+	return s.repo.ListLeaveTypes(ctx)
+}
+
+func (s *leaveService) GetBalances(ctx context.Context, employeeID uuid.UUID, caller *auth.Claims) ([]database.LeaveBalance, error) {
+	emp, err := s.employees.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !canViewLeave(caller, emp) {
+		return nil, ErrLeaveForbidden
+	}
+
+	var balances []database.LeaveBalance
+	err = repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		leaveTypes, err := txRepo.ListLeaveTypes(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range leaveTypes {
+			if err := accrue(ctx, txRepo, emp, &leaveTypes[i]); err != nil {
+				return err
+			}
+		}
+		balances, err = txRepo.ListLeaveBalances(ctx, employeeID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+func (s *leaveService) Apply(ctx context.Context, app *database.LeaveApplication, caller *auth.Claims) (*database.LeaveRequest, error) {
+	employeeID, err := applicant(app, caller)
+	if err != nil {
+		return nil, err
+	}
+	start, end := dateOf(app.StartDate), dateOf(app.EndDate)
+	if end.Sub(start) >= maxLeaveSpan {
+		return nil, customerr.InvalidField("end_date", customerr.FieldOutOfRange, "leave must not span more than 366 days")
+	}
+	days := LeaveDays(start, end)
+	if days == 0 {
+		return nil, customerr.InvalidField("end_date", customerr.FieldOutOfRange, "leave must include at least one working day")
+	}
+
+	req := &database.LeaveRequest{
+		EmployeeID:  employeeID,
+		LeaveTypeID: app.LeaveTypeID,
+		StartDate:   start,
+		EndDate:     end,
+		Days:        days,
+		Reason:      app.Reason,
+		Status:      database.LeaveStatusPending,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	err = repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		//locking the employee serializes their applications, so two cannot both draw on the same days
+		emp, err := s.employees.WithTx(tx).GetEmployeeForUpdate(ctx, employeeID)
+		if err != nil {
+			return err
+		}
+		if start.Before(dateOf(emp.HiredDate)) {
+			return customerr.InvalidField("start_date", customerr.FieldOutOfRange, "start_date must not be before the employee's hired_date")
+		}
+		leaveType, err := txRepo.GetLeaveType(ctx, app.LeaveTypeID)
+		if errors.Is(err, customerr.ErrNotFound) {
+			return customerr.InvalidField("leave_type_id", customerr.FieldInvalidValue, "leave_type_id is not a known leave type")
+		}
+		if err != nil {
+			return err
+		}
+
+		overlapping, err := txRepo.CountOverlappingLeave(ctx, employeeID, start, end)
+		if err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return customerr.Conflict("the leave overlaps another pending or approved request")
+		}
+		//unpaid leave costs pay rather than accrued days, so it needs no balance
+		if !leaveType.Unpaid {
+			if err := accrue(ctx, txRepo, emp, leaveType); err != nil {
+				return err
+			}
+			balance, err := txRepo.GetLeaveBalanceForUpdate(ctx, employeeID, leaveType.ID)
+			if err != nil {
+				return err
+			}
+			if days > balance.Available {
+				return customerr.Conflict(fmt.Sprintf("insufficient %s balance: %g days requested, %g available", leaveType.Name, days, balance.Available))
+			}
+		}
+
+		if err := txRepo.CreateLeaveRequest(ctx, req); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit.WithTx(tx), caller, AuditActionApply, AuditEntityLeaveRequest, req.ID, nil, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *leaveService) GetRequest(ctx context.Context, id uuid.UUID, caller *auth.Claims) (*database.LeaveRequest, error) {
+	req, err := s.repo.GetLeaveRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	emp, err := s.employees.GetEmployeeByID(ctx, req.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !canViewLeave(caller, emp) {
+		return nil, ErrLeaveForbidden
+	}
+	return req, nil
+}
+
+func (s *leaveService) ListEmployeeRequests(ctx context.Context, employeeID uuid.UUID, status *string, caller *auth.Claims) ([]database.LeaveRequest, error) {
+	emp, err := s.employees.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !canViewLeave(caller, emp) {
+		return nil, ErrLeaveForbidden
+	}
+	return s.repo.ListEmployeeLeaveRequests(ctx, employeeID, status)
+}
+
+func (s *leaveService) Approve(ctx context.Context, id uuid.UUID, decision *database.LeaveDecision, caller *auth.Claims) (*database.LeaveRequest, error) {
+	return s.decide(ctx, id, decision, caller, database.LeaveStatusApproved, AuditActionApprove,
+		func(txRepo repo.LeaveRepo, req *database.LeaveRequest, emp *database.Employee) error {
+			if err := checkDecider(caller, emp); err != nil {
+				return err
+			}
+			if req.Status != database.LeaveStatusPending {
+				return customerr.Conflict("only pending leave requests can be approved")
+			}
+
+			//approval draws on the days accrued so far; other pending requests do not hold them back
+			leaveType, err := txRepo.GetLeaveType(ctx, req.LeaveTypeID)
+			if err != nil {
+				return err
+			}
+			if leaveType.Unpaid {
+				return nil
+			}
+			if err := accrue(ctx, txRepo, emp, leaveType); err != nil {
+				return err
+			}
+			balance, err := txRepo.GetLeaveBalanceForUpdate(ctx, emp.ID, req.LeaveTypeID)
+			if err != nil {
+				return err
+			}
+			available := math.Round((balance.Accrued-balance.Used)*100) / 100
+			if req.Days > available {
+				return customerr.Conflict(fmt.Sprintf("insufficient %s balance: %g days requested, %g available", leaveType.Name, req.Days, available))
+			}
+			return txRepo.AddLeaveUsed(ctx, emp.ID, req.LeaveTypeID, req.Days)
+		})
+}
+
+func (s *leaveService) Reject(ctx context.Context, id uuid.UUID, decision *database.LeaveDecision, caller *auth.Claims) (*database.LeaveRequest, error) {
+	return s.decide(ctx, id, decision, caller, database.LeaveStatusRejected, AuditActionReject,
+		func(txRepo repo.LeaveRepo, req *database.LeaveRequest, emp *database.Employee) error {
+			if err := checkDecider(caller, emp); err != nil {
+				return err
+			}
+			if req.Status != database.LeaveStatusPending {
+				return customerr.Conflict("only pending leave requests can be rejected")
+			}
+			return nil
+		})
+}
+
+func (s *leaveService) Cancel(ctx context.Context, id uuid.UUID, decision *database.LeaveDecision, caller *auth.Claims) (*database.LeaveRequest, error) {
+	return s.decide(ctx, id, decision, caller, database.LeaveStatusCancelled, AuditActionCancel,
+		func(txRepo repo.LeaveRepo, req *database.LeaveRequest, emp *database.Employee) error {
+			if !canViewLeave(caller, emp) {
+				return ErrLeaveForbidden
+			}
+			if req.Status != database.LeaveStatusPending && req.Status != database.LeaveStatusApproved {
+				return customerr.Conflict("only pending or approved leave requests can be cancelled")
+			}
+			if req.Status == database.LeaveStatusPending {
+				return nil
+			}
+
+			//employees may call off approved leave until it starts; after that it takes their manager or HR
+			if !canDecideLeave(caller, emp) && !dateOf(time.Now()).Before(req.StartDate) {
+				return customerr.Conflict("leave that has started can only be cancelled by the employee's manager or HR")
+			}
+			leaveType, err := txRepo.GetLeaveType(ctx, req.LeaveTypeID)
+			if err != nil {
+				return err
+			}
+			if leaveType.Unpaid {
+				return nil
+			}
+			return txRepo.AddLeaveUsed(ctx, emp.ID, req.LeaveTypeID, -req.Days)
+		})
+}
+
+// decide moves a request to status after check approves the transition,
+// recording the caller as the decider; check runs inside the transaction with
+// the request locked
+func (s *leaveService) decide(ctx context.Context, id uuid.UUID, decision *database.LeaveDecision, caller *auth.Claims, status, action string, check func(txRepo repo.LeaveRepo, req *database.LeaveRequest, emp *database.Employee) error) (*database.LeaveRequest, error) {
+	var after *database.LeaveRequest
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		before, err := txRepo.GetLeaveRequestForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		emp, err := s.employees.WithTx(tx).GetEmployeeByID(ctx, before.EmployeeID)
+		if err != nil {
+			return err
+		}
+		if err := check(txRepo, before, emp); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		decided := *before
+		decided.Status = status
+		decided.DecisionNote = decision.Note
+		decided.DecidedAt = &now
+		decided.UpdatedAt = now
+		decided.DecidedBy = nil
+		decided.DecidedByEmail = ""
+		if caller != nil {
+			decided.DecidedByEmail = caller.Email
+			if userID, err := caller.UserID(); err == nil {
+				decided.DecidedBy = &userID
+			}
+		}
+		if err := txRepo.UpdateLeaveRequestStatus(ctx, &decided); err != nil {
+			return err
+		}
+		after = &decided
+		return recordAudit(ctx, s.audit.WithTx(tx), caller, action, AuditEntityLeaveRequest, id, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+func (s *leaveService) TeamCalendar(ctx context.Context, filter database.LeaveCalendarFilter, caller *auth.Claims) ([]database.LeaveCalendarEntry, error) {
+	//without a team, callers see their own direct reports
+	if filter.DepartmentID == nil && filter.ManagerID == nil {
+		if caller == nil || caller.EmployeeID == nil {
+			return nil, customerr.InvalidField("department_id", customerr.FieldRequired, "department_id or manager_id is required when the caller is not linked to an employee")
+		}
+		filter.ManagerID = caller.EmployeeID
+	}
+	//leave types can reveal health information, so like canDecideLeave only HR
+	//sees any team and managers only their own direct reports
+	if caller == nil || !auth.Can(caller.Role, auth.PermLeaveManage) {
+		if caller == nil || caller.EmployeeID == nil || !auth.Can(caller.Role, auth.PermLeaveApproveReports) {
+			return nil, ErrLeaveForbidden
+		}
+		if filter.ManagerID != nil && *filter.ManagerID != *caller.EmployeeID {
+			return nil, ErrLeaveForbidden
+		}
+		filter.ManagerID = caller.EmployeeID
+	}
+	filter.From, filter.To = dateOf(filter.From), dateOf(filter.To)
+	return s.repo.ListLeaveCalendar(ctx, filter)
+}
+
+// applicant returns the employee a leave application is for: the caller's own
+// record unless HR names someone else
+func applicant(app *database.LeaveApplication, caller *auth.Claims) (uuid.UUID, error) {
+	if app.EmployeeID == nil {
+		if caller == nil || caller.EmployeeID == nil {
+			return uuid.Nil, customerr.InvalidField("employee_id", customerr.FieldRequired, "employee_id is required when the caller is not linked to an employee")
+		}
+		return *caller.EmployeeID, nil
+	}
+	isSelf := caller != nil && caller.EmployeeID != nil && *caller.EmployeeID == *app.EmployeeID
+	if !isSelf && (caller == nil || !auth.Can(caller.Role, auth.PermLeaveManage)) {
+		return uuid.Nil, ErrLeaveForbidden
+	}
+	return *app.EmployeeID, nil
+}
+
+// canDecideLeave reports whether caller may approve or reject emp's leave:
+// HR may for anyone, managers for their direct reports
+func canDecideLeave(caller *auth.Claims, emp *database.Employee) bool {
+	if caller == nil {
+		return false
+	}
+	if auth.Can(caller.Role, auth.PermLeaveManage) {
+		return true
+	}
+	return auth.Can(caller.Role, auth.PermLeaveApproveReports) && caller.EmployeeID != nil &&
+		emp.ManagerID != nil && *emp.ManagerID == *caller.EmployeeID
+}
+
+// canViewLeave reports whether caller may see and cancel emp's leave: the
+// employee themselves and whoever may decide it
+func canViewLeave(caller *auth.Claims, emp *database.Employee) bool {
+	if caller != nil && caller.EmployeeID != nil && *caller.EmployeeID == emp.ID {
+		return true
+	}
+	return canDecideLeave(caller, emp)
+}
+
+// checkDecider fails unless caller may decide emp's leave and it is not
+// their own
+func checkDecider(caller *auth.Claims, emp *database.Employee) error {
+	if !canDecideLeave(caller, emp) {
+		return ErrLeaveForbidden
+	}
+	if caller.EmployeeID != nil && *caller.EmployeeID == emp.ID {
+		return ErrLeaveSelfDecision
+	}
+	return nil
+}
+
+// accrue brings the employee's balance for leaveType up to date as of today
+func accrue(ctx context.Context, txRepo repo.LeaveRepo, emp *database.Employee, leaveType *database.LeaveType) error {
+	accrued := AccruedLeave(leaveType.DaysPerYear, emp.HiredDate, time.Now())
+	return txRepo.UpsertLeaveAccrual(ctx, emp.ID, leaveType.ID, accrued)
+}
+
+// AccruedLeave returns the days of leave earned at daysPerYear from hired
+// through asOf inclusive, pro rata by day and rounded down to a hundredth, so
+// a full year's allowance is reached after 365 days of employment
+func AccruedLeave(daysPerYear float64, hired, asOf time.Time) float64 {
+	worked := dateOf(asOf).Sub(dateOf(hired)).Hours()/24 + 1
+	if worked <= 0 {
+		return 0
+	}
+	return math.Floor(daysPerYear*worked/365*100) / 100
+}
+
+// LeaveDays counts the working days, Monday to Friday, from start to end inclusive
+func LeaveDays(start, end time.Time) float64 {
+	days := 0.0
+	for day := dateOf(start); !day.After(dateOf(end)); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return days
+}
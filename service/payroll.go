@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+// ErrPayslipsForbidden is returned when the caller is neither the employee nor HR
+var ErrPayslipsForbidden = customerr.New(customerr.CodeForbidden, "you may not view this employee's payslips")
+
+type PayrollService interface {
+	ListRules(ctx context.Context) ([]database.PayrollRule, error)
+	CreateRule(ctx context.Context, rule *database.PayrollRule, actor *auth.Claims) error
+	UpdateRule(ctx context.Context, id uuid.UUID, rule *database.PayrollRule, actor *auth.Claims) error
+	DeleteRule(ctx context.Context, id uuid.UUID, actor *auth.Claims) error
+
+	//CreateRun computes a draft run for the calendar month starting on periodStart
+	CreateRun(ctx context.Context, periodStart time.Time, actor *auth.Claims) (*database.PayrollRun, error)
+	//ListRuns and GetRun add each run's totals converted to conv's currency,
+	//by default at the rates in effect on the last day of the run's month
+	ListRuns(ctx context.Context, conv *database.Conversion) ([]database.PayrollRun, error)
+	GetRun(ctx context.Context, id uuid.UUID, conv *database.Conversion) (*database.PayrollRun, error)
+	ListRunPayslips(ctx context.Context, id uuid.UUID) ([]database.Payslip, error)
+	//Recompute replaces the payslips of a draft run with ones computed from the current data
+	Recompute(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error)
+	Approve(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error)
+	Finalize(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error)
+	//EmployeePayslips lists the employee's payslips of finalized runs
+	EmployeePayslips(ctx context.Context, employeeID uuid.UUID, caller *auth.Claims) ([]database.Payslip, error)
+}
+
+type payrollService struct {
+	db    repo.TxBeginner
+	repo  repo.PayrollRepo
+	audit repo.AuditRepo
+	rates repo.ExchangeRateRepo
+}
+
+func NewPayrollService(db repo.TxBeginner, repo repo.PayrollRepo, audit repo.AuditRepo, rates repo.ExchangeRateRepo) PayrollService {
+	return &payrollService{
+		db:    db,
+		repo:  repo,
+		audit: audit,
+		rates: rates,
+	}
+}
+
+func (s *payrollService) ListRules(ctx context.Context) ([]database.PayrollRule, error) {
+	return s.repo.ListRules(ctx)
+}
+
+func (s *payrollService) CreateRule(ctx context.Context, rule *database.PayrollRule, actor *auth.Claims) error {
+	if err := checkPayrollRule(rule); err != nil {
+		return err
+	}
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+	return repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		if err := s.repo.WithTx(tx).CreateRule(ctx, rule); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionCreate, AuditEntityPayrollRule, rule.ID, nil, rule)
+	})
+}
+
+func (s *payrollService) UpdateRule(ctx context.Context, id uuid.UUID, rule *database.PayrollRule, actor *auth.Claims) error {
+	if err := checkPayrollRule(rule); err != nil {
+		return err
+	}
+	return repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		before, err := txRepo.GetRule(ctx, id)
+		if err != nil {
+			return err
+		}
+		rule.ID = id
+		rule.CreatedAt = before.CreatedAt
+		rule.UpdatedAt = time.Now()
+		if err := txRepo.UpdateRule(ctx, rule); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionUpdate, AuditEntityPayrollRule, id, before, rule)
+	})
+}
+
+func (s *payrollService) DeleteRule(ctx context.Context, id uuid.UUID, actor *auth.Claims) error {
+	return repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		before, err := txRepo.GetRule(ctx, id)
+		if err != nil {
+			return err
+		}
+		//payslips keep a copy of the lines a rule produced, so deleting it changes no run
+		if err := txRepo.DeleteRule(ctx, id); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionDelete, AuditEntityPayrollRule, id, before, nil)
+	})
+}
+
+func (s *payrollService) CreateRun(ctx context.Context, periodStart time.Time, actor *auth.Claims) (*database.PayrollRun, error) {
+	start := time.Date(periodStart.Year(), periodStart.Month(), 1, 0, 0, 0, 0, time.UTC)
+	now := time.Now().UTC()
+	if start.After(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)) {
+		return nil, customerr.InvalidField("period", customerr.FieldOutOfRange, "period must not be after the current month")
+	}
+
+	run := &database.PayrollRun{
+		PeriodStart: start,
+		PeriodEnd:   start.AddDate(0, 1, -1),
+		Status:      database.PayrollStatusDraft,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if actor != nil {
+		run.CreatedByEmail = actor.Email
+		if userID, err := actor.UserID(); err == nil {
+			run.CreatedBy = &userID
+		}
+	}
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		slips, err := s.compute(ctx, txRepo, run)
+		if err != nil {
+			return err
+		}
+		if err := txRepo.CreateRun(ctx, run); err != nil {
+			return err
+		}
+		if err := s.storePayslips(ctx, txRepo, run, slips); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionCreate, AuditEntityPayrollRun, run.ID, nil, run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (s *payrollService) ListRuns(ctx context.Context, conv *database.Conversion) ([]database.PayrollRun, error) {
+	runs, err := s.repo.ListRuns(ctx)
+	if err != nil || conv == nil {
+		return runs, err
+	}
+	//runs of the same month's end share its rates
+	converters := make(map[time.Time]*converter)
+	for i := range runs {
+		asOf := conv.AsOf
+		if asOf.IsZero() {
+			asOf = runs[i].PeriodEnd
+		}
+		c, ok := converters[asOf]
+		if !ok {
+			if c, err = newConverter(ctx, s.rates, conv.Currency, asOf); err != nil {
+				return nil, err
+			}
+			converters[asOf] = c
+		}
+		if err := convertRun(&runs[i], c); err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
+
+func (s *payrollService) GetRun(ctx context.Context, id uuid.UUID, conv *database.Conversion) (*database.PayrollRun, error) {
+	run, err := s.repo.GetRun(ctx, id)
+	if err != nil || conv == nil {
+		return run, err
+	}
+	asOf := conv.AsOf
+	if asOf.IsZero() {
+		asOf = run.PeriodEnd
+	}
+	c, err := newConverter(ctx, s.rates, conv.Currency, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if err := convertRun(run, c); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// convertRun sums the run's totals in the converter's currency. Net is
+// worked out from the rounded sums, so it still adds up.
+func convertRun(run *database.PayrollRun, c *converter) error {
+	sum := database.PayrollTotal{Currency: c.currency}
+	for _, total := range run.Totals {
+		sum.EmployeeCount += total.EmployeeCount
+		for _, field := range []struct {
+			from money.Amount
+			to   *money.Amount
+		}{
+			{total.Gross, &sum.Gross},
+			{total.Allowances, &sum.Allowances},
+			{total.Deductions, &sum.Deductions},
+		} {
+			converted, err := c.convert(field.from, total.Currency)
+			if err != nil {
+				return err
+			}
+			*field.to = field.to.Add(converted)
+		}
+	}
+	sum.Gross, sum.Allowances, sum.Deductions = c.round(sum.Gross), c.round(sum.Allowances), c.round(sum.Deductions)
+	sum.Net = sum.Gross.Add(sum.Allowances).Sub(sum.Deductions)
+	run.Converted = &sum
+	run.RateDate = &c.asOf
+	return nil
+}
+
+func (s *payrollService) ListRunPayslips(ctx context.Context, id uuid.UUID) ([]database.Payslip, error) {
+	//a 404 for an unknown run rather than an empty list
+	if _, err := s.repo.GetRun(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRunPayslips(ctx, id)
+}
+
+func (s *payrollService) Recompute(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error) {
+	var run *database.PayrollRun
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		before, err := txRepo.GetRunForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.Status != database.PayrollStatusDraft {
+			return customerr.Conflict(fmt.Sprintf("payroll run is %s, only draft runs can be recomputed", before.Status))
+		}
+
+		after := *before
+		slips, err := s.compute(ctx, txRepo, &after)
+		if err != nil {
+			return err
+		}
+		if err := txRepo.DeletePayslips(ctx, id); err != nil {
+			return err
+		}
+		if err := s.storePayslips(ctx, txRepo, &after, slips); err != nil {
+			return err
+		}
+		if err := txRepo.UpdateRunComputed(ctx, &after); err != nil {
+			return err
+		}
+		after.UpdatedAt = after.ComputedAt
+		run = &after
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionRecompute, AuditEntityPayrollRun, id, before, run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (s *payrollService) Approve(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error) {
+	return s.transition(ctx, id, actor, database.PayrollStatusDraft, database.PayrollStatusApproved, AuditActionApprove,
+		func(txRepo repo.PayrollRepo, run *database.PayrollRun, at time.Time, by *uuid.UUID, email string) error {
+			run.ApprovedAt, run.ApprovedBy, run.ApprovedByEmail = &at, by, email
+			return txRepo.ApproveRun(ctx, run)
+		})
+}
+
+func (s *payrollService) Finalize(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.PayrollRun, error) {
+	return s.transition(ctx, id, actor, database.PayrollStatusApproved, database.PayrollStatusFinalized, AuditActionFinalize,
+		func(txRepo repo.PayrollRepo, run *database.PayrollRun, at time.Time, by *uuid.UUID, email string) error {
+			run.FinalizedAt, run.FinalizedBy, run.FinalizedByEmail = &at, by, email
+			return txRepo.FinalizeRun(ctx, run)
+		})
+}
+
+func (s *payrollService) EmployeePayslips(ctx context.Context, employeeID uuid.UUID, caller *auth.Claims) ([]database.Payslip, error) {
+	if !isEmployee(caller, employeeID) && (caller == nil || !auth.Can(caller.Role, auth.PermPayrollManage)) {
+		return nil, ErrPayslipsForbidden
+	}
+	return s.repo.ListEmployeePayslips(ctx, employeeID)
+}
+
+// transition moves a run from status from to status to, letting set fill in
+// who did it and store the change
+func (s *payrollService) transition(ctx context.Context, id uuid.UUID, actor *auth.Claims, from, to, action string, set func(txRepo repo.PayrollRepo, run *database.PayrollRun, at time.Time, by *uuid.UUID, email string) error) (*database.PayrollRun, error) {
+	var run *database.PayrollRun
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		before, err := txRepo.GetRunForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.Status != from {
+			return customerr.Conflict(fmt.Sprintf("payroll run is %s, only %s runs can be %s", before.Status, from, to))
+		}
+
+		after := *before
+		after.Status = to
+		after.UpdatedAt = time.Now()
+		var by *uuid.UUID
+		email := ""
+		if actor != nil {
+			email = actor.Email
+			if userID, err := actor.UserID(); err == nil {
+				by = &userID
+			}
+		}
+		if err := set(txRepo, &after, after.UpdatedAt, by, email); err != nil {
+			return err
+		}
+		run = &after
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, action, AuditEntityPayrollRun, id, before, run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// compute works out the payslips of run's period and sets its totals
+func (s *payrollService) compute(ctx context.Context, txRepo repo.PayrollRepo, run *database.PayrollRun) ([]database.Payslip, error) {
+	rules, err := txRepo.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	employees, err := txRepo.ListPayrollEmployees(ctx, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	slips := make([]database.Payslip, len(employees))
+	for i, emp := range employees {
+		slips[i] = ComputePayslip(emp, rules, run.PeriodStart, run.PeriodEnd)
+	}
+	run.EmployeeCount = len(employees)
+	run.Totals = payrollTotals(slips)
+	run.ComputedAt = time.Now()
+	return slips, nil
+}
+
+// payrollTotals sums slips per currency, ordered by currency as the repo
+// lists them
+func payrollTotals(slips []database.Payslip) []database.PayrollTotal {
+	totals := []database.PayrollTotal{}
+	index := make(map[string]int)
+	for _, slip := range slips {
+		i, ok := index[slip.Currency]
+		if !ok {
+			i = len(totals)
+			index[slip.Currency] = i
+			totals = append(totals, database.PayrollTotal{Currency: slip.Currency})
+		}
+		total := &totals[i]
+		total.EmployeeCount++
+		total.Gross = total.Gross.Add(slip.Gross)
+		total.Allowances = total.Allowances.Add(slip.Allowances)
+		total.Deductions = total.Deductions.Add(slip.Deductions)
+		total.Net = total.Net.Add(slip.Net)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Currency < totals[j].Currency })
+	return totals
+}
+
+// storePayslips writes the payslips of run
+func (s *payrollService) storePayslips(ctx context.Context, txRepo repo.PayrollRepo, run *database.PayrollRun, slips []database.Payslip) error {
+	for i := range slips {
+		slips[i].RunID = run.ID
+		slips[i].CreatedAt = run.ComputedAt
+		if err := txRepo.CreatePayslip(ctx, &slips[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ComputePayslip works out emp's pay from start to end, a calendar month, in
+// emp's currency. Each working day, Monday to Friday, from the hired date to
+// the termination date earns the monthly salary in effect that day divided
+// by the month's working days, unless it falls in approved
+// unpaid leave. Fixed rules in emp's currency are pro rata for the days
+// paid; fixed rules in other currencies are skipped and percent rules apply
+// to gross. Amounts are exact, each rounded once to the currency's minor unit.
+func ComputePayslip(emp database.PayrollEmployee, rules []database.PayrollRule, start, end time.Time) database.Payslip {
+	start, end = dateOf(start), dateOf(end)
+	first, last := start, end
+	if hired := dateOf(emp.HiredDate); hired.After(first) {
+		first = hired
+	}
+	if emp.TerminationDate != nil {
+		if left := dateOf(*emp.TerminationDate); left.Before(last) {
+			last = left
+		}
+	}
+
+	slip := database.Payslip{
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		Status:       database.PayrollStatusDraft,
+		EmployeeID:   emp.ID,
+		EmployeeName: emp.Name,
+		Position:     emp.Position,
+		Currency:     emp.Currency,
+		AnnualSalary: salaryOn(emp, last),
+		WorkingDays:  int(LeaveDays(start, end)),
+		Lines:        []database.PayslipLine{},
+	}
+	if slip.WorkingDays == 0 {
+		return slip
+	}
+
+	places := money.MinorUnits(emp.Currency)
+	//the annual salaries of the days paid, divided only once
+	var paid money.Amount
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		if onLeave(emp.UnpaidLeave, day) {
+			slip.UnpaidLeaveDays++
+			continue
+		}
+		slip.PaidDays++
+		paid = paid.Add(salaryOn(emp, day))
+	}
+	slip.Gross = paid.MulFrac(1, int64(12*slip.WorkingDays), places)
+
+	for _, rule := range rules {
+		var amount money.Amount
+		switch {
+		case rule.Calculation == database.PayrollPercent:
+			amount = slip.Gross.Percent(rule.Amount, places)
+		case rule.Currency == emp.Currency:
+			amount = rule.Amount.MulFrac(int64(slip.PaidDays), int64(slip.WorkingDays), places)
+		default:
+			continue
+		}
+		slip.Lines = append(slip.Lines, database.PayslipLine{Name: rule.Name, Kind: rule.Kind, Amount: amount})
+		if rule.Kind == database.PayrollAllowance {
+			slip.Allowances = slip.Allowances.Add(amount)
+		} else {
+			slip.Deductions = slip.Deductions.Add(amount)
+		}
+	}
+	slip.Net = slip.Gross.Add(slip.Allowances).Sub(slip.Deductions)
+	return slip
+}
+
+// salaryOn returns emp's annual salary on day from the records in emp's
+// currency: the latest one effective by then, the earliest one for days
+// before they start, or the current salary when there are none
+func salaryOn(emp database.PayrollEmployee, day time.Time) money.Amount {
+	salary, found := emp.Salary, false
+	for _, rec := range emp.Salaries {
+		if rec.Currency != emp.Currency {
+			continue
+		}
+		if found && dateOf(rec.EffectiveDate).After(day) {
+			break
+		}
+		salary, found = rec.Salary, true
+	}
+	return salary
+}
+
+// onLeave reports whether day falls in one of the leave requests
+func onLeave(leave []database.LeaveRequest, day time.Time) bool {
+	for _, req := range leave {
+		if !day.Before(dateOf(req.StartDate)) && !day.After(dateOf(req.EndDate)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPayrollRule rejects percentages over 100, and requires a currency the
+// amount can be paid in for fixed rules and none for percent rules
+func checkPayrollRule(rule *database.PayrollRule) error {
+	if rule.Calculation == database.PayrollPercent {
+		if rule.Amount.Cmp(money.FromInt(100)) > 0 {
+			return customerr.InvalidField("amount", customerr.FieldOutOfRange, "a percent amount must not be more than 100")
+		}
+		if rule.Currency != "" {
+			return customerr.InvalidField("currency", customerr.FieldInvalidValue, "a percent rule has no currency")
+		}
+		return nil
+	}
+	if rule.Currency == "" {
+		return customerr.InvalidField("currency", customerr.FieldRequired, "currency is required for a fixed rule")
+	}
+	return checkAmount("amount", rule.Amount, rule.Currency)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+// SalaryReasonHire is the reason recorded for an employee's first salary
+const SalaryReasonHire = "Starting salary"
+
+// ErrSalaryForbidden is returned when the caller may not see the employee's salary
+var ErrSalaryForbidden = customerr.New(customerr.CodeForbidden, "you may not read this employee's salary")
+
+func (s *employeeService) ChangeSalary(ctx context.Context, id uuid.UUID, change *database.SalaryChangeRequest, pre *database.Precondition, actor *auth.Claims) (*database.SalaryRecord, error) {
+	effective := dateOf(change.EffectiveDate)
+	var rec *database.SalaryRecord
+	_, err := s.updateEmployee(ctx, id, pre, actor, func(txRepo repo.EmployeeRepo, before *database.Employee) error {
+		if effective.Before(dateOf(before.HiredDate)) {
+			return customerr.InvalidField("effective_date", customerr.FieldOutOfRange, "effective_date must not be before the employee's hired_date")
+		}
+		currency := cmp.Or(change.Currency, before.Currency)
+		if err := checkAmount("salary", change.Salary, currency); err != nil {
+			return err
+		}
+		var err error
+		rec, err = recordSalary(ctx, txRepo, actor, id, change.Salary, currency, effective, change.Reason)
+		if err != nil {
+			return err
+		}
+
+		//a backdated change only becomes the current salary if no later one exists
+		current, err := txRepo.GetSalaryAsOf(ctx, id, time.Now())
+		if err != nil {
+			return err
+		}
+		if current.Salary == before.Salary && current.Currency == before.Currency {
+			return nil
+		}
+		return txRepo.PatchEmployee(ctx, id, &database.EmployeePatch{Salary: &current.Salary, Currency: &current.Currency})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (s *employeeService) ListSalaryHistory(ctx context.Context, id uuid.UUID, caller *auth.Claims) ([]database.SalaryRecord, error) {
+	if err := s.checkSalaryVisible(ctx, id, caller); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSalaryHistory(ctx, id)
+}
+
+func (s *employeeService) GetSalaryAsOf(ctx context.Context, id uuid.UUID, asOf time.Time, caller *auth.Claims) (*database.SalaryRecord, error) {
+	if err := s.checkSalaryVisible(ctx, id, caller); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSalaryAsOf(ctx, id, asOf)
+}
+
+// checkSalaryVisible fails unless the employee exists and caller may see its
+// salary under the same rules as GET /employees/{id}
+func (s *employeeService) checkSalaryVisible(ctx context.Context, id uuid.UUID, caller *auth.Claims) error {
+	if _, err := s.getEmployee(ctx, id); err != nil {
+		return err
+	}
+	visible, err := s.salaryVisibility(ctx, caller)
+	if err != nil {
+		return err
+	}
+	if !visible(id) {
+		return ErrSalaryForbidden
+	}
+	return nil
+}
+
+// recordSalary appends a salary record approved by actor; pass a repo bound
+// to the change's transaction so the record commits or rolls back with it
+func recordSalary(ctx context.Context, txRepo repo.EmployeeRepo, actor *auth.Claims, id uuid.UUID, salary money.Amount, currency string, effective time.Time, reason string) (*database.SalaryRecord, error) {
+	rec := &database.SalaryRecord{
+		EmployeeID:    id,
+		Salary:        salary,
+		Currency:      currency,
+		EffectiveDate: effective,
+		Reason:        reason,
+		CreatedAt:     time.Now(),
+	}
+	if actor != nil {
+		rec.ApprovedByEmail = actor.Email
+		if userID, err := actor.UserID(); err == nil {
+			rec.ApprovedBy = &userID
+		}
+	}
+	if err := txRepo.CreateSalaryRecord(ctx, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// recordSalaryChange records salary as effective today when an update changed
+// it or its currency
+func recordSalaryChange(ctx context.Context, txRepo repo.EmployeeRepo, actor *auth.Claims, before *database.Employee, salary money.Amount, currency string) error {
+	if salary == before.Salary && currency == before.Currency {
+		return nil
+	}
+	_, err := recordSalary(ctx, txRepo, actor, before.ID, salary, currency, dateOf(time.Now()), "")
+	return err
+}
+
+// checkAmount fails unless amount can be paid in currency, whose minor unit
+// may allow fewer decimal places than an Amount has
+func checkAmount(field string, amount money.Amount, currency string) error {
+	places := money.MinorUnits(currency)
+	if amount.Places() <= places {
+		return nil
+	}
+	if places == 0 {
+		return customerr.InvalidField(field, customerr.FieldInvalidValue, fmt.Sprintf("%s must be a whole number in %s", field, currency))
+	}
+	return customerr.InvalidField(field, customerr.FieldInvalidValue, fmt.Sprintf("%s must have at most %d decimal places in %s", field, places, currency))
+}
+
+// dateOf drops the time of day from t, keeping its calendar date
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+func (s *employeeService) SearchEmployees(ctx context.Context, search database.EmployeeSearch, caller *auth.Claims) (*database.EmployeeSearchViewPage, error) {
+	page, err := s.searchEmployees(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := s.salaryVisibility(ctx, caller)
+	if err != nil {
+		return nil, err
+	}
+	views := &database.EmployeeSearchViewPage{
+		Results: make([]database.EmployeeSearchHit, 0, len(page.Results)),
+		Total:   page.Total,
+	}
+	for _, result := range page.Results {
+		views.Results = append(views.Results, database.EmployeeSearchHit{
+			EmployeeView: projectEmployee(result.Employee, visible),
+			Rank:         result.Rank,
+			Highlights:   result.Highlights,
+		})
+	}
+	return views, nil
+}
+
+// searchEmployees reads one unprojected page of results through the cache.
+// Entries are keyed by the list version, so every write that invalidates the
+// listings invalidates searches too.
+func (s *employeeService) searchEmployees(ctx context.Context, search database.EmployeeSearch) (*database.EmployeeSearchPage, error) {
+	version, err := s.listVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	searchJSON, err := json.Marshal(search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search: %w", err)
+	}
+	cacheKey := fmt.Sprintf("employees:search:%d:%x", version, sha256.Sum256(searchJSON))
+
+	if cached, err := s.redis.Get(ctx, cacheKey).Result(); err == nil {
+		var page database.EmployeeSearchPage
+		if err := json.Unmarshal([]byte(cached), &page); err == nil {
+			return &page, nil
+		}
+	}
+
+	var page *database.EmployeeSearchPage
+	err = repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		page, err = s.repo.WithTx(tx).SearchEmployees(ctx, search)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pageJSON, err := json.Marshal(page)
+	if err != nil {
+		return page, fmt.Errorf("failed to marshal search results: %w", err)
+	}
+	if err := s.redis.Set(ctx, cacheKey, pageJSON, 5*time.Minute).Err(); err != nil {
+		return page, fmt.Errorf("failed to cache search results: %w", err)
+	}
+	return page, nil
+}
@@ -2,64 +2,150 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
 	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
 	"github.com/lijuuu/EmployeeManagement/repo"
 	"github.com/redis/go-redis/v9"
 )
 
 type EmployeeService interface {
-	CreateEmployee(ctx context.Context, emp *database.Employee) (uuid.UUID, error)
-	GetEmployeeByID(ctx context.Context, id uuid.UUID) (*database.Employee, error)
-	UpdateEmployee(ctx context.Context, id uuid.UUID, emp *database.Employee) error
-	DeleteEmployee(ctx context.Context, id uuid.UUID) error
-	ListEmployees(ctx context.Context) ([]database.Employee, error)
+	//mutations take the acting caller, which is recorded in the audit log with the change
+	CreateEmployee(ctx context.Context, emp *database.Employee, actor *auth.Claims) (uuid.UUID, error)
+	//GetEmployeeByID and ListEmployees project salaries for caller, which is nil when anonymous
+	GetEmployeeByID(ctx context.Context, id uuid.UUID, caller *auth.Claims) (*database.EmployeeView, error)
+	//UpdateEmployee replaces the employee's editable fields and returns the stored record.
+	//Update, patch and delete fail with ErrPreconditionFailed unless the employee's version satisfies pre.
+	UpdateEmployee(ctx context.Context, id uuid.UUID, emp *database.Employee, pre *database.Precondition, actor *auth.Claims) (*database.Employee, error)
+	//PatchEmployee changes only the fields set in patch and returns the stored record
+	PatchEmployee(ctx context.Context, id uuid.UUID, patch *database.EmployeePatch, pre *database.Precondition, actor *auth.Claims) (*database.Employee, error)
+	//DeleteEmployee soft-deletes; RestoreEmployee undoes it until PurgeDeletedEmployees removes the row
+	DeleteEmployee(ctx context.Context, id uuid.UUID, pre *database.Precondition, actor *auth.Claims) error
+	RestoreEmployee(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.Employee, error)
+	//the bulk operations apply every item in one transaction and report each item's outcome
+	BulkCreateEmployees(ctx context.Context, emps []database.Employee, mode database.BulkMode, actor *auth.Claims) (*database.BulkResult, error)
+	BulkPatchEmployees(ctx context.Context, patches []database.BulkPatch, mode database.BulkMode, actor *auth.Claims) (*database.BulkResult, error)
+	BulkDeleteEmployees(ctx context.Context, deletes []database.BulkDelete, mode database.BulkMode, actor *auth.Claims) (*database.BulkResult, error)
+	//ImportEmployees creates employees read from a sheet like BulkCreateEmployees; a dry run reports the outcome without keeping anything
+	ImportEmployees(ctx context.Context, emps []database.Employee, mode database.BulkMode, dryRun bool, actor *auth.Claims) (*database.BulkResult, error)
+	//salary changes made through any write are appended to the employee's salary history.
+	//ChangeSalary records one with its own effective date and reason; it becomes the current salary unless a later one exists.
+	ChangeSalary(ctx context.Context, id uuid.UUID, change *database.SalaryChangeRequest, pre *database.Precondition, actor *auth.Claims) (*database.SalaryRecord, error)
+	//ListSalaryHistory and GetSalaryAsOf fail with ErrSalaryForbidden unless caller may see the employee's salary
+	ListSalaryHistory(ctx context.Context, id uuid.UUID, caller *auth.Claims) ([]database.SalaryRecord, error)
+	GetSalaryAsOf(ctx context.Context, id uuid.UUID, asOf time.Time, caller *auth.Claims) (*database.SalaryRecord, error)
+	ListDeletedEmployees(ctx context.Context, limit, offset int) (*database.EmployeePage, error)
+	PurgeDeletedEmployees(ctx context.Context, retention time.Duration, actor *auth.Claims) (*database.PurgeResult, error)
+	ListEmployees(ctx context.Context, filter database.EmployeeFilter, caller *auth.Claims) (*database.EmployeeViewPage, error)
+	//SearchEmployees returns a page of full-text and fuzzy matches, best first, projected as by ListEmployees
+	SearchEmployees(ctx context.Context, search database.EmployeeSearch, caller *auth.Claims) (*database.EmployeeSearchViewPage, error)
+	//ExportEmployees calls fn with every employee matching filter, ignoring its paging, projected as by ListEmployees.
+	//With conv the salaries shown are converted to its currency, by default at today's rates.
+	ExportEmployees(ctx context.Context, filter database.EmployeeFilter, conv *database.Conversion, caller *auth.Claims, fn func(database.EmployeeView) error) error
+	//SetManager fails with ErrPreconditionFailed unless the employee's version satisfies pre
+	SetManager(ctx context.Context, id uuid.UUID, managerID *uuid.UUID, pre *database.Precondition, actor *auth.Claims) error
+	//SetTermination sets or, with a nil terminationDate, clears the last day employed
+	SetTermination(ctx context.Context, id uuid.UUID, terminationDate *time.Time, pre *database.Precondition, actor *auth.Claims) (*database.Employee, error)
+	GetManagerChain(ctx context.Context, id uuid.UUID) ([]database.OrgNode, error)
+	GetReportTree(ctx context.Context, id uuid.UUID) (*database.OrgNode, error)
+	GetOrgChart(ctx context.Context) ([]*database.OrgNode, error)
 }
 
+// listVersionKey is bumped on every mutation so cached list pages for any
+// combination of query parameters fall out of use at once
+const listVersionKey = "employees:list:version"
+
 type employeeService struct {
+	db    repo.TxBeginner
 	repo  repo.EmployeeRepo
+	audit repo.AuditRepo
+	rates repo.ExchangeRateRepo
 	redis *redis.Client
 }
 
-func NewEmployeeService(repo repo.EmployeeRepo, redis *redis.Client) EmployeeService {
+func NewEmployeeService(db repo.TxBeginner, repo repo.EmployeeRepo, audit repo.AuditRepo, rates repo.ExchangeRateRepo, redis *redis.Client) EmployeeService {
 	return &employeeService{
+		db:    db,
 		repo:  repo,
+		audit: audit,
+		rates: rates,
 		redis: redis,
 	}
 }
 
-func (s *employeeService) CreateEmployee(ctx context.Context, emp *database.Employee) (uuid.UUID, error) {
+func (s *employeeService) CreateEmployee(ctx context.Context, emp *database.Employee, actor *auth.Claims) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		var err error
+		id, err = s.createEmployeeTx(ctx, tx, emp, actor)
+		return err
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := s.cacheEmployee(ctx, emp); err != nil {
+		return id, err
+	}
+	if err := invalidateEmployeeList(ctx, s.redis); err != nil {
+		return id, fmt.Errorf("failed to invalidate list cache: %w", err)
+	}
+	return id, nil
+}
+
+// createEmployeeTx fills in the server-assigned fields of emp, inserts it,
+// starts its salary history and audits the creation within tx
+func (s *employeeService) createEmployeeTx(ctx context.Context, tx pgx.Tx, emp *database.Employee, actor *auth.Claims) (uuid.UUID, error) {
 	emp.CreatedAt = time.Now()
 	emp.UpdatedAt = time.Now()
+	//the column default; the record is cached without being read back
+	emp.Version = 1
 	//check if hireddate is provided
 	if emp.HiredDate.IsZero() {
 		emp.HiredDate = time.Now()
 	}
-
-	id, err := s.repo.CreateEmployee(ctx, emp)
-	if err != nil {
+	if err := checkAmount("salary", emp.Salary, emp.Currency); err != nil {
 		return uuid.Nil, err
 	}
 
-	empJSON, err := json.Marshal(emp)
+	txRepo := s.repo.WithTx(tx)
+	id, err := txRepo.CreateEmployee(ctx, emp)
 	if err != nil {
-		return id, fmt.Errorf("failed to marshal employee: %v", err)
+		return uuid.Nil, err
 	}
-	cacheKey := fmt.Sprintf("employee:%s", id.String())
-	if err := s.redis.Set(ctx, cacheKey, empJSON, 5*time.Minute).Err(); err != nil {
-		return id, fmt.Errorf("failed to cache employee: %v", err)
+	if _, err := recordSalary(ctx, txRepo, actor, id, emp.Salary, emp.Currency, dateOf(emp.HiredDate), SalaryReasonHire); err != nil {
+		return uuid.Nil, err
 	}
-	if err := s.redis.Del(ctx, "employees:list").Err(); err != nil {
-		return id, fmt.Errorf("failed to invalidate list cache: %v", err)
+	if err := recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionCreate, AuditEntityEmployee, id, nil, emp); err != nil {
+		return uuid.Nil, err
 	}
 	return id, nil
 }
 
-func (s *employeeService) GetEmployeeByID(ctx context.Context, id uuid.UUID) (*database.Employee, error) {
+func (s *employeeService) GetEmployeeByID(ctx context.Context, id uuid.UUID, caller *auth.Claims) (*database.EmployeeView, error) {
+	emp, err := s.getEmployee(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := s.salaryVisibility(ctx, caller)
+	if err != nil {
+		return nil, err
+	}
+	view := projectEmployee(*emp, visible)
+	return &view, nil
+}
+
+// getEmployee reads the full record through the cache; callers project it before it leaves the service
+func (s *employeeService) getEmployee(ctx context.Context, id uuid.UUID) (*database.Employee, error) {
 	cacheKey := fmt.Sprintf("employee:%s", id.String())
 	if cached, err := s.redis.Get(ctx, cacheKey).Result(); err == nil {
 		var emp database.Employee
@@ -74,72 +160,346 @@ func (s *employeeService) GetEmployeeByID(ctx context.Context, id uuid.UUID) (*d
 		return nil, err
 	}
 
+	if err := s.cacheEmployee(ctx, emp); err != nil {
+		return emp, err
+	}
+	return emp, nil
+}
+
+// cacheEmployee stores the full record under its employee:<id> key
+func (s *employeeService) cacheEmployee(ctx context.Context, emp *database.Employee) error {
 	empJSON, err := json.Marshal(emp)
 	if err != nil {
-		return emp, fmt.Errorf("failed to marshal employee: %v", err)
+		return fmt.Errorf("failed to marshal employee: %w", err)
 	}
+	cacheKey := fmt.Sprintf("employee:%s", emp.ID.String())
 	if err := s.redis.Set(ctx, cacheKey, empJSON, 5*time.Minute).Err(); err != nil {
-		return emp, fmt.Errorf("failed to cache employee: %v", err)
+		return fmt.Errorf("failed to cache employee: %w", err)
 	}
-	return emp, nil
+	return nil
+}
+
+func (s *employeeService) UpdateEmployee(ctx context.Context, id uuid.UUID, emp *database.Employee, pre *database.Precondition, actor *auth.Claims) (*database.Employee, error) {
+	return s.updateEmployee(ctx, id, pre, actor, func(txRepo repo.EmployeeRepo, before *database.Employee) error {
+		if emp.HiredDate.IsZero() {
+			emp.HiredDate = before.HiredDate
+		}
+		if emp.Currency == "" {
+			emp.Currency = before.Currency
+		}
+		if err := checkAmount("salary", emp.Salary, emp.Currency); err != nil {
+			return err
+		}
+		if err := txRepo.UpdateEmployee(ctx, id, emp); err != nil {
+			return err
+		}
+		return recordSalaryChange(ctx, txRepo, actor, before, emp.Salary, emp.Currency)
+	})
+}
+
+func (s *employeeService) PatchEmployee(ctx context.Context, id uuid.UUID, patch *database.EmployeePatch, pre *database.Precondition, actor *auth.Claims) (*database.Employee, error) {
+	if patch.IsEmpty() {
+		emp, err := s.getEmployee(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPrecondition(pre, emp); err != nil {
+			return nil, err
+		}
+		return emp, nil
+	}
+	return s.updateEmployee(ctx, id, pre, actor, applyPatch(ctx, id, patch, actor))
+}
+
+// patchEmployeeTx is PatchEmployee within tx; an empty patch only checks pre
+func (s *employeeService) patchEmployeeTx(ctx context.Context, tx pgx.Tx, id uuid.UUID, patch *database.EmployeePatch, pre *database.Precondition, actor *auth.Claims) (*database.Employee, error) {
+	if patch.IsEmpty() {
+		emp, err := s.repo.WithTx(tx).GetEmployeeByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPrecondition(pre, emp); err != nil {
+			return nil, err
+		}
+		return emp, nil
+	}
+	return s.updateEmployeeTx(ctx, tx, id, pre, actor, applyPatch(ctx, id, patch, actor))
+}
+
+// applyPatch is the update that writes patch and records a salary or
+// currency it changes
+func applyPatch(ctx context.Context, id uuid.UUID, patch *database.EmployeePatch, actor *auth.Claims) func(txRepo repo.EmployeeRepo, before *database.Employee) error {
+	return func(txRepo repo.EmployeeRepo, before *database.Employee) error {
+		if patch.Salary == nil && patch.Currency == nil {
+			return txRepo.PatchEmployee(ctx, id, patch)
+		}
+		salary, currency := before.Salary, before.Currency
+		if patch.Salary != nil {
+			salary = *patch.Salary
+		}
+		if patch.Currency != nil {
+			currency = *patch.Currency
+		}
+		if err := checkAmount("salary", salary, currency); err != nil {
+			return err
+		}
+		if err := txRepo.PatchEmployee(ctx, id, patch); err != nil {
+			return err
+		}
+		return recordSalaryChange(ctx, txRepo, actor, before, salary, currency)
+	}
+}
+
+// updateEmployee runs updateEmployeeTx in its own transaction and caches the result
+func (s *employeeService) updateEmployee(ctx context.Context, id uuid.UUID, pre *database.Precondition, actor *auth.Claims, update func(txRepo repo.EmployeeRepo, before *database.Employee) error) (*database.Employee, error) {
+	var after *database.Employee
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		var err error
+		after, err = s.updateEmployeeTx(ctx, tx, id, pre, actor, update)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cacheEmployee(ctx, after); err != nil {
+		return after, err
+	}
+	if err := invalidateEmployeeList(ctx, s.redis); err != nil {
+		return after, fmt.Errorf("failed to invalidate list cache: %w", err)
+	}
+	return after, nil
+}
+
+// updateEmployeeTx runs update against the locked employee once pre holds,
+// audits the change and returns the employee as stored afterwards
+func (s *employeeService) updateEmployeeTx(ctx context.Context, tx pgx.Tx, id uuid.UUID, pre *database.Precondition, actor *auth.Claims, update func(txRepo repo.EmployeeRepo, before *database.Employee) error) (*database.Employee, error) {
+	txRepo := s.repo.WithTx(tx)
+	before, err := txRepo.GetEmployeeForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPrecondition(pre, before); err != nil {
+		return nil, err
+	}
+	if err := update(txRepo, before); err != nil {
+		return nil, err
+	}
+	after, err := txRepo.GetEmployeeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionUpdate, AuditEntityEmployee, id, before, after); err != nil {
+		return nil, err
+	}
+	return after, nil
 }
 
-func (s *employeeService) UpdateEmployee(ctx context.Context, id uuid.UUID, emp *database.Employee) error {
-	err := s.repo.UpdateEmployee(ctx, id, emp)
+func (s *employeeService) DeleteEmployee(ctx context.Context, id uuid.UUID, pre *database.Precondition, actor *auth.Claims) error {
+	var reports []uuid.UUID
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		var err error
+		reports, err = s.deleteEmployeeTx(ctx, tx, id, pre, actor)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	empJSON, err := json.Marshal(emp)
+	//direct reports lost their manager, so their cache entries go too
+	return evictEmployees(ctx, s.redis, append(reports, id))
+}
+
+// deleteEmployeeTx soft-deletes the employee within tx once pre holds and
+// returns its former direct reports
+func (s *employeeService) deleteEmployeeTx(ctx context.Context, tx pgx.Tx, id uuid.UUID, pre *database.Precondition, actor *auth.Claims) ([]uuid.UUID, error) {
+	txRepo := s.repo.WithTx(tx)
+	before, err := txRepo.GetEmployeeForUpdate(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to marshal employee: %v", err)
+		return nil, err
 	}
-	cacheKey := fmt.Sprintf("employee:%s", id.String())
-	if err := s.redis.Set(ctx, cacheKey, empJSON, 5*time.Minute).Err(); err != nil {
-		return fmt.Errorf("failed to cache employee: %v", err)
+	if err := checkPrecondition(pre, before); err != nil {
+		return nil, err
+	}
+
+	reports, err := txRepo.ListDirectReportIDs(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	if err := s.redis.Del(ctx, "employees:list").Err(); err != nil {
-		return fmt.Errorf("failed to invalidate list cache: %v", err)
+	if err := txRepo.DeleteEmployee(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := txRepo.ClearReportsManager(ctx, id); err != nil {
+		return nil, err
+	}
+	after, err := txRepo.GetDeletedEmployeeForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionDelete, AuditEntityEmployee, id, before, after); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// evictEmployees drops the cached records of the given employees and retires
+// the cached list pages
+func evictEmployees(ctx context.Context, rdb *redis.Client, ids []uuid.UUID) error {
+	if len(ids) > 0 {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = fmt.Sprintf("employee:%s", id.String())
+		}
+		if err := rdb.Del(ctx, keys...).Err(); err != nil {
+			return fmt.Errorf("failed to delete employee cache: %w", err)
+		}
+	}
+	if err := invalidateEmployeeList(ctx, rdb); err != nil {
+		return fmt.Errorf("failed to invalidate list cache: %w", err)
 	}
 	return nil
 }
 
-func (s *employeeService) DeleteEmployee(ctx context.Context, id uuid.UUID) error {
-	err := s.repo.DeleteEmployee(ctx, id)
+// checkPrecondition fails when emp has changed since the version the caller
+// sent in If-Match; it runs with the row locked so no write can slip in between
+func checkPrecondition(pre *database.Precondition, emp *database.Employee) error {
+	if !pre.Matches(emp.Version) {
+		return customerr.New(customerr.CodePreconditionFailed, "employee has been modified since it was read")
+	}
+	return nil
+}
+
+func (s *employeeService) ListEmployees(ctx context.Context, filter database.EmployeeFilter, caller *auth.Claims) (*database.EmployeeViewPage, error) {
+	if err := checkSalaryFilter(filter, caller); err != nil {
+		return nil, err
+	}
+
+	page, err := s.listEmployees(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := s.salaryVisibility(ctx, caller)
+	if err != nil {
+		return nil, err
+	}
+	views := &database.EmployeeViewPage{
+		Employees:  make([]database.EmployeeView, 0, len(page.Employees)),
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+	}
+	for _, emp := range page.Employees {
+		views.Employees = append(views.Employees, projectEmployee(emp, visible))
+	}
+	return views, nil
+}
+
+// ExportEmployees streams straight from the database: an export is too large
+// to cache and is read far less often than listing pages
+func (s *employeeService) ExportEmployees(ctx context.Context, filter database.EmployeeFilter, conv *database.Conversion, caller *auth.Claims, fn func(database.EmployeeView) error) error {
+	if err := checkSalaryFilter(filter, caller); err != nil {
+		return err
+	}
+	visible, err := s.salaryVisibility(ctx, caller)
 	if err != nil {
 		return err
 	}
+	var c *converter
+	if conv != nil {
+		if c, err = s.exportConverter(ctx, conv); err != nil {
+			return err
+		}
+	}
+	return s.repo.ExportEmployees(ctx, filter, func(emp database.Employee) error {
+		view := projectEmployee(emp, visible)
+		if c != nil && view.Salary != nil {
+			converted, err := c.convert(*view.Salary, view.Currency)
+			if err != nil {
+				return err
+			}
+			converted = c.round(converted)
+			view.Salary, view.Employee.Salary, view.Currency = &converted, converted, c.currency
+		}
+		return fn(view)
+	})
+}
 
-	cacheKey := fmt.Sprintf("employee:%s", id.String())
-	if err := s.redis.Del(ctx, cacheKey).Err(); err != nil {
-		return fmt.Errorf("failed to delete employee cache: %v", err)
+// exportConverter loads the rates of an export and checks that every
+// currency employees are paid in has one, so that the export does not fail
+// after its first rows have been sent
+func (s *employeeService) exportConverter(ctx context.Context, conv *database.Conversion) (*converter, error) {
+	asOf := conv.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now().UTC()
 	}
-	if err := s.redis.Del(ctx, "employees:list").Err(); err != nil {
-		return fmt.Errorf("failed to invalidate list cache: %v", err)
+	c, err := newConverter(ctx, s.rates, conv.Currency, asOf)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	currencies, err := s.repo.ListCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, currency := range currencies {
+		if _, err := c.convert(money.Amount{}, currency); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
 }
 
-func (s *employeeService) ListEmployees(ctx context.Context) ([]database.Employee, error) {
-	cacheKey := "employees:list"
+// listEmployees reads one unprojected page through the cache, which is shared by every caller
+func (s *employeeService) listEmployees(ctx context.Context, filter database.EmployeeFilter) (*database.EmployeePage, error) {
+	cacheKey, err := s.listCacheKey(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
 	if cached, err := s.redis.Get(ctx, cacheKey).Result(); err == nil {
-		var employees []database.Employee
-		if err := json.Unmarshal([]byte(cached), &employees); err == nil {
-			return employees, nil
+		var page database.EmployeePage
+		if err := json.Unmarshal([]byte(cached), &page); err == nil {
+			return &page, nil
 		}
 	}
 
-	employees, err := s.repo.ListEmployees(ctx)
+	page, err := s.repo.ListEmployees(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	empJSON, err := json.Marshal(employees)
+	pageJSON, err := json.Marshal(page)
 	if err != nil {
-		return employees, fmt.Errorf("failed to marshal employees: %v", err)
+		return page, fmt.Errorf("failed to marshal employees: %w", err)
 	}
-	if err := s.redis.Set(ctx, cacheKey, empJSON, 5*time.Minute).Err(); err != nil {
-		return employees, fmt.Errorf("failed to cache employees: %v", err)
+	if err := s.redis.Set(ctx, cacheKey, pageJSON, 5*time.Minute).Err(); err != nil {
+		return page, fmt.Errorf("failed to cache employees: %w", err)
 	}
-	return employees, nil
+	return page, nil
+}
+
+// listCacheKey derives the cache key for one listing from the filter and the current list version
+func (s *employeeService) listCacheKey(ctx context.Context, filter database.EmployeeFilter) (string, error) {
+	version, err := s.listVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	filterJSON, err := json.Marshal(filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal filter: %w", err)
+	}
+	return fmt.Sprintf("employees:list:%d:%x", version, sha256.Sum256(filterJSON)), nil
+}
+
+// listVersion reads the counter that invalidateEmployeeList bumps
+func (s *employeeService) listVersion(ctx context.Context) (int64, error) {
+	version, err := s.redis.Get(ctx, listVersionKey).Int64()
+	if err != nil && err != redis.Nil {
+		return 0, fmt.Errorf("failed to read list cache version: %w", err)
+	}
+	return version, nil
+}
+
+// invalidateEmployeeList retires every cached list page by bumping the list version
+func invalidateEmployeeList(ctx context.Context, rdb *redis.Client) error {
+	return rdb.Incr(ctx, listVersionKey).Err()
 }
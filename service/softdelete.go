@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+func (s *employeeService) RestoreEmployee(ctx context.Context, id uuid.UUID, actor *auth.Claims) (*database.Employee, error) {
+	var restored *database.Employee
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		txRepo := s.repo.WithTx(tx)
+		before, err := txRepo.GetDeletedEmployeeForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := txRepo.RestoreEmployee(ctx, id); err != nil {
+			return err
+		}
+		if restored, err = txRepo.GetEmployeeByID(ctx, id); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit.WithTx(tx), actor, AuditActionRestore, AuditEntityEmployee, id, before, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := invalidateEmployeeList(ctx, s.redis); err != nil {
+		return restored, fmt.Errorf("failed to invalidate list cache: %w", err)
+	}
+	return restored, nil
+}
+
+func (s *employeeService) ListDeletedEmployees(ctx context.Context, limit, offset int) (*database.EmployeePage, error) {
+	return s.repo.ListDeletedEmployees(ctx, limit, offset)
+}
+
+func (s *employeeService) PurgeDeletedEmployees(ctx context.Context, retention time.Duration, actor *auth.Claims) (*database.PurgeResult, error) {
+	result := &database.PurgeResult{Retention: retention.String(), EmployeeIDs: []uuid.UUID{}}
+	err := repo.RunInTx(ctx, s.db, func(tx pgx.Tx) error {
+		purged, err := s.repo.WithTx(tx).PurgeDeletedEmployees(ctx, retention)
+		if err != nil {
+			return err
+		}
+
+		txAudit := s.audit.WithTx(tx)
+		for i := range purged {
+			if err := recordAudit(ctx, txAudit, actor, AuditActionPurge, AuditEntityEmployee, purged[i].ID, &purged[i], nil); err != nil {
+				return err
+			}
+			result.EmployeeIDs = append(result.EmployeeIDs, purged[i].ID)
+		}
+		result.Purged = len(purged)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	//soft-deleted rows are never cached, so there is nothing to evict
+	return result, nil
+}
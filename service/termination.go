@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/repo"
+)
+
+// SetTermination records the last day the employee is employed, which is
+// what payroll pays up to; a nil terminationDate clears it. Deleting an
+// employee leaves the date alone, so a record deleted by mistake and
+// restored is paid as before.
+func (s *employeeService) SetTermination(ctx context.Context, id uuid.UUID, terminationDate *time.Time, pre *database.Precondition, actor *auth.Claims) (*database.Employee, error) {
+	return s.updateEmployee(ctx, id, pre, actor, func(txRepo repo.EmployeeRepo, before *database.Employee) error {
+		if terminationDate == nil {
+			return txRepo.SetTermination(ctx, id, nil)
+		}
+		last := dateOf(*terminationDate)
+		if last.Before(dateOf(before.HiredDate)) {
+			return customerr.InvalidField("termination_date", customerr.FieldOutOfRange, "termination_date must not be before the employee's hired_date")
+		}
+		return txRepo.SetTermination(ctx, id, &last)
+	})
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrInvalidRefreshToken = customerr.New(customerr.CodeInvalidToken, "invalid or expired refresh token")
+
+// refreshSession is stored in Redis under the hash of a live refresh token.
+// Family ties together every token rotated from the same login.
+type refreshSession struct {
+	UserID uuid.UUID `json:"user_id"`
+	Family string    `json:"family"`
+}
+
+func refreshKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "refresh:" + hex.EncodeToString(sum[:])
+}
+
+// usedRefreshKey marks a rotated token so that presenting it again can be detected
+func usedRefreshKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "refresh:used:" + hex.EncodeToString(sum[:])
+}
+
+func revokedFamilyKey(family string) string {
+	return "refresh:family:revoked:" + family
+}
+
+func revokedJTIKey(jti string) string {
+	return "revoked:jti:" + jti
+}
+
+func (s *userService) Login(ctx context.Context, email, password string) (*database.TokenResponse, error) {
+	user, err := s.Authenticate(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, user, uuid.NewString())
+}
+
+func (s *userService) Refresh(ctx context.Context, refreshToken string) (*database.TokenResponse, error) {
+	//GETDEL makes each refresh token single use even under concurrent requests
+	raw, err := s.redis.GetDel(ctx, refreshKey(refreshToken)).Result()
+	if err == redis.Nil {
+		//a rotated token coming back means it leaked; end every session in its family
+		if family, err := s.redis.Get(ctx, usedRefreshKey(refreshToken)).Result(); err == nil {
+			if err := s.redis.Set(ctx, revokedFamilyKey(family), 1, s.cfg.RefreshTokenTTL).Err(); err != nil {
+				return nil, fmt.Errorf("failed to revoke token family: %w", err)
+			}
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read refresh token: %w", err)
+	}
+
+	var session refreshSession
+	if err := json.Unmarshal([]byte(raw), &session); err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	revoked, err := s.redis.Exists(ctx, revokedFamilyKey(session.Family)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token family: %w", err)
+	}
+	if revoked > 0 {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if err := s.redis.Set(ctx, usedRefreshKey(refreshToken), session.Family, s.cfg.RefreshTokenTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	//reload the user so role changes apply from the next access token
+	user, err := s.repo.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	return s.issueTokens(ctx, user, session.Family)
+}
+
+func (s *userService) Logout(ctx context.Context, claims *auth.Claims, refreshToken string) error {
+	//deny the access token for the rest of its lifetime
+	remaining := time.Until(time.Unix(claims.ExpiresAt, 0))
+	if remaining > 0 {
+		if err := s.redis.Set(ctx, revokedJTIKey(claims.Id), 1, remaining).Err(); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	raw, err := s.redis.GetDel(ctx, refreshKey(refreshToken)).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	//end the whole login session, not just the presented token
+	var session refreshSession
+	if err := json.Unmarshal([]byte(raw), &session); err == nil && session.UserID.String() == claims.Subject {
+		if err := s.redis.Set(ctx, revokedFamilyKey(session.Family), 1, s.cfg.RefreshTokenTTL).Err(); err != nil {
+			return fmt.Errorf("failed to revoke token family: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *userService) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := s.redis.Exists(ctx, revokedJTIKey(jti)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// issueTokens signs an access token and stores a fresh refresh token in family
+func (s *userService) issueTokens(ctx context.Context, user *database.User, family string) (*database.TokenResponse, error) {
+	accessToken, err := auth.NewToken(s.cfg.JWTSecret, user, s.cfg.AccessTokenTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(buf)
+
+	sessionJSON, err := json.Marshal(refreshSession{UserID: user.ID, Family: family})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal refresh session: %w", err)
+	}
+	if err := s.redis.Set(ctx, refreshKey(refreshToken), sessionJSON, s.cfg.RefreshTokenTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &database.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.cfg.AccessTokenTTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/repo"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials = customerr.New(customerr.CodeInvalidCredentials, "invalid credentials")
+	ErrInvalidRole        = customerr.Validation("role must be one of admin, hr, manager or viewer")
+)
+
+// dummyHash is compared against when the email is unknown so that login
+// takes the same time whether or not the account exists
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+
+type UserService interface {
+	Authenticate(ctx context.Context, email, password string) (*database.User, error)
+	//Login authenticates the user and starts a session with an access and a refresh token
+	Login(ctx context.Context, email, password string) (*database.TokenResponse, error)
+	//Refresh exchanges a refresh token for a new token pair, retiring the old refresh token
+	Refresh(ctx context.Context, refreshToken string) (*database.TokenResponse, error)
+	//Logout denylists the access token and ends the refresh token's session
+	Logout(ctx context.Context, claims *auth.Claims, refreshToken string) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	CreateUser(ctx context.Context, newUser *database.NewUser) (*database.User, error)
+	ListUsers(ctx context.Context) ([]database.User, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, update *database.RoleUpdate) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	//EnsureAdmin creates an admin account for email unless one already exists
+	EnsureAdmin(ctx context.Context, email, password string) error
+}
+
+type userService struct {
+	repo  repo.UserRepo
+	redis *redis.Client
+	cfg   *config.Config
+}
+
+func NewUserService(repo repo.UserRepo, redis *redis.Client, cfg *config.Config) UserService {
+	return &userService{
+		repo:  repo,
+		redis: redis,
+		cfg:   cfg,
+	}
+}
+
+func (s *userService) Authenticate(ctx context.Context, email, password string) (*database.User, error) {
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if errors.Is(err, customerr.ErrNotFound) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+func (s *userService) CreateUser(ctx context.Context, newUser *database.NewUser) (*database.User, error) {
+	if !auth.ValidRole(newUser.Role) {
+		return nil, ErrInvalidRole
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newUser.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &database.User{
+		Email:        newUser.Email,
+		Role:         newUser.Role,
+		EmployeeID:   newUser.EmployeeID,
+		PasswordHash: string(hash),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if _, err := s.repo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *userService) ListUsers(ctx context.Context) ([]database.User, error) {
+	return s.repo.ListUsers(ctx)
+}
+
+func (s *userService) UpdateRole(ctx context.Context, id uuid.UUID, update *database.RoleUpdate) error {
+	if !auth.ValidRole(update.Role) {
+		return ErrInvalidRole
+	}
+	return s.repo.UpdateRole(ctx, id, update.Role, update.EmployeeID)
+}
+
+func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteUser(ctx, id)
+}
+
+func (s *userService) EnsureAdmin(ctx context.Context, email, password string) error {
+	if _, err := s.repo.GetUserByEmail(ctx, email); err == nil {
+		return nil
+	}
+
+	_, err := s.CreateUser(ctx, &database.NewUser{
+		Email:    email,
+		Password: password,
+		Role:     auth.RoleAdmin,
+	})
+	return err
+}
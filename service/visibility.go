@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+)
+
+// ErrSalaryFilterForbidden is returned when a caller who cannot see every
+// salary filters or sorts by salary, which would leak it indirectly
+var ErrSalaryFilterForbidden = customerr.New(customerr.CodeForbidden, "salary filters and sorting require permission to read all salaries")
+
+// salaryVisibility returns a check telling whether caller may see a given
+// employee's salary; a nil caller is anonymous
+func (s *employeeService) salaryVisibility(ctx context.Context, caller *auth.Claims) (func(uuid.UUID) bool, error) {
+	hidden := func(uuid.UUID) bool { return false }
+	if caller == nil {
+		return hidden, nil
+	}
+	if auth.Can(caller.Role, auth.PermSalaryReadAll) {
+		return func(uuid.UUID) bool { return true }, nil
+	}
+	if !auth.Can(caller.Role, auth.PermSalaryReadReports) || caller.EmployeeID == nil {
+		return hidden, nil
+	}
+
+	//managers see their direct and indirect reports, not their own record or peers
+	reports, err := s.repo.GetReports(ctx, *caller.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	reportIDs := make(map[uuid.UUID]bool, len(reports))
+	for _, report := range reports {
+		reportIDs[report.ID] = true
+	}
+	return func(id uuid.UUID) bool { return reportIDs[id] }, nil
+}
+
+// projectEmployee builds the caller's view of emp
+func projectEmployee(emp database.Employee, visible func(uuid.UUID) bool) database.EmployeeView {
+	view := database.EmployeeView{Employee: emp}
+	if visible(emp.ID) {
+		salary := emp.Salary
+		view.Salary = &salary
+	} else {
+		view.Employee.Salary = money.Amount{}
+	}
+	return view
+}
+
+// checkSalaryFilter rejects salary filters and salary ordering from callers
+// who may not read every salary
+func checkSalaryFilter(filter database.EmployeeFilter, caller *auth.Claims) error {
+	if filter.MinSalary == nil && filter.MaxSalary == nil && filter.SortBy != "salary" {
+		return nil
+	}
+	if caller == nil || !auth.Can(caller.Role, auth.PermSalaryReadAll) {
+		return ErrSalaryFilterForbidden
+	}
+	return nil
+}
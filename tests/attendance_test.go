@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/exporter"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestTimesheetExportFormats(t *testing.T) {
+	week := time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC)
+	entries := []database.TimesheetEntry{
+		{PeriodStart: week, PeriodEnd: week.AddDate(0, 0, 6), DaysWorked: 5, Shifts: 6, Hours: 42.5, OvertimeHours: 2.5},
+	}
+	write := func(format exporter.Format) []byte {
+		var out bytes.Buffer
+		w, err := exporter.NewTimesheetWriter(&out, format)
+		require.NoError(t, err)
+		defer w.Close()
+		for _, entry := range entries {
+			require.NoError(t, w.Write(entry))
+		}
+		require.NoError(t, w.Flush())
+		return out.Bytes()
+	}
+	header := []string{"period_start", "period_end", "days_worked", "shifts", "hours", "overtime_hours"}
+
+	records, err := csv.NewReader(bytes.NewReader(write(exporter.FormatCSV))).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{header, {"2024-08-05", "2024-08-11", "5", "6", "42.5", "2.5"}}, records)
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(write(exporter.FormatJSONL)), &line))
+	assert.Equal(t, 42.5, line["hours"])
+	assert.Equal(t, "2024-08-11T00:00:00Z", line["period_end"])
+
+	book, err := excelize.OpenReader(bytes.NewReader(write(exporter.FormatXLSX)))
+	require.NoError(t, err)
+	defer book.Close()
+	assert.Equal(t, "Timesheet", book.GetSheetName(0))
+	rows, err := book.GetRows("Timesheet", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, header, rows[0])
+	assert.Equal(t, []string{"5", "6", "42.5", "2.5"}, rows[1][2:])
+	serial, err := strconv.ParseFloat(rows[1][0], 64)
+	require.NoError(t, err, "dates are stored as date cells")
+	start, err := excelize.ExcelDateToTime(serial, false)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-08-05", start.Format(time.DateOnly))
+}
+
+func TestAttendanceRejectsInvalidInput(t *testing.T) {
+	//invalid parameters are rejected before the service is called
+	ctrl := controller.NewAttendanceController(nil)
+	e := newEcho()
+	id := "00000000-0000-0000-0000-000000000001"
+
+	call := func(handler echo.HandlerFunc, method, target, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		serve(handler, c)
+		return rec
+	}
+
+	rec := call(ctrl.ListAttendance, http.MethodGet, "/employees/not-a-uuid/attendance", "not-a-uuid", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	for _, query := range []string{
+		"period=yearly",
+		"from=2024-08-31&to=2024-08-01",
+		"from=2024-01-01&to=2025-06-01",
+		"from=last-monday",
+	} {
+		rec := call(ctrl.GetTimesheet, http.MethodGet, "/employees/"+id+"/timesheet?"+query, id, "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		rec = call(ctrl.ExportTimesheet, http.MethodGet, "/employees/"+id+"/timesheet/export?"+query, id, "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition), query)
+	}
+	rec = call(ctrl.ExportTimesheet, http.MethodGet, "/employees/"+id+"/timesheet/export?format=pdf", id, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAttendanceWorkflow(t *testing.T) {
+	cfg, ctrls, cleanup := setupControllers(t)
+	defer cleanup()
+	empCtrl, attCtrl := ctrls.Employee, ctrls.Attendance
+
+	admin, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+	e := newEcho()
+	hired := time.Now().AddDate(-1, 0, 0).Format(time.RFC3339)
+	lead := createTestEmployee(t, e, empCtrl, admin, `{"name": "Shift Lead", "position": "Engineer", "salary": 90000, "hired_date": "`+hired+`"}`)
+	member := createTestEmployee(t, e, empCtrl, admin, `{"name": "Shift Member", "position": "Engineer", "salary": 60000, "hired_date": "`+hired+`"}`)
+	require.Equal(t, http.StatusOK, setManager(t, e, empCtrl, admin, member.ID.String(), lead.ID.String()))
+
+	memberToken, err := generateJWTForEmployee(cfg, auth.RoleViewer, member.ID)
+	require.NoError(t, err)
+	leadToken, err := generateJWTForEmployee(cfg, auth.RoleManager, lead.ID)
+	require.NoError(t, err)
+	hr, err := generateJWTForRole(cfg, auth.RoleHR)
+	require.NoError(t, err)
+	outsider, err := generateJWTForRole(cfg, auth.RoleManager)
+	require.NoError(t, err)
+	id := member.ID.String()
+
+	//call runs handler as the holder of token on the member
+	call := func(handler echo.HandlerFunc, method, target, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		serve(middleware.JWTAuthMiddleware(cfg, stubRevocations{})(handler), c)
+		return rec
+	}
+	punch := func(handler echo.HandlerFunc, token string, at time.Time) *httptest.ResponseRecorder {
+		return call(handler, http.MethodPost, "/employees/"+id+"/attendance", token, `{"at": "`+at.Format(time.RFC3339)+`"}`)
+	}
+
+	//HR records two past shifts on a Monday and Tuesday: 9.5 and 7 hours
+	monday := time.Now().UTC().AddDate(0, 0, -14)
+	monday = time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, time.UTC)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, -1)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return monday.AddDate(0, 0, day).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	rec := punch(attCtrl.ClockIn, hr, at(0, 9, 0))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, http.StatusConflict, punch(attCtrl.ClockIn, hr, at(0, 10, 0)).Code, "already clocked in")
+	assert.Equal(t, http.StatusBadRequest, punch(attCtrl.ClockOut, hr, at(0, 8, 0)).Code, "clock-out before clock-in")
+	require.Equal(t, http.StatusOK, punch(attCtrl.ClockOut, hr, at(0, 18, 30)).Code)
+	assert.Equal(t, http.StatusConflict, punch(attCtrl.ClockOut, hr, at(0, 19, 0)).Code, "not clocked in")
+	assert.Equal(t, http.StatusConflict, punch(attCtrl.ClockIn, hr, at(0, 12, 0)).Code, "overlaps the recorded shift")
+	rec = punch(attCtrl.ClockIn, hr, time.Now().Add(time.Hour))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"at"`)
+	require.Equal(t, http.StatusCreated, punch(attCtrl.ClockIn, hr, at(1, 9, 0)).Code)
+	require.Equal(t, http.StatusOK, punch(attCtrl.ClockOut, hr, at(1, 16, 0)).Code)
+
+	//only HR may backdate a punch, and nobody else may punch for the member
+	assert.Equal(t, http.StatusForbidden, punch(attCtrl.ClockIn, memberToken, at(2, 9, 0)).Code)
+	rec = call(attCtrl.ClockIn, http.MethodPost, "/employees/"+id+"/attendance/clock-in", leadToken, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = call(attCtrl.ClockIn, http.MethodPost, "/employees/"+id+"/attendance/clock-in", memberToken, "")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var open database.AttendanceRecord
+	decodePayload(t, rec, &open)
+	assert.Nil(t, open.ClockOut)
+
+	from, to := monday.Format(time.DateOnly), monday.AddDate(0, 0, 6).Format(time.DateOnly)
+	query := "?period=weekly&from=" + from + "&to=" + to
+	rec = call(attCtrl.GetTimesheet, http.MethodGet, "/employees/"+id+"/timesheet"+query, leadToken, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var sheet database.Timesheet
+	decodePayload(t, rec, &sheet)
+	require.Len(t, sheet.Entries, 1)
+	assert.Equal(t, from, sheet.Entries[0].PeriodStart.Format(time.DateOnly))
+	assert.Equal(t, to, sheet.Entries[0].PeriodEnd.Format(time.DateOnly))
+	assert.Equal(t, int64(2), sheet.Entries[0].DaysWorked)
+	assert.Equal(t, 16.5, sheet.TotalHours)
+	assert.Equal(t, 1.5, sheet.TotalOvertimeHours)
+
+	rec = call(attCtrl.GetTimesheet, http.MethodGet, "/employees/"+id+"/timesheet?period=daily&from="+from+"&to="+to, memberToken, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	decodePayload(t, rec, &sheet)
+	assert.Len(t, sheet.Entries, 2)
+
+	rec = call(attCtrl.GetTimesheet, http.MethodGet, "/employees/"+id+"/timesheet"+query, outsider, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = call(attCtrl.ExportTimesheet, http.MethodGet, "/employees/"+id+"/timesheet/export"+query, hr, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "timesheet-"+id)
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, from+","+to+",2,2,16.5,1.5", lines[1])
+	rec = call(attCtrl.ExportTimesheet, http.MethodGet, "/employees/"+id+"/timesheet/export"+query, outsider, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition))
+}
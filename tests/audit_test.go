@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmployeeHistory(t *testing.T) {
+	cfg, ctrls, cleanup := setupControllers(t)
+	defer cleanup()
+	empCtrl, auditCtrl := ctrls.Employee, ctrls.Audit
+
+	token, err := generateJWTForRole(cfg, auth.RoleHR)
+	require.NoError(t, err)
+	authn := middleware.JWTAuthMiddleware(cfg, stubRevocations{})
+
+	e := newEcho()
+	//call runs handler as the hr user, the way the router would
+	call := func(handler echo.HandlerFunc, method, target, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		serve(authn(handler), c)
+		return rec
+	}
+
+	createRec := call(empCtrl.CreateEmployee, http.MethodPost, "/employees", "", `{"name": "Audit Subject", "position": "Analyst", "salary": 50000, "hired_date": "2024-01-15T00:00:00Z"}`)
+	require.Equal(t, http.StatusCreated, createRec.Code)
+	var emp database.Employee
+	decodePayload(t, createRec, &emp)
+	id := emp.ID.String()
+
+	updateRec := call(empCtrl.UpdateEmployee, http.MethodPut, "/employees/"+id, id, `{"name": "Audit Subject", "position": "Analyst", "salary": 55000, "hired_date": "2024-01-15T00:00:00Z"}`)
+	require.Equal(t, http.StatusOK, updateRec.Code)
+
+	deleteRec := call(empCtrl.DeleteEmployee, http.MethodDelete, "/employees/"+id, id, "")
+	require.Equal(t, http.StatusNoContent, deleteRec.Code)
+
+	//the history outlives the employee
+	historyRec := call(auditCtrl.GetEmployeeHistory, http.MethodGet, "/employees/"+id+"/history", id, "")
+	require.Equal(t, http.StatusOK, historyRec.Code)
+	var history database.AuditPage
+	decodePayload(t, historyRec, &history)
+
+	require.Len(t, history.Entries, 3)
+	assert.Equal(t, int64(3), history.Total)
+	assert.Equal(t, service.AuditActionDelete, history.Entries[0].Action)
+	assert.Equal(t, service.AuditActionUpdate, history.Entries[1].Action)
+	assert.Equal(t, service.AuditActionCreate, history.Entries[2].Action)
+
+	update := history.Entries[1]
+	assert.Equal(t, cfg.AdminEmail, update.ActorEmail)
+	require.NotNil(t, update.ActorID)
+	assert.JSONEq(t, "50000", string(update.Changes["salary"].Before))
+	assert.JSONEq(t, "55000", string(update.Changes["salary"].After))
+	assert.NotContains(t, update.Changes, "name", "unchanged fields are left out")
+
+	//deletes are soft, so the only field that changes is deleted_at
+	deletion := history.Entries[0]
+	assert.JSONEq(t, "null", string(deletion.Changes["deleted_at"].Before))
+	assert.NotEqual(t, "null", string(deletion.Changes["deleted_at"].After))
+	assert.NotContains(t, deletion.Changes, "name")
+
+	//only creations are left once the action filter is applied
+	filteredRec := call(auditCtrl.GetEmployeeHistory, http.MethodGet, "/employees/"+id+"/history?action=create", id, "")
+	var filtered database.AuditPage
+	decodePayload(t, filteredRec, &filtered)
+	require.Len(t, filtered.Entries, 1)
+	assert.Equal(t, service.AuditActionCreate, filtered.Entries[0].Action)
+
+	badRec := call(auditCtrl.ListAudit, http.MethodGet, "/audit?from=yesterday", "", "")
+	assert.Equal(t, http.StatusBadRequest, badRec.Code)
+}
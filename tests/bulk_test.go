@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callBulk sends body to a bulk handler and returns the recorded response
+func callBulk(e *echo.Echo, handler echo.HandlerFunc, method, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/employees/bulk", bytes.NewBufferString(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	serve(handler, e.NewContext(req, rec))
+	return rec
+}
+
+// decodeBulkResult decodes into a fresh value so no item outlives its response
+func decodeBulkResult(t *testing.T, rec *httptest.ResponseRecorder) database.BulkResult {
+	var result database.BulkResult
+	decodePayload(t, rec, &result)
+	return result
+}
+
+func bulkStatuses(result database.BulkResult) []string {
+	statuses := make([]string, len(result.Results))
+	for i, item := range result.Results {
+		statuses[i] = item.Status
+	}
+	return statuses
+}
+
+func TestBulkRejectsInvalidItems(t *testing.T) {
+	//an all-or-nothing batch with an invalid item never reaches the service
+	ctrl := controller.NewEmployeeController(nil, &config.Config{})
+	e := newEcho()
+
+	rec := callBulk(e, ctrl.BulkCreateEmployees, http.MethodPost, "", `{"employees": [
+		{"name": "Valid Person", "position": "Engineer", "salary": 50000},
+		{"name": "", "position": "Engineer", "salary": -1}
+	]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	result := decodeBulkResult(t, rec)
+	assert.Equal(t, database.BulkAllOrNothing, result.Mode)
+	assert.Equal(t, []string{database.BulkStatusSkipped, database.BulkStatusFailed}, bulkStatuses(result))
+	assert.Equal(t, 0, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	require.NotNil(t, result.Results[1].Error)
+	assert.Equal(t, customerr.CodeValidationFailed, result.Results[1].Error.Code)
+	assert.Len(t, result.Results[1].Error.Errors, 2)
+
+	rec = callBulk(e, ctrl.BulkPatchEmployees, http.MethodPatch, "", `{"mode": "best_effort", "employees": [
+		{"id": "`+uuid.NewString()+`", "patch": {"nickname": "x"}},
+		{"patch": {"salary": 1}}
+	]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	result = decodeBulkResult(t, rec)
+	assert.Equal(t, []string{database.BulkStatusFailed, database.BulkStatusFailed}, bulkStatuses(result))
+	assert.Equal(t, customerr.FieldUnknown, result.Results[0].Error.Errors[0].Code)
+	assert.Equal(t, "id", result.Results[1].Error.Errors[0].Field)
+
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"unknown mode", `{"mode": "sometimes", "employees": [{"id": "` + uuid.NewString() + `"}]}`},
+		{"no items", `{"employees": []}`},
+		{"not json", `{"employees": `},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := callBulk(e, ctrl.BulkDeleteEmployees, http.MethodDelete, "", tc.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestBulkEmployees(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+	e := newEcho()
+
+	//the unknown manager only fails once the rows reach the database
+	missingManager := uuid.NewString()
+	batch := `"employees": [
+		{"name": "Bulk One", "position": "Developer", "salary": 50000},
+		{"name": "Bulk Two", "position": "Developer", "salary": 51000, "manager_id": "` + missingManager + `"},
+		{"name": "Bulk Three", "position": "Developer", "salary": 52000}
+	]`
+
+	rec := callBulk(e, ctrl.BulkCreateEmployees, http.MethodPost, token, `{"mode": "all_or_nothing", `+batch+`}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	result := decodeBulkResult(t, rec)
+	assert.Equal(t, []string{database.BulkStatusRolledBack, database.BulkStatusFailed, database.BulkStatusSkipped}, bulkStatuses(result))
+	assert.Nil(t, result.Results[0].ID, "rolled back rows do not exist")
+	assert.Equal(t, 0, result.Succeeded)
+
+	rec = callBulk(e, ctrl.BulkCreateEmployees, http.MethodPost, token, `{"mode": "best_effort", `+batch+`}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	result = decodeBulkResult(t, rec)
+	assert.Equal(t, []string{database.BulkStatusCreated, database.BulkStatusFailed, database.BulkStatusCreated}, bulkStatuses(result))
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	first, third := result.Results[0].Employee, result.Results[2].Employee
+	require.NotNil(t, first)
+	require.NotNil(t, third)
+
+	patches, err := json.Marshal(map[string]interface{}{
+		"mode": "best_effort",
+		"employees": []map[string]interface{}{
+			{"id": first.ID, "version": first.Version, "patch": map[string]interface{}{"salary": 55000}},
+			{"id": third.ID, "version": first.Version + 7, "patch": map[string]interface{}{"salary": 56000}},
+		},
+	})
+	require.NoError(t, err)
+	rec = callBulk(e, ctrl.BulkPatchEmployees, http.MethodPatch, token, string(patches))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	result = decodeBulkResult(t, rec)
+	assert.Equal(t, []string{database.BulkStatusUpdated, database.BulkStatusFailed}, bulkStatuses(result))
+	assert.Equal(t, money.FromInt(55000), result.Results[0].Employee.Salary)
+	assert.Equal(t, customerr.CodePreconditionFailed, result.Results[1].Error.Code)
+
+	deletes := `{"employees": [{"id": "` + first.ID.String() + `"}, {"id": "` + third.ID.String() + `"}]}`
+	rec = callBulk(e, ctrl.BulkDeleteEmployees, http.MethodDelete, token, deletes)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	result = decodeBulkResult(t, rec)
+	assert.Equal(t, []string{database.BulkStatusDeleted, database.BulkStatusDeleted}, bulkStatuses(result))
+
+	//deleting them again fails as a whole
+	rec = callBulk(e, ctrl.BulkDeleteEmployees, http.MethodDelete, token, deletes)
+	result = decodeBulkResult(t, rec)
+	assert.Equal(t, []string{database.BulkStatusFailed, database.BulkStatusSkipped}, bulkStatuses(result))
+	assert.Equal(t, customerr.CodeNotFound, result.Results[0].Error.Code)
+}
@@ -6,62 +6,113 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
 	"github.com/lijuuu/EmployeeManagement/config"
 	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/customerr"
 	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/money"
 	"github.com/lijuuu/EmployeeManagement/repo"
 	"github.com/lijuuu/EmployeeManagement/service"
+	"github.com/lijuuu/EmployeeManagement/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-//setupTestEnvironment sets up the test envt with database and redis connections
-func setupTestEnvironment(t *testing.T) (*config.Config, *controller.EmployeeController, func()) {
-	//load configuration
+//testControllers are the server's controllers wired as in cmd/main.go
+type testControllers struct {
+	Employee     *controller.EmployeeController
+	Department   *controller.DepartmentController
+	User         *controller.UserController
+	Audit        *controller.AuditController
+	Leave        *controller.LeaveController
+	Attendance   *controller.AttendanceController
+	Payroll      *controller.PayrollController
+	ExchangeRate *controller.ExchangeRateController
+	//Users seeds accounts for the login tests
+	Users service.UserService
+}
+
+//setupControllers wires every controller against the test database and redis,
+//skipping the test when either is unavailable
+func setupControllers(t *testing.T) (*config.Config, *testControllers, func()) {
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		t.Skipf("Skipping test: failed to load config: %v", err)
 	}
 
-	//set up database connection
-	db, err := database.NewPostgresConn(context.Background(), cfg.PostgresDSN)
+	db, err := database.NewPostgresPool(context.Background(), cfg)
 	if err != nil {
 		t.Skipf("Skipping test: failed to connect to database: %v", err)
 	}
 
-	//set up Redis client
 	redisClient, err := database.InitRedis(cfg)
 	if err != nil {
+		db.Close()
 		t.Skipf("Skipping test: failed to connect to Redis: %v", err)
 	}
 
-	//initialize dependencies
-	repo := repo.NewEmployeeRepo(db)
-	svc := service.NewEmployeeService(repo, redisClient)
-	ctrl := controller.NewEmployeeController(svc, cfg)
+	employeeRepo := repo.NewEmployeeRepo(db)
+	auditRepo := repo.NewAuditRepo(db)
+	ratesRepo := repo.NewExchangeRateRepo(db)
+	employeeService := service.NewEmployeeService(db, employeeRepo, auditRepo, ratesRepo, redisClient)
+	departmentService := service.NewDepartmentService(db, repo.NewDepartmentRepo(db), auditRepo, employeeService, ratesRepo, redisClient)
+	userService := service.NewUserService(repo.NewUserRepo(db), redisClient, cfg)
+
+	ctrls := &testControllers{
+		Employee:     controller.NewEmployeeController(employeeService, cfg),
+		Department:   controller.NewDepartmentController(departmentService),
+		User:         controller.NewUserController(userService),
+		Audit:        controller.NewAuditController(service.NewAuditService(auditRepo)),
+		Leave:        controller.NewLeaveController(service.NewLeaveService(db, repo.NewLeaveRepo(db), employeeRepo, auditRepo)),
+		Attendance:   controller.NewAttendanceController(service.NewAttendanceService(db, repo.NewAttendanceRepo(db), employeeRepo)),
+		Payroll:      controller.NewPayrollController(service.NewPayrollService(db, repo.NewPayrollRepo(db), auditRepo, ratesRepo)),
+		ExchangeRate: controller.NewExchangeRateController(service.NewExchangeRateService(db, ratesRepo, auditRepo)),
+		Users:        userService,
+	}
 
-	//return cleanup function
 	cleanup := func() {
-		db.Close(context.Background())
+		db.Close()
 		redisClient.Close()
 	}
 
-	return cfg, ctrl, cleanup
+	return cfg, ctrls, cleanup
+}
+
+//setupTestEnvironment sets up the test envt with database and redis connections
+func setupTestEnvironment(t *testing.T) (*config.Config, *controller.EmployeeController, func()) {
+	cfg, ctrls, cleanup := setupControllers(t)
+	return cfg, ctrls.Employee, cleanup
 }
 
 //generateValidJWT creates a valid JWT token for testing
 func generateValidJWT(cfg *config.Config) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"email": cfg.AdminEmail,
-		"exp":   time.Now().Add(time.Hour * 24).Unix(),
-	})
-	return token.SignedString([]byte(cfg.JWTSecret))
+	return generateJWTForRole(cfg, auth.RoleAdmin)
+}
+
+//generateJWTForRole creates a valid JWT token carrying the given role
+func generateJWTForRole(cfg *config.Config, role string) (string, error) {
+	return auth.NewToken(cfg.JWTSecret, &database.User{
+		ID:    uuid.New(),
+		Email: cfg.AdminEmail,
+		Role:  role,
+	}, time.Hour*24)
+}
+
+//newEcho returns an Echo instance with the server's request validator and error handler
+func newEcho() *echo.Echo {
+	e := echo.New()
+	e.Validator = validation.New(nil)
+	e.HTTPErrorHandler = customerr.HTTPErrorHandler
+	return e
 }
 
 func TestCreateEmployee(t *testing.T) {
@@ -71,7 +122,7 @@ func TestCreateEmployee(t *testing.T) {
 	token, err := generateValidJWT(cfg)
 	require.NoError(t, err)
 
-	e := echo.New()
+	e := newEcho()
 
 	reqBody := `{
 		"name": "John Doe",
@@ -106,7 +157,7 @@ func TestCreateEmployee(t *testing.T) {
 	assert.NotEqual(t, uuid.Nil, emp.ID)
 	assert.Equal(t, "John Doe", emp.Name)
 	assert.Equal(t, "Software Engineer", emp.Position)
-	assert.Equal(t, 60000.0, emp.Salary)
+	assert.Equal(t, money.FromInt(60000), emp.Salary)
 	assert.Equal(t, "2024-06-01", emp.HiredDate.Format("2006-01-02"))
 	assert.False(t, emp.CreatedAt.IsZero())
 	assert.False(t, emp.UpdatedAt.IsZero())
@@ -119,7 +170,7 @@ func TestGetEmployee(t *testing.T) {
 	token, err := generateValidJWT(cfg)
 	require.NoError(t, err)
 
-	e := echo.New()
+	e := newEcho()
 
 	createReqBody := `{
 		"name": "Jane Smith",
@@ -146,13 +197,15 @@ func TestGetEmployee(t *testing.T) {
 	err = json.Unmarshal(empJSON, &createdEmp)
 	require.NoError(t, err)
 
+	//salary is only projected for authenticated hr/admin callers
 	getReq := httptest.NewRequest(http.MethodGet, "/employees/"+createdEmp.ID.String(), nil)
+	getReq.Header.Set("Authorization", "Bearer "+token)
 	getRec := httptest.NewRecorder()
 	getCtx := e.NewContext(getReq, getRec)
 	getCtx.SetParamNames("id")
 	getCtx.SetParamValues(createdEmp.ID.String())
 
-	err = ctrl.GetEmployee(getCtx)
+	err = middleware.OptionalJWTAuthMiddleware(cfg, stubRevocations{})(ctrl.GetEmployee)(getCtx)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, getRec.Code)
 
@@ -172,14 +225,14 @@ func TestGetEmployee(t *testing.T) {
 	assert.Equal(t, createdEmp.ID, retrievedEmp.ID)
 	assert.Equal(t, "Jane Smith", retrievedEmp.Name)
 	assert.Equal(t, "Product Manager", retrievedEmp.Position)
-	assert.Equal(t, 75000.0, retrievedEmp.Salary)
+	assert.Equal(t, money.FromInt(75000), retrievedEmp.Salary)
 }
 
 func TestListEmployees(t *testing.T) {
 	_, ctrl, cleanup := setupTestEnvironment(t)
 	defer cleanup()
 
-	e := echo.New()
+	e := newEcho()
 
 	req := httptest.NewRequest(http.MethodGet, "/employees", nil)
 	rec := httptest.NewRecorder()
@@ -198,11 +251,104 @@ func TestListEmployees(t *testing.T) {
 
 	empListJSON, err := json.Marshal(response.Payload)
 	require.NoError(t, err)
-	var employees []database.Employee
-	err = json.Unmarshal(empListJSON, &employees)
+	var page database.EmployeePage
+	err = json.Unmarshal(empListJSON, &page)
+	require.NoError(t, err)
+
+	assert.IsType(t, []database.Employee{}, page.Employees)
+	assert.GreaterOrEqual(t, page.Total, int64(len(page.Employees)))
+}
+
+func TestListEmployeesReportsVersion(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
 	require.NoError(t, err)
+	e := newEcho()
 
-	assert.IsType(t, []database.Employee{}, employees)
+	//the version listed must be the one If-Match expects
+	position := "Versioned " + uuid.NewString()[:8]
+	emp := createTestEmployee(t, e, ctrl, token, `{"name": "Vera Version", "position": "`+position+`", "salary": 50000}`)
+
+	req := httptest.NewRequest(http.MethodGet, "/employees?position="+url.QueryEscape(position), nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, ctrl.ListEmployees(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var page database.EmployeePage
+	decodePayload(t, rec, &page)
+	require.Len(t, page.Employees, 1)
+	assert.Equal(t, emp.ID, page.Employees[0].ID)
+	assert.NotZero(t, page.Employees[0].Version)
+	assert.Equal(t, emp.Version, page.Employees[0].Version)
+}
+
+func TestListEmployeesPagination(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+
+	e := newEcho()
+
+	//a unique position keeps the filtered result independent of other rows
+	position := "Pager " + uuid.New().String()
+	for _, name := range []string{"Page One", "Page Two", "Page Three"} {
+		createReqBody := `{"name": "` + name + `", "position": "` + position + `", "salary": 40000}`
+		createReq := httptest.NewRequest(http.MethodPost, "/employees", bytes.NewBufferString(createReqBody))
+		createReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		createReq.Header.Set("Authorization", "Bearer "+token)
+		createRec := httptest.NewRecorder()
+		err = ctrl.CreateEmployee(e.NewContext(createReq, createRec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, createRec.Code)
+	}
+
+	listPage := func(query string) database.EmployeePage {
+		req := httptest.NewRequest(http.MethodGet, "/employees?"+query, nil)
+		rec := httptest.NewRecorder()
+		err := ctrl.ListEmployees(e.NewContext(req, rec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response controller.Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		pageJSON, err := json.Marshal(response.Payload)
+		require.NoError(t, err)
+		var page database.EmployeePage
+		require.NoError(t, json.Unmarshal(pageJSON, &page))
+		return page
+	}
+
+	query := url.Values{"position": {position}, "sort_by": {"name"}, "limit": {"2"}}
+	first := listPage(query.Encode())
+	assert.Equal(t, int64(3), first.Total)
+	require.Len(t, first.Employees, 2)
+	assert.Equal(t, "Page One", first.Employees[0].Name)
+	assert.Equal(t, "Page Three", first.Employees[1].Name)
+	require.NotEmpty(t, first.NextCursor)
+
+	query.Set("cursor", first.NextCursor)
+	second := listPage(query.Encode())
+	require.Len(t, second.Employees, 1)
+	assert.Equal(t, "Page Two", second.Employees[0].Name)
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestListEmployeesInvalidQuery(t *testing.T) {
+	_, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	e := newEcho()
+
+	for _, query := range []string{"limit=0", "sort_by=password", "order=up", "min_salary=abc", "hired_from=01-06-2024", "cursor=not-a-cursor"} {
+		req := httptest.NewRequest(http.MethodGet, "/employees?"+query, nil)
+		rec := httptest.NewRecorder()
+		serve(ctrl.ListEmployees, e.NewContext(req, rec))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
 }
 
 func TestUpdateEmployee(t *testing.T) {
@@ -212,7 +358,7 @@ func TestUpdateEmployee(t *testing.T) {
 	token, err := generateValidJWT(cfg)
 	require.NoError(t, err)
 
-	e := echo.New()
+	e := newEcho()
 
 	createReqBody := `{
 		"name": "Bob Wilson",
@@ -271,7 +417,47 @@ func TestUpdateEmployee(t *testing.T) {
 	assert.Equal(t, createdEmp.ID, updatedEmp.ID)
 	assert.Equal(t, "Bob Wilson Jr", updatedEmp.Name)
 	assert.Equal(t, "Senior Developer", updatedEmp.Position)
-	assert.Equal(t, 80000.0, updatedEmp.Salary)
+	assert.Equal(t, money.FromInt(80000), updatedEmp.Salary)
+	//the response is read back from the database, so untouched columns are filled in
+	assert.Equal(t, createdEmp.HiredDate.Format(time.DateOnly), updatedEmp.HiredDate.Format(time.DateOnly))
+	assert.False(t, updatedEmp.CreatedAt.IsZero())
+}
+
+func TestPatchEmployee(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+
+	e := newEcho()
+	created := createTestEmployee(t, e, ctrl, token, `{"name": "Patty Patch", "position": "Developer", "salary": 70000, "hired_date": "2023-03-01T00:00:00Z"}`)
+	id := created.ID.String()
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/employees/"+id, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, controller.MIMEMergePatchJSON)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		serve(ctrl.PatchEmployee, c)
+		return rec
+	}
+
+	rec := patch(`{"salary": 72500}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var patched database.Employee
+	decodePayload(t, rec, &patched)
+	assert.Equal(t, money.FromInt(72500), patched.Salary)
+	//members left out of the patch keep their values
+	assert.Equal(t, "Patty Patch", patched.Name)
+	assert.Equal(t, "Developer", patched.Position)
+	assert.Equal(t, "2023-03-01", patched.HiredDate.Format(time.DateOnly))
+
+	assert.Equal(t, http.StatusBadRequest, patch(`{"name": null}`).Code)
+	assert.Equal(t, http.StatusBadRequest, patch(`{"salary": -1}`).Code)
 }
 
 func TestDeleteEmployee(t *testing.T) {
@@ -281,7 +467,7 @@ func TestDeleteEmployee(t *testing.T) {
 	token, err := generateValidJWT(cfg)
 	require.NoError(t, err)
 
-	e := echo.New()
+	e := newEcho()
 
 	createReqBody := `{
 		"name": "Alice Brown",
@@ -325,15 +511,18 @@ func TestDeleteEmployee(t *testing.T) {
 	getCtx.SetParamValues(createdEmp.ID.String())
 
 	err = ctrl.GetEmployee(getCtx)
-	assert.NoError(t, err)
+	require.ErrorIs(t, err, customerr.ErrNotFound)
+	customerr.HTTPErrorHandler(err, getCtx)
 	assert.Equal(t, http.StatusNotFound, getRec.Code)
 }
 
 func TestLogin(t *testing.T) {
-	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	cfg, ctrls, cleanup := setupControllers(t)
 	defer cleanup()
+	require.NoError(t, ctrls.Users.EnsureAdmin(context.Background(), cfg.AdminEmail, cfg.AdminPassword))
+	ctrl := ctrls.User
 
-	e := echo.New()
+	e := newEcho()
 
 	loginReqBody := `{
 		"email": "` + cfg.AdminEmail + `",
@@ -356,11 +545,12 @@ func TestLogin(t *testing.T) {
 	assert.Equal(t, http.StatusOK, response.StatusCode)
 	assert.NotEmpty(t, response.Payload)
 
-	tokenString, ok := response.Payload.(string)
-	assert.True(t, ok)
-	assert.NotEmpty(t, tokenString)
+	var tokens database.TokenResponse
+	decodePayload(t, rec, &tokens)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, "Bearer", tokens.TokenType)
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+	token, err := jwt.Parse(tokens.AccessToken, func(token *jwt.Token) (interface{}, error) {
 		return []byte(cfg.JWTSecret), nil
 	})
 	assert.NoError(t, err)
@@ -374,7 +564,7 @@ func TestCreateEmployeeWithoutHiredDate(t *testing.T) {
 	token, err := generateValidJWT(cfg)
 	require.NoError(t, err)
 
-	e := echo.New()
+	e := newEcho()
 
 	reqBody := `{
 		"name": "Test Employee",
@@ -408,7 +598,7 @@ func TestCreateEmployeeWithoutHiredDate(t *testing.T) {
 	assert.NotEqual(t, uuid.Nil, emp.ID)
 	assert.Equal(t, "Test Employee", emp.Name)
 	assert.Equal(t, "Tester", emp.Position)
-	assert.Equal(t, 50000.0, emp.Salary)
+	assert.Equal(t, money.FromInt(50000), emp.Salary)
 	assert.Equal(t, time.Now().Format("2006-01-02"), emp.HiredDate.Format("2006-01-02"))
 	assert.False(t, emp.CreatedAt.IsZero())
 	assert.False(t, emp.UpdatedAt.IsZero())
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//decodePayload re-decodes a Response payload into out
+func decodePayload(t *testing.T, rec *httptest.ResponseRecorder, out interface{}) {
+	var response controller.Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	payloadJSON, err := json.Marshal(response.Payload)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(payloadJSON, out))
+}
+
+func TestDepartmentMembership(t *testing.T) {
+	cfg, ctrls, cleanup := setupControllers(t)
+	defer cleanup()
+	empCtrl, deptCtrl := ctrls.Employee, ctrls.Department
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+
+	e := newEcho()
+
+	deptReq := httptest.NewRequest(http.MethodPost, "/departments", bytes.NewBufferString(`{"name": "Dept `+uuid.New().String()+`"}`))
+	deptReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	deptReq.Header.Set("Authorization", "Bearer "+token)
+	deptRec := httptest.NewRecorder()
+	require.NoError(t, deptCtrl.CreateDepartment(e.NewContext(deptReq, deptRec)))
+	require.Equal(t, http.StatusCreated, deptRec.Code)
+	var dept database.Department
+	decodePayload(t, deptRec, &dept)
+
+	empReq := httptest.NewRequest(http.MethodPost, "/employees", bytes.NewBufferString(`{"name": "Dana Member", "position": "Analyst", "salary": 52000}`))
+	empReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	empReq.Header.Set("Authorization", "Bearer "+token)
+	empRec := httptest.NewRecorder()
+	require.NoError(t, empCtrl.CreateEmployee(e.NewContext(empReq, empRec)))
+	require.Equal(t, http.StatusCreated, empRec.Code)
+	var emp database.Employee
+	decodePayload(t, empRec, &emp)
+
+	moveReq := httptest.NewRequest(http.MethodPost, "/departments/"+dept.ID.String()+"/employees", bytes.NewBufferString(`{"employee_ids": ["`+emp.ID.String()+`"]}`))
+	moveReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	moveReq.Header.Set("Authorization", "Bearer "+token)
+	moveRec := httptest.NewRecorder()
+	moveCtx := e.NewContext(moveReq, moveRec)
+	moveCtx.SetParamNames("id")
+	moveCtx.SetParamValues(dept.ID.String())
+	require.NoError(t, deptCtrl.MoveEmployees(moveCtx))
+	require.Equal(t, http.StatusOK, moveRec.Code)
+	var moved database.DepartmentAssignment
+	decodePayload(t, moveRec, &moved)
+	assert.Equal(t, emp.ID, moved.EmployeeIDs[0])
+
+	membersReq := httptest.NewRequest(http.MethodGet, "/departments/"+dept.ID.String()+"/employees", nil)
+	membersRec := httptest.NewRecorder()
+	membersCtx := e.NewContext(membersReq, membersRec)
+	membersCtx.SetParamNames("id")
+	membersCtx.SetParamValues(dept.ID.String())
+	require.NoError(t, deptCtrl.ListDepartmentEmployees(membersCtx))
+	require.Equal(t, http.StatusOK, membersRec.Code)
+	var members database.EmployeePage
+	decodePayload(t, membersRec, &members)
+	require.Len(t, members.Employees, 1)
+	assert.Equal(t, emp.ID, members.Employees[0].ID)
+	require.NotNil(t, members.Employees[0].DepartmentID)
+	assert.Equal(t, dept.ID, *members.Employees[0].DepartmentID)
+
+	deleteReq := httptest.NewRequest(http.MethodDelete, "/departments/"+dept.ID.String(), nil)
+	deleteReq.Header.Set("Authorization", "Bearer "+token)
+	deleteRec := httptest.NewRecorder()
+	deleteCtx := e.NewContext(deleteReq, deleteRec)
+	deleteCtx.SetParamNames("id")
+	deleteCtx.SetParamValues(dept.ID.String())
+	require.NoError(t, deptCtrl.DeleteDepartment(deleteCtx))
+	assert.Equal(t, http.StatusNoContent, deleteRec.Code)
+
+	getReq := httptest.NewRequest(http.MethodGet, "/employees/"+emp.ID.String(), nil)
+	getRec := httptest.NewRecorder()
+	getCtx := e.NewContext(getReq, getRec)
+	getCtx.SetParamNames("id")
+	getCtx.SetParamValues(emp.ID.String())
+	require.NoError(t, empCtrl.GetEmployee(getCtx))
+	var released database.Employee
+	decodePayload(t, getRec, &released)
+	assert.Nil(t, released.DepartmentID)
+	assert.Equal(t, emp.Version+2, released.Version, "moving in and being released each bump the version")
+}
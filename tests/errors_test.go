@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//serve runs handler and, as the server does, answers any error it returns through the central error handler
+func serve(handler echo.HandlerFunc, c echo.Context) {
+	if err := handler(c); err != nil {
+		customerr.HTTPErrorHandler(err, c)
+	}
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	cause := errors.New("dial tcp 127.0.0.1:5432: connection refused")
+
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   customerr.Code
+		detail string
+	}{
+		{"not found", customerr.NotFound("employee not found"), http.StatusNotFound, customerr.CodeNotFound, "employee not found"},
+		{"conflict", customerr.Conflict("user already exists"), http.StatusConflict, customerr.CodeConflict, "user already exists"},
+		{"validation", customerr.Validation("invalid employee data"), http.StatusBadRequest, customerr.CodeValidationFailed, "invalid employee data"},
+		{"wrapped kind", fmt.Errorf("failed to restore employee: %w", customerr.NotFound("employee not found")), http.StatusNotFound, customerr.CodeNotFound, "employee not found"},
+		{"catalog code", customerr.New(customerr.CodeInvalidCredentials, "invalid credentials"), http.StatusUnauthorized, customerr.CodeInvalidCredentials, "invalid credentials"},
+		{"precondition", customerr.New(customerr.CodePreconditionFailed, "employee has been modified since it was read"), http.StatusPreconditionFailed, customerr.CodePreconditionFailed, "employee has been modified since it was read"},
+		{"unavailable hides cause", customerr.Wrap(customerr.ErrUnavailable, "database unavailable", cause), http.StatusServiceUnavailable, customerr.CodeUnavailable, "database unavailable"},
+		{"internal hides message", fmt.Errorf("failed to list employees: %w", cause), http.StatusInternalServerError, customerr.CodeInternal, "Internal server error"},
+		{"echo error", echo.NewHTTPError(http.StatusMethodNotAllowed, "Method Not Allowed"), http.StatusMethodNotAllowed, customerr.CodeMethodNotAllowed, "Method Not Allowed"},
+	}
+
+	e := newEcho()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/employees", nil)
+			req.Header.Set(echo.HeaderXRequestID, "req-123")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			customerr.HTTPErrorHandler(tc.err, c)
+
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Equal(t, customerr.MIMEProblemJSON, rec.Header().Get(echo.HeaderContentType))
+			var problem customerr.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, tc.status, problem.Status)
+			assert.Equal(t, tc.code, problem.Code)
+			assert.Equal(t, "urn:employee-management:problem:"+string(tc.code), problem.Type)
+			assert.Equal(t, tc.detail, problem.Detail)
+			assert.Equal(t, "/employees", problem.Instance)
+			assert.Equal(t, "req-123", problem.RequestID)
+			assert.NotContains(t, rec.Body.String(), "connection refused")
+		})
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	e := newEcho()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/employees", nil), rec)
+
+	customerr.HTTPErrorHandler(customerr.InvalidFields(
+		customerr.FieldError{Field: "name", Code: customerr.FieldRequired, Message: "name is required"},
+		customerr.FieldError{Field: "salary", Code: customerr.FieldOutOfRange, Message: "salary must be positive"},
+	), c)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var problem customerr.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, customerr.CodeValidationFailed, problem.Code)
+	require.Len(t, problem.Errors, 2)
+	assert.Equal(t, "name", problem.Errors[0].Field)
+	assert.Equal(t, customerr.FieldRequired, problem.Errors[0].Code)
+	assert.Equal(t, "salary", problem.Errors[1].Field)
+}
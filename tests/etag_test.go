@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmployeeConditionalRequests(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+
+	e := newEcho()
+	created := createTestEmployee(t, e, ctrl, token, `{"name": "Olive Optimistic", "position": "Developer", "salary": 64000}`)
+	id := created.ID.String()
+
+	call := func(handler echo.HandlerFunc, method, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/employees/"+id, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		serve(handler, c)
+		return rec
+	}
+
+	get := call(ctrl.GetEmployee, http.MethodGet, "", nil)
+	require.Equal(t, http.StatusOK, get.Code)
+	etag := get.Header().Get("ETag")
+	require.Equal(t, `"1"`, etag)
+
+	//the cached record answers conditional reads too
+	notModified := call(ctrl.GetEmployee, http.MethodGet, "", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.Bytes())
+	assert.Equal(t, http.StatusNotModified, call(ctrl.GetEmployee, http.MethodGet, "", map[string]string{"If-None-Match": "W/" + etag}).Code)
+	assert.Equal(t, http.StatusOK, call(ctrl.GetEmployee, http.MethodGet, "", map[string]string{"If-None-Match": `"41"`}).Code)
+
+	patchHeaders := map[string]string{echo.HeaderContentType: controller.MIMEMergePatchJSON, "If-Match": etag}
+	patched := call(ctrl.PatchEmployee, http.MethodPatch, `{"salary": 66000}`, patchHeaders)
+	require.Equal(t, http.StatusOK, patched.Code)
+	newETag := patched.Header().Get("ETag")
+	assert.Equal(t, `"2"`, newETag)
+
+	//a write based on the version read before the patch is refused
+	stale := call(ctrl.UpdateEmployee, http.MethodPut, `{"name": "Olive Optimistic", "position": "Developer", "salary": 1000}`,
+		map[string]string{echo.HeaderContentType: echo.MIMEApplicationJSON, "If-Match": etag})
+	require.Equal(t, http.StatusPreconditionFailed, stale.Code)
+	var problem customerr.Problem
+	require.NoError(t, json.Unmarshal(stale.Body.Bytes(), &problem))
+	assert.Equal(t, customerr.CodePreconditionFailed, problem.Code)
+
+	//weak tags never satisfy If-Match
+	weak := call(ctrl.DeleteEmployee, http.MethodDelete, "", map[string]string{"If-Match": "W/" + newETag})
+	assert.Equal(t, http.StatusPreconditionFailed, weak.Code)
+
+	current := call(ctrl.GetEmployee, http.MethodGet, "", map[string]string{"If-None-Match": etag})
+	require.Equal(t, http.StatusOK, current.Code)
+	assert.Equal(t, newETag, current.Header().Get("ETag"), "refused writes leave the version alone")
+	var emp database.Employee
+	decodePayload(t, current, &emp)
+	assert.Equal(t, int64(2), emp.Version)
+
+	assert.Equal(t, http.StatusNoContent, call(ctrl.DeleteEmployee, http.MethodDelete, "", map[string]string{"If-Match": newETag}).Code)
+}
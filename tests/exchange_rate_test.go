@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"bytes"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateConvert(t *testing.T) {
+	assert.Equal(t, "0.9215", money.MustParseRate("0.921500").String())
+	assert.Equal(t, "149.62", money.MustParseRate("149.62").String())
+	for in, want := range map[string]error{
+		"lots":          money.ErrSyntax,
+		"0.00000000001": money.ErrPrecision,
+		"100000000":     money.ErrOutOfRange,
+		"0.0000000001":  nil,
+	} {
+		_, err := money.ParseRate(in)
+		assert.ErrorIs(t, err, want, in)
+	}
+
+	rates := money.Rates{"EUR": money.MustParseRate("0.8"), "JPY": money.MustParseRate("150")}
+	convert := func(amount, from, to string) string {
+		converted, err := rates.Convert(money.MustParse(amount), from, to)
+		require.NoError(t, err)
+		return converted.String()
+	}
+	assert.Equal(t, "80", convert("100", "USD", "EUR"))
+	assert.Equal(t, "125", convert("100", "EUR", "USD"))
+	assert.Equal(t, "18750", convert("100", "EUR", "JPY"), "cross rates go through USD")
+	assert.Equal(t, "0.6667", convert("100", "JPY", "USD"), "results keep four places until rounded")
+	assert.Equal(t, "100", convert("100", "GBP", "GBP"), "a currency converts to itself without a rate")
+
+	_, err := rates.Convert(money.FromInt(100), "GBP", "USD")
+	assert.ErrorIs(t, err, money.ErrNoRate)
+	assert.Contains(t, err.Error(), "GBP")
+	_, err = rates.Convert(money.FromInt(100), "USD", "GBP")
+	assert.ErrorIs(t, err, money.ErrNoRate)
+}
+
+func TestExchangeRatesRejectInvalidInput(t *testing.T) {
+	//validation runs before the service, so none is needed
+	rateCtrl := controller.NewExchangeRateController(nil)
+	e := newEcho()
+
+	call := func(handler echo.HandlerFunc, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		serve(handler, e.NewContext(req, rec))
+		return rec
+	}
+
+	rates := []struct {
+		body  string
+		field string
+	}{
+		{`{"rate": 0.92, "effective_date": "2024-08-01T00:00:00Z"}`, "currency"},
+		{`{"currency": "eur", "rate": 0.92, "effective_date": "2024-08-01T00:00:00Z"}`, "currency"},
+		{`{"currency": "EUR", "rate": 0, "effective_date": "2024-08-01T00:00:00Z"}`, "rate"},
+		{`{"currency": "EUR", "rate": -0.92, "effective_date": "2024-08-01T00:00:00Z"}`, "rate"},
+		{`{"currency": "EUR", "rate": 0.92}`, "effective_date"},
+	}
+	for _, tc := range rates {
+		rec := call(rateCtrl.SetExchangeRate, http.MethodPost, "/", tc.body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, tc.body)
+		assert.Contains(t, rec.Body.String(), `"field":"`+tc.field+`"`, tc.body)
+	}
+	assert.Equal(t, http.StatusBadRequest, call(rateCtrl.SetExchangeRate, http.MethodPost, "/", `{"currency": "EUR", "rate": "lots"}`).Code)
+
+	for _, target := range []string{"/?currency=euro", "/?as_of=August"} {
+		assert.Equal(t, http.StatusBadRequest, call(rateCtrl.ListExchangeRates, http.MethodGet, target, "").Code, target)
+	}
+
+	//currency= and as_of= are checked the same way wherever amounts are converted
+	payCtrl := controller.NewPayrollController(nil)
+	deptCtrl := controller.NewDepartmentController(nil)
+	conversions := []struct {
+		target string
+		field  string
+	}{
+		{"/?currency=eur", "currency"},
+		{"/?as_of=2024-08-31", "currency"},
+		{"/?currency=EUR&as_of=31.08.2024", "as_of"},
+	}
+	for _, handler := range []echo.HandlerFunc{payCtrl.ListPayrollRuns, deptCtrl.GetDepartmentCosts} {
+		for _, tc := range conversions {
+			rec := call(handler, http.MethodGet, tc.target, "")
+			assert.Equal(t, http.StatusBadRequest, rec.Code, tc.target)
+			assert.Contains(t, rec.Body.String(), `"field":"`+tc.field+`"`, tc.target)
+		}
+	}
+}
+
+func TestImportExchangeRatesReportsRowErrors(t *testing.T) {
+	//with an invalid row nothing is stored, so the service is never called
+	ctrl := controller.NewExchangeRateController(nil)
+	e := newEcho()
+
+	csv := "Currency,Rate,Effective Date,Source\n" +
+		"EUR,0.9215,2024-08-01,ECB\n" +
+		"USD,1,2024-08-01,\n" +
+		"GBP,lots,2024-08-01,\n" +
+		"\n" +
+		",0.78,August,\n"
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "rates.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(csv))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+	req := httptest.NewRequest(http.MethodPost, "/exchange-rates/import", &body)
+	req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	serve(ctrl.ImportExchangeRates, e.NewContext(req, rec))
+
+	report := decodeImportReport(t, rec)
+	assert.Equal(t, database.BulkAllOrNothing, report.Mode)
+	assert.Equal(t, 4, report.Rows)
+	assert.Equal(t, 0, report.Imported, "a valid row is not stored while others fail")
+	assert.Equal(t, 3, report.Failed)
+	assert.Equal(t, []string{"Source"}, report.IgnoredColumns)
+	assert.Equal(t, []database.ImportRowError{
+		{Row: 3, Field: "currency", Code: customerr.FieldInvalidValue, Message: "rates are against USD, whose rate is always 1"},
+		{Row: 4, Field: "rate", Code: customerr.FieldInvalid, Message: "rate must be a number with at most 10 decimal places"},
+		{Row: 6, Field: "effective_date", Code: customerr.FieldInvalid, Message: "effective_date must be a date such as 2024-06-01"},
+		{Row: 6, Field: "currency", Code: customerr.FieldRequired, Message: "currency is required"},
+	}, report.Errors)
+}
+
+func TestExchangeRateConversion(t *testing.T) {
+	cfg, ctrls, cleanup := setupControllers(t)
+	defer cleanup()
+	empCtrl, payCtrl, rateCtrl := ctrls.Employee, ctrls.Payroll, ctrls.ExchangeRate
+
+	admin, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+	hr, err := generateJWTForRole(cfg, auth.RoleHR)
+	require.NoError(t, err)
+	e := newEcho()
+
+	call := func(handler echo.HandlerFunc, method, target, id, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		serve(middleware.JWTAuthMiddleware(cfg, stubRevocations{})(handler), c)
+		return rec
+	}
+
+	//each test run pays a month of its own long ago, with a EUR rate from its first day
+	period := time.Date(1901, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, rand.Intn(1000), 0)
+	periodEnd := period.AddDate(0, 1, -1)
+	createTestEmployee(t, e, empCtrl, admin, `{"name": "Converted Employee", "position": "Engineer", "salary": 120000, "hired_date": "`+period.Format(time.RFC3339)+`"}`)
+
+	rec := call(rateCtrl.SetExchangeRate, http.MethodPost, "/", "", admin, `{"currency": "EUR", "rate": 0.9215, "effective_date": "`+period.Format(time.RFC3339)+`"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var rate database.ExchangeRate
+	decodePayload(t, rec, &rate)
+	defer call(rateCtrl.DeleteExchangeRate, http.MethodDelete, "/", rate.ID.String(), admin, "")
+	assert.Equal(t, money.MustParseRate("0.9215"), rate.Rate)
+
+	//storing the same currency and day again replaces the rate
+	rec = call(rateCtrl.SetExchangeRate, http.MethodPost, "/", "", admin, `{"currency": "EUR", "rate": "0.92", "effective_date": "`+period.Format(time.RFC3339)+`"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var replaced database.ExchangeRate
+	decodePayload(t, rec, &replaced)
+	assert.Equal(t, rate.ID, replaced.ID)
+	assert.Equal(t, money.MustParseRate("0.92"), replaced.Rate)
+
+	rec = call(rateCtrl.ListExchangeRates, http.MethodGet, "/?currency=EUR&as_of="+periodEnd.Format(time.DateOnly), "", hr, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var listed []database.ExchangeRate
+	decodePayload(t, rec, &listed)
+	require.Len(t, listed, 1)
+	assert.Equal(t, rate.ID, listed[0].ID)
+
+	assert.Equal(t, http.StatusForbidden, call(rateCtrl.SetExchangeRate, http.MethodPost, "/", "", hr, `{"currency": "EUR", "rate": 0.9, "effective_date": "`+period.Format(time.RFC3339)+`"}`).Code, "only admins manage rates")
+
+	rec = call(payCtrl.CreatePayrollRun, http.MethodPost, "/", "", hr, `{"period": "`+period.Format("2006-01")+`"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var run database.PayrollRun
+	decodePayload(t, rec, &run)
+	assert.Nil(t, run.Converted, "amounts are only converted on request")
+
+	rec = call(payCtrl.GetPayrollRun, http.MethodGet, "/?currency=EUR", run.ID.String(), hr, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	decodePayload(t, rec, &run)
+	require.NotNil(t, run.Converted)
+	require.NotNil(t, run.RateDate)
+	assert.Equal(t, periodEnd, *run.RateDate, "runs convert at the rates in effect at the end of their period")
+	assert.Equal(t, "EUR", run.Converted.Currency)
+	rates := money.Rates{"EUR": money.MustParseRate("0.92")}
+	var gross money.Amount
+	for _, total := range run.Totals {
+		converted, err := rates.Convert(total.Gross, total.Currency, "EUR")
+		require.NoError(t, err)
+		gross = gross.Add(converted)
+	}
+	assert.Equal(t, gross.Round(2), run.Converted.Gross)
+	assert.Equal(t, run.Converted.Gross.Add(run.Converted.Allowances).Sub(run.Converted.Deductions), run.Converted.Net)
+
+	//there is no EUR rate before the period starts
+	rec = call(payCtrl.GetPayrollRun, http.MethodGet, "/?currency=EUR&as_of="+period.AddDate(0, 0, -1).Format(time.DateOnly), run.ID.String(), hr, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "no exchange rate for EUR")
+
+	rec = call(rateCtrl.DeleteExchangeRate, http.MethodDelete, "/", rate.ID.String(), admin, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	assert.Equal(t, http.StatusNotFound, call(rateCtrl.DeleteExchangeRate, http.MethodDelete, "/", rate.ID.String(), admin, "").Code)
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/exporter"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// exportViews are one employee whose salary is visible and one whose is not
+func exportViews() []database.EmployeeView {
+	departmentID := uuid.MustParse("7d0c1a4e-5f59-4d8e-9a0b-2b9f3c6e1d20")
+	salary := money.MustParse("72500.5")
+	hired := time.Date(2023, 4, 3, 0, 0, 0, 0, time.UTC)
+	stamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []database.EmployeeView{
+		{
+			Employee: database.Employee{ID: uuid.MustParse("0f8e2d1c-3b4a-4c5d-8e6f-7a8b9c0d1e2f"), Name: "Jane, Roe", Position: "Engineer", Currency: "EUR", HiredDate: hired, DepartmentID: &departmentID, CreatedAt: stamp, UpdatedAt: stamp, Version: 2},
+			Salary:   &salary,
+		},
+		{
+			Employee: database.Employee{ID: uuid.MustParse("1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"), Name: "John Doe", Position: "Analyst", Currency: "USD", HiredDate: hired, CreatedAt: stamp, UpdatedAt: stamp, Version: 1},
+		},
+	}
+}
+
+func writeExport(t *testing.T, format exporter.Format) []byte {
+	var out bytes.Buffer
+	w, err := exporter.NewWriter(&out, format)
+	require.NoError(t, err)
+	defer w.Close()
+	for _, view := range exportViews() {
+		require.NoError(t, w.Write(view))
+	}
+	require.NoError(t, w.Flush())
+	return out.Bytes()
+}
+
+func TestExportFormats(t *testing.T) {
+	header := []string{"id", "name", "position", "salary", "currency", "hired_date", "department_id", "manager_id", "version", "created_at", "updated_at"}
+
+	records, err := csv.NewReader(bytes.NewReader(writeExport(t, exporter.FormatCSV))).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		header,
+		{"0f8e2d1c-3b4a-4c5d-8e6f-7a8b9c0d1e2f", "Jane, Roe", "Engineer", "72500.5", "EUR", "2023-04-03", "7d0c1a4e-5f59-4d8e-9a0b-2b9f3c6e1d20", "", "2", "2024-01-02T03:04:05Z", "2024-01-02T03:04:05Z"},
+		{"1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d", "John Doe", "Analyst", "", "USD", "2023-04-03", "", "", "1", "2024-01-02T03:04:05Z", "2024-01-02T03:04:05Z"},
+	}, records)
+
+	lines := strings.Split(strings.TrimSpace(string(writeExport(t, exporter.FormatJSONL))), "\n")
+	require.Len(t, lines, 2)
+	var first, second map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, 72500.5, first["salary"])
+	assert.Equal(t, "EUR", first["currency"])
+	assert.NotContains(t, second, "salary", "a hidden salary is left out")
+
+	book, err := excelize.OpenReader(bytes.NewReader(writeExport(t, exporter.FormatXLSX)))
+	require.NoError(t, err)
+	defer book.Close()
+	rows, err := book.GetRows(book.GetSheetName(0), excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, header, rows[0])
+	assert.Equal(t, []string{"0f8e2d1c-3b4a-4c5d-8e6f-7a8b9c0d1e2f", "Jane, Roe", "Engineer", "72500.5", "EUR"}, rows[1][:5])
+	assert.Equal(t, "", rows[2][3], "a hidden salary is an empty cell")
+	serial, err := strconv.ParseFloat(rows[1][5], 64)
+	require.NoError(t, err, "dates are stored as date cells")
+	hired, err := excelize.ExcelDateToTime(serial, false)
+	require.NoError(t, err)
+	assert.Equal(t, "2023-04-03", hired.Format(time.DateOnly))
+
+	_, err = exporter.ParseFormat("pdf")
+	assert.ErrorIs(t, err, customerr.ErrValidation)
+}
+
+func TestExportRejectsInvalidQuery(t *testing.T) {
+	//invalid parameters are rejected before the service is called
+	ctrl := controller.NewEmployeeController(nil, &config.Config{})
+	e := newEcho()
+
+	for _, query := range []string{"format=pdf", "sort_by=nickname", "hired_from=yesterday"} {
+		req := httptest.NewRequest(http.MethodGet, "/employees/export?"+query, nil)
+		rec := httptest.NewRecorder()
+		serve(ctrl.ExportEmployees, e.NewContext(req, rec))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition), query)
+	}
+}
+
+func TestExportEmployees(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+	e := newEcho()
+	created := createTestEmployee(t, e, ctrl, token, `{"name": "Export Subject", "position": "Auditor", "salary": 81000}`)
+
+	export := func(token, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/employees/export?"+query, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		serve(middleware.OptionalJWTAuthMiddleware(cfg, stubRevocations{})(ctrl.ExportEmployees), e.NewContext(req, rec))
+		return rec
+	}
+	//salaryOf finds the created employee in a CSV export
+	salaryOf := func(rec *httptest.ResponseRecorder) string {
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		for _, record := range records[1:] {
+			if record[0] == created.ID.String() {
+				return record[3]
+			}
+		}
+		t.Fatalf("employee %s missing from export", created.ID)
+		return ""
+	}
+
+	hr, err := generateJWTForRole(cfg, auth.RoleHR)
+	require.NoError(t, err)
+	rec := export(hr, "position=Auditor")
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "attachment")
+	assert.Equal(t, "81000", salaryOf(rec))
+	assert.Equal(t, "", salaryOf(export("", "position=Auditor")), "anonymous callers do not see salaries")
+
+	rec = export(hr, "format=jsonl&position=Auditor&sort_by=salary&order=desc")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), created.ID.String())
+
+	//filtering on salary would leak it, so only callers who see every salary may do it
+	viewer, err := generateJWTForRole(cfg, auth.RoleViewer)
+	require.NoError(t, err)
+	rec = export(viewer, "min_salary=1000")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition))
+}
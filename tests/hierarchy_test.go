@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//createTestEmployee creates an employee through the controller and returns the stored record
+func createTestEmployee(t *testing.T, e *echo.Echo, ctrl *controller.EmployeeController, token, body string) database.Employee {
+	req := httptest.NewRequest(http.MethodPost, "/employees", bytes.NewBufferString(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	require.NoError(t, ctrl.CreateEmployee(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var emp database.Employee
+	decodePayload(t, rec, &emp)
+	return emp
+}
+
+//setManager assigns managerID as id's manager and returns the response code
+func setManager(t *testing.T, e *echo.Echo, ctrl *controller.EmployeeController, token string, id, managerID string) int {
+	req := httptest.NewRequest(http.MethodPut, "/employees/"+id+"/manager", bytes.NewBufferString(`{"manager_id": "`+managerID+`"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	serve(ctrl.SetManager, c)
+	return rec.Code
+}
+
+func TestManagerHierarchy(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+
+	e := newEcho()
+
+	ceo := createTestEmployee(t, e, ctrl, token, `{"name": "Carol Chief", "position": "CEO", "salary": 200000}`)
+	lead := createTestEmployee(t, e, ctrl, token, `{"name": "Liam Lead", "position": "Lead", "salary": 120000}`)
+	dev := createTestEmployee(t, e, ctrl, token, `{"name": "Dev Doe", "position": "Developer", "salary": 90000}`)
+
+	require.Equal(t, http.StatusNoContent, setManager(t, e, ctrl, token, lead.ID.String(), ceo.ID.String()))
+	require.Equal(t, http.StatusNoContent, setManager(t, e, ctrl, token, dev.ID.String(), lead.ID.String()))
+
+	//the CEO reporting to the developer would close a loop
+	assert.Equal(t, http.StatusConflict, setManager(t, e, ctrl, token, ceo.ID.String(), dev.ID.String()))
+	assert.Equal(t, http.StatusBadRequest, setManager(t, e, ctrl, token, dev.ID.String(), dev.ID.String()))
+
+	chainReq := httptest.NewRequest(http.MethodGet, "/employees/"+dev.ID.String()+"/managers", nil)
+	chainRec := httptest.NewRecorder()
+	chainCtx := e.NewContext(chainReq, chainRec)
+	chainCtx.SetParamNames("id")
+	chainCtx.SetParamValues(dev.ID.String())
+	require.NoError(t, ctrl.GetManagerChain(chainCtx))
+	require.Equal(t, http.StatusOK, chainRec.Code)
+	var chain []database.OrgNode
+	decodePayload(t, chainRec, &chain)
+	require.Len(t, chain, 2)
+	assert.Equal(t, lead.ID, chain[0].ID)
+	assert.Equal(t, ceo.ID, chain[1].ID)
+
+	treeReq := httptest.NewRequest(http.MethodGet, "/employees/"+ceo.ID.String()+"/reports", nil)
+	treeRec := httptest.NewRecorder()
+	treeCtx := e.NewContext(treeReq, treeRec)
+	treeCtx.SetParamNames("id")
+	treeCtx.SetParamValues(ceo.ID.String())
+	require.NoError(t, ctrl.GetReports(treeCtx))
+	require.Equal(t, http.StatusOK, treeRec.Code)
+	var tree database.OrgNode
+	decodePayload(t, treeRec, &tree)
+	assert.Equal(t, ceo.ID, tree.ID)
+	require.Len(t, tree.Reports, 1)
+	assert.Equal(t, lead.ID, tree.Reports[0].ID)
+	require.Len(t, tree.Reports[0].Reports, 1)
+	assert.Equal(t, dev.ID, tree.Reports[0].Reports[0].ID)
+
+	//a stale If-Match fails, as for every other employee write
+	staleReq := httptest.NewRequest(http.MethodPut, "/employees/"+dev.ID.String()+"/manager", bytes.NewBufferString(`{"manager_id": null}`))
+	staleReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	staleReq.Header.Set("If-Match", `"`+strconv.FormatInt(dev.Version, 10)+`"`)
+	staleRec := httptest.NewRecorder()
+	staleCtx := e.NewContext(staleReq, staleRec)
+	staleCtx.SetParamNames("id")
+	staleCtx.SetParamValues(dev.ID.String())
+	serve(ctrl.SetManager, staleCtx)
+	assert.Equal(t, http.StatusPreconditionFailed, staleRec.Code, staleRec.Body.String())
+
+	//of two opposite assignments made at once only one may win
+	ann := createTestEmployee(t, e, ctrl, token, `{"name": "Ann Peer", "position": "Developer", "salary": 90000}`)
+	bob := createTestEmployee(t, e, ctrl, token, `{"name": "Bob Peer", "position": "Developer", "salary": 90000}`)
+	codes := make(chan int, 2)
+	var wg sync.WaitGroup
+	for _, pair := range [][2]string{{ann.ID.String(), bob.ID.String()}, {bob.ID.String(), ann.ID.String()}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- setManager(t, e, ctrl, token, pair[0], pair[1])
+		}()
+	}
+	wg.Wait()
+	close(codes)
+	var got []int
+	for code := range codes {
+		got = append(got, code)
+	}
+	assert.ElementsMatch(t, []int{http.StatusNoContent, http.StatusConflict}, got)
+}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// callImport uploads content as filename to the import handler
+func callImport(t *testing.T, e *echo.Echo, ctrl *controller.EmployeeController, token, query, filename string, content []byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/employees/import?"+query, &body)
+	req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	serve(ctrl.ImportEmployees, e.NewContext(req, rec))
+	return rec
+}
+
+func decodeImportReport(t *testing.T, rec *httptest.ResponseRecorder) database.ImportReport {
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var report database.ImportReport
+	decodePayload(t, rec, &report)
+	return report
+}
+
+func TestImportReportsRowErrors(t *testing.T) {
+	//with no valid rows to create the service is never called
+	ctrl := controller.NewEmployeeController(nil, &config.Config{})
+	e := newEcho()
+
+	csv := "\ufeffName,Position,Salary,Hired Date,Nickname\n" +
+		",Engineer,50000,2024-01-01,\n" +
+		"\n" +
+		"Jane Roe,Engineer,lots,yesterday,JR\n"
+	report := decodeImportReport(t, callImport(t, e, ctrl, "", "", "staff.csv", []byte(csv)))
+	assert.Equal(t, database.BulkBestEffort, report.Mode)
+	assert.Equal(t, 2, report.Rows, "blank rows are skipped")
+	assert.Equal(t, 0, report.Imported)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, []string{"Nickname"}, report.IgnoredColumns)
+	assert.Equal(t, []database.ImportRowError{
+		{Row: 2, Field: "name", Code: customerr.FieldRequired, Message: "name is required"},
+		{Row: 4, Field: "salary", Code: customerr.FieldInvalid, Message: "salary must be a number"},
+		{Row: 4, Field: "hired_date", Code: customerr.FieldInvalid, Message: "hired_date must be a date such as 2024-06-01"},
+	}, report.Errors)
+
+	//an all-or-nothing import with an invalid row creates nothing
+	book := excelize.NewFile()
+	sheet := book.GetSheetName(0)
+	require.NoError(t, book.SetSheetRow(sheet, "A1", &[]interface{}{"name", "position", "salary", "manager_id"}))
+	require.NoError(t, book.SetSheetRow(sheet, "A2", &[]interface{}{"Valid Person", "Engineer", 60000}))
+	require.NoError(t, book.SetSheetRow(sheet, "A4", &[]interface{}{"Bad Manager", "Engineer", 61000, "not-a-uuid"}))
+	xlsx, err := book.WriteToBuffer()
+	require.NoError(t, err)
+
+	report = decodeImportReport(t, callImport(t, e, ctrl, "", "mode=all_or_nothing", "staff.xlsx", xlsx.Bytes()))
+	assert.Equal(t, 2, report.Rows)
+	assert.Equal(t, 0, report.Imported)
+	assert.Equal(t, []database.ImportRowError{
+		{Row: 4, Field: "manager_id", Code: customerr.FieldInvalidUUID, Message: "manager_id must be a valid UUID"},
+	}, report.Errors)
+
+	cases := []struct {
+		name     string
+		query    string
+		filename string
+		content  string
+	}{
+		{"missing column", "", "staff.csv", "name,salary\nJane,1\n"},
+		{"empty file", "", "staff.csv", ""},
+		{"unsupported format", "", "staff.txt", "name,position,salary\n"},
+		{"not a workbook", "", "staff.xlsx", "name,position,salary\n"},
+		{"unknown mode", "mode=sometimes", "staff.csv", "name,position,salary\n"},
+		{"bad dry_run", "dry_run=maybe", "staff.csv", "name,position,salary\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := callImport(t, e, ctrl, "", tc.query, tc.filename, []byte(tc.content))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestImportEmployees(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+	e := newEcho()
+
+	csv := []byte("name,position,salary,hired_date\n" +
+		"Imported One,Developer,50000,2023-02-01\n" +
+		"Imported Two,Developer,-5,\n" +
+		"Imported Three,Developer,52000,2023-02-03\n")
+
+	report := decodeImportReport(t, callImport(t, e, ctrl, token, "dry_run=true", "staff.csv", csv))
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Imported, "rows that would be imported")
+	assert.Equal(t, 1, report.Failed)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, 3, report.Errors[0].Row)
+
+	report = decodeImportReport(t, callImport(t, e, ctrl, token, "", "staff.csv", csv))
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 3, report.Rows)
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, 1, report.Failed)
+}
@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaveDaysAndAccrual(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(time.DateOnly, s)
+		require.NoError(t, err)
+		return d
+	}
+
+	//2024-08-05 is a Monday
+	assert.Equal(t, 5.0, service.LeaveDays(date("2024-08-05"), date("2024-08-11")))
+	assert.Equal(t, 0.0, service.LeaveDays(date("2024-08-10"), date("2024-08-11")), "a weekend has no working days")
+	assert.Equal(t, 1.0, service.LeaveDays(date("2024-08-09"), date("2024-08-09")))
+
+	assert.Equal(t, 20.0, service.AccruedLeave(20, date("2023-01-01"), date("2023-12-31")), "a full year after 365 days")
+	assert.Equal(t, 4.0, service.AccruedLeave(20, date("2023-01-01"), date("2023-03-14")), "73 days is a fifth of a year")
+	assert.Equal(t, 0.05, service.AccruedLeave(20, date("2023-01-01"), date("2023-01-01")), "the hired date counts")
+	assert.Equal(t, 0.0, service.AccruedLeave(20, date("2023-01-01"), date("2022-12-31")))
+}
+
+func TestLeaveRequestsRejectInvalidInput(t *testing.T) {
+	//validation runs before the service, so none is needed
+	ctrl := controller.NewLeaveController(nil)
+	e := newEcho()
+	typeID := "00000000-0000-0000-0000-000000000001"
+
+	applications := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"missing leave type", `{"start_date": "2024-08-05T00:00:00Z", "end_date": "2024-08-09T00:00:00Z"}`, "leave_type_id"},
+		{"missing start date", `{"leave_type_id": "` + typeID + `", "end_date": "2024-08-09T00:00:00Z"}`, "start_date"},
+		{"end before start", `{"leave_type_id": "` + typeID + `", "start_date": "2024-08-09T00:00:00Z", "end_date": "2024-08-05T00:00:00Z"}`, "end_date"},
+	}
+	for _, tc := range applications {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/leave-requests", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			serve(ctrl.ApplyLeave, e.NewContext(req, rec))
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"field":"`+tc.field+`"`)
+		})
+	}
+
+	for _, query := range []string{
+		"to=2024-08-31",
+		"from=2024-08-01",
+		"from=2024-08-31&to=2024-08-01",
+		"from=2024-01-01&to=2025-06-01",
+		"from=2024-08-01&to=2024-08-31&department_id=" + typeID + "&manager_id=" + typeID,
+		"from=2024-08-01&to=2024-08-31&include_pending=maybe",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/leave-calendar?"+query, nil)
+		rec := httptest.NewRecorder()
+		serve(ctrl.GetLeaveCalendar, e.NewContext(req, rec))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func TestLeaveWorkflow(t *testing.T) {
+	cfg, ctrls, cleanup := setupControllers(t)
+	defer cleanup()
+	empCtrl, leaveCtrl := ctrls.Employee, ctrls.Leave
+
+	admin, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+	e := newEcho()
+	hired := time.Now().AddDate(-2, 0, 0).Format(time.RFC3339)
+	lead := createTestEmployee(t, e, empCtrl, admin, `{"name": "Leave Lead", "position": "Engineer", "salary": 90000, "hired_date": "`+hired+`"}`)
+	member := createTestEmployee(t, e, empCtrl, admin, `{"name": "Leave Member", "position": "Engineer", "salary": 60000, "hired_date": "`+hired+`"}`)
+	require.Equal(t, http.StatusOK, setManager(t, e, empCtrl, admin, member.ID.String(), lead.ID.String()))
+
+	memberToken, err := generateJWTForEmployee(cfg, auth.RoleViewer, member.ID)
+	require.NoError(t, err)
+	leadToken, err := generateJWTForEmployee(cfg, auth.RoleManager, lead.ID)
+	require.NoError(t, err)
+	outsider, err := generateJWTForRole(cfg, auth.RoleManager)
+	require.NoError(t, err)
+
+	//call runs handler as the holder of token, with id as the path parameter
+	call := func(handler echo.HandlerFunc, method, path, id, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		serve(middleware.JWTAuthMiddleware(cfg, stubRevocations{})(handler), c)
+		return rec
+	}
+	balance := func() database.LeaveBalance {
+		rec := call(leaveCtrl.GetLeaveBalances, http.MethodGet, "/employees/"+member.ID.String()+"/leave-balances", member.ID.String(), memberToken, "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var balances []database.LeaveBalance
+		decodePayload(t, rec, &balances)
+		for _, b := range balances {
+			if b.LeaveType == "Annual leave" {
+				return b
+			}
+		}
+		t.Fatal("no annual leave balance")
+		return database.LeaveBalance{}
+	}
+
+	annual := balance()
+	assert.Equal(t, 0.0, annual.Used)
+	assert.Greater(t, annual.Accrued, 30.0, "two years at 20 days a year")
+
+	//a Monday and Tuesday a few weeks out
+	start := time.Now().AddDate(0, 0, 21)
+	for start.Weekday() != time.Monday {
+		start = start.AddDate(0, 0, 1)
+	}
+	apply := func(from, to time.Time) *httptest.ResponseRecorder {
+		body := `{"leave_type_id": "` + annual.LeaveTypeID.String() + `", "start_date": "` + from.Format(time.RFC3339) +
+			`", "end_date": "` + to.Format(time.RFC3339) + `", "reason": "Family visit"}`
+		return call(leaveCtrl.ApplyLeave, http.MethodPost, "/leave-requests", "", memberToken, body)
+	}
+
+	rec := apply(start, start.AddDate(0, 0, 1))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var request database.LeaveRequest
+	decodePayload(t, rec, &request)
+	assert.Equal(t, database.LeaveStatusPending, request.Status)
+	assert.Equal(t, 2.0, request.Days)
+	assert.Equal(t, 2.0, balance().Pending)
+	id := request.ID.String()
+
+	assert.Equal(t, http.StatusConflict, apply(start.AddDate(0, 0, 1), start.AddDate(0, 0, 3)).Code, "overlaps the first request")
+	assert.Equal(t, http.StatusConflict, apply(start.AddDate(0, 0, 7), start.AddDate(1, 0, 0)).Code, "more days than accrued")
+
+	//only the employee's own manager or HR may decide
+	rec = call(leaveCtrl.ApproveLeave, http.MethodPost, "/leave-requests/"+id+"/approve", id, outsider, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = call(leaveCtrl.ApproveLeave, http.MethodPost, "/leave-requests/"+id+"/approve", id, memberToken, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = call(leaveCtrl.GetLeaveRequest, http.MethodGet, "/leave-requests/"+id, id, outsider, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = call(leaveCtrl.ApproveLeave, http.MethodPost, "/leave-requests/"+id+"/approve", id, leadToken, `{"note": "Enjoy"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	decodePayload(t, rec, &request)
+	assert.Equal(t, database.LeaveStatusApproved, request.Status)
+	assert.Equal(t, "Enjoy", request.DecisionNote)
+	require.NotNil(t, request.DecidedAt)
+	annual = balance()
+	assert.Equal(t, 2.0, annual.Used)
+	assert.Equal(t, 0.0, annual.Pending)
+
+	rec = call(leaveCtrl.RejectLeave, http.MethodPost, "/leave-requests/"+id+"/reject", id, leadToken, "")
+	assert.Equal(t, http.StatusConflict, rec.Code, "already decided")
+
+	//the approved leave shows on the manager's team calendar, without the reason
+	from, to := start.Format(time.DateOnly), start.AddDate(0, 0, 6).Format(time.DateOnly)
+	rec = call(leaveCtrl.GetLeaveCalendar, http.MethodGet, "/leave-calendar?from="+from+"&to="+to, "", leadToken, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var entries []database.LeaveCalendarEntry
+	decodePayload(t, rec, &entries)
+	require.Len(t, entries, 1)
+	assert.Equal(t, member.ID, entries[0].EmployeeID)
+	assert.NotContains(t, rec.Body.String(), "Family visit")
+
+	//only HR and the employees' own manager may see a team's leave
+	rec = call(leaveCtrl.GetLeaveCalendar, http.MethodGet, "/leave-calendar?from="+from+"&to="+to+"&manager_id="+lead.ID.String(), "", outsider, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code, "another manager's reports")
+	rec = call(leaveCtrl.GetLeaveCalendar, http.MethodGet, "/leave-calendar?from="+from+"&to="+to+"&manager_id="+lead.ID.String(), "", memberToken, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code, "a viewer")
+	rec = call(leaveCtrl.GetLeaveCalendar, http.MethodGet, "/leave-calendar?from="+from+"&to="+to+"&manager_id="+lead.ID.String(), "", admin, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	decodePayload(t, rec, &entries)
+	assert.Len(t, entries, 1)
+
+	//the employee calls it off before it starts and gets the days back
+	rec = call(leaveCtrl.CancelLeave, http.MethodPost, "/leave-requests/"+id+"/cancel", id, memberToken, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	decodePayload(t, rec, &request)
+	assert.Equal(t, database.LeaveStatusCancelled, request.Status)
+	assert.Equal(t, 0.0, balance().Used)
+}
+
+func TestUnpaidLeaveNeedsNoBalance(t *testing.T) {
+	cfg, ctrls, cleanup := setupControllers(t)
+	defer cleanup()
+	empCtrl, leaveCtrl := ctrls.Employee, ctrls.Leave
+
+	admin, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+	e := newEcho()
+
+	//call runs handler as admin, with id as the path parameter
+	call := func(handler echo.HandlerFunc, method, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer "+admin)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		serve(middleware.JWTAuthMiddleware(cfg, stubRevocations{})(handler), c)
+		return rec
+	}
+
+	//a type nobody ever accrues, taken by someone hired today
+	rec := call(leaveCtrl.CreateLeaveType, http.MethodPost, "", `{"name": "Unpaid `+uuid.NewString()+`", "days_per_year": 0, "unpaid": true}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var leaveType database.LeaveType
+	decodePayload(t, rec, &leaveType)
+	hire := createTestEmployee(t, e, empCtrl, admin, `{"name": "New Hire", "position": "Engineer", "salary": 50000}`)
+
+	start := time.Now().AddDate(0, 0, 14)
+	for start.Weekday() != time.Monday {
+		start = start.AddDate(0, 0, 1)
+	}
+	body := `{"employee_id": "` + hire.ID.String() + `", "leave_type_id": "` + leaveType.ID.String() + `", "start_date": "` + start.Format(time.RFC3339) +
+		`", "end_date": "` + start.AddDate(0, 0, 4).Format(time.RFC3339) + `"}`
+	rec = call(leaveCtrl.ApplyLeave, http.MethodPost, "", body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var request database.LeaveRequest
+	decodePayload(t, rec, &request)
+	id := request.ID.String()
+
+	rec = call(leaveCtrl.ApproveLeave, http.MethodPost, id, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = call(leaveCtrl.CancelLeave, http.MethodPost, id, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = call(leaveCtrl.GetLeaveBalances, http.MethodGet, hire.ID.String(), "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var balances []database.LeaveBalance
+	decodePayload(t, rec, &balances)
+	for _, b := range balances {
+		if b.LeaveTypeID == leaveType.ID {
+			assert.Equal(t, 0.0, b.Used, "unpaid leave draws on no balance")
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/lijuuu/EmployeeManagement/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	loaded, err := migrations.Load()
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+
+	assert.Equal(t, int64(1), loaded[0].Version)
+	assert.Equal(t, "create_employees", loaded[0].Name)
+	for i, m := range loaded {
+		assert.NotEmpty(t, m.Up, "migration %d has no up script", m.Version)
+		assert.NotEmpty(t, m.Down, "migration %d has no down script", m.Version)
+		if i > 0 {
+			assert.Greater(t, m.Version, loaded[i-1].Version)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmountParse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"72500.1", "72500.1"},
+		{"-0.0001", "-0.0001"},
+		{"+12", "12"},
+		{"007.50", "7.5"},
+		{".25", "0.25"},
+		{"1.25e3", "1250"},
+		{"125E-4", "0.0125"},
+		{"99999999999999.9999", "99999999999999.9999"},
+	}
+	for _, tc := range cases {
+		a, err := money.Parse(tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, a.String(), tc.in)
+	}
+
+	for in, want := range map[string]error{
+		"":                    money.ErrSyntax,
+		".":                   money.ErrSyntax,
+		"1,000":               money.ErrSyntax,
+		"lots":                money.ErrSyntax,
+		"1e":                  money.ErrSyntax,
+		"0.00001":             money.ErrPrecision,
+		"1e-5":                money.ErrPrecision,
+		"100000000000000":     money.ErrOutOfRange,
+		"1e14":                money.ErrOutOfRange,
+		"99999999999999.9999": nil,
+	} {
+		_, err := money.Parse(in)
+		assert.ErrorIs(t, err, want, in)
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	//the sum a float gets wrong
+	sum := money.MustParse("0.1").Add(money.MustParse("0.2"))
+	assert.Equal(t, money.MustParse("0.3"), sum)
+
+	assert.Equal(t, "10000.00", money.FromInt(120000).MulFrac(1, 12, 2).StringFixed(2))
+	assert.Equal(t, "4545.45", money.FromInt(120000).MulFrac(10, 12*22, 2).String())
+	assert.Equal(t, "0.01", money.MustParse("0.005").Round(2).String(), "half rounds away from zero")
+	assert.Equal(t, "-0.01", money.MustParse("-0.005").Round(2).String())
+	assert.Equal(t, "3", money.MustParse("2.5").Round(0).String())
+	assert.Equal(t, "909.09", money.MustParse("4545.45").Percent(money.FromInt(20), 2).String())
+	assert.Equal(t, "12.5", money.MustParse("2.5").Mul(money.FromInt(5), 4).String())
+	assert.Equal(t, "1250", money.FromInt(1250).StringFixed(0))
+	assert.Equal(t, "1250.500", money.MustParse("1250.5").StringFixed(3))
+
+	assert.Equal(t, 2, money.MustParse("1.25").Places())
+	assert.Equal(t, 0, money.FromInt(7).Places())
+	assert.Equal(t, -1, money.FromInt(1).Cmp(money.FromInt(2)))
+	assert.Equal(t, -1, money.FromInt(-3).Sign())
+	assert.True(t, money.Amount{}.IsZero())
+}
+
+func TestAmountJSON(t *testing.T) {
+	var body struct {
+		Salary money.Amount  `json:"salary"`
+		Bonus  *money.Amount `json:"bonus"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"salary": 72500.10, "bonus": "250.5"}`), &body))
+	assert.Equal(t, money.MustParse("72500.1"), body.Salary)
+	require.NotNil(t, body.Bonus)
+	assert.Equal(t, money.MustParse("250.5"), *body.Bonus)
+
+	out, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"salary": 72500.1, "bonus": 250.5}`, string(out))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"salary": 1.00001}`), &body))
+	assert.Error(t, json.Unmarshal([]byte(`{"salary": true}`), &body))
+}
+
+func TestAmountNumeric(t *testing.T) {
+	var n pgtype.Numeric
+	require.NoError(t, n.Scan("72500.1000"))
+	var a money.Amount
+	require.NoError(t, a.ScanNumeric(n))
+	assert.Equal(t, money.MustParse("72500.1"), a)
+
+	v, err := a.NumericValue()
+	require.NoError(t, err)
+	text, err := v.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "72500.1000", text)
+
+	require.NoError(t, n.Scan("1.00001"))
+	assert.ErrorIs(t, a.ScanNumeric(n), money.ErrPrecision)
+	assert.Error(t, a.ScanNumeric(pgtype.Numeric{}), "NULL is not an amount")
+}
+
+func TestCurrencies(t *testing.T) {
+	assert.True(t, money.ValidCurrency("USD"))
+	assert.True(t, money.ValidCurrency("JPY"))
+	assert.False(t, money.ValidCurrency("usd"))
+	assert.False(t, money.ValidCurrency("XAU"), "precious metals are not pay")
+	assert.Equal(t, 2, money.MinorUnits("EUR"))
+	assert.Equal(t, 0, money.MinorUnits("JPY"))
+	assert.Equal(t, 3, money.MinorUnits("KWD"))
+}
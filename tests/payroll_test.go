@@ -0,0 +1,263 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/lijuuu/EmployeeManagement/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputePayslip(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(time.DateOnly, s)
+		require.NoError(t, err)
+		return d
+	}
+	//August 2024 has 22 working days
+	start, end := date("2024-08-01"), date("2024-08-31")
+	rules := []database.PayrollRule{
+		{Name: "Meal allowance", Kind: database.PayrollAllowance, Calculation: database.PayrollFixed, Amount: money.FromInt(110), Currency: "USD"},
+		{Name: "Income tax", Kind: database.PayrollDeduction, Calculation: database.PayrollPercent, Amount: money.FromInt(20)},
+	}
+	employee := func() database.PayrollEmployee {
+		return database.PayrollEmployee{ID: uuid.New(), Name: "Pay Roll", Position: "Engineer", Salary: money.FromInt(120000), Currency: "USD", HiredDate: date("2020-01-01")}
+	}
+
+	slip := service.ComputePayslip(employee(), rules, start, end)
+	assert.Equal(t, 22, slip.WorkingDays)
+	assert.Equal(t, 22, slip.PaidDays)
+	assert.Equal(t, "USD", slip.Currency)
+	assert.Equal(t, money.FromInt(10000), slip.Gross, "a twelfth of the annual salary")
+	assert.Equal(t, money.FromInt(110), slip.Allowances)
+	assert.Equal(t, money.FromInt(2000), slip.Deductions)
+	assert.Equal(t, money.FromInt(8110), slip.Net)
+	assert.Equal(t, []database.PayslipLine{
+		{Name: "Meal allowance", Kind: database.PayrollAllowance, Amount: money.FromInt(110)},
+		{Name: "Income tax", Kind: database.PayrollDeduction, Amount: money.FromInt(2000)},
+	}, slip.Lines)
+
+	hired := employee()
+	hired.HiredDate = date("2024-08-19")
+	slip = service.ComputePayslip(hired, rules, start, end)
+	assert.Equal(t, 10, slip.PaidDays)
+	assert.Equal(t, money.MustParse("4545.45"), slip.Gross)
+	assert.Equal(t, money.FromInt(50), slip.Allowances, "fixed amounts are pro rata for the days paid")
+	assert.Equal(t, money.MustParse("909.09"), slip.Deductions)
+	assert.Equal(t, money.MustParse("3686.36"), slip.Net)
+
+	left := employee()
+	terminated := date("2024-08-09")
+	left.TerminationDate = &terminated
+	slip = service.ComputePayslip(left, rules, start, end)
+	assert.Equal(t, 7, slip.PaidDays, "paid through the termination date")
+	assert.Equal(t, money.MustParse("3181.82"), slip.Gross)
+
+	onLeave := employee()
+	onLeave.UnpaidLeave = []database.LeaveRequest{{StartDate: date("2024-08-12"), EndDate: date("2024-08-18")}}
+	slip = service.ComputePayslip(onLeave, rules, start, end)
+	assert.Equal(t, 5, slip.UnpaidLeaveDays)
+	assert.Equal(t, 17, slip.PaidDays)
+	assert.Equal(t, money.MustParse("7727.27"), slip.Gross)
+
+	raised := employee()
+	raised.Salaries = []database.SalaryRecord{
+		{Salary: money.FromInt(96000), Currency: "USD", EffectiveDate: date("2023-01-01")},
+		{Salary: money.FromInt(120000), Currency: "USD", EffectiveDate: date("2024-08-16")},
+	}
+	slip = service.ComputePayslip(raised, nil, start, end)
+	assert.Equal(t, money.FromInt(9000), slip.Gross, "11 days at 8000 a month and 11 at 10000")
+	assert.Equal(t, money.FromInt(120000), slip.AnnualSalary)
+	assert.Equal(t, money.FromInt(9000), slip.Net)
+	assert.Empty(t, slip.Lines)
+
+	//yen have no minor unit, and the dollar allowance is not paid in them
+	yen := employee()
+	yen.Salary, yen.Currency = money.FromInt(7000000), "JPY"
+	yen.Salaries = []database.SalaryRecord{
+		{Salary: money.FromInt(96000), Currency: "USD", EffectiveDate: date("2023-01-01")},
+		{Salary: money.FromInt(7000000), Currency: "JPY", EffectiveDate: date("2024-08-16")},
+	}
+	yen.HiredDate = date("2024-08-19")
+	slip = service.ComputePayslip(yen, rules, start, end)
+	assert.Equal(t, "JPY", slip.Currency)
+	assert.Equal(t, money.FromInt(265152), slip.Gross, "10/22 of 7000000/12 rounded to the yen")
+	assert.Equal(t, []database.PayslipLine{
+		{Name: "Income tax", Kind: database.PayrollDeduction, Amount: money.FromInt(53030)},
+	}, slip.Lines)
+	assert.Equal(t, money.FromInt(212122), slip.Net)
+}
+
+func TestPayrollRejectsInvalidInput(t *testing.T) {
+	//validation runs before the service, so none is needed
+	ctrl := controller.NewPayrollController(nil)
+	e := newEcho()
+
+	post := func(handler echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		serve(handler, e.NewContext(req, rec))
+		return rec
+	}
+
+	for _, body := range []string{`{}`, `{"period": "2024-13"}`, `{"period": "August"}`, `{"period": "2024-08-01"}`} {
+		rec := post(ctrl.CreatePayrollRun, body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		assert.Contains(t, rec.Body.String(), `"field":"period"`, body)
+	}
+
+	rules := []struct {
+		body  string
+		field string
+	}{
+		{`{"kind": "allowance", "calculation": "fixed", "amount": 100}`, "name"},
+		{`{"name": "Bonus", "kind": "bonus", "calculation": "fixed", "amount": 100}`, "kind"},
+		{`{"name": "Bonus", "kind": "allowance", "calculation": "tiered", "amount": 100}`, "calculation"},
+		{`{"name": "Bonus", "kind": "allowance", "calculation": "fixed", "amount": -1}`, "amount"},
+		{`{"name": "Bonus", "kind": "allowance", "calculation": "fixed", "amount": 100, "currency": "usd"}`, "currency"},
+	}
+	for _, tc := range rules {
+		rec := post(ctrl.CreatePayrollRule, tc.body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, tc.body)
+		assert.Contains(t, rec.Body.String(), `"field":"`+tc.field+`"`, tc.body)
+	}
+}
+
+func TestPayrollWorkflow(t *testing.T) {
+	cfg, ctrls, cleanup := setupControllers(t)
+	defer cleanup()
+	empCtrl, payCtrl := ctrls.Employee, ctrls.Payroll
+
+	admin, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+	hr, err := generateJWTForRole(cfg, auth.RoleHR)
+	require.NoError(t, err)
+	e := newEcho()
+
+	//there is one run per month, so each test run pays a month of its own long ago
+	period := time.Date(1901, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, rand.Intn(1000), 0)
+	emp := createTestEmployee(t, e, empCtrl, admin, `{"name": "Payroll Employee", "position": "Engineer", "salary": 120000, "hired_date": "`+period.Format(time.RFC3339)+`"}`)
+	empToken, err := generateJWTForEmployee(cfg, auth.RoleViewer, emp.ID)
+	require.NoError(t, err)
+	outsider, err := generateJWTForRole(cfg, auth.RoleViewer)
+	require.NoError(t, err)
+
+	//call runs handler as the holder of token, with id as the path parameter
+	call := func(handler echo.HandlerFunc, method, id, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		serve(middleware.JWTAuthMiddleware(cfg, stubRevocations{})(handler), c)
+		return rec
+	}
+
+	ruleName := fmt.Sprintf("Test allowance %s", uuid.NewString())
+	rec := call(payCtrl.CreatePayrollRule, http.MethodPost, "", hr, `{"name": "`+ruleName+`", "kind": "allowance", "calculation": "fixed", "amount": 250, "currency": "USD"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var rule database.PayrollRule
+	decodePayload(t, rec, &rule)
+	defer call(payCtrl.DeletePayrollRule, http.MethodDelete, rule.ID.String(), hr, "")
+
+	rec = call(payCtrl.CreatePayrollRun, http.MethodPost, "", hr, `{"period": "`+period.Format("2006-01")+`"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var run database.PayrollRun
+	decodePayload(t, rec, &run)
+	assert.Equal(t, database.PayrollStatusDraft, run.Status)
+	assert.Equal(t, period, run.PeriodStart)
+	require.NotEmpty(t, run.Totals)
+	assert.Equal(t, "USD", run.Totals[0].Currency)
+	runID := run.ID.String()
+
+	rec = call(payCtrl.CreatePayrollRun, http.MethodPost, "", hr, `{"period": "`+period.Format("2006-01")+`"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code, "one run per month")
+
+	payslip := func() database.Payslip {
+		rec := call(payCtrl.ListPayrollRunPayslips, http.MethodGet, runID, hr, "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var slips []database.Payslip
+		decodePayload(t, rec, &slips)
+		for _, slip := range slips {
+			if slip.EmployeeID == emp.ID {
+				return slip
+			}
+		}
+		t.Fatal("no payslip for the employee")
+		return database.Payslip{}
+	}
+	slip := payslip()
+	assert.Equal(t, money.FromInt(10000), slip.Gross)
+	assert.Contains(t, slip.Lines, database.PayslipLine{Name: ruleName, Kind: database.PayrollAllowance, Amount: money.FromInt(250)})
+
+	//a draft picks up rule changes when recomputed
+	rec = call(payCtrl.UpdatePayrollRule, http.MethodPut, rule.ID.String(), hr, `{"name": "`+ruleName+`", "kind": "allowance", "calculation": "fixed", "amount": 300, "currency": "USD"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = call(payCtrl.RecomputePayrollRun, http.MethodPost, runID, hr, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, payslip().Lines, database.PayslipLine{Name: ruleName, Kind: database.PayrollAllowance, Amount: money.FromInt(300)})
+
+	//pay ends at the termination date, and clearing it restores the full month
+	rec = call(empCtrl.SetTermination, http.MethodPut, emp.ID.String(), hr, `{"termination_date": "`+period.AddDate(0, 0, -1).Format(time.RFC3339)+`"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "terminated before being hired")
+	rec = call(empCtrl.SetTermination, http.MethodPut, emp.ID.String(), hr, `{"termination_date": "`+period.AddDate(0, 0, 14).Format(time.RFC3339)+`"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var terminated database.Employee
+	decodePayload(t, rec, &terminated)
+	require.NotNil(t, terminated.TerminationDate)
+	require.Equal(t, http.StatusOK, call(payCtrl.RecomputePayrollRun, http.MethodPost, runID, hr, "").Code)
+	assert.Equal(t, -1, payslip().Gross.Cmp(money.FromInt(10000)), "paid only up to the termination date")
+	rec = call(empCtrl.SetTermination, http.MethodPut, emp.ID.String(), hr, `{"termination_date": null}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, http.StatusOK, call(payCtrl.RecomputePayrollRun, http.MethodPost, runID, hr, "").Code)
+	assert.Equal(t, money.FromInt(10000), payslip().Gross)
+
+	assert.Equal(t, http.StatusConflict, call(payCtrl.FinalizePayrollRun, http.MethodPost, runID, admin, "").Code, "a draft must be approved first")
+	rec = call(payCtrl.ApprovePayrollRun, http.MethodPost, runID, admin, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	decodePayload(t, rec, &run)
+	assert.Equal(t, database.PayrollStatusApproved, run.Status)
+	require.NotNil(t, run.ApprovedAt)
+	assert.Equal(t, http.StatusConflict, call(payCtrl.RecomputePayrollRun, http.MethodPost, runID, hr, "").Code)
+
+	//employees only see payslips of finalized runs
+	rec = call(payCtrl.ListEmployeePayslips, http.MethodGet, emp.ID.String(), empToken, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var own []database.Payslip
+	decodePayload(t, rec, &own)
+	assert.Empty(t, own)
+
+	rec = call(payCtrl.FinalizePayrollRun, http.MethodPost, runID, admin, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	decodePayload(t, rec, &run)
+	assert.Equal(t, database.PayrollStatusFinalized, run.Status)
+	assert.Equal(t, http.StatusConflict, call(payCtrl.RecomputePayrollRun, http.MethodPost, runID, hr, "").Code, "a finalized run cannot be recomputed")
+	assert.Equal(t, http.StatusConflict, call(payCtrl.ApprovePayrollRun, http.MethodPost, runID, admin, "").Code)
+
+	rec = call(payCtrl.ListEmployeePayslips, http.MethodGet, emp.ID.String(), empToken, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	decodePayload(t, rec, &own)
+	require.Len(t, own, 1)
+	assert.Equal(t, run.ID, own[0].RunID)
+	assert.Equal(t, database.PayrollStatusFinalized, own[0].Status)
+	rec = call(payCtrl.ListEmployeePayslips, http.MethodGet, emp.ID.String(), outsider, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/lijuuu/EmployeeManagement/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSalaryRequestsRejectInvalidInput(t *testing.T) {
+	//validation runs before the service, so none is needed
+	ctrl := controller.NewEmployeeController(nil, &config.Config{})
+	e := newEcho()
+	id := "00000000-0000-0000-0000-000000000001"
+
+	cases := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"missing effective date", `{"salary": 50000}`, "effective_date"},
+		{"future effective date", `{"salary": 50000, "effective_date": "2999-01-01T00:00:00Z"}`, "effective_date"},
+		{"non-positive salary", `{"salary": 0, "effective_date": "2024-01-01T00:00:00Z"}`, "salary"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/employees/"+id+"/salary-history", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+			serve(ctrl.ChangeSalary, c)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"field":"`+tc.field+`"`)
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/employees/"+id+"/salary?as_of=last-week", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	serve(ctrl.GetSalaryAsOf, c)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSalaryHistory(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	hr, err := generateJWTForRole(cfg, auth.RoleHR)
+	require.NoError(t, err)
+	e := newEcho()
+	emp := createTestEmployee(t, e, ctrl, hr, `{"name": "Salary Timeline", "position": "Engineer", "salary": 50000, "hired_date": "2023-01-10T00:00:00Z"}`)
+	id := emp.ID.String()
+
+	//call runs handler for path as the holder of token
+	call := func(handler echo.HandlerFunc, method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		serve(middleware.JWTAuthMiddleware(cfg, stubRevocations{})(handler), c)
+		return rec
+	}
+	history := func() []database.SalaryRecord {
+		rec := call(ctrl.ListSalaryHistory, http.MethodGet, "/employees/"+id+"/salary-history", hr, "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var records []database.SalaryRecord
+		decodePayload(t, rec, &records)
+		return records
+	}
+	salaryOn := func(date string) *httptest.ResponseRecorder {
+		return call(ctrl.GetSalaryAsOf, http.MethodGet, "/employees/"+id+"/salary?as_of="+date, hr, "")
+	}
+
+	records := history()
+	require.Len(t, records, 1)
+	assert.Equal(t, money.FromInt(50000), records[0].Salary)
+	assert.Equal(t, "2023-01-10", records[0].EffectiveDate.Format("2006-01-02"))
+	assert.Equal(t, service.SalaryReasonHire, records[0].Reason)
+
+	//a raise through PATCH is recorded as effective today
+	rec := call(ctrl.PatchEmployee, http.MethodPatch, "/employees/"+id, hr, `{"salary": 55000}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	records = history()
+	require.Len(t, records, 2)
+	assert.Equal(t, money.FromInt(55000), records[0].Salary)
+	require.NotNil(t, records[0].ApprovedBy, "the approver is the caller")
+
+	//a backdated change fills in the timeline without replacing the later salary
+	rec = call(ctrl.ChangeSalary, http.MethodPost, "/employees/"+id+"/salary-history", hr,
+		`{"salary": 52000, "effective_date": "2023-06-01T00:00:00Z", "reason": "Mid-year review"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Len(t, history(), 3)
+
+	var current database.SalaryRecord
+	rec = salaryOn("2023-07-15")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	decodePayload(t, rec, &current)
+	assert.Equal(t, money.FromInt(52000), current.Salary)
+	assert.Equal(t, "Mid-year review", current.Reason)
+
+	rec = salaryOn("2023-03-01")
+	decodePayload(t, rec, &current)
+	assert.Equal(t, money.FromInt(50000), current.Salary)
+
+	assert.Equal(t, http.StatusNotFound, salaryOn("2022-12-31").Code, "before the history starts")
+
+	rec = call(ctrl.ChangeSalary, http.MethodPost, "/employees/"+id+"/salary-history", hr,
+		`{"salary": 40000, "effective_date": "2022-01-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "before the hired date")
+
+	//salary history follows the same visibility rules as the salary itself
+	viewer, err := generateJWTForRole(cfg, auth.RoleViewer)
+	require.NoError(t, err)
+	rec = call(ctrl.ListSalaryHistory, http.MethodGet, "/employees/"+id+"/salary-history", viewer, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), string(customerr.CodeForbidden))
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchEmployeesInvalidQuery(t *testing.T) {
+	//invalid parameters are rejected before the service is called
+	ctrl := controller.NewEmployeeController(nil, &config.Config{})
+	e := newEcho()
+
+	for _, query := range []string{"", "q=", "q=%20%20", "q=" + strings.Repeat("a", 201), "q=jane&limit=0", "q=jane&offset=-1"} {
+		req := httptest.NewRequest(http.MethodGet, "/employees/search?"+query, nil)
+		rec := httptest.NewRecorder()
+		serve(ctrl.SearchEmployees, e.NewContext(req, rec))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func TestSearchEmployees(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+	e := newEcho()
+
+	byName := createTestEmployee(t, e, ctrl, token, `{"name": "Quillon Vexmoor", "position": "Engineer", "salary": 70000}`)
+	byPosition := createTestEmployee(t, e, ctrl, token, `{"name": "Ada Quillon-Smith", "position": "Quillon Cartographer", "salary": 71000}`)
+	markup := createTestEmployee(t, e, ctrl, token, `{"name": "Zeb <b>Vexmoor</b>", "position": "Analyst", "salary": 72000}`)
+
+	search := func(q string) database.EmployeeSearchViewPage {
+		req := httptest.NewRequest(http.MethodGet, "/employees/search?q="+url.QueryEscape(q), nil)
+		rec := httptest.NewRecorder()
+		serve(ctrl.SearchEmployees, e.NewContext(req, rec))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var page database.EmployeeSearchViewPage
+		decodePayload(t, rec, &page)
+		return page
+	}
+	ids := func(page database.EmployeeSearchViewPage) []string {
+		var out []string
+		for _, hit := range page.Results {
+			out = append(out, hit.ID.String())
+		}
+		return out
+	}
+
+	page := search("quillon")
+	require.GreaterOrEqual(t, len(page.Results), 2)
+	assert.Equal(t, byName.ID, page.Results[0].ID, "a name match outranks a position match")
+	assert.Equal(t, "<mark>Quillon</mark> Vexmoor", page.Results[0].Highlights.Name)
+	assert.Contains(t, ids(page), byPosition.ID.String())
+	assert.Nil(t, page.Results[0].Salary, "anonymous callers do not see salaries")
+
+	//a misspelling is still found through trigram similarity
+	assert.Contains(t, ids(search("Quilon Vexmor")), byName.ID.String())
+
+	//names are escaped in highlights so they can be rendered as HTML
+	page = search("vexmoor")
+	for _, hit := range page.Results {
+		if hit.ID == markup.ID {
+			assert.Equal(t, "Zeb &lt;b&gt;<mark>Vexmoor</mark>&lt;/b&gt;", hit.Highlights.Name)
+		}
+	}
+	assert.Contains(t, ids(page), markup.ID.String())
+
+	//deleting invalidates cached results
+	req := httptest.NewRequest(http.MethodDelete, "/employees/"+markup.ID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(markup.ID.String())
+	require.NoError(t, ctrl.DeleteEmployee(c))
+	assert.NotContains(t, ids(search("vexmoor")), markup.ID.String())
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftDeleteRestoreAndPurge(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+
+	e := newEcho()
+	manager := createTestEmployee(t, e, ctrl, token, `{"name": "Deleted Manager", "position": "Lead", "salary": 100000}`)
+	report := createTestEmployee(t, e, ctrl, token, `{"name": "Orphaned Report", "position": "Engineer", "salary": 80000}`)
+	require.Equal(t, http.StatusNoContent, setManager(t, e, ctrl, token, report.ID.String(), manager.ID.String()))
+
+	//call runs handler with the employee ID as the :id path parameter
+	call := func(handler echo.HandlerFunc, method, target, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		serve(handler, c)
+		return rec
+	}
+
+	id := manager.ID.String()
+	require.Equal(t, http.StatusNoContent, call(ctrl.DeleteEmployee, http.MethodDelete, "/employees/"+id, id).Code)
+	assert.Equal(t, http.StatusNotFound, call(ctrl.GetEmployee, http.MethodGet, "/employees/"+id, id).Code)
+	assert.Equal(t, http.StatusNotFound, call(ctrl.DeleteEmployee, http.MethodDelete, "/employees/"+id, id).Code, "already deleted")
+
+	//the report no longer points at a deleted manager
+	var orphan database.Employee
+	decodePayload(t, call(ctrl.GetEmployee, http.MethodGet, "/employees/"+report.ID.String(), report.ID.String()), &orphan)
+	assert.Nil(t, orphan.ManagerID)
+
+	deletedRec := call(ctrl.ListDeletedEmployees, http.MethodGet, "/employees/deleted?limit=100", "")
+	require.Equal(t, http.StatusOK, deletedRec.Code)
+	var deleted database.EmployeePage
+	decodePayload(t, deletedRec, &deleted)
+	found := false
+	for _, emp := range deleted.Employees {
+		if emp.ID == manager.ID {
+			found = true
+			assert.NotNil(t, emp.DeletedAt)
+		}
+	}
+	assert.True(t, found, "deleted employee is listed")
+
+	restoreRec := call(ctrl.RestoreEmployee, http.MethodPost, "/employees/"+id+"/restore", id)
+	require.Equal(t, http.StatusOK, restoreRec.Code)
+	var restored database.Employee
+	decodePayload(t, restoreRec, &restored)
+	assert.Equal(t, manager.ID, restored.ID)
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, http.StatusOK, call(ctrl.GetEmployee, http.MethodGet, "/employees/"+id, id).Code)
+	assert.Equal(t, http.StatusNotFound, call(ctrl.RestoreEmployee, http.MethodPost, "/employees/"+id+"/restore", id).Code, "not deleted")
+
+	//with no retention, a fresh deletion is purged straight away and cannot be restored
+	require.Equal(t, http.StatusNoContent, call(ctrl.DeleteEmployee, http.MethodDelete, "/employees/"+id, id).Code)
+	retention := cfg.SoftDeleteRetention
+	cfg.SoftDeleteRetention = 0
+	defer func() { cfg.SoftDeleteRetention = retention }()
+
+	purgeRec := call(ctrl.PurgeEmployees, http.MethodPost, "/employees/purge", "")
+	require.Equal(t, http.StatusOK, purgeRec.Code)
+	var purged database.PurgeResult
+	decodePayload(t, purgeRec, &purged)
+	assert.Contains(t, purged.EmployeeIDs, manager.ID)
+	assert.Equal(t, len(purged.EmployeeIDs), purged.Purged)
+	assert.Equal(t, http.StatusNotFound, call(ctrl.RestoreEmployee, http.MethodPost, "/employees/"+id+"/restore", id).Code)
+}
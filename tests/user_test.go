@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//stubRevocations denylists a fixed set of token IDs
+type stubRevocations map[string]bool
+
+func (s stubRevocations) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return s[jti], nil
+}
+
+func TestRequirePermission(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "test-secret"}
+
+	e := newEcho()
+	e.PUT("/employees/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, middleware.JWTAuthMiddleware(cfg, stubRevocations{}), middleware.RequirePermission(auth.PermEmployeesWrite))
+
+	cases := map[string]int{
+		auth.RoleAdmin:   http.StatusNoContent,
+		auth.RoleHR:      http.StatusNoContent,
+		auth.RoleManager: http.StatusForbidden,
+		auth.RoleViewer:  http.StatusForbidden,
+	}
+	for role, want := range cases {
+		token, err := generateJWTForRole(cfg, role)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPut, "/employees/123", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, want, rec.Code, role)
+	}
+
+	//tokens without a role, as issued before roles existed, are rejected
+	legacy, err := generateJWTForRole(cfg, "")
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPut, "/employees/123", nil)
+	req.Header.Set("Authorization", "Bearer "+legacy)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRevokedTokenRejected(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "test-secret"}
+
+	token, err := generateJWTForRole(cfg, auth.RoleAdmin)
+	require.NoError(t, err)
+	claims, err := auth.ParseToken(cfg.JWTSecret, token)
+	require.NoError(t, err)
+
+	e := newEcho()
+	e.GET("/me", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, middleware.JWTAuthMiddleware(cfg, stubRevocations{claims.Id: true}))
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRefreshRotationAndLogout(t *testing.T) {
+	cfg, ctrls, cleanup := setupControllers(t)
+	defer cleanup()
+	require.NoError(t, ctrls.Users.EnsureAdmin(context.Background(), cfg.AdminEmail, cfg.AdminPassword))
+	ctrl := ctrls.User
+
+	e := newEcho()
+
+	post := func(handler echo.HandlerFunc, path, body, bearer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+			//run the handler behind the auth middleware so claims are set
+			handler = middleware.JWTAuthMiddleware(cfg, stubRevocations{})(handler)
+		}
+		serve(handler, c)
+		return rec
+	}
+
+	loginRec := post(ctrl.Login, "/login", `{"email": "`+cfg.AdminEmail+`", "password": "`+cfg.AdminPassword+`"}`, "")
+	require.Equal(t, http.StatusOK, loginRec.Code)
+	var first database.TokenResponse
+	decodePayload(t, loginRec, &first)
+
+	refreshRec := post(ctrl.Refresh, "/refresh", `{"refresh_token": "`+first.RefreshToken+`"}`, "")
+	require.Equal(t, http.StatusOK, refreshRec.Code)
+	var second database.TokenResponse
+	decodePayload(t, refreshRec, &second)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	//the rotated token is single use, and reusing it ends the session
+	reuseRec := post(ctrl.Refresh, "/refresh", `{"refresh_token": "`+first.RefreshToken+`"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, reuseRec.Code)
+	revokedRec := post(ctrl.Refresh, "/refresh", `{"refresh_token": "`+second.RefreshToken+`"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, revokedRec.Code)
+
+	logoutRec := post(ctrl.Logout, "/logout", `{}`, second.AccessToken)
+	assert.Equal(t, http.StatusNoContent, logoutRec.Code)
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/controller"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/money"
+	"github.com/lijuuu/EmployeeManagement/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmployeeRequestValidation(t *testing.T) {
+	v := validation.New([]string{"Engineer", "Analyst"})
+	today := time.Now().UTC()
+
+	valid := database.EmployeeCreateRequest{Name: "Jane Doe", Position: "Engineer", Salary: money.FromInt(60000), HiredDate: today}
+	require.NoError(t, v.Validate(&valid))
+
+	//the hired date is optional and defaults to today
+	noDate := valid
+	noDate.HiredDate = time.Time{}
+	require.NoError(t, v.Validate(&noDate))
+
+	cases := []struct {
+		name   string
+		mutate func(r *database.EmployeeCreateRequest)
+		field  string
+		code   string
+	}{
+		{"blank name", func(r *database.EmployeeCreateRequest) { r.Name = "   " }, "name", customerr.FieldRequired},
+		{"long name", func(r *database.EmployeeCreateRequest) { r.Name = string(bytes.Repeat([]byte("a"), 201)) }, "name", customerr.FieldOutOfRange},
+		{"missing position", func(r *database.EmployeeCreateRequest) { r.Position = "" }, "position", customerr.FieldRequired},
+		{"unlisted position", func(r *database.EmployeeCreateRequest) { r.Position = "Wizard" }, "position", customerr.FieldInvalidValue},
+		{"negative salary", func(r *database.EmployeeCreateRequest) { r.Salary = money.FromInt(-1) }, "salary", customerr.FieldOutOfRange},
+		{"huge salary", func(r *database.EmployeeCreateRequest) { r.Salary = money.FromInt(1e12) }, "salary", customerr.FieldOutOfRange},
+		{"lower-case currency", func(r *database.EmployeeCreateRequest) { r.Currency = "usd" }, "currency", customerr.FieldInvalidValue},
+		{"unknown currency", func(r *database.EmployeeCreateRequest) { r.Currency = "XYZ" }, "currency", customerr.FieldInvalidValue},
+		{"future hire", func(r *database.EmployeeCreateRequest) { r.HiredDate = today.AddDate(0, 0, 2) }, "hired_date", customerr.FieldOutOfRange},
+		{"ancient hire", func(r *database.EmployeeCreateRequest) { r.HiredDate = time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC) }, "hired_date", customerr.FieldOutOfRange},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := valid
+			tc.mutate(&req)
+			err := v.Validate(&req)
+			require.ErrorIs(t, err, customerr.ErrValidation)
+
+			var domainErr *customerr.Error
+			require.ErrorAs(t, err, &domainErr)
+			require.Len(t, domainErr.Fields, 1)
+			assert.Equal(t, tc.field, domainErr.Fields[0].Field)
+			assert.Equal(t, tc.code, domainErr.Fields[0].Code)
+		})
+	}
+
+	//any position is accepted without a whitelist
+	open := valid
+	open.Position = "Wizard"
+	assert.NoError(t, validation.New(nil).Validate(&open))
+}
+
+func TestCreateEmployeeRejectsInvalidBody(t *testing.T) {
+	//validation runs before the service, so none is needed
+	ctrl := controller.NewEmployeeController(nil, &config.Config{})
+	e := newEcho()
+
+	body := `{"id": "00000000-0000-0000-0000-000000000001", "name": "", "position": "Engineer", "salary": -5, "hired_date": "2999-01-01T00:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/employees", bytes.NewBufferString(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	serve(ctrl.CreateEmployee, e.NewContext(req, rec))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var problem customerr.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, customerr.CodeValidationFailed, problem.Code)
+
+	fields := map[string]string{}
+	for _, fe := range problem.Errors {
+		fields[fe.Field] = fe.Code
+	}
+	assert.Equal(t, map[string]string{
+		"name":       customerr.FieldRequired,
+		"salary":     customerr.FieldOutOfRange,
+		"hired_date": customerr.FieldOutOfRange,
+	}, fields)
+}
+
+func TestPatchEmployeeRejectsInvalidPatch(t *testing.T) {
+	ctrl := controller.NewEmployeeController(nil, &config.Config{})
+	e := newEcho()
+	id := "00000000-0000-0000-0000-000000000001"
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		field       string
+		code        string
+	}{
+		{"wrong media type", echo.MIMETextPlain, `{"salary": 1}`, http.StatusUnsupportedMediaType, "", ""},
+		{"not an object", controller.MIMEMergePatchJSON, `[{"salary": 1}]`, http.StatusBadRequest, "", ""},
+		{"removing a field", controller.MIMEMergePatchJSON, `{"name": null}`, http.StatusBadRequest, "name", customerr.FieldRequired},
+		{"read-only field", controller.MIMEMergePatchJSON, `{"id": "` + id + `"}`, http.StatusBadRequest, "id", customerr.FieldReadOnly},
+		{"termination date", controller.MIMEMergePatchJSON, `{"termination_date": "2025-03-31T00:00:00Z"}`, http.StatusBadRequest, "termination_date", customerr.FieldReadOnly},
+		{"unknown field", controller.MIMEMergePatchJSON, `{"nickname": "JD"}`, http.StatusBadRequest, "nickname", customerr.FieldUnknown},
+		{"wrong type", controller.MIMEMergePatchJSON, `{"salary": "lots"}`, http.StatusBadRequest, "salary", customerr.FieldInvalid},
+		{"bad date", controller.MIMEMergePatchJSON, `{"hired_date": "yesterday"}`, http.StatusBadRequest, "hired_date", customerr.FieldInvalid},
+		{"future date", echo.MIMEApplicationJSON, `{"hired_date": "2999-01-01T00:00:00Z"}`, http.StatusBadRequest, "hired_date", customerr.FieldOutOfRange},
+		{"invalid value", controller.MIMEMergePatchJSON, `{"salary": 0}`, http.StatusBadRequest, "salary", customerr.FieldOutOfRange},
+		{"too precise", controller.MIMEMergePatchJSON, `{"salary": 1.00001}`, http.StatusBadRequest, "salary", customerr.FieldInvalid},
+		{"unknown currency", controller.MIMEMergePatchJSON, `{"currency": "XYZ"}`, http.StatusBadRequest, "currency", customerr.FieldInvalidValue},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/employees/"+id, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, tc.contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+			serve(ctrl.PatchEmployee, c)
+
+			require.Equal(t, tc.status, rec.Code)
+			if tc.field == "" {
+				return
+			}
+			var problem customerr.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			require.Len(t, problem.Errors, 1)
+			assert.Equal(t, tc.field, problem.Errors[0].Field)
+			assert.Equal(t, tc.code, problem.Errors[0].Code)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lijuuu/EmployeeManagement/auth"
+	"github.com/lijuuu/EmployeeManagement/config"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/database"
+	"github.com/lijuuu/EmployeeManagement/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//generateJWTForEmployee creates a token for a user linked to employeeID
+func generateJWTForEmployee(cfg *config.Config, role string, employeeID uuid.UUID) (string, error) {
+	return auth.NewToken(cfg.JWTSecret, &database.User{
+		ID:         uuid.New(),
+		Email:      "linked@example.com",
+		Role:       role,
+		EmployeeID: &employeeID,
+	}, time.Hour)
+}
+
+func TestOptionalJWTAuth(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "test-secret"}
+
+	e := newEcho()
+	e.GET("/employees", func(c echo.Context) error {
+		if middleware.ClaimsFrom(c) == nil {
+			return c.String(http.StatusOK, "anonymous")
+		}
+		return c.String(http.StatusOK, middleware.ClaimsFrom(c).Role)
+	}, middleware.OptionalJWTAuthMiddleware(cfg, stubRevocations{}))
+
+	get := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/employees", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "anonymous", rec.Body.String())
+
+	token, err := generateJWTForRole(cfg, auth.RoleViewer)
+	require.NoError(t, err)
+	rec = get("Bearer " + token)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, auth.RoleViewer, rec.Body.String())
+
+	//a token that is sent must be valid; it never silently degrades to anonymous
+	assert.Equal(t, http.StatusUnauthorized, get("Bearer not-a-token").Code)
+}
+
+func TestSalaryVisibility(t *testing.T) {
+	cfg, ctrl, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	token, err := generateValidJWT(cfg)
+	require.NoError(t, err)
+
+	e := newEcho()
+	manager := createTestEmployee(t, e, ctrl, token, `{"name": "Visibility Manager", "position": "Lead", "salary": 120000}`)
+	report := createTestEmployee(t, e, ctrl, token, `{"name": "Visibility Report", "position": "Engineer", "salary": 90000}`)
+	outsider := createTestEmployee(t, e, ctrl, token, `{"name": "Visibility Outsider", "position": "Engineer", "salary": 95000}`)
+	require.Equal(t, http.StatusNoContent, setManager(t, e, ctrl, token, report.ID.String(), manager.ID.String()))
+
+	viewer, err := generateJWTForRole(cfg, auth.RoleViewer)
+	require.NoError(t, err)
+	hr, err := generateJWTForRole(cfg, auth.RoleHR)
+	require.NoError(t, err)
+	managerToken, err := generateJWTForEmployee(cfg, auth.RoleManager, manager.ID)
+	require.NoError(t, err)
+
+	//salaryOf fetches id as the holder of token and returns the salary field, if any
+	salaryOf := func(token string, id uuid.UUID) (float64, bool) {
+		req := httptest.NewRequest(http.MethodGet, "/employees/"+id.String(), nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id.String())
+		require.NoError(t, middleware.OptionalJWTAuthMiddleware(cfg, stubRevocations{})(ctrl.GetEmployee)(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var fields map[string]interface{}
+		decodePayload(t, rec, &fields)
+		salary, ok := fields["salary"].(float64)
+		return salary, ok
+	}
+
+	//run twice so the second round is served from the cache
+	for round := 0; round < 2; round++ {
+		_, ok := salaryOf("", report.ID)
+		assert.False(t, ok, "anonymous")
+		_, ok = salaryOf(viewer, report.ID)
+		assert.False(t, ok, "viewer")
+
+		salary, ok := salaryOf(hr, outsider.ID)
+		assert.True(t, ok, "hr")
+		assert.Equal(t, 95000.0, salary)
+
+		salary, ok = salaryOf(managerToken, report.ID)
+		assert.True(t, ok, "manager on own report")
+		assert.Equal(t, 90000.0, salary)
+		_, ok = salaryOf(managerToken, outsider.ID)
+		assert.False(t, ok, "manager on someone else's report")
+	}
+
+	//filtering on salary would leak it, so only callers who see every salary may do it
+	req := httptest.NewRequest(http.MethodGet, "/employees?min_salary=100000", nil)
+	req.Header.Set("Authorization", "Bearer "+managerToken)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	err = middleware.OptionalJWTAuthMiddleware(cfg, stubRevocations{})(ctrl.ListEmployees)(c)
+	require.ErrorIs(t, err, customerr.ErrForbidden)
+	customerr.HTTPErrorHandler(err, c)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	var problem customerr.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, customerr.CodeForbidden, problem.Code)
+}
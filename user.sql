@@ -0,0 +1,28 @@
+-- name: CreateUser :one
+INSERT INTO users (id, email, password_hash, role, employee_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id;
+
+-- name: GetUserByID :one
+SELECT id, email, password_hash, role, employee_id, created_at, updated_at
+FROM users
+WHERE id = $1;
+
+-- name: GetUserByEmail :one
+SELECT id, email, password_hash, role, employee_id, created_at, updated_at
+FROM users
+WHERE email = $1;
+
+-- name: ListUsers :many
+SELECT id, email, password_hash, role, employee_id, created_at, updated_at
+FROM users
+ORDER BY email;
+
+-- name: UpdateUserRole :execrows
+UPDATE users
+SET role = $1, employee_id = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $3;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1;
@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lijuuu/EmployeeManagement/customerr"
+	"github.com/lijuuu/EmployeeManagement/money"
+)
+
+// Validator checks request DTOs against their `validate` tags. It implements
+// echo.Validator, so handlers run it through ctx.Validate.
+//
+// Besides the built-in rules it understands:
+//   - notblank: a string with something other than whitespace
+//   - position: a position from the configured whitelist (any when the list is empty)
+//   - notfuture: a time no later than today
+//   - mindate=YYYY-MM-DD: a time on or after the given date
+//   - currency: an ISO 4217 currency code
+//
+// money.Amount and money.Rate fields are compared as numbers by gt, lte and
+// the like.
+type Validator struct {
+	validate  *validator.Validate
+	positions map[string]bool
+}
+
+// New returns a Validator that accepts the given positions; nil or empty allows any
+func New(positions []string) *Validator {
+	v := &Validator{validate: validator.New(validator.WithRequiredStructEnabled())}
+	if len(positions) > 0 {
+		v.positions = make(map[string]bool, len(positions))
+		for _, p := range positions {
+			v.positions[p] = true
+		}
+	}
+
+	//report fields under their JSON names
+	v.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.validate.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+	v.validate.RegisterValidation("position", func(fl validator.FieldLevel) bool {
+		return v.positions == nil || v.positions[fl.Field().String()]
+	})
+	v.validate.RegisterValidation("notfuture", func(fl validator.FieldLevel) bool {
+		t, ok := fl.Field().Interface().(time.Time)
+		return ok && !t.After(endOfToday())
+	})
+	v.validate.RegisterValidation("mindate", func(fl validator.FieldLevel) bool {
+		t, ok := fl.Field().Interface().(time.Time)
+		min, err := time.Parse(time.DateOnly, fl.Param())
+		return ok && err == nil && !t.Before(min)
+	})
+	v.validate.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return money.ValidCurrency(fl.Field().String())
+	})
+	v.validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		switch value := field.Interface().(type) {
+		case money.Amount:
+			return value.Float64()
+		case money.Rate:
+			return value.Float64()
+		}
+		return nil
+	}, money.Amount{}, money.Rate{})
+	return v
+}
+
+// Validate returns nil for a valid request, or a customerr validation error
+// listing every invalid field
+func (v *Validator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	fields := make([]customerr.FieldError, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		fields[i] = fieldError(fe)
+	}
+	return customerr.InvalidFields(fields...)
+}
+
+// fieldError turns a failed rule into a client-facing field error
+func fieldError(fe validator.FieldError) customerr.FieldError {
+	field := fe.Field()
+	switch fe.Tag() {
+	case "required", "notblank":
+		return customerr.FieldError{Field: field, Code: customerr.FieldRequired, Message: field + " is required"}
+	case "max":
+		return customerr.FieldError{Field: field, Code: customerr.FieldOutOfRange, Message: fmt.Sprintf("%s must be at most %s characters", field, fe.Param())}
+	case "min":
+		return customerr.FieldError{Field: field, Code: customerr.FieldOutOfRange, Message: fmt.Sprintf("%s must be at least %s characters", field, fe.Param())}
+	case "gt":
+		return customerr.FieldError{Field: field, Code: customerr.FieldOutOfRange, Message: fmt.Sprintf("%s must be greater than %s", field, fe.Param())}
+	case "gte":
+		return customerr.FieldError{Field: field, Code: customerr.FieldOutOfRange, Message: fmt.Sprintf("%s must be at least %s", field, fe.Param())}
+	case "lte":
+		return customerr.FieldError{Field: field, Code: customerr.FieldOutOfRange, Message: fmt.Sprintf("%s must be at most %s", field, fe.Param())}
+	case "notfuture":
+		return customerr.FieldError{Field: field, Code: customerr.FieldOutOfRange, Message: field + " must not be in the future"}
+	case "mindate":
+		return customerr.FieldError{Field: field, Code: customerr.FieldOutOfRange, Message: fmt.Sprintf("%s must be on or after %s", field, fe.Param())}
+	case "currency":
+		return customerr.FieldError{Field: field, Code: customerr.FieldInvalidValue, Message: field + " must be an ISO 4217 currency code"}
+	case "position":
+		return customerr.FieldError{Field: field, Code: customerr.FieldInvalidValue, Message: field + " is not an allowed position"}
+	case "oneof":
+		return customerr.FieldError{Field: field, Code: customerr.FieldInvalidValue, Message: fmt.Sprintf("%s must be one of %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))}
+	}
+	return customerr.FieldError{Field: field, Code: customerr.FieldInvalid, Message: field + " is invalid"}
+}
+
+// endOfToday is the last instant of the current UTC day, so any time on
+// today's date is accepted
+func endOfToday() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour).Add(24*time.Hour - time.Nanosecond)
+}